@@ -26,13 +26,17 @@ func (d retryer) RetryRules(r *request.Request) time.Duration {
 
 func init() {
 	initClient = func(c *client.Client) {
-		r := retryer{}
-		if c.Config.MaxRetries == nil || aws.IntValue(c.Config.MaxRetries) == aws.UseServiceDefaultRetries {
-			r.NumMaxRetries = 10
-		} else {
-			r.NumMaxRetries = *c.Config.MaxRetries
+		// A ThrottleRetryer set in the client's config replaces DynamoDB's
+		// default retryer.
+		if _, ok := c.Config.Retryer.(*ThrottleRetryer); !ok {
+			r := retryer{}
+			if c.Config.MaxRetries == nil || aws.IntValue(c.Config.MaxRetries) == aws.UseServiceDefaultRetries {
+				r.NumMaxRetries = 10
+			} else {
+				r.NumMaxRetries = *c.Config.MaxRetries
+			}
+			c.Retryer = r
 		}
-		c.Retryer = r
 
 		c.Handlers.Build.PushBack(disableCompression)
 		c.Handlers.Unmarshal.PushFront(validateCRC32)
@@ -0,0 +1,153 @@
+package dynamodbattribute
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+)
+
+// A BatchWriteEncoder marshals Go values directly into the DynamoDB JSON
+// request body of a BatchWriteItem call, writing each item to the
+// io.Writer as it is added. Only the AttributeValues of the item being
+// added are held in memory, instead of those of every item in the batch,
+// as building a BatchWriteItemInput requires.
+//
+//     var body bytes.Buffer
+//     enc := dynamodbattribute.NewBatchWriteEncoder(&body)
+//     for _, order := range orders {
+//         if err := enc.Put("Orders", order); err != nil {
+//             return err
+//         }
+//     }
+//     if err := enc.Close(); err != nil {
+//         return err
+//     }
+//
+// Requests must be added grouped by table, as the request body has a single
+// list of requests per table. Adding a request for a table after requests
+// for another table have been added returns an error. DynamoDB limits the
+// number of requests of a BatchWriteItem call, see Len.
+//
+// A BatchWriteEncoder is not safe for concurrent use.
+type BatchWriteEncoder struct {
+	w   io.Writer
+	enc *Encoder
+
+	table  string
+	tables map[string]bool
+	n      int
+	err    error
+	closed bool
+}
+
+// NewBatchWriteEncoder returns a BatchWriteEncoder writing the request
+// body to w. The `opts` functional options are applied to the Encoder the
+// items are marshaled with.
+func NewBatchWriteEncoder(w io.Writer, opts ...func(*Encoder)) *BatchWriteEncoder {
+	return &BatchWriteEncoder{
+		w:      w,
+		enc:    NewEncoder(opts...),
+		tables: map[string]bool{},
+	}
+}
+
+// Put marshals the item, which must marshal to an AttributeValue map, and
+// writes it as a PutRequest for the table.
+func (b *BatchWriteEncoder) Put(table string, item interface{}) error {
+	return b.write(table, "PutRequest", "Item", item)
+}
+
+// Delete marshals the key, which must marshal to an AttributeValue map,
+// and writes it as a DeleteRequest for the table.
+func (b *BatchWriteEncoder) Delete(table string, key interface{}) error {
+	return b.write(table, "DeleteRequest", "Key", key)
+}
+
+// Len returns the number of requests written.
+func (b *BatchWriteEncoder) Len() int {
+	return b.n
+}
+
+// Close writes the end of the request body. No requests can be added once
+// the encoder is closed. An error is returned if no requests were added,
+// as DynamoDB requires at least one.
+func (b *BatchWriteEncoder) Close() error {
+	if b.err != nil || b.closed {
+		return b.err
+	}
+	b.closed = true
+
+	if b.n == 0 {
+		b.err = &InvalidMarshalError{msg: "batch write request has no items"}
+		return b.err
+	}
+	return b.writeString("]}}")
+}
+
+func (b *BatchWriteEncoder) write(table, request, member string, in interface{}) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.closed {
+		return &InvalidMarshalError{msg: "batch write encoder is closed"}
+	}
+
+	av, err := b.enc.Encode(in)
+	if err != nil {
+		return err
+	}
+	if av.M == nil {
+		return &InvalidMarshalError{msg: "batch write " + member + " must marshal to a map"}
+	}
+	body, err := jsonutil.BuildJSON(av.M)
+	if err != nil {
+		return err
+	}
+
+	if table != b.table || b.n == 0 {
+		if b.tables[table] {
+			return &InvalidMarshalError{msg: "batch write requests for table " + table + " must be added together"}
+		}
+		name, err := json.Marshal(table)
+		if err != nil {
+			return err
+		}
+
+		prefix := `{"RequestItems":{`
+		if b.n != 0 {
+			prefix = "],"
+		}
+		if err := b.writeString(prefix + string(name) + ":["); err != nil {
+			return err
+		}
+		b.table = table
+		b.tables[table] = true
+	} else if err := b.writeString(","); err != nil {
+		return err
+	}
+
+	if err := b.writeString(`{"` + request + `":{"` + member + `":`); err != nil {
+		return err
+	}
+	if _, err := b.w.Write(body); err != nil {
+		b.err = err
+		return err
+	}
+	if err := b.writeString("}}"); err != nil {
+		return err
+	}
+
+	b.n++
+	return nil
+}
+
+// writeString writes s, keeping the error so the partially written body
+// cannot be continued.
+func (b *BatchWriteEncoder) writeString(s string) error {
+	if _, err := io.WriteString(b.w, s); err != nil {
+		b.err = err
+		return err
+	}
+	return nil
+}
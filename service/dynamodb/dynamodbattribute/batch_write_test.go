@@ -0,0 +1,92 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestBatchWriteEncoder(t *testing.T) {
+	type order struct {
+		ID    string
+		Total int
+	}
+
+	var body bytes.Buffer
+	enc := NewBatchWriteEncoder(&body)
+	for _, o := range []order{{"a", 1}, {"b", 2}} {
+		if err := enc.Put("Orders", o); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if err := enc.Delete("Customers", map[string]string{"ID": "c"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := enc.Put("Orders", order{"c", 3}); err == nil {
+		t.Errorf("expect error adding to a previous table")
+	}
+	if err := enc.Put("Orders", "abc"); err == nil {
+		t.Errorf("expect error for non-map item")
+	}
+	if e, a := 3, enc.Len(); e != a {
+		t.Errorf("expect %d requests, got %d", e, a)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := enc.Put("Orders", order{"d", 4}); err == nil {
+		t.Errorf("expect error once closed")
+	}
+
+	var actual dynamodb.BatchWriteItemInput
+	if err := jsonutil.UnmarshalJSON(&actual, bytes.NewReader(body.Bytes())); err != nil {
+		t.Fatalf("expect no error, got %v, %s", err, body.String())
+	}
+	expect := dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"Orders": {
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"ID": {S: aws.String("a")}, "Total": {N: aws.String("1")},
+				}}},
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"ID": {S: aws.String("b")}, "Total": {N: aws.String("2")},
+				}}},
+			},
+			"Customers": {
+				{DeleteRequest: &dynamodb.DeleteRequest{Key: map[string]*dynamodb.AttributeValue{
+					"ID": {S: aws.String("c")},
+				}}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestBatchWriteEncoderEmpty(t *testing.T) {
+	var body bytes.Buffer
+	if err := NewBatchWriteEncoder(&body).Close(); err == nil {
+		t.Errorf("expect error closing an empty batch")
+	}
+}
+
+func TestBatchWriteEncoderOptions(t *testing.T) {
+	var body bytes.Buffer
+	enc := NewBatchWriteEncoder(&body, func(e *Encoder) {
+		e.NumbersAsStrings = true
+	})
+	if err := enc.Put("T", map[string]int{"N": 1}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := `{"RequestItems":{"T":[{"PutRequest":{"Item":{"N":{"S":"1"}}}}]}}`, body.String(); e != a {
+		t.Errorf("expect %s, got %s", e, a)
+	}
+}
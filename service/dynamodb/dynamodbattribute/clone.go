@@ -0,0 +1,103 @@
+package dynamodbattribute
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Clone returns a deep copy of the AttributeValue. Nested maps, lists,
+// sets, byte slices, and the value pointers are all copied, so the clone
+// can be modified without affecting av.
+func Clone(av *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if av == nil {
+		return nil
+	}
+
+	c := &dynamodb.AttributeValue{}
+	if av.B != nil {
+		c.B = cloneBytes(av.B)
+	}
+	if av.BOOL != nil {
+		v := *av.BOOL
+		c.BOOL = &v
+	}
+	if av.BS != nil {
+		c.BS = make([][]byte, len(av.BS))
+		for i, b := range av.BS {
+			c.BS[i] = cloneBytes(b)
+		}
+	}
+	if av.L != nil {
+		c.L = CloneList(av.L)
+	}
+	if av.M != nil {
+		c.M = CloneMap(av.M)
+	}
+	if av.N != nil {
+		v := *av.N
+		c.N = &v
+	}
+	if av.NS != nil {
+		c.NS = cloneStrings(av.NS)
+	}
+	if av.NULL != nil {
+		v := *av.NULL
+		c.NULL = &v
+	}
+	if av.S != nil {
+		v := *av.S
+		c.S = &v
+	}
+	if av.SS != nil {
+		c.SS = cloneStrings(av.SS)
+	}
+
+	return c
+}
+
+// CloneMap returns a deep copy of the map of AttributeValues, such as an
+// item. See Clone.
+func CloneMap(m map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[string]*dynamodb.AttributeValue, len(m))
+	for k, av := range m {
+		c[k] = Clone(av)
+	}
+	return c
+}
+
+// CloneList returns a deep copy of the list of AttributeValues. See Clone.
+func CloneList(l []*dynamodb.AttributeValue) []*dynamodb.AttributeValue {
+	if l == nil {
+		return nil
+	}
+
+	c := make([]*dynamodb.AttributeValue, len(l))
+	for i, av := range l {
+		c[i] = Clone(av)
+	}
+	return c
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+func cloneStrings(ss []*string) []*string {
+	c := make([]*string, len(ss))
+	for i, s := range ss {
+		if s != nil {
+			v := *s
+			c[i] = &v
+		}
+	}
+	return c
+}
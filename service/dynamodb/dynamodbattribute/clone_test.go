@@ -0,0 +1,65 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func testCloneTemplate() map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"b":    {B: []byte{1, 2}},
+		"bool": {BOOL: aws.Bool(true)},
+		"bs":   {BS: [][]byte{{1}, {2}}},
+		"l": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("a")},
+			{M: map[string]*dynamodb.AttributeValue{"n": {N: aws.String("1")}}},
+		}},
+		"ns":   {NS: []*string{aws.String("1"), aws.String("2")}},
+		"null": {NULL: aws.Bool(true)},
+		"ss":   {SS: []*string{aws.String("a")}},
+		"empty": {
+			L: []*dynamodb.AttributeValue{},
+			M: map[string]*dynamodb.AttributeValue{},
+		},
+	}
+}
+
+func TestCloneMap(t *testing.T) {
+	template := testCloneTemplate()
+	clone := CloneMap(template)
+	if !reflect.DeepEqual(template, clone) {
+		t.Fatalf("expect %v, got %v", template, clone)
+	}
+
+	// Modify every part of the clone, the template must not change.
+	clone["b"].B[0] = 9
+	*clone["bool"].BOOL = false
+	clone["bs"].BS[0][0] = 9
+	*clone["l"].L[0].S = "z"
+	*clone["l"].L[1].M["n"].N = "9"
+	clone["l"].L[1].M["x"] = &dynamodb.AttributeValue{S: aws.String("x")}
+	*clone["ns"].NS[0] = "9"
+	*clone["null"].NULL = false
+	*clone["ss"].SS[0] = "z"
+	clone["empty"].L = append(clone["empty"].L, &dynamodb.AttributeValue{})
+	clone["new"] = &dynamodb.AttributeValue{}
+
+	if e, a := testCloneTemplate(), template; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect template unchanged %v, got %v", e, a)
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	if v := Clone(nil); v != nil {
+		t.Errorf("expect nil, got %v", v)
+	}
+	if v := CloneMap(nil); v != nil {
+		t.Errorf("expect nil, got %v", v)
+	}
+	if v := CloneList(nil); v != nil {
+		t.Errorf("expect nil, got %v", v)
+	}
+}
@@ -0,0 +1,88 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// encodeCompressed encodes the value of a field with the `compress` tag
+// option. []byte and string values are gzipped as is, and values of other
+// types are JSON encoded first. Values smaller than the Encoder's
+// CompressThreshold are encoded as if the field had no `compress` option.
+func (e *Encoder) encodeCompressed(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	fieldTag.Compress = false
+
+	ev := valueElem(v)
+	if !ev.IsValid() {
+		return e.encode(av, v, fieldTag)
+	}
+
+	var data []byte
+	switch {
+	case ev.Kind() == reflect.String:
+		data = []byte(ev.String())
+	case ev.Kind() == reflect.Slice && ev.Type().Elem().Kind() == reflect.Uint8:
+		data = ev.Bytes()
+	default:
+		b, err := json.Marshal(ev.Interface())
+		if err != nil {
+			return &InvalidMarshalError{msg: "failed to JSON encode compressed value, " + err.Error()}
+		}
+		data = b
+	}
+	if len(data) == 0 || len(data) < e.CompressThreshold {
+		return e.encode(av, v, fieldTag)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	av.B = buf.Bytes()
+
+	return nil
+}
+
+// isGzip returns if b starts with the gzip header's magic number.
+func isGzip(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b
+}
+
+// decodeCompressed decodes the gzipped binary attribute of a field with the
+// `compress` tag option into v.
+func (d *Decoder) decodeCompressed(b []byte, v reflect.Value) error {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return &UnmarshalTypeError{Value: "compressed binary, " + err.Error(), Type: v.Type()}
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &UnmarshalTypeError{Value: "compressed binary, " + err.Error(), Type: v.Type()}
+	}
+
+	_, v = indirect(v, false)
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(string(data))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes(data)
+	default:
+		if !v.CanAddr() {
+			return &UnmarshalTypeError{Value: "compressed binary", Type: v.Type()}
+		}
+		if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+			return &UnmarshalTypeError{Value: "compressed JSON, " + err.Error(), Type: v.Type()}
+		}
+	}
+
+	return nil
+}
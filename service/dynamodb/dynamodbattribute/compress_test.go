@@ -0,0 +1,99 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testCompressed struct {
+	Body    string            `dynamodbav:",compress"`
+	Raw     []byte            `dynamodbav:",compress"`
+	Meta    map[string]string `dynamodbav:",compress"`
+	Pointer *string           `dynamodbav:",compress,omitempty"`
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	in := testCompressed{
+		Body: strings.Repeat("abc", 1000),
+		Raw:  []byte(strings.Repeat("x", 1000)),
+		Meta: map[string]string{"a": "b"},
+	}
+
+	av, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, name := range []string{"Body", "Raw", "Meta"} {
+		if !isGzip(av[name].B) {
+			t.Errorf("expect %s to be compressed, got %v", name, av[name])
+		}
+	}
+	if e, a := 100, len(av["Body"].B); a > e {
+		t.Errorf("expect compressed size less than %d, got %d", e, a)
+	}
+	if _, ok := av["Pointer"]; ok {
+		t.Errorf("expect nil pointer omitted")
+	}
+
+	var actual testCompressed
+	if err := UnmarshalMap(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestCompressThreshold(t *testing.T) {
+	in := testCompressed{
+		Body:    "small",
+		Raw:     []byte{1, 2, 3},
+		Meta:    map[string]string{"a": "b"},
+		Pointer: aws.String(strings.Repeat("abc", 100)),
+	}
+
+	av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+		e.CompressThreshold = 64
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"Body": {S: aws.String("small")},
+		"Raw":  {B: []byte{1, 2, 3}},
+		"Meta": {M: map[string]*dynamodb.AttributeValue{"a": {S: aws.String("b")}}},
+	}
+	for name, e := range expect {
+		if a := av[name]; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s, expect %v, got %v", name, e, a)
+		}
+	}
+	if !isGzip(av["Pointer"].B) {
+		t.Errorf("expect Pointer compressed, got %v", av["Pointer"])
+	}
+
+	var actual testCompressed
+	if err := UnmarshalMap(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestCompressInvalid(t *testing.T) {
+	av := map[string]*dynamodb.AttributeValue{
+		"Body": {B: []byte{0x1f, 0x8b, 0, 0}},
+	}
+
+	var actual testCompressed
+	err := UnmarshalMap(av, &actual)
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %T, %v", err, err)
+	}
+}
@@ -0,0 +1,115 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testSharedCodecItem struct {
+	ID     string
+	Parent *testSharedCodecItem `dynamodbav:",omitempty"`
+	Tags   []string             `dynamodbav:",stringset"`
+	Counts map[string]int
+}
+
+func TestSharedEncoderDecoder(t *testing.T) {
+	e := NewEncoder(func(e *Encoder) {
+		e.MaxDepth = 8
+		e.StrictTypes = true
+		e.StrictFieldNames = true
+	})
+	d := NewDecoder(func(d *Decoder) {
+		d.FlattenStructs = true
+	})
+
+	codecs := []struct {
+		e *Encoder
+		d *Decoder
+	}{
+		{e, d},
+		{defaultEncoder, defaultDecoder},
+	}
+
+	for i, c := range codecs {
+		var wg sync.WaitGroup
+		errs := make(chan error, 50)
+		for j := 0; j < cap(errs); j++ {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+
+				in := testSharedCodecItem{
+					ID:     string(rune('a' + j%26)),
+					Parent: &testSharedCodecItem{ID: "parent"},
+					Tags:   []string{"x", "y"},
+					Counts: map[string]int{"n": j},
+				}
+				av, err := c.e.Encode(in)
+				if err != nil {
+					errs <- err
+					return
+				}
+				var out testSharedCodecItem
+				if err := c.d.Decode(av, &out); err != nil {
+					errs <- err
+					return
+				}
+				if !reflect.DeepEqual(in, out) {
+					t.Errorf("%d, expect %v, got %v", i, in, out)
+				}
+			}(j)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+	}
+}
+
+func TestDefaultEncoderDecoderCopies(t *testing.T) {
+	d := DefaultDecoder()
+	d.AppendSlices = true
+	e := DefaultEncoder()
+	e.NullEmptyString = false
+
+	if d == defaultDecoder || defaultDecoder.AppendSlices {
+		t.Errorf("expect copy of default decoder")
+	}
+	if e == defaultEncoder || !defaultEncoder.NullEmptyString {
+		t.Errorf("expect copy of default encoder")
+	}
+
+	// The helpers without options keep the default configuration.
+	items := []map[string]*dynamodb.AttributeValue{
+		{"ID": {S: aws.String("b")}},
+	}
+	out := []testSharedCodecItem{{ID: "a"}}
+	if err := UnmarshalListOfMaps(items, &out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []testSharedCodecItem{{ID: "b"}}, out; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var m map[string]string
+	if err := UnmarshalMapWithOptions(items[0], &m); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := map[string]string{"ID": "b"}, m; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av, err := Marshal("")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if av.NULL == nil || !*av.NULL {
+		t.Errorf("expect empty string marshaled to NULL, got %v", av)
+	}
+}
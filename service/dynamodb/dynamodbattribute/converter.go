@@ -1,50 +1,3 @@
-// Package dynamodbattribute provides conversion utilities from dynamodb.AttributeValue
-// to concrete Go types and structures. These conversion utilities allow you to
-// convert a Struct, Slice, Map, or Scalar value to or from dynamodb.AttributeValue.
-// These are most useful to serialize concrete types to dynamodb.AttributeValue for
-// requests or unmarshalling the dynamodb.AttributeValue into a well known typed form.
-//
-// Converting []byte fields to dynamodb.AttributeValue are only currently supported
-// if the input is a map[string]interface{} type. []byte within typed structs are not
-// converted correctly and are converted into base64 strings. This is a known bug,
-// and will be fixed in a later release.
-//
-// Convert concrete type to dynamodb.AttributeValue: See (ExampleConvertTo)
-//
-//     type Record struct {
-//         MyField string
-//         Letters []string
-//         A2Num   map[string]int
-//     }
-//
-//     ...
-//
-//     r := Record{
-//         MyField: "dynamodbattribute.ConvertToX example",
-//         Letters: []string{"a", "b", "c", "d"},
-//         A2Num:   map[string]int{"a": 1, "b": 2, "c": 3},
-//     }
-//     av, err := dynamodbattribute.ConvertTo(r)
-//     fmt.Println(av, err)
-//
-// Convert dynamodb.AttributeValue to Concrete type: See (ExampleConvertFrom)
-//
-//     r2 := Record{}
-//     err = dynamodbattribute.ConvertFrom(av, &r2)
-//     fmt.Println(err, reflect.DeepEqual(r, r2))
-//
-// Use Conversion utilities with DynamoDB.PutItem: See ()
-//
-//     svc := dynamodb.New(nil)
-//     item, err := dynamodbattribute.ConvertToMap(r)
-//     if err != nil {
-//         fmt.Println("Failed to convert", err)
-//         return
-//     }
-//     result, err := svc.PutItem(&dynamodb.PutItemInput{
-//         Item:      item,
-//         TableName: aws.String("exampleTable"),
-//     })
 package dynamodbattribute
 
 import (
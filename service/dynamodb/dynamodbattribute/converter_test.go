@@ -490,3 +490,48 @@ func compareObjects(t *testing.T, expected interface{}, actual interface{}) {
 			awsutil.Prettify(actual))
 	}
 }
+
+type convertMigrationStruct struct {
+	Name    string            `json:"name"`
+	Count   int               `json:"count,omitempty"`
+	Ratio   float64           `json:"ratio"`
+	Enabled bool              `json:"enabled"`
+	Ignored string            `json:"-"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]string `json:"attrs"`
+	Child   *mySimpleStruct   `json:"child"`
+}
+
+func TestConvertMarshalParity(t *testing.T) {
+	in := convertMigrationStruct{
+		Name:    "abc",
+		Ratio:   3.14,
+		Enabled: true,
+		Ignored: "ignored",
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]string{"k": "v"},
+	}
+
+	converted, err := ConvertToMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	marshaled, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(converted, marshaled) {
+		t.Errorf("expect %v, got %v", converted, marshaled)
+	}
+
+	var fromConvert, fromUnmarshal convertMigrationStruct
+	if err := ConvertFromMap(marshaled, &fromConvert); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := UnmarshalMap(converted, &fromUnmarshal); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(fromConvert, fromUnmarshal) {
+		t.Errorf("expect %v, got %v", fromConvert, fromUnmarshal)
+	}
+}
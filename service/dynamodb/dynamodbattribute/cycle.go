@@ -0,0 +1,99 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A visitKey identifies a pointer, map, or slice value being encoded. The
+// type is included as a struct and its first field share an address, and
+// the length as slices of an array may share the address of its first
+// element.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// visitKeyOf returns the visitKey of v, and true, if v is a non-nil
+// pointer, map, or slice which may reference itself. Interfaces are
+// unwrapped to the value they hold.
+func visitKeyOf(v reflect.Value) (visitKey, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			return visitKey{}, false
+		}
+		return visitKey{ptr: v.Pointer(), typ: v.Type()}, true
+	case reflect.Slice:
+		if v.IsNil() || v.Len() == 0 {
+			return visitKey{}, false
+		}
+		return visitKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}, true
+	}
+
+	return visitKey{}, false
+}
+
+// visit adds the key to the values being encoded, returning a CycleError
+// if the value is already being encoded, i.e. it references itself.
+func (e *Encoder) visit(key visitKey) error {
+	for _, k := range e.visiting {
+		if k == key {
+			return &CycleError{Type: key.typ}
+		}
+	}
+	e.visiting = append(e.visiting, key)
+
+	return nil
+}
+
+// checkDepth returns a LimitExceededError if the struct, map, or list
+// being encoded is nested deeper than the Encoder's MaxDepth.
+func (e *Encoder) checkDepth() error {
+	if e.MaxDepth > 0 && e.depth > e.MaxDepth {
+		return &LimitExceededError{Limit: "nesting depth", Max: e.MaxDepth, Actual: e.depth}
+	}
+
+	return nil
+}
+
+// A CycleError is an error type representing a Go value which references
+// itself, such as a struct with a pointer to itself, and so cannot be
+// marshaled.
+type CycleError struct {
+	emptyOrigError
+
+	// Document path of the value referencing an enclosing value, e.g.
+	// "Parent.Parent".
+	Path string
+
+	// Go value type of the value.
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *CycleError) Code() string {
+	return "CycleError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *CycleError) Message() string {
+	msg := "Go value of type " + e.Type.String() + " references itself"
+	if len(e.Path) != 0 {
+		msg += ", " + e.Path
+	}
+	return msg
+}
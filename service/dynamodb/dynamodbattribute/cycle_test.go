@@ -0,0 +1,87 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testCycleNode struct {
+	Name string
+	Next *testCycleNode
+}
+
+func TestEncodeCycle(t *testing.T) {
+	a := &testCycleNode{Name: "a"}
+	b := &testCycleNode{Name: "b", Next: a}
+	a.Next = b
+
+	_, err := Marshal(a)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	ce, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("expect *CycleError, got %T, %v", err, err)
+	}
+	if e, a := "Next.Next", ce.Path; e != a {
+		t.Errorf("expect path %q, got %q", e, a)
+	}
+	if e, a := reflect.TypeOf(a), ce.Type; e != a {
+		t.Errorf("expect type %v, got %v", e, a)
+	}
+}
+
+func TestEncodeCycleMap(t *testing.T) {
+	m := map[string]interface{}{"a": "b"}
+	m["self"] = []interface{}{m}
+
+	_, err := Marshal(m)
+	if e, a := "CycleError: Go value of type map[string]interface {} references itself, self[0]", errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestEncodeSharedValueNotCycle(t *testing.T) {
+	shared := &testCycleNode{Name: "shared"}
+	in := struct {
+		A, B *testCycleNode
+		L    []*testCycleNode
+	}{A: shared, B: shared, L: []*testCycleNode{shared, shared}}
+
+	if _, err := Marshal(in); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
+
+func TestEncodeMaxDepth(t *testing.T) {
+	in := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []interface{}{
+				map[string]interface{}{"c": "d"},
+			},
+		},
+	}
+
+	for _, max := range []int{0, 3} {
+		_, err := MarshalWithOptions(in, func(e *Encoder) {
+			e.MaxDepth = max
+		})
+		if err != nil {
+			t.Errorf("%d, expect no error, got %v", max, err)
+		}
+	}
+
+	_, err := MarshalWithOptions(in, func(e *Encoder) {
+		e.MaxDepth = 2
+	})
+	if e, a := "LimitExceededError: nesting depth 3 exceeds limit of 2, a.b[0]", errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
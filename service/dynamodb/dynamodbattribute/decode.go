@@ -0,0 +1,1491 @@
+package dynamodbattribute
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// An Unmarshaler is an interface to provide custom unmarshaling of
+// AttributeValues. Use this to provide custom logic determining
+// how AttributeValues should be unmarshaled.
+// 		type ExampleUnmarshaler struct {
+// 			Value int
+// 		}
+//
+// 		func (u *exampleUnmarshaler) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+// 			if av.N == nil {
+// 				return nil
+// 			}
+//
+// 			n, err := strconv.ParseInt(*av.N, 10, 0)
+// 			if err != nil {
+// 				return err
+// 			}
+//
+// 			u.Value = n
+// 			return nil
+// 		}
+type Unmarshaler interface {
+	UnmarshalDynamoDBAttributeValue(*dynamodb.AttributeValue) error
+}
+
+// A NullableSetter is an interface for types which represent an explicit
+// NULL AttributeValue without being a pointer, similar to the sql.Null
+// types. The decoder calls SetNull instead of setting the value to its zero
+// value when unmarshaling a NULL AttributeValue into the type.
+//
+//     type OptionalString struct {
+//         Value string
+//         Null  bool
+//     }
+//
+//     func (s *OptionalString) SetNull() {
+//         *s = OptionalString{Null: true}
+//     }
+//
+// Types which implement Unmarshaler will have UnmarshalDynamoDBAttributeValue
+// called instead of SetNull.
+type NullableSetter interface {
+	SetNull()
+}
+
+// Unmarshal will unmarshal DynamoDB AttributeValues to Go value types.
+// Both generic interface{} and concrete types are valid unmarshal
+// destination types.
+//
+// Unmarshal will allocate maps, slices, and pointers as needed to
+// unmarshal the AttributeValue into the provided type value.
+//
+// When unmarshaling AttributeValues into structs Unmarshal matches
+// the field names of the struct to the AttributeValue Map keys.
+// Initially it will look for exact field name matching, but will
+// fall back to case insensitive if not exact match is found.
+//
+// With the exception of omitempty, omitemptyelem, binaryset, numberset
+// and stringset all struct tags used by Marshal are also used by
+// Unmarshal.
+//
+// A struct field tagged with the `required` option must have a matching
+// attribute in the AttributeValue Map. If one or more required attributes
+// are absent Unmarshal will return a MissingAttributeError listing all of
+// them, after the attributes which are present have been unmarshaled.
+//
+//     // Field must be present in the item
+//     Field string `dynamodbav:"email,required"`
+//
+// The string tag option also allows a String Set whose members are numbers
+// to be unmarshaled into a slice, array, or set map of a numeric type, for
+// tables written by clients which stored numbers in string sets.
+//
+//     // Field is unmarshaled from an SS such as ["1", "2"]
+//     Field []int `dynamodbav:",string"`
+//
+// If the output value, or any value nested within it, implements the
+// Validator interface its Validate method will be called once the value
+// has been unmarshaled. A failure is returned as a ValidationError which
+// includes the path of the attribute that failed.
+//
+// When decoding AttributeValues to interfaces Unmarshal will use the
+// following types.
+//
+//     []byte,                 AV Binary (B)
+//     [][]byte,               AV Binary Set (BS)
+//     bool,                   AV Boolean (BOOL)
+//     []interface{},          AV List (L)
+//     map[string]interface{}, AV Map (M)
+//     float64,                AV Number (N)
+//     []float64,              AV Number Set (NS)
+//     string,                 AV String (S)
+//     []string,               AV String Set (SS)
+//
+// When unmarshaling any error that occurs will halt the unmarshal
+// and return the error.
+//
+// The output value provided must be a non-nil pointer
+func Unmarshal(av *dynamodb.AttributeValue, out interface{}) error {
+	return defaultDecoder.Decode(av, out)
+}
+
+// UnmarshalWithOptions will unmarshal DynamoDB AttributeValues to Go value
+// types, the same as Unmarshal. The `opts` functional options are applied
+// to the Decoder used.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalWithOptions(av *dynamodb.AttributeValue, out interface{}, opts ...func(*Decoder)) error {
+	if len(opts) == 0 {
+		return defaultDecoder.Decode(av, out)
+	}
+
+	return NewDecoder(opts...).Decode(av, out)
+}
+
+// UnmarshalMap is an alias for Unmarshal which unmarshals from
+// a map of AttributeValues.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalMap(m map[string]*dynamodb.AttributeValue, out interface{}) error {
+	return UnmarshalMapWithOptions(m, out)
+}
+
+// UnmarshalMapWithOptions is an alias for UnmarshalWithOptions which
+// unmarshals from a map of AttributeValues.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalMapWithOptions(m map[string]*dynamodb.AttributeValue, out interface{}, opts ...func(*Decoder)) error {
+	return UnmarshalWithOptions(&dynamodb.AttributeValue{M: m}, out, opts...)
+}
+
+// UnmarshalList is an alias for Unmarshal func which unmarshals
+// a slice of AttributeValues.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalList(l []*dynamodb.AttributeValue, out interface{}) error {
+	return UnmarshalListWithOptions(l, out)
+}
+
+// UnmarshalListWithOptions is an alias for UnmarshalWithOptions func which
+// unmarshals a slice of AttributeValues.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalListWithOptions(l []*dynamodb.AttributeValue, out interface{}, opts ...func(*Decoder)) error {
+	return UnmarshalWithOptions(&dynamodb.AttributeValue{L: l}, out, opts...)
+}
+
+// UnmarshalListOfMaps is an alias for Unmarshal func which unmarshals a
+// slice of maps of attribute values.
+//
+// This is useful for when you need to unmarshal the Items from a DynamoDB
+// Query API call.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalListOfMaps(l []map[string]*dynamodb.AttributeValue, out interface{}) error {
+	return UnmarshalListOfMapsWithOptions(l, out)
+}
+
+// UnmarshalListOfMapsWithOptions is an alias for UnmarshalWithOptions func
+// which unmarshals a slice of maps of attribute values.
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalListOfMapsWithOptions(l []map[string]*dynamodb.AttributeValue, out interface{}, opts ...func(*Decoder)) error {
+	items := make([]*dynamodb.AttributeValue, len(l))
+	for i, m := range l {
+		items[i] = &dynamodb.AttributeValue{M: m}
+	}
+
+	return UnmarshalListWithOptions(items, out, opts...)
+}
+
+// UnmarshalStringMap unmarshals an item whose attributes are all strings
+// into a map[string]string, without the reflection Unmarshal uses. Use for
+// items known to only have String (S) attributes, such as those of config
+// or metadata tables read in hot loops.
+//
+// An UnmarshalTypeError is returned if any attribute is not a String.
+func UnmarshalStringMap(m map[string]*dynamodb.AttributeValue) (map[string]string, error) {
+	out := make(map[string]string, len(m))
+	for k, av := range m {
+		if av == nil || av.S == nil {
+			typ := "nil"
+			if av != nil {
+				typ = attributeValueTypeName(av)
+			}
+			return nil, &UnmarshalTypeError{Value: typ + " attribute " + k, Type: stringMapType}
+		}
+		out[k] = *av.S
+	}
+
+	return out, nil
+}
+
+// A Decoder provides unmarshaling AttributeValues to Go value types.
+//
+// A Decoder is safe for concurrent use by multiple goroutines, so a single
+// configured Decoder can be shared, unless its Trace option is set. Its
+// options must not be modified once it is in use. Create another Decoder
+// for a different configuration.
+type Decoder struct {
+	MarshalOptions
+
+	// Number of goroutines used to decode the elements of a list into a
+	// slice or array, such as the Items of a Query or Scan page with
+	// UnmarshalListOfMaps. The elements are decoded in chunks by a bounded
+	// pool of workers, and their order is preserved. Only the outermost
+	// list is decoded concurrently, nested lists are decoded serially.
+	//
+	// When enabled, Unmarshaler and Validator implementations may be
+	// called concurrently.
+	//
+	// Values less than 2 will decode serially, the default.
+	Concurrency int
+
+	// Instructs the decoder to decode AttributeValue Numbers as
+	// Number type instead of float64 when the destination type
+	// is interface{}. Similar to encoding/json.Number
+	UseNumber bool
+
+	// Reports a sample of the errors returned by Decode. See
+	// ErrorSampler.
+	//
+	// Disabled by default.
+	ErrorSampler *ErrorSampler
+
+	// Records how each struct field was decoded. See DecodeTrace.
+	//
+	// Disabled by default.
+	Trace *DecodeTrace
+
+	// Decrypts the attributes of fields with the `encrypted` struct tag
+	// option. Required to unmarshal values with encrypted fields. See
+	// EncryptionProvider.
+	Encryption EncryptionProvider
+
+	// Fields of nested struct types will be unmarshaled from the attributes
+	// named "Parent.Child" written by the Encoder's FlattenStructs option,
+	// if the item does not have the nested map attribute "Parent".
+	//
+	// Disabled by default.
+	FlattenStructs bool
+
+	// Binary values will be unmarshaled into []byte slices and interfaces
+	// without being copied, sharing the backing array of the
+	// AttributeValue's B or BS value. Avoids an allocation per binary
+	// value. The caller must ensure the AttributeValue is not modified
+	// while the unmarshaled value is in use. Arrays are always copied into.
+	//
+	// Disabled by default.
+	ZeroCopyBinary bool
+
+	// String AttributeValues will be unmarshaled into numeric types, the
+	// same as if every numeric field had the `string` struct tag option.
+	// Use to unmarshal values marshaled with the Encoder's
+	// NumbersAsStrings option.
+	//
+	// Disabled by default.
+	NumbersAsStrings bool
+
+	// NULL AttributeValues unmarshaled into values which cannot be nil,
+	// such as numbers, strings, bools, and structs, will return a
+	// NullIntoNonPointerError instead of setting the value to its zero
+	// value, which could mask missing data. Pointers, interfaces, maps,
+	// slices, and NullableSetter implementations are still set to nil or
+	// null.
+	//
+	// Disabled by default.
+	StrictNulls bool
+
+	// Numbers unmarshaled into integer types which have a fractional part,
+	// such as "1.5", will return a PrecisionLossError instead of a
+	// strconv.NumError. Integral numbers written with a fraction or
+	// exponent, such as "2.0" or "1e3", are unmarshaled.
+	//
+	// Disabled by default.
+	StrictNumbers bool
+
+	// Interns the map keys and string values of decoded values, so values
+	// decoded from many items share the storage of repeated attribute
+	// names and strings. See StringInterner. A StringInterner can be
+	// shared by Decoders.
+	//
+	// Disabled by default.
+	Interner *StringInterner
+
+	// Lists and sets unmarshaled into non-nil slices will have their
+	// elements appended to the slice's existing elements, instead of
+	// replacing the slice. Use to accumulate the Items of each Query or
+	// Scan page into a single slice with UnmarshalListOfMaps. Arrays are
+	// always overwritten.
+	//
+	// Disabled by default.
+	AppendSlices bool
+
+	// Lower case names of the attributes DecodeFields decodes into the
+	// outermost struct.
+	projection map[string]bool
+}
+
+// defaultDecoder is the Decoder with the default configuration shared by
+// Unmarshal, UnmarshalMap, UnmarshalList, and UnmarshalListOfMaps.
+var defaultDecoder = NewDecoder()
+
+// DefaultDecoder returns a copy of the Decoder with the default
+// configuration used by Unmarshal, UnmarshalMap, UnmarshalList, and
+// UnmarshalListOfMaps. Changes to the copy do not affect those functions.
+func DefaultDecoder() *Decoder {
+	d := *defaultDecoder
+	return &d
+}
+
+// NewDecoder creates a new Decoder with default configuration. Use
+// the `opts` functional options to override the default configuration.
+func NewDecoder(opts ...func(*Decoder)) *Decoder {
+	d := &Decoder{
+		MarshalOptions: MarshalOptions{
+			SupportJSONTags: true,
+		},
+	}
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// Decode will unmarshal an AttributeValue into a Go value type. An error
+// will be return if the decoder is unable to unmarshal the AttributeValue
+// to the provide Go value type.
+//
+// The output value provided must be a non-nil pointer
+func (d *Decoder) Decode(av *dynamodb.AttributeValue, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || !v.IsValid() {
+		err := &InvalidUnmarshalError{Type: reflect.TypeOf(out)}
+		d.ErrorSampler.Observe(err)
+		return err
+	}
+
+	if d.Trace != nil && d.Concurrency > 1 {
+		serial := *d
+		serial.Concurrency = 0
+		d = &serial
+	}
+
+	err := d.decode(av, v, tag{})
+	d.ErrorSampler.Observe(err)
+	return err
+}
+
+// DecodeFields is Decode, except only the attributes named by fields are
+// decoded. Other attributes of the AttributeValue map are ignored, and the
+// corresponding struct fields are not modified or checked for the
+// `required` tag option. This avoids decoding attributes the caller does
+// not use, e.g. when only a few attributes of a large item are needed.
+//
+//     var order Order
+//     err := decoder.DecodeFields(&dynamodb.AttributeValue{M: item}, &order, "ID", "Status")
+//
+// Attribute names are matched the same as by Decode, and only apply to the
+// attributes of the outermost map. Nested values of the named attributes
+// are decoded in full.
+//
+// The output value provided must be a non-nil pointer
+func (d *Decoder) DecodeFields(av *dynamodb.AttributeValue, out interface{}, fields ...string) error {
+	if av == nil || av.M == nil {
+		return d.Decode(av, out)
+	}
+
+	projected := make(map[string]*dynamodb.AttributeValue, len(fields))
+	projection := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		projection[strings.ToLower(name)] = true
+		if k := attrName(av.M, name); len(k) != 0 {
+			projected[k] = av.M[k]
+		}
+	}
+
+	pd := *d
+	if t := reflect.TypeOf(out); t != nil && t.Kind() == reflect.Ptr && schemaIndirect(t).Kind() == reflect.Struct {
+		pd.projection = projection
+	}
+	return pd.Decode(&dynamodb.AttributeValue{M: projected}, out)
+}
+
+var stringInterfaceMapType = reflect.TypeOf(map[string]interface{}(nil))
+var stringMapType = reflect.TypeOf(map[string]string(nil))
+var byteSliceSlicetype = reflect.TypeOf([][]byte(nil))
+var timeType = reflect.TypeOf(time.Time{})
+
+func (d *Decoder) decode(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	if fieldTag.Compress && av != nil && isGzip(av.B) {
+		return d.decodeCompressed(av.B, v)
+	}
+
+	var u interface{}
+	if av == nil || av.NULL != nil {
+		u, v = indirect(v, true)
+		if u != nil {
+			return d.unmarshal(u, av)
+		}
+		return d.decodeNull(v)
+	}
+
+	u, v = indirect(v, false)
+	if u != nil {
+		if err := d.unmarshal(u, av); err != nil {
+			return err
+		}
+		return tryValidator(reflect.ValueOf(u))
+	}
+
+	if fieldTag.UUID {
+		if err := decodeUUID(av, v); err != nil {
+			return err
+		}
+		return tryValidator(v)
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() != 0 {
+		if u, ok := lookupUnion(v.Type()); ok && av.M != nil {
+			return d.decodeUnion(av, v, u, fieldTag)
+		}
+		return &UnmarshalTypeError{Value: "attribute value", Type: v.Type()}
+	}
+	if v.Kind() == reflect.Interface && av.M != nil && len(d.TypeAttribute) != 0 {
+		if ok, err := d.decodeRegisteredType(av, v); ok {
+			return err
+		}
+	}
+
+	var err error
+	switch u := setUnmarshalerOf(v); {
+	case u != nil:
+		err = d.decodeSet(av, u)
+	case av.B != nil:
+		err = d.decodeBinary(av.B, v)
+	case av.BOOL != nil:
+		err = d.decodeBool(av.BOOL, v)
+	case av.BS != nil:
+		err = d.decodeBinarySet(av.BS, v)
+	case av.L != nil:
+		err = d.decodeList(av.L, v)
+	case av.M != nil:
+		err = d.decodeMap(av.M, v)
+	case av.N != nil:
+		err = d.decodeNumber(av.N, v)
+	case av.NS != nil:
+		err = d.decodeNumberSet(av.NS, v)
+	case av.S != nil:
+		err = d.decodeString(av.S, v, fieldTag)
+	case av.SS != nil:
+		err = d.decodeStringSet(av.SS, v, fieldTag)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tryValidator(v)
+}
+
+func (d *Decoder) decodeBinary(b []byte, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		v.Set(reflect.ValueOf(d.binaryBytes(b)))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return &UnmarshalTypeError{Value: "binary", Type: v.Type()}
+		}
+		v.SetBytes(d.binaryBytes(b))
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return &UnmarshalTypeError{Value: "binary", Type: v.Type()}
+		}
+		if v.Len() < len(b) {
+			return &UnmarshalTypeError{
+				Value: fmt.Sprintf("binary of length %d", len(b)),
+				Type:  v.Type(),
+			}
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+	default:
+		return &UnmarshalTypeError{Value: "binary", Type: v.Type()}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeBool(b *bool, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(*b)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(*b))
+	default:
+		return &UnmarshalTypeError{Value: "bool", Type: v.Type()}
+	}
+
+	return nil
+}
+
+// binaryBytes returns the bytes of a binary value to be unmarshaled into a
+// slice, copied unless the Decoder's ZeroCopyBinary option is enabled.
+func (d *Decoder) binaryBytes(b []byte) []byte {
+	if d.ZeroCopyBinary {
+		return b
+	}
+
+	buf := make([]byte, len(b))
+	copy(buf, b)
+	return buf
+}
+
+func (d *Decoder) decodeBinarySet(bs [][]byte, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		set := make([][]byte, len(bs))
+		for i, b := range bs {
+			if err := d.decodeBinary(b, reflect.ValueOf(&set[i]).Elem()); err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(set))
+		return nil
+	}
+
+	v, err := d.makeCollection(v, len(bs), "binary set")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < v.Len() && i < len(bs); i++ {
+		if err := d.decode(&dynamodb.AttributeValue{B: bs[i]}, v.Index(i), tag{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeNumber(n *string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		i, err := d.decodeNumberToInterface(n)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(i))
+		return nil
+	case reflect.String:
+		if v.Type() != numberType {
+			return &UnmarshalTypeError{Value: "number", Type: v.Type()}
+		}
+		v.SetString(*n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(*n, 10, 64)
+		if err != nil && d.StrictNumbers {
+			i, err = strictInt(*n, v.Type(), err)
+		}
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(i) {
+			return &UnmarshalTypeError{
+				Value: fmt.Sprintf("number overflow, %s", *n),
+				Type:  v.Type(),
+			}
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(*n, 10, 64)
+		if err != nil && d.StrictNumbers {
+			i, err = strictUint(*n, v.Type(), err)
+		}
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(i) {
+			return &UnmarshalTypeError{
+				Value: fmt.Sprintf("number overflow, %s", *n),
+				Type:  v.Type(),
+			}
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		i, err := strconv.ParseFloat(*n, 64)
+		if err != nil {
+			return err
+		}
+		if v.OverflowFloat(i) {
+			return &UnmarshalTypeError{
+				Value: fmt.Sprintf("number overflow, %s", *n),
+				Type:  v.Type(),
+			}
+		}
+		v.SetFloat(i)
+	default:
+		return &UnmarshalTypeError{Value: "number", Type: v.Type()}
+	}
+
+	return nil
+}
+
+// strictInt parses n, which strconv.ParseInt failed to parse with err, as
+// an integer written with a fraction or exponent, such as "1.0" or "1e3".
+// A PrecisionLossError is returned if n has a fractional part.
+func strictInt(n string, t reflect.Type, err error) (int64, error) {
+	i, err := strictInteger(n, t, err)
+	if err != nil {
+		return 0, err
+	}
+	if i.Cmp(minInt64) < 0 || i.Cmp(maxInt64) > 0 {
+		return 0, &UnmarshalTypeError{
+			Value: fmt.Sprintf("number overflow, %s", n),
+			Type:  t,
+		}
+	}
+	return i.Int64(), nil
+}
+
+var (
+	minInt64 = big.NewInt(math.MinInt64)
+	maxInt64 = big.NewInt(math.MaxInt64)
+)
+
+// strictUint is strictInt for unsigned integers.
+func strictUint(n string, t reflect.Type, err error) (uint64, error) {
+	i, err := strictInteger(n, t, err)
+	if err != nil {
+		return 0, err
+	}
+	if i.Sign() < 0 || i.BitLen() > 64 {
+		return 0, &UnmarshalTypeError{
+			Value: fmt.Sprintf("number overflow, %s", n),
+			Type:  t,
+		}
+	}
+	return i.Uint64(), nil
+}
+
+func strictInteger(n string, t reflect.Type, err error) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(n)
+	if !ok {
+		return nil, err
+	}
+	if !r.IsInt() {
+		return nil, &PrecisionLossError{Value: n, Type: t}
+	}
+	return r.Num(), nil
+}
+
+func (d *Decoder) decodeNumberToInterface(n *string) (interface{}, error) {
+	if d.UseNumber {
+		return Number(*n), nil
+	}
+
+	// Default to float64 for all numbers
+	return strconv.ParseFloat(*n, 64)
+}
+
+func (d *Decoder) decodeNumberSet(ns []*string, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		if d.UseNumber {
+			set := make([]Number, len(ns))
+			for i, n := range ns {
+				set[i] = Number(*n)
+			}
+			v.Set(reflect.ValueOf(set))
+			return nil
+		}
+
+		set := make([]float64, len(ns))
+		for i, n := range ns {
+			if err := d.decodeNumber(n, reflect.ValueOf(&set[i]).Elem()); err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(set))
+		return nil
+	}
+
+	if v.Kind() == reflect.Map {
+		return d.decodeMapSet(ns, true, v, tag{})
+	}
+
+	v, err := d.makeCollection(v, len(ns), "number set")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < v.Len() && i < len(ns); i++ {
+		if err := d.decode(&dynamodb.AttributeValue{N: ns[i]}, v.Index(i), tag{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeList(avList []*dynamodb.AttributeValue, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		s := make([]interface{}, len(avList))
+		for i, av := range avList {
+			if err := d.decodeElem(av, reflect.ValueOf(&s[i]).Elem(), "["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(s))
+		return nil
+	}
+
+	v, err := d.makeCollection(v, len(avList), "list")
+	if err != nil {
+		return err
+	}
+	if n := v.Len(); n < len(avList) {
+		avList = avList[:n]
+	}
+	if d.Concurrency > 1 && len(avList) > 1 {
+		return d.decodeListConcurrently(avList, v)
+	}
+	for i, av := range avList {
+		if err := d.decodeElem(av, v.Index(i), "["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeElem decodes the element of a list or map at the path segment.
+func (d *Decoder) decodeElem(av *dynamodb.AttributeValue, v reflect.Value, segment string) error {
+	if d.Trace != nil {
+		d.Trace.push(segment)
+		defer d.Trace.pop()
+	}
+
+	if err := d.decode(av, v, tag{}); err != nil {
+		return prefixValidationPath(err, segment)
+	}
+	return nil
+}
+
+// decodeListConcurrently decodes the list elements into v using a pool of
+// d.Concurrency workers. The error returned is the same error a serial
+// decode would return, the error of the first element which failed.
+func (d *Decoder) decodeListConcurrently(avList []*dynamodb.AttributeValue, v reflect.Value) error {
+	elemDecoder := *d
+	elemDecoder.Concurrency = 0
+
+	workers := d.Concurrency
+	if workers > len(avList) {
+		workers = len(avList)
+	}
+
+	// Split the list into several chunks per worker so the work is balanced
+	// when elements take differing amounts of time to decode.
+	chunkSize := len(avList) / (workers * 4)
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	numChunks := (len(avList) + chunkSize - 1) / chunkSize
+
+	errs := make([]error, numChunks)
+
+	// Index of the first chunk which failed. Chunks after it are skipped,
+	// as their errors would not be returned.
+	firstFailed := int32(numChunks)
+
+	chunks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				if int32(c) > atomic.LoadInt32(&firstFailed) {
+					continue
+				}
+
+				end := (c + 1) * chunkSize
+				if end > len(avList) {
+					end = len(avList)
+				}
+				for i := c * chunkSize; i < end; i++ {
+					if err := elemDecoder.decode(avList[i], v.Index(i), tag{}); err != nil {
+						errs[c] = prefixValidationPath(err, "["+strconv.Itoa(i)+"]")
+						for {
+							first := atomic.LoadInt32(&firstFailed)
+							if int32(c) >= first || atomic.CompareAndSwapInt32(&firstFailed, first, int32(c)) {
+								break
+							}
+						}
+						break
+					}
+				}
+			}
+		}()
+	}
+	for c := 0; c < numChunks; c++ {
+		chunks <- c
+	}
+	close(chunks)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// makeCollection prepares v, a slice or array, to receive n elements,
+// returning the value the elements are decoded into. Slices are replaced
+// with a new slice of length n, or with the AppendSlices option are
+// extended by n zero elements which are returned as a subslice. Arrays are
+// zeroed.
+func (d *Decoder) makeCollection(v reflect.Value, n int, avType string) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Slice:
+		if !d.AppendSlices || v.IsNil() {
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+			return v, nil
+		}
+
+		m := v.Len()
+		if m+n <= v.Cap() {
+			v.SetLen(m + n)
+			zero := reflect.Zero(v.Type().Elem())
+			for i := m; i < m+n; i++ {
+				v.Index(i).Set(zero)
+			}
+		} else {
+			v.Set(reflect.AppendSlice(v, reflect.MakeSlice(v.Type(), n, n)))
+		}
+		return v.Slice(m, m+n), nil
+	case reflect.Array:
+		v.Set(reflect.Zero(v.Type()))
+		return v, nil
+	default:
+		return v, &UnmarshalTypeError{Value: avType, Type: v.Type()}
+	}
+}
+
+func (d *Decoder) decodeMap(avMap map[string]*dynamodb.AttributeValue, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Map:
+		t := v.Type()
+		if t.Key().Kind() != reflect.String && !reflect.PtrTo(t.Key()).Implements(textUnmarshalerType) {
+			return &UnmarshalTypeError{Value: "map string key", Type: t.Key()}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+	case reflect.Struct:
+		return d.decodeStruct(avMap, v)
+	case reflect.Interface:
+		v.Set(reflect.MakeMap(stringInterfaceMapType))
+		v = v.Elem()
+	default:
+		return &UnmarshalTypeError{Value: "map", Type: v.Type()}
+	}
+
+	for k, av := range avMap {
+		key, err := decodeMapKey(d.intern(k), v.Type().Key())
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := d.decodeElem(av, elem, k); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// decodeMapKey returns the map key of type t for the attribute name k. Key
+// types implementing encoding.TextUnmarshaler unmarshal the name as text.
+func decodeMapKey(k string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		key := reflect.New(t)
+		if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k)); err != nil {
+			return reflect.Value{}, err
+		}
+		return key.Elem(), nil
+	}
+
+	return reflect.ValueOf(k).Convert(t), nil
+}
+
+// projectedField returns if the field's name or any of its aliases are in
+// the DecodeFields projection.
+func projectedField(projection map[string]bool, f field) bool {
+	if projection[strings.ToLower(f.Name)] {
+		return true
+	}
+	if len(f.ReadFrom) != 0 && projection[strings.ToLower(f.ReadFrom)] {
+		return true
+	}
+	for _, alias := range f.Aliases {
+		if projection[strings.ToLower(alias)] {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Decoder) decodeStruct(avMap map[string]*dynamodb.AttributeValue, v reflect.Value) error {
+	if d.projection == nil && d.Trace == nil {
+		if p := cachedStructProgram(v.Type(), d.MarshalOptions); p != nil {
+			return d.decodeStructProgram(avMap, v, p)
+		}
+	}
+
+	var missing []string
+
+	projection := d.projection
+	if projection != nil {
+		// Only the outermost struct is projected.
+		nested := *d
+		nested.projection = nil
+		d = &nested
+	}
+
+	fields := unionStructFields(v.Type(), d.MarshalOptions)
+	for _, f := range fields {
+		if projection != nil && !projectedField(projection, f) {
+			continue
+		}
+
+		name := f.Name
+		av, ok := attrByName(avMap, name)
+		if !ok && len(f.ReadFrom) != 0 {
+			name = f.ReadFrom
+			av, ok = attrByName(avMap, name)
+		}
+		for i := 0; !ok && i < len(f.Aliases); i++ {
+			name = f.Aliases[i]
+			av, ok = attrByName(avMap, name)
+		}
+		if !ok && d.FlattenStructs && isFlattenedStruct(f.Type) {
+			if m := unflattenStructField(avMap, f.Name); m != nil {
+				name = f.Name
+				av, ok = &dynamodb.AttributeValue{M: m}, true
+			}
+		}
+		if !ok {
+			if d.Trace != nil {
+				d.Trace.record(f, v.Type().FieldByIndex(f.Index).Name, "", TraceMissing, nil)
+			}
+			if f.Required {
+				missing = append(missing, f.Name)
+			}
+			continue
+		}
+
+		fv, _ := fieldByIndex(v, f.Index, func(v *reflect.Value) bool {
+			v.Set(reflect.New(v.Type().Elem()))
+			return true // to continue the loop.
+		})
+		if f.Encrypted {
+			var err error
+			if av, err = d.decryptField(avMap, av, name, fv.Type()); err != nil {
+				return prefixValidationPath(err, f.Name)
+			}
+		}
+		if d.Trace != nil {
+			if err := d.decodeTracedField(avMap, av, fv, v.Type(), f, name); err != nil {
+				return prefixValidationPath(err, f.Name)
+			}
+			continue
+		}
+		if err := d.decode(av, fv, f.tag); err != nil {
+			return prefixValidationPath(err, f.Name)
+		}
+	}
+	if d.Trace != nil {
+		d.Trace.recordUnknown(avMap, fields)
+	}
+
+	if len(missing) != 0 {
+		return &MissingAttributeError{Attributes: missing, Type: v.Type()}
+	}
+
+	return nil
+}
+
+// decodeTracedField decodes the field from its attribute, recording the
+// outcome in the Decoder's trace.
+func (d *Decoder) decodeTracedField(avMap map[string]*dynamodb.AttributeValue, av *dynamodb.AttributeValue,
+	fv reflect.Value, t reflect.Type, f field, name string) error {
+	goName := t.FieldByIndex(f.Index).Name
+	attr := attrName(avMap, name)
+
+	// Reserve the field's position so it is recorded before the fields of
+	// any nested struct.
+	i := len(d.Trace.Fields)
+	d.Trace.record(f, goName, attr, TraceDecoded, nil)
+
+	d.Trace.push(f.Name)
+	err := d.decode(av, fv, f.tag)
+	d.Trace.pop()
+
+	switch {
+	case err != nil:
+		d.Trace.Fields[i].Outcome = TraceFailed
+		d.Trace.Fields[i].Err = err
+	case av == nil || av.NULL != nil:
+		d.Trace.Fields[i].Outcome = TraceNull
+	}
+	return err
+}
+
+func (d *Decoder) decodeNull(v reflect.Value) error {
+	if v.IsValid() && v.Kind() != reflect.Ptr && v.CanAddr() {
+		if s, ok := v.Addr().Interface().(NullableSetter); ok {
+			s.SetNull()
+			return nil
+		}
+		if u := setUnmarshalerOf(v); u != nil {
+			return d.decodeSet(nil, u)
+		}
+	}
+
+	if v.IsValid() && v.CanSet() {
+		if d.StrictNulls && !nillable(v.Kind()) {
+			return &NullIntoNonPointerError{Type: v.Type()}
+		}
+		v.Set(reflect.Zero(v.Type()))
+	}
+
+	return nil
+}
+
+// nillable returns if values of the kind can be nil.
+func nillable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
+
+func (d *Decoder) decodeString(s *string, v reflect.Value, fieldTag tag) error {
+	if fieldTag.AsString || d.NumbersAsStrings {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return d.decodeNumber(s, v)
+		}
+	}
+
+	// To maintain backwards compatibility with ConvertFrom family of methods which
+	// converted strings to time.Time structs
+	if v.Kind() == reflect.Struct && v.Type().ConvertibleTo(timeType) {
+		t, err := time.Parse(time.RFC3339, *s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t).Convert(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(d.intern(*s))
+	case reflect.Interface:
+		// Ensure type aliasing is handled properly
+		v.Set(reflect.ValueOf(d.intern(*s)).Convert(v.Type()))
+	default:
+		return &UnmarshalTypeError{Value: "string", Type: v.Type()}
+	}
+
+	return nil
+}
+
+// decodeStringSet decodes the members of a string set. Members are
+// decoded into numeric types if the field has the string tag option, for
+// sets of numbers written as strings.
+func (d *Decoder) decodeStringSet(ss []*string, v reflect.Value, fieldTag tag) error {
+	if v.Kind() == reflect.Interface {
+		set := make([]string, len(ss))
+		for i, s := range ss {
+			set[i] = d.intern(*s)
+		}
+		v.Set(reflect.ValueOf(set))
+		return nil
+	}
+
+	elemTag := tag{AsString: fieldTag.AsString}
+	if v.Kind() == reflect.Map {
+		return d.decodeMapSet(ss, false, v, elemTag)
+	}
+
+	v, err := d.makeCollection(v, len(ss), "string set")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < v.Len() && i < len(ss); i++ {
+		if err := d.decode(&dynamodb.AttributeValue{S: ss[i]}, v.Index(i), elemTag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeMapSet decodes the members of a string or number set as the keys
+// of a map with struct{} or bool values, decoding the keys with the tag.
+func (d *Decoder) decodeMapSet(set []*string, numbers bool, v reflect.Value, keyTag tag) error {
+	setType := "string set"
+	if numbers {
+		setType = "number set"
+	}
+
+	t := v.Type()
+	if !isSetMapType(t) {
+		return &UnmarshalTypeError{Value: setType, Type: t}
+	}
+
+	member := reflect.Zero(t.Elem())
+	if t.Elem().Kind() == reflect.Bool {
+		member = reflect.ValueOf(true).Convert(t.Elem())
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(t))
+	}
+	for _, s := range set {
+		av := &dynamodb.AttributeValue{S: s}
+		if numbers {
+			av = &dynamodb.AttributeValue{N: s}
+		}
+
+		key := reflect.New(t.Key()).Elem()
+		if err := d.decode(av, key, keyTag); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, member)
+	}
+
+	return nil
+}
+
+// indirect will walk a value's interface or pointer value types. Returning
+// the final value or the value a unmarshaler is defined on. The unmarshaler
+// returned is either an UnmarshalerV2 or Unmarshaler.
+//
+// Based on the enoding/json type reflect value type indirection in Go Stdlib
+// https://golang.org/src/encoding/json/decode.go indirect func.
+func indirect(v reflect.Value, decodingNull bool) (interface{}, reflect.Value) {
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		v = v.Addr()
+	}
+	for {
+		// Load value from interface, but only if the result will be
+		// usefully addressable.
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Ptr) {
+				v = e
+				continue
+			}
+		}
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		if v.Elem().Kind() != reflect.Ptr && decodingNull && v.CanSet() {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 {
+			switch u := v.Interface().(type) {
+			case UnmarshalerV2:
+				return u, reflect.Value{}
+			case Unmarshaler:
+				return u, reflect.Value{}
+			}
+		}
+		v = v.Elem()
+	}
+
+	return nil, v
+}
+
+// A Validator is an interface a Go value type can implement to check its
+// invariants after it has been unmarshaled. The Decoder calls Validate on
+// the output value, and on any nested value within it, once that value has
+// been populated. Nested values are validated before the values containing
+// them.
+//
+//     type Order struct {
+//         ID       string
+//         Quantity int
+//     }
+//
+//     func (o *Order) Validate() error {
+//         if o.Quantity < 1 {
+//             return fmt.Errorf("quantity must be positive")
+//         }
+//         return nil
+//     }
+//
+// Validate is not called for values unmarshaled from NULL AttributeValues.
+// Errors returned by Validate are wrapped in a ValidationError.
+type Validator interface {
+	Validate() error
+}
+
+// tryValidator calls Validate on v, or on a pointer to v, if the type
+// implements the Validator interface.
+func tryValidator(v reflect.Value) error {
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		v = v.Addr()
+	}
+	if !v.IsValid() || !v.CanInterface() || v.Type().NumMethod() == 0 {
+		return nil
+	}
+
+	if vd, ok := v.Interface().(Validator); ok {
+		if err := vd.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+
+	return nil
+}
+
+// prefixValidationPath prepends the path segment to the attribute path of
+// err if it is a ValidationError, NullIntoNonPointerError, or
+// PrecisionLossError. Other errors are returned unmodified.
+func prefixValidationPath(err error, segment string) error {
+	var path *string
+	switch e := err.(type) {
+	case *ValidationError:
+		path = &e.Path
+	case *NullIntoNonPointerError:
+		path = &e.Path
+	case *PrecisionLossError:
+		path = &e.Path
+	default:
+		return err
+	}
+
+	switch {
+	case len(*path) == 0:
+		*path = segment
+	case (*path)[0] == '[':
+		*path = segment + *path
+	default:
+		*path = segment + "." + *path
+	}
+
+	return err
+}
+
+// A ValidationError is an error type representing a Validator which failed
+// after its value was unmarshaled.
+type ValidationError struct {
+	// Document path to the attribute whose value failed validation, e.g.
+	// "Orders[2].Address". Empty if the output value itself failed.
+	Path string
+
+	// Error returned by the Validate method.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *ValidationError) Code() string {
+	return "ValidationError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *ValidationError) Message() string {
+	if len(e.Path) == 0 {
+		return "validation failed, " + e.Err.Error()
+	}
+	return "validation failed for " + e.Path + ", " + e.Err.Error()
+}
+
+// OrigErr returns the error returned by the Validate method, satisfying
+// the awserr.Error interface.
+func (e *ValidationError) OrigErr() error {
+	return e.Err
+}
+
+// A MissingAttributeError is an error type representing one or more
+// attributes tagged as required which were absent from the AttributeValue
+// map being unmarshaled into a struct.
+type MissingAttributeError struct {
+	emptyOrigError
+
+	// Names of the required attributes which were not found.
+	Attributes []string
+
+	// Go value type being unmarshaled.
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *MissingAttributeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *MissingAttributeError) Code() string {
+	return "MissingAttributeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *MissingAttributeError) Message() string {
+	return "missing required attributes [" + strings.Join(e.Attributes, ", ") +
+		"] for Go value of type " + e.Type.String()
+}
+
+// A NullIntoNonPointerError is an error type representing a NULL
+// AttributeValue unmarshaled into a Go value which cannot be nil, returned
+// when the Decoder's StrictNulls option is enabled.
+type NullIntoNonPointerError struct {
+	emptyOrigError
+
+	// Document path to the NULL attribute, e.g. "Orders[2].Total". Empty
+	// if the output value itself is NULL.
+	Path string
+
+	// Go value type the NULL was unmarshaled into.
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *NullIntoNonPointerError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *NullIntoNonPointerError) Code() string {
+	return "NullIntoNonPointerError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *NullIntoNonPointerError) Message() string {
+	msg := "cannot unmarshal NULL into Go value of type " + e.Type.String()
+	if len(e.Path) != 0 {
+		msg += ", " + e.Path
+	}
+	return msg
+}
+
+// A PrecisionLossError is an error type representing a number which
+// cannot be unmarshaled into a Go integer type without truncating its
+// fractional part, returned when the Decoder's StrictNumbers option is
+// enabled.
+type PrecisionLossError struct {
+	emptyOrigError
+
+	// Document path to the number attribute, e.g. "Orders[2].Quantity".
+	// Empty if the output value itself is the number.
+	Path string
+
+	// The number's value, as it was read from the AttributeValue.
+	Value string
+
+	// Go value type the number was unmarshaled into.
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *PrecisionLossError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *PrecisionLossError) Code() string {
+	return "PrecisionLossError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *PrecisionLossError) Message() string {
+	msg := "cannot unmarshal number " + e.Value + " into Go value of type " +
+		e.Type.String() + " without loss of precision"
+	if len(e.Path) != 0 {
+		msg += ", " + e.Path
+	}
+	return msg
+}
+
+// An UnmarshalTypeError is an error type representing a error
+// unmarshaling the AttributeValue's element to a Go value type.
+// Includes details about the AttributeValue type and Go value type.
+type UnmarshalTypeError struct {
+	emptyOrigError
+	Value string
+	Type  reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnmarshalTypeError) Code() string {
+	return "UnmarshalTypeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnmarshalTypeError) Message() string {
+	return "cannot unmarshal " + e.Value + " into Go value of type " + e.Type.String()
+}
+
+// An InvalidUnmarshalError is an error type representing an invalid type
+// encountered while unmarshaling a AttributeValue to a Go value type.
+type InvalidUnmarshalError struct {
+	emptyOrigError
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidUnmarshalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidUnmarshalError) Code() string {
+	return "InvalidUnmarshalError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidUnmarshalError) Message() string {
+	if e.Type == nil {
+		return "cannot unmarshal to nil value"
+	}
+	if e.Type.Kind() != reflect.Ptr {
+		return "cannot unmarshal to non-pointer value, got " + e.Type.String()
+	}
+	return "cannot unmarshal to nil value, " + e.Type.String()
+}
+
+type emptyOrigError struct{}
+
+func (e emptyOrigError) OrigErr() error {
+	return nil
+}
@@ -0,0 +1,1398 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestUnmarshalErrorTypes(t *testing.T) {
+	var _ awserr.Error = (*UnmarshalTypeError)(nil)
+	var _ awserr.Error = (*InvalidUnmarshalError)(nil)
+	var _ awserr.Error = (*MissingAttributeError)(nil)
+}
+
+func TestUnmarshalShared(t *testing.T) {
+	for i, c := range sharedTestCases {
+		err := Unmarshal(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	cases := []struct {
+		in               *dynamodb.AttributeValue
+		actual, expected interface{}
+		err              error
+	}{
+		//------------
+		// Sets
+		//------------
+		{
+			in: &dynamodb.AttributeValue{BS: [][]byte{
+				{48, 49}, {50, 51},
+			}},
+			actual:   &[][]byte{},
+			expected: [][]byte{{48, 49}, {50, 51}},
+		},
+		{
+			in: &dynamodb.AttributeValue{NS: []*string{
+				aws.String("123"), aws.String("321"),
+			}},
+			actual:   &[]int{},
+			expected: []int{123, 321},
+		},
+		{
+			in: &dynamodb.AttributeValue{NS: []*string{
+				aws.String("123"), aws.String("321"),
+			}},
+			actual:   &[]interface{}{},
+			expected: []interface{}{123., 321.},
+		},
+		{
+			in: &dynamodb.AttributeValue{SS: []*string{
+				aws.String("abc"), aws.String("123"),
+			}},
+			actual:   &[]string{},
+			expected: &[]string{"abc", "123"},
+		},
+		{
+			in: &dynamodb.AttributeValue{SS: []*string{
+				aws.String("abc"), aws.String("123"),
+			}},
+			actual:   &[]*string{},
+			expected: &[]*string{aws.String("abc"), aws.String("123")},
+		},
+		//------------
+		// Interfaces
+		//------------
+		{
+			in: &dynamodb.AttributeValue{B: []byte{48, 49}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: []byte{48, 49},
+		},
+		{
+			in: &dynamodb.AttributeValue{BS: [][]byte{
+				{48, 49}, {50, 51},
+			}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: [][]byte{{48, 49}, {50, 51}},
+		},
+		{
+			in: &dynamodb.AttributeValue{BOOL: aws.Bool(true)},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: bool(true),
+		},
+		{
+			in: &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{
+				{S: aws.String("abc")}, {S: aws.String("123")},
+			}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: []interface{}{"abc", "123"},
+		},
+		{
+			in: &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+				"123": {S: aws.String("abc")},
+				"abc": {S: aws.String("123")},
+			}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: map[string]interface{}{"123": "abc", "abc": "123"},
+		},
+		{
+			in: &dynamodb.AttributeValue{N: aws.String("123")},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: float64(123),
+		},
+		{
+			in: &dynamodb.AttributeValue{NS: []*string{
+				aws.String("123"), aws.String("321"),
+			}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: []float64{123., 321.},
+		},
+		{
+			in: &dynamodb.AttributeValue{S: aws.String("123")},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: "123",
+		},
+		{
+			in: &dynamodb.AttributeValue{SS: []*string{
+				aws.String("123"), aws.String("321"),
+			}},
+			actual: func() interface{} {
+				var v interface{}
+				return &v
+			}(),
+			expected: []string{"123", "321"},
+		},
+		{
+			in: &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+				"abc": {S: aws.String("123")},
+				"Cba": {S: aws.String("321")},
+			}},
+			actual:   &struct{ Abc, Cba string }{},
+			expected: struct{ Abc, Cba string }{Abc: "123", Cba: "321"},
+		},
+		{
+			in:     &dynamodb.AttributeValue{N: aws.String("512")},
+			actual: new(uint8),
+			err: &UnmarshalTypeError{
+				Value: fmt.Sprintf("number overflow, 512"),
+				Type:  reflect.TypeOf(uint8(0)),
+			},
+		},
+		{
+			in:     &dynamodb.AttributeValue{S: aws.String("abc")},
+			actual: new(int),
+			err: &UnmarshalTypeError{
+				Value: "string",
+				Type:  reflect.TypeOf(int(0)),
+			},
+		},
+	}
+
+	for i, c := range cases {
+		err := Unmarshal(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestInterfaceInput(t *testing.T) {
+	var v interface{}
+	expected := []interface{}{"abc", "123"}
+	err := Unmarshal(&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{
+		{S: aws.String("abc")}, {S: aws.String("123")},
+	}}, &v)
+	assertConvertTest(t, 0, v, expected, err, nil)
+}
+
+func TestUnmarshalError(t *testing.T) {
+	cases := []struct {
+		in               *dynamodb.AttributeValue
+		actual, expected interface{}
+		err              error
+	}{
+		{
+			in:       &dynamodb.AttributeValue{},
+			actual:   int(0),
+			expected: nil,
+			err:      &InvalidUnmarshalError{Type: reflect.TypeOf(int(0))},
+		},
+	}
+
+	for i, c := range cases {
+		err := Unmarshal(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshalListShared(t *testing.T) {
+	for i, c := range sharedListTestCases {
+		err := UnmarshalList(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshalListError(t *testing.T) {
+	cases := []struct {
+		in               []*dynamodb.AttributeValue
+		actual, expected interface{}
+		err              error
+	}{
+		{
+			in:       []*dynamodb.AttributeValue{},
+			actual:   []interface{}{},
+			expected: nil,
+			err:      &InvalidUnmarshalError{Type: reflect.TypeOf([]interface{}{})},
+		},
+	}
+
+	for i, c := range cases {
+		err := UnmarshalList(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshalMapShared(t *testing.T) {
+	for i, c := range sharedMapTestCases {
+		err := UnmarshalMap(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshalMapError(t *testing.T) {
+	cases := []struct {
+		in               map[string]*dynamodb.AttributeValue
+		actual, expected interface{}
+		err              error
+	}{
+		{
+			in:       map[string]*dynamodb.AttributeValue{},
+			actual:   map[string]interface{}{},
+			expected: nil,
+			err:      &InvalidUnmarshalError{Type: reflect.TypeOf(map[string]interface{}{})},
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"BOOL": {BOOL: aws.Bool(true)},
+			},
+			actual:   &map[int]interface{}{},
+			expected: nil,
+			err:      &UnmarshalTypeError{Value: "map string key", Type: reflect.TypeOf(int(0))},
+		},
+	}
+
+	for i, c := range cases {
+		err := UnmarshalMap(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+func TestUnmarshalListOfMaps(t *testing.T) {
+	type testItem struct {
+		Value  string
+		Value2 int
+	}
+
+	cases := []struct {
+		in               []map[string]*dynamodb.AttributeValue
+		actual, expected interface{}
+		err              error
+	}{
+		{ // Simple map conversion.
+			in: []map[string]*dynamodb.AttributeValue{
+				{
+					"Value": &dynamodb.AttributeValue{
+						BOOL: aws.Bool(true),
+					},
+				},
+			},
+			actual: &[]map[string]interface{}{},
+			expected: []map[string]interface{}{
+				{
+					"Value": true,
+				},
+			},
+		},
+		{ // attribute to struct.
+			in: []map[string]*dynamodb.AttributeValue{
+				{
+					"Value": &dynamodb.AttributeValue{
+						S: aws.String("abc"),
+					},
+					"Value2": &dynamodb.AttributeValue{
+						N: aws.String("123"),
+					},
+				},
+			},
+			actual: &[]testItem{},
+			expected: []testItem{
+				{
+					Value:  "abc",
+					Value2: 123,
+				},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		err := UnmarshalListOfMaps(c.in, c.actual)
+		assertConvertTest(t, i, c.actual, c.expected, err, c.err)
+	}
+}
+
+type unmarshalUnmarshaler struct {
+	Value  string
+	Value2 int
+	Value3 bool
+}
+
+func (u *unmarshalUnmarshaler) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if av.M == nil {
+		return fmt.Errorf("expected AttributeValue to be map")
+	}
+
+	if v, ok := av.M["abc"]; !ok {
+		return fmt.Errorf("expected `abc` map key")
+	} else if v.S == nil {
+		return fmt.Errorf("expected `abc` map value string")
+	} else {
+		u.Value = *v.S
+	}
+
+	if v, ok := av.M["def"]; !ok {
+		return fmt.Errorf("expected `def` map key")
+	} else if v.N == nil {
+		return fmt.Errorf("expected `def` map value number")
+	} else {
+		n, err := strconv.ParseInt(*v.N, 10, 64)
+		if err != nil {
+			return err
+		}
+		u.Value2 = int(n)
+	}
+
+	if v, ok := av.M["ghi"]; !ok {
+		return fmt.Errorf("expected `ghi` map key")
+	} else if v.BOOL == nil {
+		return fmt.Errorf("expected `ghi` map value number")
+	} else {
+		u.Value3 = *v.BOOL
+	}
+
+	return nil
+}
+
+func TestUnmarshalUnmashaler(t *testing.T) {
+	u := &unmarshalUnmarshaler{}
+	av := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"abc": {S: aws.String("value")},
+			"def": {N: aws.String("123")},
+			"ghi": {BOOL: aws.Bool(true)},
+		},
+	}
+
+	err := Unmarshal(av, u)
+	if err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+
+	if e, a := "value", u.Value; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 123, u.Value2; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := true, u.Value3; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestDecodeNumberSetIntoInts(t *testing.T) {
+	var v []int
+	av := &dynamodb.AttributeValue{NS: []*string{aws.String("1"), aws.String("2")}}
+	if err := Unmarshal(av, &v); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []int{1, 2}, v; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestDecodeEmbeddedPointerStruct(t *testing.T) {
+	type B struct {
+		Bint int
+	}
+	type C struct {
+		Cint int
+	}
+	type A struct {
+		Aint int
+		*B
+		*C
+	}
+	av := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Aint": {N: aws.String("321")},
+			"Bint": {N: aws.String("123")},
+		},
+	}
+	decoder := NewDecoder()
+	a := A{}
+	err := decoder.Decode(av, &a)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 321, a.Aint; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	// Embedded pointer struct can be created automatically.
+	if e, a := 123, a.Bint; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	// But not for absent fields.
+	if a.C != nil {
+		t.Errorf("expect nil, got %v", a.C)
+	}
+}
+
+func TestDecodeRequiredAttributes(t *testing.T) {
+	type Record struct {
+		ID    string `dynamodbav:"id,required"`
+		Email string `dynamodbav:"email,required"`
+		Name  string `dynamodbav:"name,required"`
+		Notes string `dynamodbav:"notes"`
+	}
+
+	av := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("abc")},
+		},
+	}
+
+	var r Record
+	err := Unmarshal(av, &r)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	mErr, ok := err.(*MissingAttributeError)
+	if !ok {
+		t.Fatalf("expect *MissingAttributeError, got %T", err)
+	}
+	if e, a := []string{"email", "name"}, mErr.Attributes; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := reflect.TypeOf(r), mErr.Type; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "MissingAttributeError: missing required attributes [email, name] for Go value of type dynamodbattribute.Record", err.Error(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+	// Attributes present are still decoded.
+	if e, a := "abc", r.ID; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av.M["email"] = &dynamodb.AttributeValue{S: aws.String("a@example.com")}
+	av.M["name"] = &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	if err := Unmarshal(av, &r); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
+
+type testValidatedAddress struct {
+	Zip string
+}
+
+func (a testValidatedAddress) Validate() error {
+	if len(a.Zip) != 5 {
+		return fmt.Errorf("invalid zip %q", a.Zip)
+	}
+	return nil
+}
+
+type testValidatedOrder struct {
+	ID        string
+	Addresses []testValidatedAddress
+	Quantity  int
+}
+
+func (o *testValidatedOrder) Validate() error {
+	if o.Quantity < 1 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	return nil
+}
+
+func TestDecodeValidator(t *testing.T) {
+	cases := []struct {
+		in         map[string]*dynamodb.AttributeValue
+		expectPath string
+		expectMsg  string
+	}{
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"ID":       {S: aws.String("abc")},
+				"Quantity": {N: aws.String("1")},
+				"Addresses": {L: []*dynamodb.AttributeValue{
+					{M: map[string]*dynamodb.AttributeValue{"Zip": {S: aws.String("12345")}}},
+				}},
+			},
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"ID":       {S: aws.String("abc")},
+				"Quantity": {N: aws.String("0")},
+			},
+			expectMsg: "ValidationError: validation failed, quantity must be positive",
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"ID":       {S: aws.String("abc")},
+				"Quantity": {N: aws.String("1")},
+				"Addresses": {L: []*dynamodb.AttributeValue{
+					{M: map[string]*dynamodb.AttributeValue{"Zip": {S: aws.String("12345")}}},
+					{M: map[string]*dynamodb.AttributeValue{"Zip": {S: aws.String("123")}}},
+				}},
+			},
+			expectPath: "Addresses[1]",
+			expectMsg:  `ValidationError: validation failed for Addresses[1], invalid zip "123"`,
+		},
+	}
+
+	for i, c := range cases {
+		var o testValidatedOrder
+		err := UnmarshalMap(c.in, &o)
+		if len(c.expectMsg) == 0 {
+			if err != nil {
+				t.Errorf("case %d, expect no error, got %v", i, err)
+			}
+			continue
+		}
+		vErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("case %d, expect *ValidationError, got %T, %v", i, err, err)
+		}
+		if e, a := c.expectPath, vErr.Path; e != a {
+			t.Errorf("case %d, expect path %q, got %q", i, e, a)
+		}
+		if e, a := c.expectMsg, vErr.Error(); e != a {
+			t.Errorf("case %d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestDecodeValidatorNestedMapPath(t *testing.T) {
+	var v map[string]map[string]testValidatedAddress
+	err := UnmarshalMap(map[string]*dynamodb.AttributeValue{
+		"home": {M: map[string]*dynamodb.AttributeValue{
+			"primary": {M: map[string]*dynamodb.AttributeValue{"Zip": {S: aws.String("1")}}},
+		}},
+	}, &v)
+	vErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expect *ValidationError, got %T, %v", err, err)
+	}
+	if e, a := "home.primary", vErr.Path; e != a {
+		t.Errorf("expect path %q, got %q", e, a)
+	}
+}
+
+func TestUnmarshalWithOptions(t *testing.T) {
+	type testRecord struct {
+		Value string `json:"value"`
+	}
+	disableJSON := func(d *Decoder) {
+		d.SupportJSONTags = false
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"value": {S: aws.String("abc")},
+		"Value": {S: aws.String("123")},
+	}
+
+	var r testRecord
+	if err := UnmarshalMapWithOptions(item, &r, disableJSON); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "123", r.Value; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var rs []testRecord
+	err := UnmarshalListOfMapsWithOptions([]map[string]*dynamodb.AttributeValue{item}, &rs, disableJSON)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "123", rs[0].Value; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	r = testRecord{}
+	if err := UnmarshalWithOptions(&dynamodb.AttributeValue{M: item}, &r); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "abc", r.Value; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUnmarshalAlias(t *testing.T) {
+	type testRecord struct {
+		Name  string `dynamodbav:"name,alias=title"`
+		Count int    `dynamodbav:",alias=total"`
+	}
+
+	cases := []struct {
+		in     map[string]*dynamodb.AttributeValue
+		expect testRecord
+	}{
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"title": {S: aws.String("old")},
+				"total": {N: aws.String("1")},
+			},
+			expect: testRecord{Name: "old", Count: 1},
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"name":  {S: aws.String("new")},
+				"title": {S: aws.String("old")},
+				"Count": {N: aws.String("2")},
+			},
+			expect: testRecord{Name: "new", Count: 2},
+		},
+	}
+
+	for i, c := range cases {
+		var actual testRecord
+		if err := UnmarshalMap(c.in, &actual); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, actual; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	av, err := MarshalMap(testRecord{Name: "new", Count: 3})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := av["title"]; ok {
+		t.Errorf("expect alias not to be marshaled, got %v", av)
+	}
+	if e, a := "new", aws.StringValue(av["name"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUnmarshalMultipleAliases(t *testing.T) {
+	type testRecord struct {
+		Email string `dynamodbav:"email,alias=email_address,alias=mail"`
+	}
+
+	cases := []struct {
+		in     map[string]*dynamodb.AttributeValue
+		expect string
+	}{
+		{map[string]*dynamodb.AttributeValue{"mail": {S: aws.String("c")}}, "c"},
+		{map[string]*dynamodb.AttributeValue{
+			"email_address": {S: aws.String("b")},
+			"mail":          {S: aws.String("c")},
+		}, "b"},
+		{map[string]*dynamodb.AttributeValue{
+			"email":         {S: aws.String("a")},
+			"email_address": {S: aws.String("b")},
+		}, "a"},
+	}
+
+	for i, c := range cases {
+		var actual testRecord
+		if err := UnmarshalMap(c.in, &actual); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, actual.Email; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	av, err := MarshalMap(testRecord{Email: "a"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(av); e != a {
+		t.Errorf("expect only the canonical name to be marshaled, got %v", av)
+	}
+}
+
+func TestUnmarshalReadFrom(t *testing.T) {
+	type testRecord struct {
+		Name string `dynamodbav:"newName,readfrom=oldName,alias=legacyName"`
+	}
+
+	cases := []struct {
+		in     map[string]*dynamodb.AttributeValue
+		expect string
+	}{
+		{map[string]*dynamodb.AttributeValue{"oldName": {S: aws.String("a")}}, "a"},
+		{map[string]*dynamodb.AttributeValue{"newName": {S: aws.String("b")}}, "b"},
+		{map[string]*dynamodb.AttributeValue{
+			"oldName": {S: aws.String("a")},
+			"newName": {S: aws.String("b")},
+		}, "b"},
+		{map[string]*dynamodb.AttributeValue{
+			"oldName":    {S: aws.String("a")},
+			"legacyName": {S: aws.String("c")},
+		}, "a"},
+		{map[string]*dynamodb.AttributeValue{"legacyName": {S: aws.String("c")}}, "c"},
+	}
+
+	for i, c := range cases {
+		var actual testRecord
+		if err := UnmarshalMap(c.in, &actual); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, actual.Name; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	av, err := MarshalMap(testRecord{Name: "a"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{"newName": {S: aws.String("a")}}
+	if e, a := expect, av; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUnmarshalMapSet(t *testing.T) {
+	in := map[string]*dynamodb.AttributeValue{
+		"Strings": {SS: []*string{aws.String("a"), aws.String("b")}},
+		"Flags":   {SS: []*string{aws.String("on")}},
+		"Numbers": {NS: []*string{aws.String("10"), aws.String("2")}},
+	}
+
+	var actual testMapSetStruct
+	if err := UnmarshalMap(in, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := testMapSetStruct{
+		Strings: map[string]struct{}{"a": {}, "b": {}},
+		Flags:   map[string]bool{"on": true},
+		Numbers: map[int]struct{}{10: {}, 2: {}},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestUnmarshalMapSetInvalid(t *testing.T) {
+	var actual map[string]int
+	err := Unmarshal(&dynamodb.AttributeValue{SS: []*string{aws.String("a")}}, &actual)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %T", err)
+	}
+}
+
+type testConcurrentItem struct {
+	ID    int
+	Name  string
+	Tags  []string
+	Attrs map[string]int
+}
+
+func testConcurrentItems(n int) []map[string]*dynamodb.AttributeValue {
+	items := make([]map[string]*dynamodb.AttributeValue, n)
+	for i := range items {
+		id := strconv.Itoa(i)
+		items[i] = map[string]*dynamodb.AttributeValue{
+			"ID":   {N: aws.String(id)},
+			"Name": {S: aws.String("name" + id)},
+			"Tags": {L: []*dynamodb.AttributeValue{
+				{S: aws.String("a" + id)}, {S: aws.String("b" + id)},
+			}},
+			"Attrs": {M: map[string]*dynamodb.AttributeValue{
+				"x": {N: aws.String(id)},
+			}},
+		}
+	}
+	return items
+}
+
+func TestUnmarshalListOfMapsConcurrency(t *testing.T) {
+	items := testConcurrentItems(1000)
+
+	var expect []testConcurrentItem
+	if err := UnmarshalListOfMaps(items, &expect); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	for _, n := range []int{2, 3, 8, 2000} {
+		var actual []testConcurrentItem
+		err := UnmarshalListOfMapsWithOptions(items, &actual, func(d *Decoder) {
+			d.Concurrency = n
+		})
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", n, err)
+		}
+		if !reflect.DeepEqual(expect, actual) {
+			t.Errorf("%d, expect concurrent decode to match serial decode", n)
+		}
+	}
+}
+
+func TestUnmarshalListOfMapsConcurrencyError(t *testing.T) {
+	items := testConcurrentItems(100)
+	items[30]["ID"] = &dynamodb.AttributeValue{S: aws.String("abc")}
+	items[70]["ID"] = &dynamodb.AttributeValue{BOOL: aws.Bool(true)}
+
+	var actual []testConcurrentItem
+	expect := UnmarshalListOfMaps(items, &actual)
+	if expect == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	for i := 0; i < 10; i++ {
+		err := UnmarshalListOfMapsWithOptions(items, &actual, func(d *Decoder) {
+			d.Concurrency = 4
+		})
+		if !reflect.DeepEqual(expect, err) {
+			t.Errorf("%d, expect %v, got %v", i, expect, err)
+		}
+	}
+}
+
+func TestUnmarshalAppendSlices(t *testing.T) {
+	items := testConcurrentItems(10)
+
+	var expect []testConcurrentItem
+	if err := UnmarshalListOfMaps(items, &expect); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Accumulate pages of items, with and without spare capacity, and
+	// decoded serially and concurrently.
+	for _, concurrency := range []int{0, 4} {
+		actual := make([]testConcurrentItem, 0, 5)
+		for _, page := range [][]map[string]*dynamodb.AttributeValue{items[:3], items[3:4], items[4:]} {
+			err := UnmarshalListOfMapsWithOptions(page, &actual, func(d *Decoder) {
+				d.AppendSlices = true
+				d.Concurrency = concurrency
+			})
+			if err != nil {
+				t.Fatalf("%d, expect no error, got %v", concurrency, err)
+			}
+		}
+		if !reflect.DeepEqual(expect, actual) {
+			t.Errorf("%d, expect appended pages to match, got %v", concurrency, actual)
+		}
+	}
+
+	// Spare capacity with stale elements is zeroed before decoding into.
+	backing := []testConcurrentItem{{ID: 1}, {ID: 2, Name: "stale", Tags: []string{"x"}}}
+	actual := backing[:1]
+	err := UnmarshalListOfMapsWithOptions([]map[string]*dynamodb.AttributeValue{
+		{"ID": {N: aws.String("3")}},
+	}, &actual, func(d *Decoder) {
+		d.AppendSlices = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []testConcurrentItem{{ID: 1}, {ID: 3}}, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Sets are appended, and arrays are overwritten.
+	type record struct {
+		Set   []string `dynamodbav:",stringset"`
+		Array [2]int
+	}
+	r := record{Set: []string{"a"}, Array: [2]int{1, 2}}
+	err = UnmarshalMapWithOptions(map[string]*dynamodb.AttributeValue{
+		"Set":   {SS: []*string{aws.String("b")}},
+		"Array": {L: []*dynamodb.AttributeValue{{N: aws.String("3")}}},
+	}, &r, func(d *Decoder) {
+		d.AppendSlices = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := (record{Set: []string{"a", "b"}, Array: [2]int{3, 0}}), r; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Without the option the slice is replaced.
+	if err := UnmarshalListOfMaps(items[:2], &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect[:2], actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func benchmarkUnmarshalListOfMaps(b *testing.B, concurrency int) {
+	items := testConcurrentItems(5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var out []testConcurrentItem
+		err := UnmarshalListOfMapsWithOptions(items, &out, func(d *Decoder) {
+			d.Concurrency = concurrency
+		})
+		if err != nil {
+			b.Fatalf("expect no error, got %v", err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalListOfMaps_Serial(b *testing.B) {
+	benchmarkUnmarshalListOfMaps(b, 0)
+}
+
+func BenchmarkUnmarshalListOfMaps_Concurrency2(b *testing.B) {
+	benchmarkUnmarshalListOfMaps(b, 2)
+}
+
+func BenchmarkUnmarshalListOfMaps_Concurrency4(b *testing.B) {
+	benchmarkUnmarshalListOfMaps(b, 4)
+}
+
+func BenchmarkUnmarshalListOfMaps_Concurrency8(b *testing.B) {
+	benchmarkUnmarshalListOfMaps(b, 8)
+}
+
+type testNullableString struct {
+	Value string
+	Null  bool
+}
+
+func (s *testNullableString) SetNull() {
+	*s = testNullableString{Null: true}
+}
+
+func TestUnmarshalNullableSetter(t *testing.T) {
+	type testRecord struct {
+		Name    testNullableString
+		Ptr     *testNullableString
+		Missing testNullableString
+	}
+
+	in := map[string]*dynamodb.AttributeValue{
+		"Name": {NULL: aws.Bool(true)},
+		"Ptr":  {NULL: aws.Bool(true)},
+	}
+
+	actual := testRecord{
+		Name:    testNullableString{Value: "abc"},
+		Ptr:     &testNullableString{Value: "abc"},
+		Missing: testNullableString{Value: "abc"},
+	}
+	if err := UnmarshalMap(in, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := testRecord{
+		Name:    testNullableString{Null: true},
+		Missing: testNullableString{Value: "abc"},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestUnmarshalNumericStringSet(t *testing.T) {
+	type testRecord struct {
+		Ints   []int              `dynamodbav:",string"`
+		Floats []float64          `dynamodbav:",string"`
+		Array  [2]uint8           `dynamodbav:",string"`
+		Set    map[int64]struct{} `dynamodbav:",string"`
+	}
+
+	set := &dynamodb.AttributeValue{SS: []*string{aws.String("1"), aws.String("2")}}
+	in := map[string]*dynamodb.AttributeValue{
+		"Ints":   set,
+		"Floats": {SS: []*string{aws.String("1.5"), aws.String("-2")}},
+		"Array":  set,
+		"Set":    set,
+	}
+
+	var actual testRecord
+	if err := UnmarshalMap(in, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := testRecord{
+		Ints:   []int{1, 2},
+		Floats: []float64{1.5, -2},
+		Array:  [2]uint8{1, 2},
+		Set:    map[int64]struct{}{1: {}, 2: {}},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+
+	in = map[string]*dynamodb.AttributeValue{
+		"Ints": {SS: []*string{aws.String("1"), aws.String("abc")}},
+	}
+	if err := UnmarshalMap(in, &actual); err == nil {
+		t.Errorf("expect error for non-numeric member")
+	}
+
+	// Without the tag option the members are not coerced.
+	var untagged struct{ Ints []int }
+	if err := UnmarshalMap(map[string]*dynamodb.AttributeValue{"Ints": set}, &untagged); err == nil {
+		t.Errorf("expect error without string tag option")
+	}
+}
+
+func TestUnmarshalStrictNulls(t *testing.T) {
+	type nested struct {
+		Total int
+	}
+	type testRecord struct {
+		Name     testNullableString
+		Ptr      *int
+		List     []int
+		Map      map[string]int
+		Iface    interface{}
+		Count    int
+		Orders   []nested
+		Optional string
+	}
+
+	null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	cases := []struct {
+		in     map[string]*dynamodb.AttributeValue
+		expect string
+	}{
+		{
+			in: map[string]*dynamodb.AttributeValue{
+				"Name": null, "Ptr": null, "List": null, "Map": null, "Iface": null,
+			},
+		},
+		{
+			in:     map[string]*dynamodb.AttributeValue{"Count": null},
+			expect: "NullIntoNonPointerError: cannot unmarshal NULL into Go value of type int, Count",
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{"Orders": {L: []*dynamodb.AttributeValue{
+				{M: map[string]*dynamodb.AttributeValue{"Total": {N: aws.String("1")}}},
+				{M: map[string]*dynamodb.AttributeValue{"Total": null}},
+			}}},
+			expect: "NullIntoNonPointerError: cannot unmarshal NULL into Go value of type int, Orders[1].Total",
+		},
+		{
+			in: map[string]*dynamodb.AttributeValue{"Map": {M: map[string]*dynamodb.AttributeValue{
+				"a": null,
+			}}},
+			expect: "NullIntoNonPointerError: cannot unmarshal NULL into Go value of type int, Map.a",
+		},
+	}
+
+	for i, c := range cases {
+		var actual testRecord
+		err := UnmarshalMapWithOptions(c.in, &actual, func(d *Decoder) {
+			d.StrictNulls = true
+		})
+		if e, a := c.expect, errString(err); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+
+		// The zero value is set by default.
+		if err := UnmarshalMap(c.in, &actual); err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+	}
+
+	var s string
+	err := UnmarshalWithOptions(null, &s, func(d *Decoder) {
+		d.StrictNulls = true
+	})
+	if e, a := "NullIntoNonPointerError: cannot unmarshal NULL into Go value of type string", errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestUnmarshalStrictNumbers(t *testing.T) {
+	type testRecord struct {
+		Count int
+		Size  uint8
+		Price float64
+	}
+
+	cases := []struct {
+		in     map[string]*dynamodb.AttributeValue
+		expect testRecord
+		err    string
+	}{
+		{
+			in:     map[string]*dynamodb.AttributeValue{"Count": {N: aws.String("2.0")}, "Size": {N: aws.String("1e2")}},
+			expect: testRecord{Count: 2, Size: 100},
+		},
+		{
+			in:     map[string]*dynamodb.AttributeValue{"Price": {N: aws.String("1.5")}},
+			expect: testRecord{Price: 1.5},
+		},
+		{
+			in:  map[string]*dynamodb.AttributeValue{"Count": {N: aws.String("1.5")}},
+			err: "PrecisionLossError: cannot unmarshal number 1.5 into Go value of type int without loss of precision, Count",
+		},
+		{
+			in:  map[string]*dynamodb.AttributeValue{"Size": {N: aws.String("-1E-2")}},
+			err: "PrecisionLossError: cannot unmarshal number -1E-2 into Go value of type uint8 without loss of precision, Size",
+		},
+		{
+			in:  map[string]*dynamodb.AttributeValue{"Size": {N: aws.String("3e2")}},
+			err: "UnmarshalTypeError: cannot unmarshal number overflow, 3e2",
+		},
+		{
+			in:  map[string]*dynamodb.AttributeValue{"Count": {N: aws.String("1e19")}},
+			err: "UnmarshalTypeError: cannot unmarshal number overflow, 1e19",
+		},
+		{
+			in:  map[string]*dynamodb.AttributeValue{"Size": {N: aws.String("-1.0")}},
+			err: "UnmarshalTypeError: cannot unmarshal number overflow, -1.0",
+		},
+	}
+
+	for i, c := range cases {
+		var actual testRecord
+		err := UnmarshalMapWithOptions(c.in, &actual, func(d *Decoder) {
+			d.StrictNumbers = true
+		})
+		if e, a := c.err, errString(err); !strings.HasPrefix(a, e) || (len(e) == 0) != (len(a) == 0) {
+			t.Errorf("%d, expect %q error, got %q", i, e, a)
+		}
+		if err == nil && !reflect.DeepEqual(c.expect, actual) {
+			t.Errorf("%d, expect %v, got %v", i, c.expect, actual)
+		}
+	}
+
+	// Values with fractional parts are a strconv error by default.
+	var actual testRecord
+	err := UnmarshalMap(map[string]*dynamodb.AttributeValue{"Count": {N: aws.String("1.5")}}, &actual)
+	if _, ok := err.(*strconv.NumError); !ok {
+		t.Errorf("expect strconv.NumError, got %T", err)
+	}
+
+	var n int
+	err = UnmarshalWithOptions(&dynamodb.AttributeValue{N: aws.String("0.25")}, &n, func(d *Decoder) {
+		d.StrictNumbers = true
+	})
+	if e, ok := err.(*PrecisionLossError); !ok || e.Value != "0.25" || len(e.Path) != 0 {
+		t.Errorf("expect PrecisionLossError, got %v", err)
+	}
+}
+
+func TestDecoderDecodeFields(t *testing.T) {
+	type nested struct {
+		A, B string
+	}
+	type item struct {
+		ID        string
+		Status    string `dynamodbav:"status"`
+		Count     int    `dynamodbav:",required"`
+		Nested    nested
+		Untouched string
+	}
+
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"ID":     {S: aws.String("abc")},
+		"Status": {S: aws.String("NEW")},
+		"Count":  {S: aws.String("not a number")},
+		"Nested": {M: map[string]*dynamodb.AttributeValue{
+			"A": {S: aws.String("a")},
+			"B": {S: aws.String("b")},
+		}},
+		"Untouched": {S: aws.String("new")},
+	}}
+
+	actual := item{Untouched: "old"}
+	if err := NewDecoder().DecodeFields(av, &actual, "ID", "status", "Nested"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := item{
+		ID:        "abc",
+		Status:    "NEW",
+		Nested:    nested{A: "a", B: "b"},
+		Untouched: "old",
+	}
+	if e, a := expect, actual; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var m map[string]interface{}
+	if err := NewDecoder().DecodeFields(av, &m, "ID"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := map[string]interface{}{"ID": "abc"}, m; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+type testTextKey struct {
+	Region, ID string
+}
+
+func (k testTextKey) MarshalText() ([]byte, error) {
+	return []byte(k.Region + "/" + k.ID), nil
+}
+
+func (k *testTextKey) UnmarshalText(b []byte) error {
+	parts := strings.SplitN(string(b), "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid key %q", b)
+	}
+	k.Region, k.ID = parts[0], parts[1]
+	return nil
+}
+
+func TestTextMarshalerMapKeys(t *testing.T) {
+	in := map[testTextKey]int{
+		{Region: "us-west-2", ID: "a"}: 1,
+		{Region: "eu-west-1", ID: "b"}: 2,
+	}
+
+	av, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"us-west-2/a": {N: aws.String("1")},
+		"eu-west-1/b": {N: aws.String("2")},
+	}}
+	if e, a := expect, av; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var actual map[testTextKey]int
+	if err := Unmarshal(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av.M["invalid"] = &dynamodb.AttributeValue{N: aws.String("3")}
+	if err := Unmarshal(av, &actual); err == nil {
+		t.Errorf("expect error")
+	}
+}
+
+func TestDecodeZeroCopyBinary(t *testing.T) {
+	type blob struct {
+		Data  []byte
+		Set   [][]byte
+		Any   interface{}
+		Fixed [2]byte
+	}
+
+	data := []byte{1, 2}
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"Data":  {B: data},
+		"Set":   {BS: [][]byte{data}},
+		"Any":   {B: data},
+		"Fixed": {B: data},
+	}}
+
+	for _, zeroCopy := range []bool{false, true} {
+		var out blob
+		err := UnmarshalWithOptions(av, &out, func(d *Decoder) {
+			d.ZeroCopyBinary = zeroCopy
+		})
+		if err != nil {
+			t.Fatalf("%t, expect no error, got %v", zeroCopy, err)
+		}
+
+		shared := map[string]bool{
+			"Data": &out.Data[0] == &data[0],
+			"Set":  &out.Set[0][0] == &data[0],
+			"Any":  &out.Any.([]byte)[0] == &data[0],
+		}
+		for name, isShared := range shared {
+			if e, a := zeroCopy, isShared; e != a {
+				t.Errorf("%t, expect %s shared %t, got %t", zeroCopy, name, e, a)
+			}
+		}
+		if e, a := [2]byte{1, 2}, out.Fixed; e != a {
+			t.Errorf("%t, expect %v, got %v", zeroCopy, e, a)
+		}
+	}
+}
+
+func benchmarkUnmarshalBinary(b *testing.B, zeroCopy bool) {
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"Data": {B: make([]byte, 64*1024)},
+	}}
+	d := NewDecoder(func(d *Decoder) {
+		d.ZeroCopyBinary = zeroCopy
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var out struct{ Data []byte }
+		if err := d.Decode(av, &out); err != nil {
+			b.Fatalf("expect no error, got %v", err)
+		}
+	}
+}
+
+func TestUnmarshalStringMap(t *testing.T) {
+	in := map[string]*dynamodb.AttributeValue{
+		"region": {S: aws.String("us-west-2")},
+		"empty":  {S: aws.String("")},
+	}
+	actual, err := UnmarshalStringMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := map[string]string{"region": "us-west-2", "empty": ""}, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	in["count"] = &dynamodb.AttributeValue{N: aws.String("1")}
+	actual, err = UnmarshalStringMap(in)
+	if e, a := "UnmarshalTypeError: cannot unmarshal N attribute count into Go value of type map[string]string", errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+	if actual != nil {
+		t.Errorf("expect nil map, got %v", actual)
+	}
+
+	actual, err = UnmarshalStringMap(nil)
+	if err != nil || actual == nil || len(actual) != 0 {
+		t.Errorf("expect empty map, got %v, %v", actual, err)
+	}
+}
+
+var benchmarkStringMapItem = map[string]*dynamodb.AttributeValue{
+	"ID":          {S: aws.String("config-1")},
+	"Region":      {S: aws.String("us-west-2")},
+	"Endpoint":    {S: aws.String("https://example.com")},
+	"Owner":       {S: aws.String("team")},
+	"Environment": {S: aws.String("production")},
+}
+
+func BenchmarkUnmarshalStringMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalStringMap(benchmarkStringMapItem); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalStringMap_Reflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var m map[string]string
+		if err := UnmarshalMap(benchmarkStringMapItem, &m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalBinary_Copy(b *testing.B) {
+	benchmarkUnmarshalBinary(b, false)
+}
+
+func BenchmarkUnmarshalBinary_ZeroCopy(b *testing.B) {
+	benchmarkUnmarshalBinary(b, true)
+}
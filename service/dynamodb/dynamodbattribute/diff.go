@@ -0,0 +1,122 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DiffKind is the kind of difference between an attribute of two items.
+type DiffKind int
+
+// Enumeration of the differences Diff reports.
+const (
+	// DiffAdded is an attribute which is only in the new item.
+	DiffAdded DiffKind = iota
+
+	// DiffRemoved is an attribute which is only in the old item.
+	DiffRemoved
+
+	// DiffChanged is an attribute in both items whose values are not
+	// Equal.
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("DiffKind(%d)", int(k))
+	}
+}
+
+// An AttributeDiff is a difference between an attribute of two items.
+type AttributeDiff struct {
+	Kind DiffKind
+
+	// Path is the attribute names from the item to the attribute. The path
+	// of a top level attribute has a single name, and nested map attributes
+	// have the names of each parent map.
+	Path []string
+
+	// The old and new value of the attribute. Old is nil for added
+	// attributes, and New is nil for removed attributes.
+	Old, New *dynamodb.AttributeValue
+}
+
+func (d AttributeDiff) String() string {
+	return fmt.Sprintf("%s %s", d.Kind, strings.Join(d.Path, "."))
+}
+
+// Diff returns the differences between the attributes of the old and new
+// items, sorted by path. Values are compared with Equal.
+//
+// Attributes which are maps in both items are compared by their nested
+// attributes, so only the nested attributes which differ are reported. All
+// other values, including lists and sets, are reported as changed as a
+// whole.
+func Diff(oldItem, newItem map[string]*dynamodb.AttributeValue) []AttributeDiff {
+	var diffs []AttributeDiff
+	diffMaps(nil, oldItem, newItem, &diffs)
+
+	sort.Sort(diffsByPath(diffs))
+	return diffs
+}
+
+func diffMaps(path []string, oldMap, newMap map[string]*dynamodb.AttributeValue, diffs *[]AttributeDiff) {
+	for name, oldAV := range oldMap {
+		attrPath := appendPath(path, name)
+
+		newAV, ok := newMap[name]
+		if !ok {
+			*diffs = append(*diffs, AttributeDiff{Kind: DiffRemoved, Path: attrPath, Old: oldAV})
+			continue
+		}
+
+		if oldAV != nil && newAV != nil && oldAV.M != nil && newAV.M != nil {
+			diffMaps(attrPath, oldAV.M, newAV.M, diffs)
+			continue
+		}
+		if !Equal(oldAV, newAV) {
+			*diffs = append(*diffs, AttributeDiff{Kind: DiffChanged, Path: attrPath, Old: oldAV, New: newAV})
+		}
+	}
+
+	for name, newAV := range newMap {
+		if _, ok := oldMap[name]; !ok {
+			*diffs = append(*diffs, AttributeDiff{Kind: DiffAdded, Path: appendPath(path, name), New: newAV})
+		}
+	}
+}
+
+// appendPath returns a new path of name appended to path, without
+// modifying path's backing array.
+func appendPath(path []string, name string) []string {
+	p := make([]string, len(path)+1)
+	copy(p, path)
+	p[len(path)] = name
+	return p
+}
+
+type diffsByPath []AttributeDiff
+
+func (x diffsByPath) Len() int { return len(x) }
+
+func (x diffsByPath) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x diffsByPath) Less(i, j int) bool {
+	a, b := x[i].Path, x[j].Path
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
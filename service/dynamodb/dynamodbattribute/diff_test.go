@@ -0,0 +1,78 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestDiff(t *testing.T) {
+	oldItem := map[string]*dynamodb.AttributeValue{
+		"id":      {S: aws.String("abc")},
+		"count":   {N: aws.String("1")},
+		"price":   {N: aws.String("1.50")},
+		"removed": {BOOL: aws.Bool(true)},
+		"tags":    {SS: []*string{aws.String("a"), aws.String("b")}},
+		"list":    {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city":   {S: aws.String("Seattle")},
+			"street": {S: aws.String("Main")},
+			"geo": {M: map[string]*dynamodb.AttributeValue{
+				"lat": {N: aws.String("47.6")},
+			}},
+		}},
+		"kind": {M: map[string]*dynamodb.AttributeValue{}},
+	}
+	newItem := map[string]*dynamodb.AttributeValue{
+		"id":    {S: aws.String("abc")},
+		"count": {N: aws.String("2")},
+		"price": {N: aws.String("1.5")},
+		"added": {S: aws.String("new")},
+		"tags":  {SS: []*string{aws.String("b"), aws.String("a")}},
+		"list":  {L: []*dynamodb.AttributeValue{{S: aws.String("b")}}},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+			"zip":  {S: aws.String("97201")},
+			"geo": {M: map[string]*dynamodb.AttributeValue{
+				"lat": {N: aws.String("47.60")},
+			}},
+		}},
+		"kind": {S: aws.String("map")},
+	}
+
+	expect := []AttributeDiff{
+		{Kind: DiffAdded, Path: []string{"added"}, New: newItem["added"]},
+		{Kind: DiffChanged, Path: []string{"address", "city"},
+			Old: oldItem["address"].M["city"], New: newItem["address"].M["city"]},
+		{Kind: DiffRemoved, Path: []string{"address", "street"}, Old: oldItem["address"].M["street"]},
+		{Kind: DiffAdded, Path: []string{"address", "zip"}, New: newItem["address"].M["zip"]},
+		{Kind: DiffChanged, Path: []string{"count"}, Old: oldItem["count"], New: newItem["count"]},
+		{Kind: DiffChanged, Path: []string{"kind"}, Old: oldItem["kind"], New: newItem["kind"]},
+		{Kind: DiffChanged, Path: []string{"list"}, Old: oldItem["list"], New: newItem["list"]},
+		{Kind: DiffRemoved, Path: []string{"removed"}, Old: oldItem["removed"]},
+	}
+
+	actual := Diff(oldItem, newItem)
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestDiffEqual(t *testing.T) {
+	item := testCloneTemplate()
+	if diffs := Diff(item, CloneMap(item)); len(diffs) != 0 {
+		t.Errorf("expect no diffs, got %v", diffs)
+	}
+	if diffs := Diff(nil, nil); len(diffs) != 0 {
+		t.Errorf("expect no diffs, got %v", diffs)
+	}
+}
+
+func TestAttributeDiffString(t *testing.T) {
+	d := AttributeDiff{Kind: DiffChanged, Path: []string{"address", "city"}}
+	if e, a := "changed address.city", d.String(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
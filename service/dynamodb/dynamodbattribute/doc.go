@@ -0,0 +1,58 @@
+// Package dynamodbattribute provides marshaling utilities for marshaling to
+// dynamodb.AttributeValue types and unmarshaling to Go value types. These
+// utilities allow you to marshal slices, maps, structs, and scalar values
+// to and from dynamodb.AttributeValue. These are useful when marshaling
+// Go value tyes to dynamodb.AttributeValue for DynamoDB requests, or
+// unmarshaling the dynamodb.AttributeValue back into a Go value type.
+//
+// Marshal Go value types to dynamodb.AttributeValue: See (ExampleMarshal)
+//
+//     type Record struct {
+//         MyField string
+//         Letters []string
+//         A2Num   map[string]int
+//     }
+//
+//     ...
+//
+//     r := Record{
+//         MyField: "dynamodbattribute.Marshal example",
+//         Letters: []string{"a", "b", "c", "d"},
+//         A2Num:   map[string]int{"a": 1, "b": 2, "c": 3},
+//     }
+//     av, err := dynamodbattribute.Marshal(r)
+//     fmt.Println(av, err)
+//
+// Unmarshal dynamodb.AttributeValue to Go value type: See (ExampleUnmarshal)
+//
+//     r2 := Record{}
+//     err = dynamodbattribute.Unmarshal(av, &r2)
+//     fmt.Println(err, reflect.DeepEqual(r, r2))
+//
+// Marshal Go value type for DynamoDB.PutItem:
+//
+//     sess := session.New()
+//     svc := dynamodb.New(sess)
+//     item, err := dynamodbattribute.MarshalMap(r)
+//     if err != nil {
+//         fmt.Println("Failed to convert", err)
+//         return
+//     }
+//     result, err := svc.PutItem(&dynamodb.PutItemInput{
+//         Item:      item,
+//         TableName: aws.String("exampleTable"),
+//     })
+//
+// The ConvertTo, ConvertToList, ConvertToMap, ConvertFrom, ConvertFromMap
+// and ConvertFromList methods convert values by first round tripping them
+// through encoding/json, so only `json` struct tags are respected. []byte
+// fields within typed structs are not converted correctly by these methods
+// and are converted into base64 strings. Prefer the Marshal and Unmarshal
+// family of functions, which do not have this limitation.
+//
+// The Marshal and Unmarshal functions honor the same `json` struct tags as
+// the Convert functions, so their results match for structs using only
+// `json` tags. Marshal differs from ConvertTo in that empty strings are
+// marshaled as NULL instead of an empty S. Disable Encoder.NullEmptyString
+// to match the ConvertTo behavior.
+package dynamodbattribute
@@ -0,0 +1,342 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// An OrderedMap is a document value which keeps its attributes in order,
+// unlike map[string]interface{} whose iteration order is random, so
+// logging and diffing documents is deterministic. Unmarshal into an
+// OrderedMap instead of a map[string]interface{} to inspect items whose
+// schema is not known.
+//
+//     doc := dynamodbattribute.NewOrderedMap()
+//     if err := dynamodbattribute.UnmarshalMap(item, doc); err != nil {
+//         return err
+//     }
+//     city, ok := doc.Get("address.city")
+//
+// Attributes set with Set are kept in the order they were added.
+// AttributeValue maps are not ordered, so the attributes of an unmarshaled
+// OrderedMap, and any map nested within it, are ordered by name. Nested
+// maps are unmarshaled as *OrderedMap values and lists as []interface{}.
+// Other values are unmarshaled the same as into an interface{}.
+//
+// The zero value is an empty OrderedMap ready to use.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{}
+}
+
+// Len returns the number of attributes in the map.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the names of the attributes in order.
+func (m *OrderedMap) Keys() []string {
+	return append([]string{}, m.keys...)
+}
+
+// Value returns the value of the attribute name, and if it is present.
+func (m *OrderedMap) Value(name string) (interface{}, bool) {
+	v, ok := m.values[name]
+	return v, ok
+}
+
+// SetValue sets the value of the attribute name. New attributes are added
+// after the existing attributes.
+func (m *OrderedMap) SetValue(name string, v interface{}) {
+	if m.values == nil {
+		m.values = map[string]interface{}{}
+	}
+	if _, ok := m.values[name]; !ok {
+		m.keys = append(m.keys, name)
+	}
+	m.values[name] = v
+}
+
+// Delete removes the attribute name, returning if it was present.
+func (m *OrderedMap) Delete(name string) bool {
+	if _, ok := m.values[name]; !ok {
+		return false
+	}
+
+	delete(m.values, name)
+	for i, k := range m.keys {
+		if k == name {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Get returns the value at the document path, such as "a.b[2].c", and if
+// it is present. Paths are made of attribute names separated by "." and
+// list indexes such as "[2]".
+func (m *OrderedMap) Get(path string) (interface{}, bool) {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var v interface{} = m
+	for _, s := range segments {
+		switch c := v.(type) {
+		case *OrderedMap:
+			if s.isIndex {
+				return nil, false
+			}
+			var ok bool
+			if v, ok = c.Value(s.name); !ok {
+				return nil, false
+			}
+		case []interface{}:
+			if !s.isIndex || s.index >= len(c) {
+				return nil, false
+			}
+			v = c[s.index]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// Set sets the value at the document path, such as "a.b[2].c". Maps along
+// the path are added if not present. Lists along the path must already
+// have an element at the index. An InvalidPathError is returned if the path
+// is malformed or cannot be set.
+func (m *OrderedMap) Set(path string, v interface{}) error {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return err
+	}
+
+	var parent interface{} = m
+	for i, s := range segments {
+		last := i == len(segments)-1
+
+		switch c := parent.(type) {
+		case *OrderedMap:
+			if s.isIndex {
+				return &InvalidPathError{Path: path, msg: "cannot index map with " + s.String()}
+			}
+			if last {
+				c.SetValue(s.name, v)
+				return nil
+			}
+			child, ok := c.Value(s.name)
+			if !ok {
+				if segments[i+1].isIndex {
+					return &InvalidPathError{Path: path, msg: "list " + s.name + " not present"}
+				}
+				child = NewOrderedMap()
+				c.SetValue(s.name, child)
+			}
+			parent = child
+		case []interface{}:
+			if !s.isIndex {
+				return &InvalidPathError{Path: path, msg: "cannot get attribute " + s.name + " of list"}
+			}
+			if s.index >= len(c) {
+				return &InvalidPathError{Path: path, msg: "list index " + s.String() + " out of range"}
+			}
+			if last {
+				c[s.index] = v
+				return nil
+			}
+			parent = c[s.index]
+		default:
+			return &InvalidPathError{Path: path, msg: fmt.Sprintf("cannot set %s of %T value", s, c)}
+		}
+	}
+
+	return nil
+}
+
+// MarshalDynamoDBAttributeValue marshals the map as a Map AttributeValue,
+// satisfying the Marshaler interface.
+func (m *OrderedMap) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	av.M = make(map[string]*dynamodb.AttributeValue, len(m.keys))
+	for _, k := range m.keys {
+		elem, err := Marshal(m.values[k])
+		if err != nil {
+			return err
+		}
+		av.M[k] = elem
+	}
+
+	return nil
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals the Map AttributeValue into
+// the map, ordering its attributes by name. Satisfies the Unmarshaler
+// interface.
+func (m *OrderedMap) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if av == nil || av.NULL != nil {
+		*m = OrderedMap{}
+		return nil
+	}
+	if av.M == nil {
+		return &UnmarshalTypeError{Value: "non-map attribute value", Type: orderedMapType}
+	}
+
+	keys := make([]string, 0, len(av.M))
+	for k := range av.M {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		v, err := orderedMapValue(av.M[k])
+		if err != nil {
+			return err
+		}
+		values[k] = v
+	}
+	*m = OrderedMap{keys: keys, values: values}
+
+	return nil
+}
+
+// String returns the attributes of the map in order, such as
+// "{a: 1, b: [x y]}".
+func (m *OrderedMap) String() string {
+	parts := make([]string, len(m.keys))
+	for i, k := range m.keys {
+		parts[i] = fmt.Sprintf("%s: %v", k, m.values[k])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+var orderedMapType = reflect.TypeOf(OrderedMap{})
+
+// orderedMapValue unmarshals the AttributeValue as an OrderedMap value.
+func orderedMapValue(av *dynamodb.AttributeValue) (interface{}, error) {
+	switch {
+	case av == nil:
+		return nil, nil
+	case av.M != nil:
+		m := NewOrderedMap()
+		if err := m.UnmarshalDynamoDBAttributeValue(av); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case av.L != nil:
+		l := make([]interface{}, len(av.L))
+		for i, elem := range av.L {
+			v, err := orderedMapValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = v
+		}
+		return l, nil
+	}
+
+	var v interface{}
+	if err := Unmarshal(av, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// A pathSegment is an attribute name or list index of a document path.
+type pathSegment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return s.name
+}
+
+// parseDocumentPath parses the document path, such as "a.b[2].c", into its
+// attribute name and list index segments.
+func parseDocumentPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, &InvalidPathError{Path: path, msg: "unterminated list index"}
+			}
+			n, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || n < 0 {
+				return nil, &InvalidPathError{Path: path, msg: "invalid list index " + path[i:i+end+1]}
+			}
+			segments = append(segments, pathSegment{index: n, isIndex: true})
+			i += end + 1
+		case path[i] == '.' && len(segments) != 0 && i+1 < len(path):
+			i++
+			fallthrough
+		default:
+			if len(segments) != 0 && path[i-1] != '.' {
+				return nil, &InvalidPathError{Path: path, msg: "expected . or [ after " + segments[len(segments)-1].String()}
+			}
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, &InvalidPathError{Path: path, msg: "empty attribute name"}
+			}
+			segments = append(segments, pathSegment{name: path[i : i+end]})
+			i += end
+		}
+	}
+	if len(segments) == 0 || segments[0].isIndex {
+		return nil, &InvalidPathError{Path: path, msg: "path must begin with an attribute name"}
+	}
+
+	return segments, nil
+}
+
+// An InvalidPathError is an error type representing a document path which
+// is malformed, or which cannot be followed in the document.
+type InvalidPathError struct {
+	emptyOrigError
+
+	// The document path, e.g. "a.b[2].c".
+	Path string
+
+	msg string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidPathError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidPathError) Code() string {
+	return "InvalidPathError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidPathError) Message() string {
+	return e.msg + ", " + strconv.Quote(e.Path)
+}
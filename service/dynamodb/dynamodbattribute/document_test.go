@@ -0,0 +1,121 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestOrderedMapUnmarshal(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"status": {S: aws.String("active")},
+		"count":  {N: aws.String("2")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"zip":  {S: aws.String("98101")},
+			"city": {S: aws.String("Seattle")},
+		}},
+		"orders": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"id": {S: aws.String("a")}}},
+		}},
+		"none": {NULL: aws.Bool(true)},
+	}
+
+	doc := NewOrderedMap()
+	if err := UnmarshalMap(item, doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"address", "count", "none", "orders", "status"}, doc.Keys(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "{address: {city: Seattle, zip: 98101}, count: 2, none: <nil>, orders: [{id: a}], status: active}",
+		doc.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	cases := []struct {
+		path   string
+		expect interface{}
+		ok     bool
+	}{
+		{"status", "active", true},
+		{"count", float64(2), true},
+		{"address.city", "Seattle", true},
+		{"orders[0].id", "a", true},
+		{"none", nil, true},
+		{"orders[1].id", nil, false},
+		{"address[0]", nil, false},
+		{"status.length", nil, false},
+		{"missing", nil, false},
+		{"orders[", nil, false},
+	}
+	for _, c := range cases {
+		v, ok := doc.Get(c.path)
+		if e, a := c.ok, ok; e != a {
+			t.Errorf("%s, expect present %t, got %t", c.path, e, a)
+		}
+		if e, a := c.expect, v; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s, expect %v, got %v", c.path, e, a)
+		}
+	}
+
+	// Round trip back to the item.
+	av, err := MarshalMap(doc)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := item, av; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestOrderedMapSet(t *testing.T) {
+	var doc OrderedMap
+	doc.SetValue("b", 1)
+	doc.SetValue("a", "x")
+	doc.SetValue("b", 2)
+	if err := doc.Set("c.d.e", true); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := doc.Set("list", []interface{}{"x", NewOrderedMap()}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := doc.Set("list[1].f", "g"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := doc.Set("list[0]", "y"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "{b: 2, a: x, c: {d: {e: true}}, list: [y {f: g}]}", doc.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if !doc.Delete("a") || doc.Delete("a") {
+		t.Errorf("expect a deleted once")
+	}
+	if e, a := []string{"b", "c", "list"}, doc.Keys(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	cases := map[string]string{
+		"":          `InvalidPathError: path must begin with an attribute name, ""`,
+		"[0]":       `InvalidPathError: path must begin with an attribute name, "[0]"`,
+		"a..b":      `InvalidPathError: empty attribute name, "a..b"`,
+		"list[x]":   `InvalidPathError: invalid list index [x], "list[x]"`,
+		"list[0]b":  `InvalidPathError: expected . or [ after [0], "list[0]b"`,
+		"list[2]":   `InvalidPathError: list index [2] out of range, "list[2]"`,
+		"list.f":    `InvalidPathError: cannot get attribute f of list, "list.f"`,
+		"c[0]":      `InvalidPathError: cannot index map with [0], "c[0]"`,
+		"new[0]":    `InvalidPathError: list new not present, "new[0]"`,
+		"b.c":       `InvalidPathError: cannot set c of int value, "b.c"`,
+		"list[0].x": `InvalidPathError: cannot set x of string value, "list[0].x"`,
+	}
+	for path, expect := range cases {
+		if e, a := expect, errString(doc.Set(path, 1)); e != a {
+			t.Errorf("%s, expect %q, got %q", path, e, a)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// duplicateAttributeCache caches the duplicate attribute name, if any, of
+// struct types for each combination of MarshalOptions.
+var duplicateAttributeCache = struct {
+	sync.RWMutex
+	m map[fieldCacheKey]*DuplicateAttributeError
+}{m: map[fieldCacheKey]*DuplicateAttributeError{}}
+
+// checkDuplicateAttributes returns a DuplicateAttributeError if two fields
+// of the struct type t are marshaled to the same attribute name.
+func checkDuplicateAttributes(t reflect.Type, opts MarshalOptions) error {
+	key := fieldCacheKey{typ: t, opts: opts}
+
+	duplicateAttributeCache.RLock()
+	dup, ok := duplicateAttributeCache.m[key]
+	duplicateAttributeCache.RUnlock()
+
+	if !ok {
+		dup = findDuplicateAttribute(t, opts)
+		duplicateAttributeCache.Lock()
+		duplicateAttributeCache.m[key] = dup
+		duplicateAttributeCache.Unlock()
+	}
+	if dup == nil {
+		return nil
+	}
+
+	// Copy the cached error, as its path is set by the caller.
+	err := *dup
+	return &err
+}
+
+// findDuplicateAttribute returns the first pair of fields of the struct
+// type t sharing an attribute name, ignoring case. Promoted fields hidden
+// by a field at a shallower embedding depth are not duplicates.
+func findDuplicateAttribute(t reflect.Type, opts MarshalOptions) *DuplicateAttributeError {
+	fields := candidateFields(t, opts)
+	for i := 0; i+1 < len(fields); i++ {
+		if i > 0 && fields[i-1].Name == fields[i].Name {
+			// Only the shallowest fields of each name are compared.
+			continue
+		}
+		a, b := fields[i], fields[i+1]
+		if a.Name == b.Name && len(a.Index) == len(b.Index) {
+			return newDuplicateAttributeError(t, a, b)
+		}
+	}
+
+	resolved := unionStructFields(t, opts)
+	for i := range resolved {
+		for j := i + 1; j < len(resolved); j++ {
+			if strings.EqualFold(resolved[i].Name, resolved[j].Name) {
+				return newDuplicateAttributeError(t, resolved[i], resolved[j])
+			}
+		}
+	}
+
+	return nil
+}
+
+func newDuplicateAttributeError(t reflect.Type, a, b field) *DuplicateAttributeError {
+	return &DuplicateAttributeError{
+		Type:      t,
+		Attribute: a.Name,
+		Fields:    []string{goFieldPath(t, a.Index), goFieldPath(t, b.Index)},
+	}
+}
+
+// goFieldPath returns the Go selector of the field at index of the struct
+// type t, including the embedded structs it is promoted through, e.g.
+// "Base.ID".
+func goFieldPath(t reflect.Type, index []int) string {
+	names := make([]string, 0, len(index))
+	for _, i := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		sf := t.Field(i)
+		names = append(names, sf.Name)
+		t = sf.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// A DuplicateAttributeError is an error type representing two fields of a
+// struct which are marshaled to the same attribute name, or names which
+// differ only by case, returned by an Encoder with StrictFieldNames
+// enabled.
+type DuplicateAttributeError struct {
+	emptyOrigError
+
+	// Document path of the struct, e.g. "Orders[2]". Empty for the
+	// outermost value.
+	Path string
+
+	// Go value type of the struct.
+	Type reflect.Type
+
+	// The attribute name, and the Go selectors of the two fields sharing
+	// it, e.g. "Base.ID".
+	Attribute string
+	Fields    []string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *DuplicateAttributeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *DuplicateAttributeError) Code() string {
+	return "DuplicateAttributeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *DuplicateAttributeError) Message() string {
+	msg := fmt.Sprintf("attribute %s of %s is used by fields %s",
+		e.Attribute, e.Type, strings.Join(e.Fields, " and "))
+	if len(e.Path) != 0 {
+		msg += ", " + e.Path
+	}
+	return msg
+}
@@ -0,0 +1,72 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncodeStrictFieldNames(t *testing.T) {
+	type caseFold struct {
+		ID string
+		Id string
+	}
+	type nested struct {
+		Items []unionConflictOuter
+	}
+
+	cases := []struct {
+		in     interface{}
+		expect string
+	}{
+		{unionConflictOuter{}, "DuplicateAttributeError: attribute Value of dynamodbattribute.unionConflictOuter is used by fields unionConflictA.Value and unionConflictB.Value"},
+		{unionTaggedOuter{}, "DuplicateAttributeError: attribute Value of dynamodbattribute.unionTaggedOuter is used by fields unionTaggedB.V and unionTaggedA.Value"},
+		{unionComplex{}, "DuplicateAttributeError: attribute C of dynamodbattribute.unionComplex is used by fields unionSimple.C and unionExported.C"},
+		{caseFold{ID: "a", Id: "b"}, "DuplicateAttributeError: attribute ID of dynamodbattribute.caseFold is used by fields ID and Id"},
+		{nested{Items: []unionConflictOuter{{}}}, "DuplicateAttributeError: attribute Value of dynamodbattribute.unionConflictOuter is used by fields unionConflictA.Value and unionConflictB.Value, Items[0]"},
+	}
+
+	for i, c := range cases {
+		// Duplicates are resolved without error by default.
+		if _, err := Marshal(c.in); err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+
+		_, err := MarshalWithOptions(c.in, func(e *Encoder) {
+			e.StrictFieldNames = true
+		})
+		if _, ok := err.(*DuplicateAttributeError); !ok {
+			t.Errorf("%d, expect *DuplicateAttributeError, got %T, %v", i, err, err)
+			continue
+		}
+		if e, a := c.expect, err.Error(); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestEncodeStrictFieldNamesShadowed(t *testing.T) {
+	// Promoted fields hidden by a shallower field are not duplicates.
+	cases := []interface{}{
+		unionDeepOuter{},
+		struct {
+			unionConflictB
+			Value int
+		}{},
+	}
+
+	for i, c := range cases {
+		_, err := MarshalWithOptions(c, func(e *Encoder) {
+			e.StrictFieldNames = true
+		})
+		if err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+	}
+}
+
+func TestGoFieldPath(t *testing.T) {
+	typ := reflect.TypeOf(unionDeepOuter{})
+	if e, a := "unionDeepMiddle.unionConflictA.Other", goFieldPath(typ, []int{0, 0, 1}); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
@@ -0,0 +1,1192 @@
+package dynamodbattribute
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A Marshaler is an interface to provide custom marshaling of Go value types
+// to AttributeValues. Use this to provide custom logic determining how a
+// Go Value type should be marshaled.
+//
+//		type ExampleMarshaler struct {
+//			Value int
+//		}
+//		func (m *ExampleMarshaler) 	MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+//			n := fmt.Sprintf("%v", m.Value)
+//			av.N = &n
+//			return nil
+//		}
+//
+type Marshaler interface {
+	MarshalDynamoDBAttributeValue(*dynamodb.AttributeValue) error
+}
+
+// Marshal will serialize the passed in Go value type into a DynamoDB AttributeValue
+// type. This value can be used in DynamoDB API operations to simplify marshaling
+// your Go value types into AttributeValues.
+//
+// Marshal will recurse down Maps and Slices to marshal their elements. Struct
+// fields are marshaled using the `dynamodbav` struct tag, falling back to the
+// `json` struct tag if `dynamodbav` is not set.
+//
+// Marshal supports the following struct tag options:
+//
+//     // Field is ignored
+//     Field int `dynamodbav:"-"`
+//
+//     // Field AttributeValue map key "myName"
+//     Field int `dynamodbav:"myName"`
+//
+//     // Field AttributeValue map key "myName", and
+//     // Field is omitted if it is empty
+//     Field int `dynamodbav:"myName,omitempty"`
+//
+//     // Field AttributeValue map key "Field", and
+//     // Field is omitted if it is empty
+//     Field int `dynamodbav:",omitempty"`
+//
+//     // Field is omitted if it is the zero value of its type, or its
+//     // IsZero method returns true. Unlike omitempty, empty non-nil
+//     // slices and maps are not omitted, and structs are.
+//     Field Point `dynamodbav:",omitzero"`
+//
+//     // Field's elems will be omitted if empty
+//     // only valid for slices, and maps.
+//     Field []string `dynamodbav:",omitemptyelem"`
+//
+//     // Field will be marshaled as a AttributeValue string
+//     // only value for number types, (int,uint,float)
+//     Field int `dynamodbav:",string"`
+//
+//     // Field will be marshaled as a binary set
+//     Field [][]byte `dynamodbav:",binaryset"`
+//
+//     // Field will be marshaled as a number set
+//     Field []int `dynamodbav:",numberset"`
+//
+//     // Field will be marshaled as a string set
+//     Field []string `dynamodbav:",stringset"`
+//
+//     // Field's keys will be marshaled as a string set. Maps with bool
+//     // values only include the keys whose value is true.
+//     Field map[string]struct{} `dynamodbav:",stringset"`
+//
+//     // Field's keys will be marshaled as a number set
+//     Field map[int]bool `dynamodbav:",numberset"`
+//
+//     // Field AttributeValue map key "myName", and
+//     // Field will be unmarshaled from "oldName" if "myName" is not present
+//     Field int `dynamodbav:"myName,alias=oldName"`
+//
+//     // Field will be unmarshaled from the first of "oldName" and "Legacy"
+//     // present if "myName" is not present. Only "myName" is marshaled.
+//     Field int `dynamodbav:"myName,alias=oldName,alias=Legacy"`
+//
+//     // Field AttributeValue map key "newName", and Field will be
+//     // unmarshaled from "oldName" if "newName" is not present, before
+//     // any aliases. Allows an attribute to be renamed while items with
+//     // the old name are still being read.
+//     Field int `dynamodbav:"newName,readfrom=oldName"`
+//
+//     // Field may not be changed once written, or
+//     // only written if not already set. See WriteRules.
+//     Field int `dynamodbav:",immutable"`
+//     Field int `dynamodbav:",writeonce"`
+//
+//     // Field is the item's version for optimistic locking. See
+//     // Encoder.IncrementVersion and WriteRules.
+//     Field int64 `dynamodbav:",version"`
+//
+//     // Field will be gzipped into a binary attribute. See
+//     // Encoder.CompressThreshold. Types other than string and []byte
+//     // are JSON encoded before being compressed.
+//     Field string `dynamodbav:",compress"`
+//
+//     // Field will be encrypted into a binary attribute. See
+//     // EncryptionProvider.
+//     Field string `dynamodbav:",encrypted"`
+//
+//     // Field will be marshaled as the 16 byte binary form of the UUID
+//     // instead of its 36 character string. Only valid for [16]byte
+//     // types, such as uuid.UUID, and strings.
+//     Field uuid.UUID `dynamodbav:"id,uuid"`
+//
+//     // Field is the table's partition key, or sort key. Does not change
+//     // how the field is marshaled. See KeyOf.
+//     Field string `dynamodbav:"id,hashkey"`
+//     Field int64  `dynamodbav:"created,rangekey"`
+//
+//     // Field's registered union member is selected by the "kind"
+//     // attribute instead of the union's discriminator. See RegisterUnion.
+//     Field Entity `dynamodbav:",union=kind"`
+//
+// The omitempty tag is only used during Marshaling and is ignored for
+// Unmarshal. Any zero value or a value when marshaled results in a
+// AttributeValue NULL will be added to AttributeValue Maps during struct
+// marshal. The omitemptyelem tag works the same as omitempty except it
+// applies to maps and slices instead of struct fields, and will not be
+// included in the marshaled AttributeValue Map, List, or Set.
+//
+// For convenience and backwards compatibility with ConvertTo functions
+// json struct tags are supported by the Marshal and Unmarshal. If
+// both json and dynamodbav struct tags are provided the json tag will
+// be ignored in favor of dynamodbav.
+//
+// All struct fields and with anonymous fields, are marshaled unless the
+// any of the following conditions are meet.
+//
+//     - the field is not exported
+//     - json or dynamodbav field tag is "-"
+//     - json or dynamodbav field tag specifies "omitempty", and is empty.
+//
+// Anonymous struct fields are marshaled as if their inner exported fields
+// were fields in the outer struct. Name conflicts between promoted fields
+// are resolved with the same rules as encoding/json. The shallowest field
+// wins, a tagged field wins over untagged fields at the same depth, and
+// any remaining conflicting fields are ignored.
+//
+// Pointer and interfaces values encode as the value pointed to or contained
+// in the interface. A nil value encodes as the AttributeValue NULL value.
+//
+// Channel, complex, and function values are not encoded and will be skipped
+// when walking the value to be marshaled.
+//
+// When marshaling any error that occurs will halt the marshal and return
+// the error.
+//
+// Marshal cannot represent cyclic data structures. Passing cyclic
+// structures to Marshal will return a CycleError.
+func Marshal(in interface{}) (*dynamodb.AttributeValue, error) {
+	return MarshalWithOptions(in)
+}
+
+// MarshalWithOptions will serialize the passed in Go value type into a
+// DynamoDB AttributeValue type, the same as Marshal. The `opts` functional
+// options are applied to the Encoder used.
+//
+//     av, err := dynamodbattribute.MarshalWithOptions(r, func(e *dynamodbattribute.Encoder) {
+//         e.NullEmptyString = false
+//     })
+func MarshalWithOptions(in interface{}, opts ...func(*Encoder)) (*dynamodb.AttributeValue, error) {
+	if len(opts) == 0 {
+		return defaultEncoder.Encode(in)
+	}
+
+	e := getEncoder(opts)
+	defer putEncoder(e)
+
+	return e.Encode(in)
+}
+
+// MarshalMap is an alias for Marshal func which marshals Go value
+// type to a map of AttributeValues.
+func MarshalMap(in interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	return MarshalMapWithOptions(in)
+}
+
+// MarshalMapWithOptions is an alias for MarshalWithOptions func which
+// marshals Go value type to a map of AttributeValues.
+func MarshalMapWithOptions(in interface{}, opts ...func(*Encoder)) (map[string]*dynamodb.AttributeValue, error) {
+	av, err := MarshalWithOptions(in, opts...)
+	if err != nil || av == nil || av.M == nil {
+		return map[string]*dynamodb.AttributeValue{}, err
+	}
+
+	return av.M, nil
+}
+
+// MarshalList is an alias for Marshal func which marshals Go value
+// type to a slice of AttributeValues.
+func MarshalList(in interface{}) ([]*dynamodb.AttributeValue, error) {
+	return MarshalListWithOptions(in)
+}
+
+// MarshalListWithOptions is an alias for MarshalWithOptions func which
+// marshals Go value type to a slice of AttributeValues.
+func MarshalListWithOptions(in interface{}, opts ...func(*Encoder)) ([]*dynamodb.AttributeValue, error) {
+	av, err := MarshalWithOptions(in, opts...)
+	if err != nil || av == nil || av.L == nil {
+		return []*dynamodb.AttributeValue{}, err
+	}
+
+	return av.L, nil
+}
+
+// MarshalValueMap marshals each of the Go values into the
+// ExpressionAttributeValues of a request. The names are prefixed with ":"
+// if they are not already, and must otherwise only contain letters,
+// digits, and underscores.
+//
+//     values, err := dynamodbattribute.MarshalValueMap(map[string]interface{}{
+//         "status": "SHIPPED",
+//         ":limit": 10,
+//     })
+//     // values is {":status": {S: "SHIPPED"}, ":limit": {N: "10"}}
+func MarshalValueMap(in map[string]interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	return MarshalValueMapWithOptions(in)
+}
+
+// MarshalValueMapWithOptions is MarshalValueMap with the `opts` functional
+// options applied to the Encoder used.
+func MarshalValueMapWithOptions(in map[string]interface{}, opts ...func(*Encoder)) (map[string]*dynamodb.AttributeValue, error) {
+	e := getEncoder(opts)
+	defer putEncoder(e)
+
+	values := make(map[string]*dynamodb.AttributeValue, len(in))
+	for name, v := range in {
+		placeholder, err := valuePlaceholder(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := values[placeholder]; ok {
+			return nil, &InvalidMarshalError{msg: "duplicate expression attribute value " + placeholder}
+		}
+
+		av, err := e.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		values[placeholder] = av
+	}
+
+	return values, nil
+}
+
+// valuePlaceholder returns the name prefixed with ":", validating it is a
+// valid expression attribute value placeholder.
+func valuePlaceholder(name string) (string, error) {
+	placeholder := name
+	if !strings.HasPrefix(placeholder, ":") {
+		placeholder = ":" + placeholder
+	}
+
+	valid := len(placeholder) > 1
+	for _, r := range placeholder[1:] {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			valid = false
+			break
+		}
+	}
+	if !valid {
+		return "", &InvalidMarshalError{msg: "invalid expression attribute value placeholder " + strconv.Quote(name)}
+	}
+
+	return placeholder, nil
+}
+
+// A MarshalOptions is a collection of options shared between marshaling
+// and unmarshaling
+type MarshalOptions struct {
+	// States that the encoding/json struct tags should be supported.
+	// if a `dynamodbav` struct tag is also provided the encoding/json
+	// tag will be ignored.
+	//
+	// Enabled by default.
+	SupportJSONTags bool
+
+	// Name of the attribute the registered name of a value's type is
+	// stored in, when the value is held by an interface{}. Allows the
+	// value to be unmarshaled into its original type. See RegisterType.
+	//
+	// Disabled by default.
+	TypeAttribute string
+}
+
+// An Encoder provides marshaling Go value types to AttributeValues.
+//
+// An Encoder is safe for concurrent use by multiple goroutines, so a single
+// configured Encoder can be shared. Its options must not be modified once
+// it is in use. Create another Encoder for a different configuration.
+type Encoder struct {
+	MarshalOptions
+
+	// Empty strings, "", will be marked as NULL AttributeValue types.
+	// Empty strings are not valid values for DynamoDB. Will not apply
+	// to lists, sets, or maps. Use the struct field tag `omitempty` to
+	// skip empty (zero) values instead.
+	//
+	// Enabled by default.
+	NullEmptyString bool
+
+	// Numbers will be marshaled as String AttributeValue types, the same
+	// as if every numeric field had the `string` struct tag option, such
+	// as for tables whose sort keys store numbers as strings. Number sets
+	// are not affected. Use the Decoder's NumbersAsStrings option to
+	// unmarshal them.
+	//
+	// Disabled by default.
+	NumbersAsStrings bool
+
+	// Limits the marshaled AttributeValue is validated against. A
+	// LimitExceededError is returned if the value exceeds any of the
+	// limits. Set to DefaultLimits to validate against DynamoDB's limits.
+	//
+	// Disabled by default.
+	Limits Limits
+
+	// Fields with the `version` struct tag option will be marshaled as
+	// their value plus one, the version the item will have once written.
+	// The Go value is not modified. See WriteRules for the condition which
+	// ensures the item was not modified since it was read.
+	//
+	// Disabled by default.
+	IncrementVersion bool
+
+	// Values of types which cannot be marshaled, such as channels and
+	// funcs, will return an UnsupportedTypeError instead of being skipped
+	// when they are the fields of structs, or elements of maps and lists.
+	//
+	// Disabled by default.
+	StrictTypes bool
+
+	// Structs with two fields marshaled to the same attribute name, or
+	// names which differ only by case, will return a
+	// DuplicateAttributeError. By default the fields are resolved with the
+	// rules encoding/json uses, which may drop both fields.
+	//
+	// Disabled by default.
+	StrictFieldNames bool
+
+	// Minimum size in bytes of the values of fields with the `compress`
+	// struct tag option which will be compressed. Smaller values are
+	// marshaled as if the field did not have the tag option, as gzip's
+	// overhead outweighs the savings for small values.
+	//
+	// Defaults to 0, compressing all non-empty values.
+	CompressThreshold int
+
+	// Encrypts the attributes of fields with the `encrypted` struct tag
+	// option. Required to marshal values with encrypted fields. See
+	// EncryptionProvider.
+	Encryption EncryptionProvider
+
+	// Fields of nested struct types will be marshaled as attributes of the
+	// outer struct named "Parent.Child", instead of as a nested map. This
+	// suits tables whose update expressions address flat attributes. The
+	// Decoder's FlattenStructs option reverses this.
+	//
+	// Disabled by default.
+	FlattenStructs bool
+
+	// Maximum depth of nested structs, maps, and lists which will be
+	// marshaled. The outermost value, such as the item, is not counted. A
+	// LimitExceededError is returned for values nested deeper. Values which
+	// reference themselves, such as a struct with a pointer to itself,
+	// return a CycleError regardless of this limit.
+	//
+	// Defaults to 0, no limit.
+	MaxDepth int
+
+	// Names of the attributes of the outermost struct which will be
+	// marshaled. Other fields are skipped. Fields of nested structs are not
+	// filtered. Useful to marshal only some of an item's attributes, e.g.
+	// the attribute values of an UpdateItem request.
+	//
+	//     av, err := dynamodbattribute.MarshalMapWithOptions(order, func(e *dynamodbattribute.Encoder) {
+	//         e.IncludeFields = []string{"Status", "Total"}
+	//     })
+	//
+	// Defaults to nil, marshaling all fields.
+	IncludeFields []string
+
+	// Names of the attributes of the outermost struct which will not be
+	// marshaled, e.g. the key attributes of an item, which cannot be
+	// updated. Applied after IncludeFields.
+	//
+	// Defaults to nil, marshaling all fields.
+	ExcludeFields []string
+
+	// Reports a sample of the errors returned by Encode. See
+	// ErrorSampler.
+	//
+	// Disabled by default.
+	ErrorSampler *ErrorSampler
+
+	// Depth of the value being encoded, and the pointers, maps, and slices
+	// enclosing it. Only set on the copy of the Encoder made by Encode.
+	depth    int
+	visiting []visitKey
+}
+
+// NewEncoder creates a new Encoder with default configuration. Use
+// the `opts` functional options to override the default configuration.
+func NewEncoder(opts ...func(*Encoder)) *Encoder {
+	e := &Encoder{}
+	e.Reset()
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e
+}
+
+// Reset restores the Encoder to the default configuration NewEncoder
+// creates. This allows an Encoder to be reused, e.g. pooled with a
+// sync.Pool, by a batch writer marshaling many items.
+func (e *Encoder) Reset() {
+	*e = Encoder{
+		MarshalOptions: MarshalOptions{
+			SupportJSONTags: true,
+		},
+		NullEmptyString: true,
+	}
+}
+
+// defaultEncoder is the Encoder with the default configuration shared by
+// Marshal, MarshalMap, and MarshalList.
+var defaultEncoder = NewEncoder()
+
+// DefaultEncoder returns a copy of the Encoder with the default
+// configuration used by Marshal, MarshalMap, and MarshalList. Changes to
+// the copy do not affect those functions.
+func DefaultEncoder() *Encoder {
+	e := *defaultEncoder
+	return &e
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		return NewEncoder()
+	},
+}
+
+// getEncoder returns an Encoder from the pool with the opts applied. The
+// Encoder must be released with putEncoder when no longer used.
+func getEncoder(opts []func(*Encoder)) *Encoder {
+	e := encoderPool.Get().(*Encoder)
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
+
+func putEncoder(e *Encoder) {
+	e.Reset()
+	encoderPool.Put(e)
+}
+
+// Encode will marshal a Go value type to an AttributeValue. Returning
+// the AttributeValue constructed or error.
+func (e *Encoder) Encode(in interface{}) (*dynamodb.AttributeValue, error) {
+	// The value is encoded by a copy of the Encoder, as the depth and cycle
+	// detection state is kept in the Encoder.
+	enc := *e
+
+	av := &dynamodb.AttributeValue{}
+	if err := enc.encode(av, reflect.ValueOf(in), tag{}); err != nil {
+		e.ErrorSampler.Observe(err)
+		return nil, err
+	}
+	if err := e.Limits.Validate(av); err != nil {
+		e.ErrorSampler.Observe(err)
+		return nil, err
+	}
+
+	return av, nil
+}
+
+func (e *Encoder) encode(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	key, ok := visitKeyOf(v)
+	if !ok {
+		return e.encodeValue(av, v, fieldTag)
+	}
+
+	if err := e.visit(key); err != nil {
+		return err
+	}
+	err := e.encodeValue(av, v, fieldTag)
+	e.visiting = e.visiting[:len(e.visiting)-1]
+
+	return err
+}
+
+func (e *Encoder) encodeValue(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	// We should check for omitted values first before dereferencing.
+	if fieldTag.OmitEmpty && emptyValue(v) {
+		encodeNull(av)
+		return nil
+	}
+
+	if v.Kind() == reflect.Interface && !v.IsNil() && (v.NumMethod() != 0 || len(e.TypeAttribute) != 0) {
+		elemTag := fieldTag
+		elemTag.OmitEmpty = false
+		if err := e.encodeValue(av, v.Elem(), elemTag); err != nil {
+			return err
+		}
+		if v.NumMethod() != 0 {
+			encodeUnionDiscriminator(av, v, fieldTag)
+		} else {
+			e.encodeTypeAttribute(av, v)
+		}
+		return nil
+	}
+
+	// Handle both pointers and interface conversion into types
+	v = valueElem(v)
+
+	if v.Kind() != reflect.Invalid {
+		if fieldTag.UUID {
+			return encodeUUID(av, v)
+		}
+		if used, err := e.tryMarshaler(av, v); used {
+			return err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		encodeNull(av)
+
+	case reflect.Struct:
+		return e.encodeStruct(av, v)
+
+	case reflect.Map:
+		return e.encodeMap(av, v, fieldTag)
+
+	case reflect.Slice, reflect.Array:
+		return e.encodeSlice(av, v, fieldTag)
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return &unsupportedMarshalTypeError{Type: v.Type()}
+
+	default:
+		return e.encodeScalar(av, v, fieldTag)
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeStruct(av *dynamodb.AttributeValue, v reflect.Value) error {
+	// To maintain backwards compatibility with ConvertTo family of methods which
+	// converted time.Time structs to strings
+	if t, ok := v.Interface().(time.Time); ok {
+		s := t.Format(time.RFC3339Nano)
+		av.S = &s
+		return nil
+	}
+
+	if err := e.checkDepth(); err != nil {
+		return err
+	}
+	if e.StrictFieldNames {
+		if err := checkDuplicateAttributes(v.Type(), e.MarshalOptions); err != nil {
+			return err
+		}
+	}
+	if !e.NumbersAsStrings && (e.depth != 0 || e.IncludeFields == nil && e.ExcludeFields == nil) {
+		if p := cachedStructProgram(v.Type(), e.MarshalOptions); p != nil {
+			e.encodeStructProgram(av, v, p)
+			return nil
+		}
+	}
+
+	fields := unionStructFields(v.Type(), e.MarshalOptions)
+
+	// Material descriptions of the encrypted fields.
+	var descs map[string]map[string]string
+
+	// Allocate the field AttributeValues together instead of individually.
+	elems := make([]dynamodb.AttributeValue, len(fields))
+	av.M = make(map[string]*dynamodb.AttributeValue, len(fields))
+	for i, f := range fields {
+		if f.Name == "" {
+			return &InvalidMarshalError{msg: "map key cannot be empty"}
+		}
+		if e.depth == 0 && !e.includeField(f.Name) {
+			continue
+		}
+
+		fv, found := fieldByIndex(v, f.Index, func(v *reflect.Value) bool {
+			return false
+		})
+		if !found {
+			continue
+		}
+		if f.OmitZero && zeroValue(fv) {
+			continue
+		}
+
+		if f.Version && e.IncrementVersion {
+			next, err := nextVersion(fv)
+			if err != nil {
+				return err
+			}
+			fv = next
+		}
+
+		elem := &elems[i]
+		var err error
+		e.depth++
+		if f.Compress {
+			err = e.encodeCompressed(elem, fv, f.tag)
+		} else {
+			err = e.encode(elem, fv, f.tag)
+		}
+		e.depth--
+		skip, err := e.keepOrOmitEmpty(f.OmitEmpty, elem, err)
+		if err != nil {
+			return prefixEncodePath(err, f.Name)
+		} else if skip {
+			continue
+		}
+
+		if f.Encrypted {
+			desc, err := e.encryptField(elem, f.Name)
+			if err != nil {
+				return err
+			}
+			if desc != nil {
+				if descs == nil {
+					descs = map[string]map[string]string{}
+				}
+				descs[f.Name] = desc
+			}
+		}
+
+		if e.FlattenStructs {
+			if elem.M != nil && isFlattenedStruct(f.Type) {
+				if err := flattenStructField(av.M, f.Name, elem); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, ok := av.M[f.Name]; ok {
+				return &InvalidMarshalError{msg: "duplicate flattened attribute name " + f.Name}
+			}
+		}
+
+		av.M[f.Name] = elem
+	}
+	if descs != nil {
+		encodeMaterialDescriptions(av, descs)
+	}
+	if len(av.M) == 0 {
+		encodeNull(av)
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeMap(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	if fieldTag.AsStrSet || fieldTag.AsNumSet {
+		return e.encodeMapSet(av, v, fieldTag)
+	}
+	if err := e.checkDepth(); err != nil {
+		return err
+	}
+
+	// Allocate the element AttributeValues together instead of individually.
+	elems := make([]dynamodb.AttributeValue, v.Len())
+	av.M = make(map[string]*dynamodb.AttributeValue, v.Len())
+	for i, key := range v.MapKeys() {
+		keyName, err := encodeMapKey(key)
+		if err != nil {
+			return err
+		}
+		if keyName == "" {
+			return &InvalidMarshalError{msg: "map key cannot be empty"}
+		}
+
+		elemVal := v.MapIndex(key)
+		elem := &elems[i]
+		e.depth++
+		err = e.encode(elem, elemVal, tag{})
+		e.depth--
+		skip, err := e.keepOrOmitEmpty(fieldTag.OmitEmptyElem, elem, err)
+		if err != nil {
+			return prefixEncodePath(err, keyName)
+		} else if skip {
+			continue
+		}
+
+		av.M[keyName] = elem
+	}
+	if len(av.M) == 0 {
+		encodeNull(av)
+	}
+
+	return nil
+}
+
+// encodeMapKey returns the attribute name of a map key. Keys of types
+// implementing encoding.TextMarshaler are marshaled as their text, unless
+// the key is a string kind.
+func encodeMapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if tm, ok := key.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	return fmt.Sprint(key.Interface()), nil
+}
+
+// encodeMapSet encodes the keys of a map with struct{} or bool values as
+// a string or number set. Keys with a false bool value are not members of
+// the set.
+func (e *Encoder) encodeMapSet(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	if !isSetMapType(v.Type()) {
+		return &InvalidMarshalError{
+			msg: "set map must have struct{} or bool values, " + v.Type().String(),
+		}
+	}
+
+	if fieldTag.AsNumSet {
+		e = e.numberSetEncoder()
+	}
+
+	members := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		if v.Type().Elem().Kind() == reflect.Bool && !v.MapIndex(key).Bool() {
+			continue
+		}
+
+		elem := dynamodb.AttributeValue{}
+		if err := e.encode(&elem, key, tag{}); err != nil {
+			return err
+		}
+		if fieldTag.AsNumSet {
+			if elem.N == nil {
+				return &InvalidMarshalError{msg: "number set must only contain non-nil string numbers"}
+			}
+			members = append(members, *elem.N)
+		} else {
+			if elem.S == nil {
+				return &InvalidMarshalError{msg: "string set must only contain non-nil strings"}
+			}
+			members = append(members, *elem.S)
+		}
+	}
+	if len(members) == 0 {
+		encodeNull(av)
+		return nil
+	}
+
+	// Map iteration order is random, sort so the set is marshaled the same
+	// each time.
+	sort.Strings(members)
+	set := make([]*string, len(members))
+	for i := range members {
+		set[i] = &members[i]
+	}
+	if fieldTag.AsNumSet {
+		av.NS = set
+	} else {
+		av.SS = set
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeSlice(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	switch v.Type().Elem().Kind() {
+	case reflect.Uint8:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		if len(b) == 0 {
+			encodeNull(av)
+			return nil
+		}
+		av.B = b
+
+	default:
+		var elemFn func(*dynamodb.AttributeValue) error
+
+		if fieldTag.AsBinSet || v.Type() == byteSliceSlicetype { // Binary Set
+			av.BS = make([][]byte, 0, v.Len())
+			elemFn = func(elem *dynamodb.AttributeValue) error {
+				if elem.B == nil {
+					return &InvalidMarshalError{msg: "binary set must only contain non-nil byte slices"}
+				}
+				av.BS = append(av.BS, elem.B)
+				return nil
+			}
+		} else if fieldTag.AsNumSet { // Number Set
+			e = e.numberSetEncoder()
+			av.NS = make([]*string, 0, v.Len())
+			elemFn = func(elem *dynamodb.AttributeValue) error {
+				if elem.N == nil {
+					return &InvalidMarshalError{msg: "number set must only contain non-nil string numbers"}
+				}
+				av.NS = append(av.NS, elem.N)
+				return nil
+			}
+		} else if fieldTag.AsStrSet { // String Set
+			av.SS = make([]*string, 0, v.Len())
+			elemFn = func(elem *dynamodb.AttributeValue) error {
+				if elem.S == nil {
+					return &InvalidMarshalError{msg: "string set must only contain non-nil strings"}
+				}
+				av.SS = append(av.SS, elem.S)
+				return nil
+			}
+		} else { // List
+			if err := e.checkDepth(); err != nil {
+				return err
+			}
+			av.L = make([]*dynamodb.AttributeValue, 0, v.Len())
+			elemFn = func(elem *dynamodb.AttributeValue) error {
+				av.L = append(av.L, elem)
+				return nil
+			}
+		}
+
+		if n, err := e.encodeList(v, fieldTag, elemFn); err != nil {
+			return err
+		} else if n == 0 {
+			encodeNull(av)
+		}
+	}
+
+	return nil
+}
+
+// numberSetEncoder returns the Encoder to marshal the members of a number
+// set with, which does not marshal numbers as strings.
+func (e *Encoder) numberSetEncoder() *Encoder {
+	if !e.NumbersAsStrings {
+		return e
+	}
+
+	set := *e
+	set.NumbersAsStrings = false
+	return &set
+}
+
+func (e *Encoder) encodeList(v reflect.Value, fieldTag tag, elemFn func(*dynamodb.AttributeValue) error) (int, error) {
+	// Allocate the element AttributeValues together instead of individually.
+	elems := make([]dynamodb.AttributeValue, v.Len())
+
+	count := 0
+	for i := 0; i < v.Len(); i++ {
+		elem := &elems[i]
+		e.depth++
+		err := e.encode(elem, v.Index(i), tag{OmitEmpty: fieldTag.OmitEmptyElem})
+		e.depth--
+		skip, err := e.keepOrOmitEmpty(fieldTag.OmitEmptyElem, elem, err)
+		if err != nil {
+			return 0, prefixEncodePath(err, "["+strconv.Itoa(i)+"]")
+		} else if skip {
+			continue
+		}
+
+		if err := elemFn(elem); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (e *Encoder) encodeScalar(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		av.BOOL = new(bool)
+		*av.BOOL = v.Bool()
+
+	case reflect.String:
+		if v.Type() != numberType {
+			e.encodeString(av, v)
+			break
+		}
+		fallthrough
+
+	default:
+		// Fallback to encoding numbers, will return invalid type if not supported
+		if err := e.encodeNumber(av, v); err != nil {
+			return err
+		}
+		if (fieldTag.AsString || e.NumbersAsStrings) && av.N != nil {
+			av.S = av.N
+			av.N = nil
+		}
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeNumber(av *dynamodb.AttributeValue, v reflect.Value) error {
+	var out string
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out = encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		out = encodeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		out = encodeFloat(v.Float(), v.Type().Bits())
+	case reflect.String:
+		if v.Len() == 0 {
+			encodeNull(av)
+			return nil
+		}
+		out = v.String()
+	default:
+		return &unsupportedMarshalTypeError{Type: v.Type()}
+	}
+
+	av.N = &out
+
+	return nil
+}
+
+func (e *Encoder) encodeString(av *dynamodb.AttributeValue, v reflect.Value) {
+	s := v.String()
+	if len(s) == 0 && e.NullEmptyString {
+		encodeNull(av)
+		return
+	}
+
+	av.S = &s
+}
+
+func encodeInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+func encodeUint(u uint64) string {
+	return strconv.FormatUint(u, 10)
+}
+func encodeFloat(f float64, bitSize int) string {
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
+
+// isSetMapType returns if the map type t can be marshaled as a set, with
+// the map's keys being the members of the set.
+func isSetMapType(t reflect.Type) bool {
+	switch elem := t.Elem(); elem.Kind() {
+	case reflect.Bool:
+		return true
+	case reflect.Struct:
+		return elem.NumField() == 0
+	}
+	return false
+}
+
+func encodeNull(av *dynamodb.AttributeValue) {
+	t := true
+	*av = dynamodb.AttributeValue{NULL: &t}
+}
+
+func valueElem(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// emptyValue returns if the value is empty for the omitempty tag option.
+// Values with an IsZero method, such as time.Time, are empty if it returns
+// true. Pointers and interfaces are only empty if they are nil.
+func emptyValue(v reflect.Value) bool {
+	if k := v.Kind(); k != reflect.Ptr && k != reflect.Interface {
+		if zero, ok := isZeroByMethod(v); ok {
+			return zero
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (e *Encoder) tryMarshaler(av *dynamodb.AttributeValue, v reflect.Value) (bool, error) {
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	if v.Type().NumMethod() == 0 || !v.CanInterface() {
+		return false, nil
+	}
+
+	if m, ok := v.Interface().(MarshalerV2); ok {
+		return true, m.MarshalDynamoDBAttributeValueWithEncoder(e.marshalerEncoder(), av)
+	}
+	if m, ok := v.Interface().(Marshaler); ok {
+		return true, m.MarshalDynamoDBAttributeValue(av)
+	}
+	if m, ok := v.Interface().(SetMarshaler); ok {
+		return true, encodeSet(av, m)
+	}
+
+	return false, nil
+}
+
+// includeField returns if the field of the outermost struct with the
+// attribute name is marshaled by the Encoder's IncludeFields and
+// ExcludeFields options.
+func (e *Encoder) includeField(name string) bool {
+	if e.IncludeFields != nil && !containsString(e.IncludeFields, name) {
+		return false
+	}
+	return !containsString(e.ExcludeFields, name)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Encoder) keepOrOmitEmpty(omitEmpty bool, av *dynamodb.AttributeValue, err error) (bool, error) {
+	if err != nil {
+		if ute, ok := err.(*unsupportedMarshalTypeError); ok {
+			if e.StrictTypes {
+				return false, &UnsupportedTypeError{Type: ute.Type}
+			}
+			return true, nil
+		}
+		return false, err
+	}
+
+	if av.NULL != nil && omitEmpty {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// An InvalidMarshalError is an error type representing an error
+// occurring when marshaling a Go value type to an AttributeValue.
+type InvalidMarshalError struct {
+	emptyOrigError
+	msg string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidMarshalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidMarshalError) Code() string {
+	return "InvalidMarshalError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidMarshalError) Message() string {
+	return e.msg
+}
+
+// An UnsupportedTypeError is an error type representing a value of a Go
+// type which cannot be marshaled, returned by an Encoder with StrictTypes
+// enabled.
+type UnsupportedTypeError struct {
+	emptyOrigError
+
+	// Document path of the value, e.g. "Handlers[2]".
+	Path string
+
+	// Go value type which is not supported.
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnsupportedTypeError) Code() string {
+	return "UnsupportedTypeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnsupportedTypeError) Message() string {
+	return "Go value type " + e.Type.String() + " is not supported, " + e.Path
+}
+
+// prefixEncodePath prepends the path segment to the Path of the errors
+// returned while encoding which report the document path of the value,
+// UnsupportedTypeError, LimitExceededError, CycleError, and
+// DuplicateAttributeError. Other errors are returned unchanged.
+func prefixEncodePath(err error, segment string) error {
+	var path *string
+	switch e := err.(type) {
+	case *UnsupportedTypeError:
+		path = &e.Path
+	case *LimitExceededError:
+		path = &e.Path
+	case *CycleError:
+		path = &e.Path
+	case *DuplicateAttributeError:
+		path = &e.Path
+	default:
+		return err
+	}
+
+	switch {
+	case len(*path) == 0:
+		*path = segment
+	case (*path)[0] == '[':
+		*path = segment + *path
+	default:
+		*path = segment + "." + *path
+	}
+
+	return err
+}
+
+// An unsupportedMarshalTypeError represents a Go value type
+// which cannot be marshaled into an AttributeValue and should
+// be skipped by the marshaler.
+type unsupportedMarshalTypeError struct {
+	emptyOrigError
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *unsupportedMarshalTypeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *unsupportedMarshalTypeError) Code() string {
+	return "unsupportedMarshalTypeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *unsupportedMarshalTypeError) Message() string {
+	return "Go value type " + e.Type.String() + " is not supported"
+}
@@ -0,0 +1,549 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestMarshalErrorTypes(t *testing.T) {
+	var _ awserr.Error = (*InvalidMarshalError)(nil)
+	var _ awserr.Error = (*unsupportedMarshalTypeError)(nil)
+}
+
+func TestMarshalShared(t *testing.T) {
+	for i, c := range sharedTestCases {
+		av, err := Marshal(c.expected)
+		assertConvertTest(t, i, av, c.in, err, c.err)
+	}
+}
+
+func TestMarshalListShared(t *testing.T) {
+	for i, c := range sharedListTestCases {
+		av, err := MarshalList(c.expected)
+		assertConvertTest(t, i, av, c.in, err, c.err)
+	}
+}
+
+func TestMarshalMapShared(t *testing.T) {
+	for i, c := range sharedMapTestCases {
+		av, err := MarshalMap(c.expected)
+		assertConvertTest(t, i, av, c.in, err, c.err)
+	}
+}
+
+type marshalMarshaler struct {
+	Value  string
+	Value2 int
+	Value3 bool
+	Value4 time.Time
+}
+
+func (m *marshalMarshaler) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	av.M = map[string]*dynamodb.AttributeValue{
+		"abc": {S: &m.Value},
+		"def": {N: aws.String(fmt.Sprintf("%d", m.Value2))},
+		"ghi": {BOOL: &m.Value3},
+		"jkl": {S: aws.String(m.Value4.Format(time.RFC3339Nano))},
+	}
+
+	return nil
+}
+
+func TestMarshalMashaler(t *testing.T) {
+	m := &marshalMarshaler{
+		Value:  "value",
+		Value2: 123,
+		Value3: true,
+		Value4: testDate,
+	}
+
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"abc": {S: aws.String("value")},
+			"def": {N: aws.String("123")},
+			"ghi": {BOOL: aws.Bool(true)},
+			"jkl": {S: aws.String("2016-05-03T17:06:26.209072Z")},
+		},
+	}
+
+	actual, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+type testOmitEmptyElemListStruct struct {
+	Values []string `dynamodbav:",omitemptyelem"`
+}
+
+type testOmitEmptyElemMapStruct struct {
+	Values map[string]interface{} `dynamodbav:",omitemptyelem"`
+}
+
+func TestMarshalListOmitEmptyElem(t *testing.T) {
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Values": {L: []*dynamodb.AttributeValue{
+				{S: aws.String("abc")},
+				{S: aws.String("123")},
+			}},
+		},
+	}
+
+	m := testOmitEmptyElemListStruct{Values: []string{"abc", "", "123"}}
+
+	actual, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestMarshalMapOmitEmptyElem(t *testing.T) {
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Values": {M: map[string]*dynamodb.AttributeValue{
+				"abc": {N: aws.String("123")},
+				"klm": {S: aws.String("abc")},
+			}},
+		},
+	}
+
+	m := testOmitEmptyElemMapStruct{Values: map[string]interface{}{
+		"abc": 123.,
+		"efg": nil,
+		"hij": "",
+		"klm": "abc",
+	}}
+
+	actual, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestMarshalSkipsUnsupportedTypes(t *testing.T) {
+	type testUnsupportedStruct struct {
+		Value  string
+		Chan   chan int
+		Func   func()
+		Value2 int
+	}
+
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Value":  {S: aws.String("abc")},
+			"Value2": {N: aws.String("123")},
+		},
+	}
+
+	actual, err := Marshal(testUnsupportedStruct{
+		Value: "abc", Chan: make(chan int), Func: func() {}, Value2: 123,
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestMarshalEmbeddedStruct(t *testing.T) {
+	type B struct {
+		Bint int
+	}
+	type a struct {
+		Aint int
+		B
+		*unexportedEmbedded
+	}
+
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Aint":  {N: aws.String("321")},
+			"Bint":  {N: aws.String("123")},
+			"Value": {S: aws.String("abc")},
+		},
+	}
+
+	actual, err := Marshal(a{
+		Aint:               321,
+		B:                  B{Bint: 123},
+		unexportedEmbedded: &unexportedEmbedded{Value: "abc"},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+type unexportedEmbedded struct {
+	Value string
+}
+
+func TestMarshalWithOptions(t *testing.T) {
+	type testRecord struct {
+		Value string
+		Tags  []string
+	}
+	in := testRecord{Tags: []string{""}}
+
+	disableNull := func(e *Encoder) {
+		e.NullEmptyString = false
+	}
+
+	av, err := MarshalWithOptions(in, disableNull)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"Value": {S: aws.String("")},
+			"Tags":  {L: []*dynamodb.AttributeValue{{S: aws.String("")}}},
+		},
+	}
+	if !reflect.DeepEqual(expect, av) {
+		t.Errorf("expect %v, got %v", expect, av)
+	}
+
+	m, err := MarshalMapWithOptions(in, disableNull)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect.M, m) {
+		t.Errorf("expect %v, got %v", expect.M, m)
+	}
+
+	l, err := MarshalListWithOptions(in.Tags, disableNull)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect.M["Tags"].L, l) {
+		t.Errorf("expect %v, got %v", expect.M["Tags"].L, l)
+	}
+}
+
+func TestEncoderReset(t *testing.T) {
+	e := NewEncoder(func(e *Encoder) {
+		e.SupportJSONTags = false
+		e.NullEmptyString = false
+	})
+	e.Reset()
+
+	if e, a := NewEncoder(), e; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestMarshalPooledEncoderOptions(t *testing.T) {
+	_, err := MarshalWithOptions("", func(e *Encoder) {
+		e.NullEmptyString = false
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Options must not leak to subsequent marshals from the pool.
+	av, err := Marshal("")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if av.NULL == nil || !*av.NULL {
+		t.Errorf("expect NULL, got %v", av)
+	}
+}
+
+type benchmarkMarshalItem struct {
+	ID      string
+	Count   int
+	Price   float64
+	Enabled bool
+	Tags    []string `dynamodbav:",stringset"`
+	Values  []int
+	Attrs   map[string]string
+	Nested  benchmarkMarshalNested
+}
+
+type benchmarkMarshalNested struct {
+	Name  string
+	Value int64
+}
+
+func BenchmarkMarshalMap(b *testing.B) {
+	item := benchmarkMarshalItem{
+		ID:      "abc123",
+		Count:   123,
+		Price:   12.34,
+		Enabled: true,
+		Tags:    []string{"a", "b", "c"},
+		Values:  []int{1, 2, 3, 4, 5},
+		Attrs:   map[string]string{"a": "1", "b": "2"},
+		Nested:  benchmarkMarshalNested{Name: "nested", Value: 1234},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalMap(item); err != nil {
+			b.Fatalf("expect no error, got %v", err)
+		}
+	}
+}
+
+type testMapSetStruct struct {
+	Strings map[string]struct{} `dynamodbav:",stringset"`
+	Flags   map[string]bool     `dynamodbav:",stringset"`
+	Numbers map[int]struct{}    `dynamodbav:",numberset"`
+	Empty   map[string]bool     `dynamodbav:",stringset"`
+}
+
+func TestMarshalMapSet(t *testing.T) {
+	in := testMapSetStruct{
+		Strings: map[string]struct{}{"b": {}, "a": {}, "c": {}},
+		Flags:   map[string]bool{"on": true, "off": false},
+		Numbers: map[int]struct{}{10: {}, 2: {}},
+		Empty:   map[string]bool{"off": false},
+	}
+
+	actual, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"Strings": {SS: []*string{aws.String("a"), aws.String("b"), aws.String("c")}},
+		"Flags":   {SS: []*string{aws.String("on")}},
+		"Numbers": {NS: []*string{aws.String("10"), aws.String("2")}},
+		"Empty":   {NULL: aws.Bool(true)},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestMarshalMapSetInvalid(t *testing.T) {
+	cases := []interface{}{
+		struct {
+			Set map[string]int `dynamodbav:",stringset"`
+		}{Set: map[string]int{"a": 1}},
+		struct {
+			Set map[string]struct{} `dynamodbav:",numberset"`
+		}{Set: map[string]struct{}{"a": {}}},
+		struct {
+			Set map[string]struct{} `dynamodbav:",stringset"`
+		}{Set: map[string]struct{}{"": {}}},
+	}
+
+	for i, c := range cases {
+		_, err := Marshal(c)
+		if err == nil {
+			t.Fatalf("%d, expect error, got none", i)
+		}
+		if _, ok := err.(*InvalidMarshalError); !ok {
+			t.Errorf("%d, expect InvalidMarshalError, got %T", i, err)
+		}
+	}
+}
+
+func TestEncoderStrictTypes(t *testing.T) {
+	type handler struct {
+		Name string
+		Fn   func()
+	}
+	type service struct {
+		Handlers []handler
+		Events   map[string]chan int
+	}
+
+	cases := []struct {
+		in   interface{}
+		path string
+	}{
+		{service{Handlers: []handler{{Name: "a", Fn: func() {}}}}, "Handlers[0].Fn"},
+		{service{Events: map[string]chan int{"created": make(chan int)}}, "Events.created"},
+	}
+
+	for i, c := range cases {
+		// Unsupported types are skipped by default.
+		if _, err := Marshal(c.in); err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+
+		_, err := MarshalWithOptions(c.in, func(e *Encoder) {
+			e.StrictTypes = true
+		})
+		ute, ok := err.(*UnsupportedTypeError)
+		if !ok {
+			t.Errorf("%d, expect UnsupportedTypeError, got %T, %v", i, err, err)
+			continue
+		}
+		if e, a := c.path, ute.Path; e != a {
+			t.Errorf("%d, expect %q path, got %q", i, e, a)
+		}
+	}
+}
+
+func TestEncoderNumbersAsStrings(t *testing.T) {
+	type testRecord struct {
+		ID     string
+		Count  int
+		Ratio  float64
+		Small  uint8
+		Exact  Number
+		Counts map[string]int
+		List   []int
+		Set    []int            `dynamodbav:",numberset"`
+		MapSet map[int]struct{} `dynamodbav:",numberset"`
+	}
+	in := testRecord{
+		ID: "abc", Count: -12, Ratio: 0.5, Small: 3, Exact: "12.50",
+		Counts: map[string]int{"a": 1}, List: []int{1, 2}, Set: []int{7},
+		MapSet: map[int]struct{}{8: {}},
+	}
+
+	av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+		e.NumbersAsStrings = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{
+		"ID":    {S: aws.String("abc")},
+		"Count": {S: aws.String("-12")},
+		"Ratio": {S: aws.String("0.5")},
+		"Small": {S: aws.String("3")},
+		"Exact": {S: aws.String("12.50")},
+		"Counts": {M: map[string]*dynamodb.AttributeValue{
+			"a": {S: aws.String("1")},
+		}},
+		"List": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("1")}, {S: aws.String("2")},
+		}},
+		"Set":    {NS: []*string{aws.String("7")}},
+		"MapSet": {NS: []*string{aws.String("8")}},
+	}
+	if !reflect.DeepEqual(expect, av) {
+		t.Errorf("expect %v, got %v", expect, av)
+	}
+
+	var actual testRecord
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.NumbersAsStrings = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(in, actual) {
+		t.Errorf("expect %v, got %v", in, actual)
+	}
+
+	if err := UnmarshalMap(av, &actual); err == nil {
+		t.Errorf("expect error unmarshaling strings into numbers by default")
+	}
+}
+
+func TestEncoderFieldFilter(t *testing.T) {
+	type address struct {
+		ID   string
+		City string
+	}
+	type order struct {
+		ID      string
+		Status  string
+		Total   int
+		Address address
+	}
+	in := order{ID: "abc", Status: "NEW", Total: 5, Address: address{ID: "home", City: "Seattle"}}
+
+	cases := []struct {
+		include, exclude []string
+		expect           []string
+	}{
+		{nil, nil, []string{"Address", "ID", "Status", "Total"}},
+		{[]string{"Status", "Total"}, nil, []string{"Status", "Total"}},
+		{nil, []string{"ID"}, []string{"Address", "Status", "Total"}},
+		{[]string{"ID", "Address", "Unknown"}, []string{"ID"}, []string{"Address"}},
+		{[]string{}, nil, nil},
+	}
+
+	for i, c := range cases {
+		av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+			e.IncludeFields = c.include
+			e.ExcludeFields = c.exclude
+		})
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+
+		var names []string
+		for _, name := range []string{"Address", "ID", "Status", "Total"} {
+			if _, ok := av[name]; ok {
+				names = append(names, name)
+			}
+		}
+		if e, a := c.expect, names; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v attributes, got %v", i, e, a)
+		}
+		if addr, ok := av["Address"]; ok {
+			if _, ok := addr.M["ID"]; !ok {
+				t.Errorf("%d, expect nested struct fields not to be filtered", i)
+			}
+		}
+	}
+}
+
+func TestMarshalValueMap(t *testing.T) {
+	values, err := MarshalValueMap(map[string]interface{}{
+		"status": "SHIPPED",
+		":limit": 10,
+		"tags":   []string{"a"},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		":status": {S: aws.String("SHIPPED")},
+		":limit":  {N: aws.String("10")},
+		":tags":   {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+	}
+	if e, a := expect, values; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestMarshalValueMapInvalid(t *testing.T) {
+	cases := []struct {
+		in     map[string]interface{}
+		expect string
+	}{
+		{map[string]interface{}{":": 1}, `InvalidMarshalError: invalid expression attribute value placeholder ":"`},
+		{map[string]interface{}{"a-b": 1}, `InvalidMarshalError: invalid expression attribute value placeholder "a-b"`},
+		{map[string]interface{}{"#a": 1}, `InvalidMarshalError: invalid expression attribute value placeholder "#a"`},
+		{map[string]interface{}{"a": 1, ":a": 2}, `InvalidMarshalError: duplicate expression attribute value :a`},
+	}
+
+	for i, c := range cases {
+		_, err := MarshalValueMap(c.in)
+		if err == nil {
+			t.Errorf("%d, expect error, got none", i)
+		} else if e, a := c.expect, err.Error(); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MaterialDescriptionAttribute is the name of the attribute the material
+// descriptions of a struct's encrypted fields are stored in. The attribute
+// is a map of each encrypted attribute's name to its material description.
+const MaterialDescriptionAttribute = "*amzn-ddb-map-desc*"
+
+// An EncryptionProvider encrypts and decrypts the attributes of struct
+// fields with the `encrypted` tag option. The provider is responsible for
+// key management, such as wrapping data keys with AWS KMS.
+//
+// The plaintext of an attribute is its DynamoDB wire JSON, so fields of any
+// type can be encrypted. The ciphertext is stored as a binary attribute, and
+// the material description returned by Encrypt is stored in the
+// MaterialDescriptionAttribute attribute of the item, to be passed back to
+// Decrypt.
+type EncryptionProvider interface {
+	// Encrypt returns the ciphertext of the named attribute's plaintext,
+	// and the material description needed to decrypt it, e.g. the
+	// encrypted data key and algorithm.
+	Encrypt(name string, plaintext []byte) (ciphertext []byte, desc map[string]string, err error)
+
+	// Decrypt returns the plaintext of the named attribute's ciphertext.
+	Decrypt(name string, ciphertext []byte, desc map[string]string) ([]byte, error)
+}
+
+// encryptField replaces the AttributeValue of the encrypted field name with
+// its ciphertext, returning the material description. NULL values are not
+// encrypted.
+func (e *Encoder) encryptField(av *dynamodb.AttributeValue, name string) (map[string]string, error) {
+	if av.NULL != nil {
+		return nil, nil
+	}
+	if e.Encryption == nil {
+		return nil, &InvalidMarshalError{
+			msg: fmt.Sprintf("encrypted field %s requires an EncryptionProvider", name),
+		}
+	}
+
+	plaintext, err := MarshalWireJSON(av)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, desc, err := e.Encryption.Encrypt(name, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	*av = dynamodb.AttributeValue{B: ciphertext}
+	return desc, nil
+}
+
+// encodeMaterialDescriptions adds the material descriptions of a struct's
+// encrypted fields to its AttributeValue map.
+func encodeMaterialDescriptions(av *dynamodb.AttributeValue, descs map[string]map[string]string) {
+	m := make(map[string]*dynamodb.AttributeValue, len(descs))
+	for name, desc := range descs {
+		descAV := &dynamodb.AttributeValue{M: make(map[string]*dynamodb.AttributeValue, len(desc))}
+		for k, v := range desc {
+			v := v
+			descAV.M[k] = &dynamodb.AttributeValue{S: &v}
+		}
+		m[name] = descAV
+	}
+	av.M[MaterialDescriptionAttribute] = &dynamodb.AttributeValue{M: m}
+}
+
+// decryptField returns the plaintext AttributeValue of the encrypted field's
+// attribute av, with the attribute name and Go type t. NULL values are
+// returned as is.
+func (d *Decoder) decryptField(avMap map[string]*dynamodb.AttributeValue, av *dynamodb.AttributeValue, name string, t reflect.Type) (*dynamodb.AttributeValue, error) {
+	if av == nil || av.NULL != nil {
+		return av, nil
+	}
+	if av.B == nil {
+		return nil, &UnmarshalTypeError{Value: "encrypted attribute without binary ciphertext", Type: t}
+	}
+	if d.Encryption == nil {
+		return nil, &InvalidMarshalError{
+			msg: fmt.Sprintf("encrypted attribute %s requires an EncryptionProvider", name),
+		}
+	}
+
+	var desc map[string]string
+	if descs, ok := avMap[MaterialDescriptionAttribute]; ok && descs != nil {
+		if descAV, ok := descs.M[name]; ok && descAV != nil {
+			desc = make(map[string]string, len(descAV.M))
+			for k, v := range descAV.M {
+				if v != nil && v.S != nil {
+					desc[k] = *v.S
+				}
+			}
+		}
+	}
+
+	plaintext, err := d.Encryption.Decrypt(name, av.B, desc)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalWireJSON(plaintext)
+}
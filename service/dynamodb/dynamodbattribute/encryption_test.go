@@ -0,0 +1,109 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// testXORProvider is a reversible, insecure provider for testing.
+type testXORProvider struct {
+	key byte
+}
+
+func (p testXORProvider) xor(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[i] ^ p.key
+	}
+	return out
+}
+
+func (p testXORProvider) Encrypt(name string, plaintext []byte) ([]byte, map[string]string, error) {
+	return p.xor(plaintext), map[string]string{"keyId": fmt.Sprintf("xor-%d", p.key)}, nil
+}
+
+func (p testXORProvider) Decrypt(name string, ciphertext []byte, desc map[string]string) ([]byte, error) {
+	if e, a := fmt.Sprintf("xor-%d", p.key), desc["keyId"]; e != a {
+		return nil, fmt.Errorf("expect %s key, got %s", e, a)
+	}
+	return p.xor(ciphertext), nil
+}
+
+type testEncryptedItem struct {
+	ID      string
+	SSN     string            `dynamodbav:",encrypted"`
+	Details map[string]string `dynamodbav:",encrypted"`
+	Empty   *string           `dynamodbav:",encrypted"`
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	provider := testXORProvider{key: 42}
+	in := testEncryptedItem{
+		ID:      "abc",
+		SSN:     "123-45-6789",
+		Details: map[string]string{"dob": "2000-01-01"},
+	}
+
+	av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+		e.Encryption = provider
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "abc", aws.StringValue(av["ID"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	for _, name := range []string{"SSN", "Details"} {
+		if av[name].B == nil || bytes.Contains(av[name].B, []byte(in.SSN)) {
+			t.Errorf("expect %s encrypted, got %v", name, av[name])
+		}
+	}
+	if e, a := true, aws.BoolValue(av["Empty"].NULL); e != a {
+		t.Errorf("expect NULL not encrypted, got %v", av["Empty"])
+	}
+	expectDesc := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"SSN":     {M: map[string]*dynamodb.AttributeValue{"keyId": {S: aws.String("xor-42")}}},
+		"Details": {M: map[string]*dynamodb.AttributeValue{"keyId": {S: aws.String("xor-42")}}},
+	}}
+	if e, a := expectDesc, av[MaterialDescriptionAttribute]; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var actual testEncryptedItem
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.Encryption = provider
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Decrypting with the wrong key fails.
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.Encryption = testXORProvider{key: 7}
+	})
+	if err == nil {
+		t.Errorf("expect error")
+	}
+}
+
+func TestEncryptionMissingProvider(t *testing.T) {
+	in := testEncryptedItem{ID: "abc", SSN: "123-45-6789"}
+	if _, err := MarshalMap(in); err == nil {
+		t.Errorf("expect marshal error")
+	}
+
+	av := map[string]*dynamodb.AttributeValue{"SSN": {B: []byte{1}}}
+	var actual testEncryptedItem
+	if err := UnmarshalMap(av, &actual); err == nil {
+		t.Errorf("expect unmarshal error")
+	}
+}
@@ -0,0 +1,136 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Equal returns if the AttributeValues a and b are semantically equal, in
+// the same way DynamoDB compares values.
+//
+// Numbers are compared by value, so "1", "1.0", and "10E-1" are equal. Sets
+// are compared without regard to the order of their members. Lists are
+// compared in order, and maps by key.
+func Equal(a, b *dynamodb.AttributeValue) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if (a.B == nil) != (b.B == nil) || !bytes.Equal(a.B, b.B) {
+		return false
+	}
+	if (a.BOOL == nil) != (b.BOOL == nil) || a.BOOL != nil && *a.BOOL != *b.BOOL {
+		return false
+	}
+	if (a.NULL == nil) != (b.NULL == nil) || a.NULL != nil && *a.NULL != *b.NULL {
+		return false
+	}
+	if (a.S == nil) != (b.S == nil) || a.S != nil && *a.S != *b.S {
+		return false
+	}
+	if (a.N == nil) != (b.N == nil) || a.N != nil && canonicalNumber(*a.N) != canonicalNumber(*b.N) {
+		return false
+	}
+
+	if (a.BS == nil) != (b.BS == nil) || !equalBinarySet(a.BS, b.BS) {
+		return false
+	}
+	if (a.NS == nil) != (b.NS == nil) || !equalStringSet(a.NS, b.NS, canonicalNumber) {
+		return false
+	}
+	if (a.SS == nil) != (b.SS == nil) || !equalStringSet(a.SS, b.SS, nil) {
+		return false
+	}
+
+	if (a.L == nil) != (b.L == nil) || !equalList(a.L, b.L) {
+		return false
+	}
+	if (a.M == nil) != (b.M == nil) || !equalMap(a.M, b.M) {
+		return false
+	}
+
+	return true
+}
+
+func equalList(a, b []*dynamodb.AttributeValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalMap(a, b map[string]*dynamodb.AttributeValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok || !Equal(av, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalNumber returns the number string in a form which compares equal
+// for equal values. Strings which are not valid numbers are returned as is.
+func canonicalNumber(n string) string {
+	r, ok := new(big.Rat).SetString(n)
+	if !ok {
+		return n
+	}
+	return r.RatString()
+}
+
+// equalStringSet returns if the string sets contain the same members, after
+// each member is passed through the canonical func if set.
+func equalStringSet(a, b []*string, canonical func(string) string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	as, bs := make([]string, len(a)), make([]string, len(b))
+	for i := range a {
+		as[i], bs[i] = aws.StringValue(a[i]), aws.StringValue(b[i])
+		if canonical != nil {
+			as[i], bs[i] = canonical(as[i]), canonical(bs[i])
+		}
+	}
+
+	return equalSortedStrings(as, bs)
+}
+
+func equalBinarySet(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	as, bs := make([]string, len(a)), make([]string, len(b))
+	for i := range a {
+		as[i], bs[i] = string(a[i]), string(b[i])
+	}
+
+	return equalSortedStrings(as, bs)
+}
+
+// equalSortedStrings sorts a and b, returning if they contain the same
+// strings.
+func equalSortedStrings(a, b []string) bool {
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
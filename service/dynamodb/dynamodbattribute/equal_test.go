@@ -0,0 +1,89 @@
+package dynamodbattribute
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		a, b   *dynamodb.AttributeValue
+		expect bool
+	}{
+		{nil, nil, true},
+		{nil, &dynamodb.AttributeValue{NULL: aws.Bool(true)}, false},
+		{&dynamodb.AttributeValue{S: aws.String("abc")}, &dynamodb.AttributeValue{S: aws.String("abc")}, true},
+		{&dynamodb.AttributeValue{S: aws.String("abc")}, &dynamodb.AttributeValue{S: aws.String("abd")}, false},
+		{&dynamodb.AttributeValue{S: aws.String("1")}, &dynamodb.AttributeValue{N: aws.String("1")}, false},
+		{&dynamodb.AttributeValue{N: aws.String("1")}, &dynamodb.AttributeValue{N: aws.String("1.0")}, true},
+		{&dynamodb.AttributeValue{N: aws.String("0.1")}, &dynamodb.AttributeValue{N: aws.String("1E-1")}, true},
+		{&dynamodb.AttributeValue{N: aws.String("-0")}, &dynamodb.AttributeValue{N: aws.String("0")}, true},
+		{&dynamodb.AttributeValue{N: aws.String("1")}, &dynamodb.AttributeValue{N: aws.String("1.01")}, false},
+		{&dynamodb.AttributeValue{B: []byte{1, 2}}, &dynamodb.AttributeValue{B: []byte{1, 2}}, true},
+		{&dynamodb.AttributeValue{B: []byte{1, 2}}, &dynamodb.AttributeValue{B: []byte{2, 1}}, false},
+		{&dynamodb.AttributeValue{BOOL: aws.Bool(true)}, &dynamodb.AttributeValue{BOOL: aws.Bool(true)}, true},
+		{&dynamodb.AttributeValue{BOOL: aws.Bool(true)}, &dynamodb.AttributeValue{BOOL: aws.Bool(false)}, false},
+		{&dynamodb.AttributeValue{NULL: aws.Bool(true)}, &dynamodb.AttributeValue{NULL: aws.Bool(true)}, true},
+		{
+			&dynamodb.AttributeValue{SS: []*string{aws.String("a"), aws.String("b")}},
+			&dynamodb.AttributeValue{SS: []*string{aws.String("b"), aws.String("a")}},
+			true,
+		},
+		{
+			&dynamodb.AttributeValue{SS: []*string{aws.String("a"), aws.String("b")}},
+			&dynamodb.AttributeValue{SS: []*string{aws.String("a")}},
+			false,
+		},
+		{
+			&dynamodb.AttributeValue{NS: []*string{aws.String("1"), aws.String("2.50")}},
+			&dynamodb.AttributeValue{NS: []*string{aws.String("2.5"), aws.String("1.0")}},
+			true,
+		},
+		{
+			&dynamodb.AttributeValue{BS: [][]byte{{1}, {2}}},
+			&dynamodb.AttributeValue{BS: [][]byte{{2}, {1}}},
+			true,
+		},
+		{
+			&dynamodb.AttributeValue{BS: [][]byte{{1}, {2}}},
+			&dynamodb.AttributeValue{BS: [][]byte{{1}, {3}}},
+			false,
+		},
+		{
+			&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{N: aws.String("1")}, {S: aws.String("a")}}},
+			&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{N: aws.String("1.0")}, {S: aws.String("a")}}},
+			true,
+		},
+		{
+			&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{N: aws.String("1")}, {S: aws.String("a")}}},
+			&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("a")}, {N: aws.String("1")}}},
+			false,
+		},
+		{
+			&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"a": {N: aws.String("10")}}},
+			&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"a": {N: aws.String("1e1")}}},
+			true,
+		},
+		{
+			&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"a": {N: aws.String("10")}}},
+			&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"b": {N: aws.String("10")}}},
+			false,
+		},
+		{
+			&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{}},
+			&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}},
+			false,
+		},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, Equal(c.a, c.b); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+		if e, a := c.expect, Equal(c.b, c.a); e != a {
+			t.Errorf("%d, expect %v reversed, got %v", i, e, a)
+		}
+	}
+}
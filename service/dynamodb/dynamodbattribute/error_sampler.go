@@ -0,0 +1,132 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// An ErrorSample is a marshal or unmarshal error reported by an
+// ErrorSampler.
+type ErrorSample struct {
+	// The error returned by the Encoder or Decoder.
+	Err error
+
+	// Code identifying the kind of error, the awserr.Error code if the
+	// error provides one, otherwise the error's Go type.
+	Code string
+
+	// Number of errors with the same code observed in the current
+	// interval, including this one and those which were not reported.
+	Count int
+}
+
+// An ErrorSampler reports a representative sample of the errors returned by
+// an Encoder or Decoder, so high volume services can monitor serialization
+// failures without flooding their logs.
+//
+// Within each interval the first error of each code is reported, and the
+// rate further errors of the code are reported at decays exponentially,
+// the 2nd, 4th, 8th, and so on. At most Max errors are reported per
+// interval across all codes.
+//
+//     sampler := dynamodbattribute.NewErrorSampler(10, func(s dynamodbattribute.ErrorSample) {
+//         log.Printf("%d %s errors, %v", s.Count, s.Code, s.Err)
+//     })
+//     decoder := dynamodbattribute.NewDecoder(func(d *dynamodbattribute.Decoder) {
+//         d.ErrorSampler = sampler
+//     })
+//
+// An ErrorSampler is safe to share between Encoders and Decoders used
+// concurrently. The Report function is called synchronously by the
+// Encoder or Decoder which returned the error.
+type ErrorSampler struct {
+	// Maximum number of errors reported per interval.
+	Max int
+
+	// Length of the sampling interval. Defaults to one minute.
+	Interval time.Duration
+
+	// Called with each error sampled.
+	Report func(ErrorSample)
+
+	// Returns the current time, used by tests.
+	now func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	reported    int
+	counts      map[string]int
+}
+
+// NewErrorSampler creates a new ErrorSampler which calls report with at
+// most max errors per minute. Use the `opts` functional options to override
+// the default configuration.
+func NewErrorSampler(max int, report func(ErrorSample), opts ...func(*ErrorSampler)) *ErrorSampler {
+	s := &ErrorSampler{
+		Max:      max,
+		Interval: time.Minute,
+		Report:   report,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Observe records the error, and reports it if it is sampled. Nil errors
+// and nil ErrorSamplers are ignored.
+func (s *ErrorSampler) Observe(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	sample, ok := s.sample(err)
+	if ok && s.Report != nil {
+		s.Report(sample)
+	}
+}
+
+func (s *ErrorSampler) sample(err error) (ErrorSample, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	t := now()
+	if s.counts == nil || t.Sub(s.windowStart) >= interval {
+		s.windowStart = t
+		s.reported = 0
+		s.counts = map[string]int{}
+	}
+
+	code := errorSampleCode(err)
+	s.counts[code]++
+	count := s.counts[code]
+
+	// Only powers of two are sampled, decaying the rate errors of the same
+	// code are reported at.
+	if count&(count-1) != 0 || s.reported >= s.Max {
+		return ErrorSample{}, false
+	}
+	s.reported++
+
+	return ErrorSample{Err: err, Code: code, Count: count}, true
+}
+
+func errorSampleCode(err error) string {
+	if c, ok := err.(interface {
+		Code() string
+	}); ok {
+		return c.Code()
+	}
+	return reflect.TypeOf(err).String()
+}
@@ -0,0 +1,117 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestErrorSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	var samples []ErrorSample
+	s := NewErrorSampler(3, func(s ErrorSample) {
+		samples = append(samples, s)
+	}, func(s *ErrorSampler) {
+		s.now = func() time.Time { return now }
+	})
+
+	for i := 0; i < 5; i++ {
+		s.Observe(&UnmarshalTypeError{Value: "number", Type: stringInterfaceMapType})
+	}
+	s.Observe(fmt.Errorf("other"))
+	s.Observe(&MissingAttributeError{Attributes: []string{"ID"}})
+	s.Observe(nil)
+
+	expect := []struct {
+		code  string
+		count int
+	}{
+		{"UnmarshalTypeError", 1},
+		{"UnmarshalTypeError", 2},
+		{"UnmarshalTypeError", 4},
+	}
+	if e, a := len(expect), len(samples); e != a {
+		t.Fatalf("expect %d samples, got %d, %v", e, a, samples)
+	}
+	for i, e := range expect {
+		if a := samples[i]; e.code != a.Code || e.count != a.Count {
+			t.Errorf("%d, expect %s %d, got %s %d", i, e.code, e.count, a.Code, a.Count)
+		}
+	}
+
+	// New interval resets the counts and budget.
+	now = now.Add(time.Minute)
+	samples = nil
+	s.Observe(fmt.Errorf("other"))
+	if e, a := 1, len(samples); e != a {
+		t.Fatalf("expect %d samples, got %d", e, a)
+	}
+	if e, a := "*errors.errorString", samples[0].Code; e != a {
+		t.Errorf("expect %q code, got %q", e, a)
+	}
+	if e, a := 1, samples[0].Count; e != a {
+		t.Errorf("expect %d count, got %d", e, a)
+	}
+}
+
+func TestErrorSamplerNil(t *testing.T) {
+	var s *ErrorSampler
+	s.Observe(fmt.Errorf("error"))
+}
+
+func TestDecoderErrorSampler(t *testing.T) {
+	var samples []ErrorSample
+	d := NewDecoder(func(d *Decoder) {
+		d.ErrorSampler = NewErrorSampler(10, func(s ErrorSample) {
+			samples = append(samples, s)
+		})
+	})
+
+	var v struct{ A int }
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"A": {S: aws.String("abc")},
+	}}
+	if err := d.Decode(av, &v); err == nil {
+		t.Fatalf("expect error")
+	}
+	if err := d.Decode(av, v); err == nil {
+		t.Fatalf("expect error")
+	}
+
+	if e, a := 2, len(samples); e != a {
+		t.Fatalf("expect %d samples, got %d", e, a)
+	}
+	if e, a := "UnmarshalTypeError", samples[0].Code; e != a {
+		t.Errorf("expect %q code, got %q", e, a)
+	}
+	if e, a := "InvalidUnmarshalError", samples[1].Code; e != a {
+		t.Errorf("expect %q code, got %q", e, a)
+	}
+}
+
+func TestEncoderErrorSampler(t *testing.T) {
+	var samples []ErrorSample
+	e := NewEncoder(func(e *Encoder) {
+		e.Limits = Limits{MaxElements: 1}
+		e.ErrorSampler = NewErrorSampler(10, func(s ErrorSample) {
+			samples = append(samples, s)
+		})
+	})
+
+	if _, err := e.Encode([]int{1, 2}); err == nil {
+		t.Fatalf("expect error")
+	}
+	if _, err := e.Encode([]int{1}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 1, len(samples); e != a {
+		t.Fatalf("expect %d samples, got %d", e, a)
+	}
+	if e, a := "LimitExceededError", samples[0].Code; e != a {
+		t.Errorf("expect %q code, got %q", e, a)
+	}
+}
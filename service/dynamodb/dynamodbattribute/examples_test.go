@@ -78,3 +78,75 @@ func ExampleConvertFrom() {
 	// Output:
 	// <nil> true
 }
+
+func ExampleMarshal() {
+	type Record struct {
+		Bytes   []byte
+		MyField string
+		Letters []string
+		A2Num   map[string]int
+	}
+
+	r := Record{
+		Bytes:   []byte{48, 49},
+		MyField: "MyFieldValue",
+		Letters: []string{"a", "b", "c", "d"},
+		A2Num:   map[string]int{"a": 1, "b": 2, "c": 3},
+	}
+	av, err := dynamodbattribute.Marshal(r)
+	fmt.Println("err", err)
+	fmt.Println("Bytes", av.M["Bytes"])
+	fmt.Println("MyField", av.M["MyField"])
+	fmt.Println("Letters", av.M["Letters"])
+	fmt.Println("A2Num", len(av.M["A2Num"].M))
+
+	// Output:
+	// err <nil>
+	// Bytes {
+	//   B: [48,49]
+	// }
+	// MyField {
+	//   S: "MyFieldValue"
+	// }
+	// Letters {
+	//   L: [
+	//     {
+	//       S: "a"
+	//     },
+	//     {
+	//       S: "b"
+	//     },
+	//     {
+	//       S: "c"
+	//     },
+	//     {
+	//       S: "d"
+	//     }
+	//   ]
+	// }
+	// A2Num 3
+}
+
+func ExampleUnmarshal() {
+	type Record struct {
+		Bytes   []byte
+		MyField string
+		Letters []string
+		A2Num   map[string]int
+	}
+
+	r := Record{
+		Bytes:   []byte{48, 49},
+		MyField: "MyFieldValue",
+		Letters: []string{"a", "b", "c", "d"},
+		A2Num:   map[string]int{"a": 1, "b": 2, "c": 3},
+	}
+	av, err := dynamodbattribute.Marshal(r)
+
+	r2 := Record{}
+	err = dynamodbattribute.Unmarshal(av, &r2)
+	fmt.Println(err, reflect.DeepEqual(r, r2))
+
+	// Output:
+	// <nil> true
+}
@@ -0,0 +1,128 @@
+package dynamodbattribute
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// An ExportDecoder reads the items of a DynamoDB table export in the
+// DYNAMODB_JSON format, one line per item, e.g.
+//
+//     {"Item":{"ID":{"S":"abc"},"Count":{"N":"1"}}}
+//
+// Items are read from the underlying reader one at a time, so exports much
+// larger than memory can be processed.
+//
+//     d := dynamodbattribute.NewExportDecoder(gzipReader)
+//     for {
+//         var r Record
+//         if err := d.Decode(&r); err == io.EOF {
+//             break
+//         } else if err != nil {
+//             return err
+//         }
+//         // process r
+//     }
+type ExportDecoder struct {
+	r       *bufio.Reader
+	decoder *Decoder
+	line    int
+}
+
+// NewExportDecoder creates a new ExportDecoder reading the export from r.
+// The `opts` functional options configure the Decoder items are unmarshaled
+// with.
+func NewExportDecoder(r io.Reader, opts ...func(*Decoder)) *ExportDecoder {
+	return &ExportDecoder{
+		r:       bufio.NewReader(r),
+		decoder: NewDecoder(opts...),
+	}
+}
+
+// Decode reads the next item of the export and unmarshals it into out.
+// io.EOF is returned when there are no more items.
+//
+// The output value provided must be a non-nil pointer
+func (d *ExportDecoder) Decode(out interface{}) error {
+	item, err := d.DecodeItem()
+	if err != nil {
+		return err
+	}
+
+	if err := d.decoder.Decode(&dynamodb.AttributeValue{M: item}, out); err != nil {
+		return &ExportDecodeError{Line: d.line, Err: err}
+	}
+	return nil
+}
+
+// DecodeItem reads the next item of the export. io.EOF is returned when
+// there are no more items.
+func (d *ExportDecoder) DecodeItem() (map[string]*dynamodb.AttributeValue, error) {
+	for {
+		b, err := d.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(b) == 0) {
+			return nil, err
+		}
+		d.line++
+
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			continue
+		}
+
+		var line struct {
+			Item json.RawMessage
+		}
+		if err := json.Unmarshal(b, &line); err != nil {
+			return nil, &ExportDecodeError{Line: d.line, Err: err}
+		}
+		if len(line.Item) == 0 {
+			return nil, &ExportDecodeError{Line: d.line, Err: fmt.Errorf("missing Item")}
+		}
+
+		item, err := UnmarshalWireJSONMap(line.Item)
+		if err != nil {
+			return nil, &ExportDecodeError{Line: d.line, Err: err}
+		}
+		return item, nil
+	}
+}
+
+// An ExportDecodeError is an error type representing a line of a table
+// export which could not be decoded.
+type ExportDecodeError struct {
+	// Line number of the export, starting at 1.
+	Line int
+
+	// The error decoding the line.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *ExportDecodeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *ExportDecodeError) Code() string {
+	return "ExportDecodeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *ExportDecodeError) Message() string {
+	return fmt.Sprintf("line %d, %v", e.Line, e.Err)
+}
+
+// OrigErr returns the error decoding the line, satisfying the
+// awserr.Error interface.
+func (e *ExportDecodeError) OrigErr() error {
+	return e.Err
+}
@@ -0,0 +1,73 @@
+package dynamodbattribute
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExportDecoder(t *testing.T) {
+	export := `{"Item":{"ID":{"S":"a"},"Count":{"N":"1"}}}
+{"Item":{"ID":{"S":"b"},"Count":{"N":"2"}}}
+
+{"Item":{"ID":{"S":"c"}}}`
+
+	type item struct {
+		ID    string
+		Count int
+	}
+
+	d := NewExportDecoder(strings.NewReader(export))
+	var actual []item
+	for {
+		var v item
+		err := d.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		actual = append(actual, v)
+	}
+
+	expect := []item{{"a", 1}, {"b", 2}, {"c", 0}}
+	if e, a := len(expect), len(actual); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	for i := range expect {
+		if e, a := expect[i], actual[i]; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestExportDecoderError(t *testing.T) {
+	cases := []struct {
+		export string
+		line   int
+	}{
+		{`{"Item":{"ID":{"S":"a"}}}` + "\n" + `{"Item":`, 2},
+		{"\n" + `{"Other":{}}`, 2},
+		{`{"Item":{"ID":{"S":"a","N":"1"}}}`, 1},
+		{`{"Item":{"ID":{"N":"1"}}}`, 1},
+	}
+
+	for i, c := range cases {
+		d := NewExportDecoder(strings.NewReader(c.export))
+		var err error
+		for err == nil {
+			var v struct{ ID string }
+			err = d.Decode(&v)
+		}
+
+		decodeErr, ok := err.(*ExportDecodeError)
+		if !ok {
+			t.Errorf("%d, expect ExportDecodeError, got %T, %v", i, err, err)
+			continue
+		}
+		if e, a := c.line, decodeErr.Line; e != a {
+			t.Errorf("%d, expect line %d, got %d", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type field struct {
+	tag
+
+	Name        string
+	NameFromTag bool
+
+	Index []int
+	Type  reflect.Type
+}
+
+// fieldByIndex returns the nested field of v found at index. If an embedded
+// struct pointer along the way is nil, onEmbeddedNilStruct is called and the
+// walk continues only if it returns true. The returned bool is false if the
+// walk was stopped.
+func fieldByIndex(v reflect.Value, index []int,
+	onEmbeddedNilStruct func(*reflect.Value) bool) (reflect.Value, bool) {
+	fv := v
+	for i, x := range index {
+		if i > 0 {
+			if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+				if fv.IsNil() && !onEmbeddedNilStruct(&fv) {
+					return reflect.Value{}, false
+				}
+				fv = fv.Elem()
+			}
+		}
+		fv = fv.Field(x)
+	}
+	return fv, true
+}
+
+// attrByName returns the AttributeValue in avMap for the name provided.
+// An exact match is preferred, falling back to a case insensitive match.
+func attrByName(avMap map[string]*dynamodb.AttributeValue, name string) (*dynamodb.AttributeValue, bool) {
+	if av, ok := avMap[name]; ok {
+		return av, true
+	}
+	for k, av := range avMap {
+		if strings.EqualFold(k, name) {
+			return av, true
+		}
+	}
+	return nil, false
+}
+
+// unionStructFields returns the list of fields for the struct type t,
+// including the fields of embedded structs, which are promoted as if they
+// were defined on t.
+//
+// Field name conflicts are resolved with the same rules encoding/json uses.
+// Of the fields sharing a name, the fields at the shallowest embedding depth
+// are considered. If exactly one of those is named by a struct tag it is
+// used, otherwise if there is only a single field at that depth it is used.
+// All other cases are ambiguous and every field with the name is dropped.
+//
+// The fields are cached per type and MarshalOptions, and the returned slice
+// must not be modified.
+func unionStructFields(t reflect.Type, opts MarshalOptions) []field {
+	key := fieldCacheKey{typ: t, opts: opts}
+	if fields, ok := fieldCache.Load(key); ok {
+		return fields
+	}
+
+	return fieldCache.LoadOrStore(key, typeFields(t, opts))
+}
+
+// typeFields returns the list of fields for the struct type t, resolving
+// name conflicts between promoted fields. See unionStructFields.
+func typeFields(t reflect.Type, opts MarshalOptions) []field {
+	fields := candidateFields(t, opts)
+
+	// Delete all fields that are hidden by the Go rules for embedded fields,
+	// except that fields with tags are promoted.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		// One iteration per name.
+		// Find the sequence of fields with the name of this first field.
+		fi := fields[i]
+		name := fi.Name
+		for advance = 1; i+advance < len(fields); advance++ {
+			fj := fields[i+advance]
+			if fj.Name != name {
+				break
+			}
+		}
+		if advance == 1 { // Only one field with this name
+			out = append(out, fi)
+			continue
+		}
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	fields = out
+	sort.Sort(fieldsByIndex(fields))
+
+	return fields
+}
+
+// candidateFields returns all fields of the struct type t, including the
+// fields of embedded structs, sorted by name. Fields sharing a name have
+// not been resolved.
+func candidateFields(t reflect.Type, opts MarshalOptions) []field {
+	fields := []field{}
+
+	current := []field{}
+	next := []field{{Type: t}}
+
+	// Count of queued names for current level and the next.
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{}
+
+	// Types already visited at an earlier level.
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.Type] {
+				continue
+			}
+			visited[f.Type] = true
+
+			for i := 0; i < f.Type.NumField(); i++ {
+				sf := f.Type.Field(i)
+				if sf.PkgPath != "" && !sf.Anonymous {
+					// Unexported fields are never encoded or decoded
+					continue
+				}
+
+				fieldTag := tag{}
+				if !fieldTag.parseAVTag(sf.Tag) && opts.SupportJSONTags {
+					fieldTag.parseJSONTag(sf.Tag)
+				}
+				if fieldTag.Ignore {
+					continue
+				}
+
+				index := make([]int, len(f.Index)+1)
+				copy(index, f.Index)
+				index[len(f.Index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous && fieldTag.Name == "" && ft.Kind() == reflect.Struct {
+					// Record new anonymous struct to explore in next round.
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, field{
+							Name:  ft.Name(),
+							Index: index,
+							Type:  ft,
+						})
+					}
+					continue
+				}
+				if sf.PkgPath != "" {
+					// Embedded unexported non-struct types are not accessible
+					continue
+				}
+
+				newField := field{
+					tag:   fieldTag,
+					Name:  sf.Name,
+					Index: index,
+					Type:  sf.Type,
+				}
+				if len(fieldTag.Name) != 0 {
+					newField.Name = fieldTag.Name
+					newField.NameFromTag = true
+				}
+
+				fields = append(fields, newField)
+				if count[f.Type] > 1 {
+					// If there were multiple instances, add a second,
+					// so that the annihilation code will see a duplicate.
+					// It only cares about the distinction between 1 or 2,
+					// so don't bother generating any more copies.
+					fields = append(fields, fields[len(fields)-1])
+				}
+			}
+		}
+	}
+
+	sort.Sort(fieldsByName(fields))
+
+	return fields
+}
+
+// dominantField looks through the fields, all of which are known to
+// have the same name, to find the single field that dominates the
+// others using Go's embedding rules, modified by the presence of
+// struct tags. If there are multiple top-level fields, the boolean
+// will be false: This condition is an error in Go and we skip all
+// the fields.
+func dominantField(fields []field) (field, bool) {
+	// The fields are sorted in increasing index-length order, then by
+	// presence of tag. That means that the first field is the dominant
+	// one. We need only check for error cases: two fields at top level,
+	// either both tagged or neither tagged.
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) &&
+		fields[0].NameFromTag == fields[1].NameFromTag {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+// fieldsByName sorts field by name, breaking ties with depth,
+// then breaking ties with "name came from tag", then
+// breaking ties with index sequence.
+type fieldsByName []field
+
+func (x fieldsByName) Len() int { return len(x) }
+
+func (x fieldsByName) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x fieldsByName) Less(i, j int) bool {
+	if x[i].Name != x[j].Name {
+		return x[i].Name < x[j].Name
+	}
+	if len(x[i].Index) != len(x[j].Index) {
+		return len(x[i].Index) < len(x[j].Index)
+	}
+	if x[i].NameFromTag != x[j].NameFromTag {
+		return x[i].NameFromTag
+	}
+	return fieldsByIndex(x).Less(i, j)
+}
+
+// fieldsByIndex sorts field by index sequence.
+type fieldsByIndex []field
+
+func (x fieldsByIndex) Len() int { return len(x) }
+
+func (x fieldsByIndex) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x fieldsByIndex) Less(i, j int) bool {
+	for k, xik := range x[i].Index {
+		if k >= len(x[j].Index) {
+			return false
+		}
+		if xik != x[j].Index[k] {
+			return xik < x[j].Index[k]
+		}
+	}
+	return len(x[i].Index) < len(x[j].Index)
+}
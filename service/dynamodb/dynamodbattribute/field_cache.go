@@ -0,0 +1,44 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"sync"
+)
+
+var fieldCache = fieldCacher{
+	cache: map[fieldCacheKey][]field{},
+}
+
+type fieldCacheKey struct {
+	typ  reflect.Type
+	opts MarshalOptions
+}
+
+// fieldCacher caches the fields of struct types for each combination of
+// MarshalOptions, so that struct tags are only parsed once per type.
+type fieldCacher struct {
+	mu    sync.RWMutex
+	cache map[fieldCacheKey][]field
+}
+
+// Load returns the cached fields for the key, and if they were found.
+func (c *fieldCacher) Load(key fieldCacheKey) ([]field, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	fields, ok := c.cache[key]
+	return fields, ok
+}
+
+// LoadOrStore returns the fields for the key if they are already cached.
+// Otherwise fields is stored and returned.
+func (c *fieldCacher) LoadOrStore(key fieldCacheKey, fields []field) []field {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.cache[key]; ok {
+		return cached
+	}
+	c.cache[key] = fields
+	return fields
+}
@@ -0,0 +1,247 @@
+package dynamodbattribute
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testUnionValues struct {
+	Name  string
+	Value interface{}
+}
+
+type unionSimple struct {
+	A int
+	B string
+	C []string
+}
+
+type unionComplex struct {
+	unionSimple
+	A int
+	B bool
+	unionExported
+}
+
+type unionExported struct {
+	D int
+	C string
+}
+
+type unionTaggedOuter struct {
+	unionTaggedA
+	unionTaggedB
+}
+
+type unionTaggedA struct {
+	Value string
+}
+
+type unionTaggedB struct {
+	V string `dynamodbav:"Value"`
+}
+
+type unionConflictOuter struct {
+	unionConflictA
+	unionConflictB
+}
+
+type unionConflictA struct {
+	Value string
+	Other string
+}
+
+type unionConflictB struct {
+	Value string
+}
+
+type unionDeepOuter struct {
+	unionDeepMiddle
+	unionConflictB
+}
+
+type unionDeepMiddle struct {
+	unionConflictA
+}
+
+func TestUnionStructFields(t *testing.T) {
+	var cases = []struct {
+		in     interface{}
+		expect []testUnionValues
+	}{
+		{
+			in: unionSimple{1, "2", []string{"abc"}},
+			expect: []testUnionValues{
+				{"A", 1},
+				{"B", "2"},
+				{"C", []string{"abc"}},
+			},
+		},
+		{
+			in: unionComplex{
+				unionSimple: unionSimple{1, "2", []string{"abc"}},
+				A:           2,
+				B:           true,
+				unionExported: unionExported{
+					D: 4,
+					C: "string",
+				},
+			},
+			expect: []testUnionValues{
+				{"A", 2},
+				{"B", true},
+				{"D", 4},
+			},
+		},
+		{
+			// Tagged field dominates untagged field at the same depth.
+			in: unionTaggedOuter{unionTaggedA{"a"}, unionTaggedB{"b"}},
+			expect: []testUnionValues{
+				{"Value", "b"},
+			},
+		},
+		{
+			// Conflicting untagged fields at the same depth are dropped.
+			in: unionConflictOuter{unionConflictA{"a", "other"}, unionConflictB{"b"}},
+			expect: []testUnionValues{
+				{"Other", "other"},
+			},
+		},
+		{
+			// Shallowest field dominates deeper fields.
+			in: unionDeepOuter{unionDeepMiddle{unionConflictA{"a", "other"}}, unionConflictB{"b"}},
+			expect: []testUnionValues{
+				{"Other", "other"},
+				{"Value", "b"},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		v := reflect.ValueOf(c.in)
+
+		fields := unionStructFields(v.Type(), MarshalOptions{SupportJSONTags: true})
+		if e, a := len(c.expect), len(fields); e != a {
+			t.Errorf("%d, expect %v fields, got %d", i, e, a)
+			continue
+		}
+		for j, f := range fields {
+			expected := c.expect[j]
+			if e, a := expected.Name, f.Name; e != a {
+				t.Errorf("%d:%d expect %v, got %v", i, j, e, a)
+			}
+			actual := v.FieldByIndex(f.Index).Interface()
+			if e, a := expected.Value, actual; !reflect.DeepEqual(e, a) {
+				t.Errorf("%d:%d expect %v, got %v", i, j, e, a)
+			}
+		}
+	}
+}
+
+func TestUnionStructFieldsMatchJSON(t *testing.T) {
+	type jsonTaggedB struct {
+		V string `json:"Value"`
+	}
+	type jsonTaggedOuter struct {
+		unionTaggedA
+		jsonTaggedB
+	}
+
+	var cases = []interface{}{
+		unionComplex{unionSimple: unionSimple{1, "2", []string{"abc"}}, A: 2},
+		jsonTaggedOuter{unionTaggedA{"a"}, jsonTaggedB{"b"}},
+		unionConflictOuter{unionConflictA{"a", "other"}, unionConflictB{"b"}},
+		unionDeepOuter{unionDeepMiddle{unionConflictA{"a", "other"}}, unionConflictB{"b"}},
+	}
+
+	for i, c := range cases {
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		jsonMap := map[string]interface{}{}
+		if err := json.Unmarshal(b, &jsonMap); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		expect := []string{}
+		for k := range jsonMap {
+			expect = append(expect, k)
+		}
+		sort.Strings(expect)
+
+		av, err := Marshal(c)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		actual := []string{}
+		for k := range av.M {
+			actual = append(actual, k)
+		}
+		sort.Strings(actual)
+
+		if !reflect.DeepEqual(expect, actual) {
+			t.Errorf("%d, expect %v fields, got %v", i, expect, actual)
+		}
+	}
+}
+
+func TestDecodeEmbeddedFieldPrecedence(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"Value": {S: aws.String("abc")},
+		"Other": {S: aws.String("123")},
+	}
+
+	var actual unionDeepOuter
+	if err := UnmarshalMap(item, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := unionDeepOuter{
+		unionDeepMiddle{unionConflictA{Other: "123"}},
+		unionConflictB{Value: "abc"},
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestCachedUnionStructFields(t *testing.T) {
+	typ := reflect.TypeOf(unionComplex{})
+
+	for _, opts := range []MarshalOptions{{SupportJSONTags: true}, {}} {
+		expect := typeFields(typ, opts)
+		for i := 0; i < 2; i++ {
+			actual := unionStructFields(typ, opts)
+			if !reflect.DeepEqual(expect, actual) {
+				t.Errorf("%v:%d, expect %v, got %v", opts, i, expect, actual)
+			}
+		}
+
+		if _, ok := fieldCache.Load(fieldCacheKey{typ: typ, opts: opts}); !ok {
+			t.Errorf("%v, expect fields to be cached", opts)
+		}
+	}
+}
+
+func BenchmarkUnionStructFields(b *testing.B) {
+	typ := reflect.TypeOf(unionComplex{})
+	opts := MarshalOptions{SupportJSONTags: true}
+
+	for i := 0; i < b.N; i++ {
+		unionStructFields(typ, opts)
+	}
+}
+
+func BenchmarkTypeFields(b *testing.B) {
+	typ := reflect.TypeOf(unionComplex{})
+	opts := MarshalOptions{SupportJSONTags: true}
+
+	for i := 0; i < b.N; i++ {
+		typeFields(typ, opts)
+	}
+}
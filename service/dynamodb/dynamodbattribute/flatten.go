@@ -0,0 +1,64 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// isFlattenedStruct returns if fields of type t are flattened by the
+// FlattenStructs Encoder and Decoder options. Structs which are marshaled
+// as scalars, such as time.Time, or by a custom Marshaler or SetMarshaler
+// are not.
+func isFlattenedStruct(t reflect.Type) bool {
+	t = schemaIndirect(t)
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	pt := reflect.PtrTo(t)
+	for _, it := range []reflect.Type{marshalerType, unmarshalerType, marshalerV2Type, unmarshalerV2Type, setMarshalerType, setUnmarshalerType} {
+		if t.Implements(it) || pt.Implements(it) {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenStructField adds the attributes of the nested struct field name's
+// AttributeValue map to the parent struct's map, prefixing their names with
+// "name.".
+func flattenStructField(parent map[string]*dynamodb.AttributeValue, name string, elem *dynamodb.AttributeValue) error {
+	for k, v := range elem.M {
+		flatName := name + "." + k
+		if _, ok := parent[flatName]; ok {
+			return &InvalidMarshalError{msg: "duplicate flattened attribute name " + flatName}
+		}
+		parent[flatName] = v
+	}
+
+	return nil
+}
+
+// unflattenStructField returns the attributes of avMap flattened from the
+// nested struct field name, with the "name." prefix removed. Nil is
+// returned if there are none.
+func unflattenStructField(avMap map[string]*dynamodb.AttributeValue, name string) map[string]*dynamodb.AttributeValue {
+	prefix := name + "."
+
+	var m map[string]*dynamodb.AttributeValue
+	for k, v := range avMap {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if m == nil {
+			m = map[string]*dynamodb.AttributeValue{}
+		}
+		m[k[len(prefix):]] = v
+	}
+
+	return m
+}
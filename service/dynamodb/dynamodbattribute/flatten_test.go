@@ -0,0 +1,101 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testFlattenGeo struct {
+	Lat, Lng float64
+}
+
+type testFlattenAddress struct {
+	City string
+	Geo  testFlattenGeo
+}
+
+type testFlattenItem struct {
+	ID      string
+	Address testFlattenAddress
+	Tags    []string
+}
+
+func TestEncodeFlattenStructs(t *testing.T) {
+	item := testFlattenItem{
+		ID:      "abc",
+		Address: testFlattenAddress{City: "Seattle", Geo: testFlattenGeo{Lat: 47.6, Lng: -122.3}},
+		Tags:    []string{"a"},
+	}
+
+	e := NewEncoder(func(e *Encoder) {
+		e.FlattenStructs = true
+	})
+	av, err := e.Encode(item)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"ID":              {S: aws.String("abc")},
+		"Address.City":    {S: aws.String("Seattle")},
+		"Address.Geo.Lat": {N: aws.String("47.6")},
+		"Address.Geo.Lng": {N: aws.String("-122.3")},
+		"Tags":            {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+	}
+	if e, a := expect, av.M; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var actual testFlattenItem
+	d := NewDecoder(func(d *Decoder) {
+		d.FlattenStructs = true
+	})
+	if err := d.Decode(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := item, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestEncodeFlattenStructsDuplicateName(t *testing.T) {
+	type item struct {
+		Address struct{ City string }
+		City    string `dynamodbav:"Address.City"`
+	}
+
+	e := NewEncoder(func(e *Encoder) {
+		e.FlattenStructs = true
+	})
+	_, err := e.Encode(item{})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := err.(*InvalidMarshalError); !ok {
+		t.Errorf("expect *InvalidMarshalError, got %T", err)
+	}
+}
+
+func TestDecodeFlattenStructsNestedMap(t *testing.T) {
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"ID": {S: aws.String("abc")},
+		"Address": {M: map[string]*dynamodb.AttributeValue{
+			"City": {S: aws.String("Seattle")},
+		}},
+		"Address.City": {S: aws.String("Portland")},
+	}}
+
+	var actual testFlattenItem
+	d := NewDecoder(func(d *Decoder) {
+		d.FlattenStructs = true
+	})
+	if err := d.Decode(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "Seattle", actual.Address.City; e != a {
+		t.Errorf("expect nested map to take precedence, %v, got %v", e, a)
+	}
+}
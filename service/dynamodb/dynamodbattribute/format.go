@@ -0,0 +1,154 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Format returns a stable, human readable representation of the
+// AttributeValue, suitable for test failure output and golden files.
+//
+// Each value is written with its data type followed by its value. Map keys
+// and set members are sorted so the output does not depend on map iteration
+// or set order, and nested maps and lists are indented one level per depth.
+//
+//     M {
+//       count: N 2
+//       name: S "abc"
+//       tags: SS ["a", "b"]
+//     }
+func Format(av *dynamodb.AttributeValue) string {
+	var buf bytes.Buffer
+	formatValue(&buf, av, 0)
+	return buf.String()
+}
+
+// FormatMap returns the stable, human readable representation of the item.
+// See Format.
+func FormatMap(item map[string]*dynamodb.AttributeValue) string {
+	var buf bytes.Buffer
+	formatMembers(&buf, item, 0)
+	return buf.String()
+}
+
+func formatValue(buf *bytes.Buffer, av *dynamodb.AttributeValue, depth int) {
+	switch {
+	case av == nil:
+		buf.WriteString("<nil>")
+	case av.S != nil:
+		buf.WriteString("S ")
+		buf.WriteString(strconv.Quote(*av.S))
+	case av.N != nil:
+		buf.WriteString("N ")
+		buf.WriteString(*av.N)
+	case av.B != nil:
+		buf.WriteString("B ")
+		buf.WriteString(base64.StdEncoding.EncodeToString(av.B))
+	case av.BOOL != nil:
+		buf.WriteString("BOOL ")
+		buf.WriteString(strconv.FormatBool(*av.BOOL))
+	case av.NULL != nil:
+		buf.WriteString("NULL")
+	case av.M != nil:
+		buf.WriteString("M ")
+		formatMembers(buf, av.M, depth)
+	case av.L != nil:
+		buf.WriteString("L ")
+		if len(av.L) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for _, v := range av.L {
+			formatIndent(buf, depth+1)
+			formatValue(buf, v, depth+1)
+			buf.WriteByte('\n')
+		}
+		formatIndent(buf, depth)
+		buf.WriteByte(']')
+	case av.SS != nil:
+		members := make([]string, 0, len(av.SS))
+		for _, s := range av.SS {
+			if s != nil {
+				members = append(members, strconv.Quote(*s))
+			}
+		}
+		formatSet(buf, "SS", members)
+	case av.NS != nil:
+		members := make([]string, 0, len(av.NS))
+		for _, n := range av.NS {
+			if n != nil {
+				members = append(members, *n)
+			}
+		}
+		formatSet(buf, "NS", members)
+	case av.BS != nil:
+		members := make([]string, 0, len(av.BS))
+		for _, b := range av.BS {
+			members = append(members, base64.StdEncoding.EncodeToString(b))
+		}
+		formatSet(buf, "BS", members)
+	default:
+		buf.WriteString("<empty>")
+	}
+}
+
+func formatMembers(buf *bytes.Buffer, m map[string]*dynamodb.AttributeValue, depth int) {
+	if len(m) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("{\n")
+	for _, k := range keys {
+		formatIndent(buf, depth+1)
+		buf.WriteString(formatName(k))
+		buf.WriteString(": ")
+		formatValue(buf, m[k], depth+1)
+		buf.WriteByte('\n')
+	}
+	formatIndent(buf, depth)
+	buf.WriteByte('}')
+}
+
+func formatSet(buf *bytes.Buffer, typ string, members []string) {
+	sort.Strings(members)
+	buf.WriteString(typ)
+	buf.WriteString(" [")
+	buf.WriteString(strings.Join(members, ", "))
+	buf.WriteByte(']')
+}
+
+func formatIndent(buf *bytes.Buffer, depth int) {
+	for i := 0; i < depth; i++ {
+		buf.WriteString("  ")
+	}
+}
+
+// formatName returns the attribute name, quoted if it is empty or contains
+// characters which would make the output ambiguous.
+func formatName(name string) string {
+	if len(name) == 0 {
+		return `""`
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '-', r == '.':
+		default:
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
@@ -0,0 +1,75 @@
+package dynamodbattribute
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestFormatMap(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"name":  {S: aws.String("abc \"x\"")},
+		"count": {N: aws.String("2")},
+		"data":  {B: []byte{1, 2, 3}},
+		"ok":    {BOOL: aws.Bool(true)},
+		"none":  {NULL: aws.Bool(true)},
+		"tags":  {SS: []*string{aws.String("b"), aws.String("a")}},
+		"nums":  {NS: []*string{aws.String("3"), aws.String("1")}},
+		"list": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("x")},
+			{M: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1")}}},
+		}},
+		"empty":    {L: []*dynamodb.AttributeValue{}},
+		"my attr":  {M: map[string]*dynamodb.AttributeValue{}},
+		"bin.sets": {BS: [][]byte{{2}, {1}}},
+	}
+
+	expect := `{
+  bin.sets: BS [AQ==, Ag==]
+  count: N 2
+  data: B AQID
+  empty: L []
+  list: L [
+    S "x"
+    M {
+      k: N 1
+    }
+  ]
+  "my attr": M {}
+  name: S "abc \"x\""
+  none: NULL
+  nums: NS [1, 3]
+  ok: BOOL true
+  tags: SS ["a", "b"]
+}`
+	if e, a := expect, FormatMap(item); e != a {
+		t.Errorf("expect\n%s\ngot\n%s", e, a)
+	}
+
+	// Output is stable across calls regardless of map iteration order.
+	for i := 0; i < 10; i++ {
+		if e, a := expect, FormatMap(item); e != a {
+			t.Fatalf("expect stable output, got\n%s", a)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		in     *dynamodb.AttributeValue
+		expect string
+	}{
+		{nil, "<nil>"},
+		{&dynamodb.AttributeValue{}, "<empty>"},
+		{&dynamodb.AttributeValue{S: aws.String("")}, `S ""`},
+		{&dynamodb.AttributeValue{N: aws.String("1.5")}, "N 1.5"},
+		{&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"": {NULL: aws.Bool(true)}}}, "M {\n  \"\": NULL\n}"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, Format(c.in); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package dynamodbattribute
+
+import "sync"
+
+// Default bounds of a StringInterner.
+const (
+	DefaultInternMaxStrings = 4096
+	DefaultInternMaxLength  = 64
+)
+
+// A StringInterner is a bounded table of strings shared by the values a
+// Decoder decodes. When many items with the same schema are decoded, such
+// as the pages of a large Scan, the map keys and repeated string values of
+// each decoded item share the interned string instead of holding their
+// own, so the strings of the AttributeValues can be garbage collected.
+//
+// Strings longer than the maximum length are not interned. Once the table
+// holds the maximum number of strings, new strings are no longer interned.
+//
+// A StringInterner is safe for concurrent use by multiple goroutines, and
+// can be shared by Decoders.
+type StringInterner struct {
+	maxStrings, maxLength int
+
+	mu sync.RWMutex
+	m  map[string]string
+}
+
+// NewStringInterner returns a StringInterner holding up to maxStrings
+// strings of at most maxLength bytes. Values less than 1 use
+// DefaultInternMaxStrings and DefaultInternMaxLength.
+func NewStringInterner(maxStrings, maxLength int) *StringInterner {
+	if maxStrings < 1 {
+		maxStrings = DefaultInternMaxStrings
+	}
+	if maxLength < 1 {
+		maxLength = DefaultInternMaxLength
+	}
+
+	return &StringInterner{
+		maxStrings: maxStrings,
+		maxLength:  maxLength,
+		m:          map[string]string{},
+	}
+}
+
+// Intern returns the interned string equal to s, interning s if it is not
+// already and the table is not full. Returns s if it is not interned.
+func (i *StringInterner) Intern(s string) string {
+	if len(s) > i.maxLength {
+		return s
+	}
+
+	i.mu.RLock()
+	interned, ok := i.m[s]
+	i.mu.RUnlock()
+	if ok {
+		return interned
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if interned, ok := i.m[s]; ok {
+		return interned
+	}
+	if len(i.m) >= i.maxStrings {
+		return s
+	}
+	i.m[s] = s
+
+	return s
+}
+
+// Len returns the number of strings interned.
+func (i *StringInterner) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.m)
+}
+
+// intern returns the Decoder's interned string equal to s, or s if the
+// Decoder has no StringInterner.
+func (d *Decoder) intern(s string) string {
+	if d.Interner == nil {
+		return s
+	}
+	return d.Interner.Intern(s)
+}
@@ -0,0 +1,94 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// stringData returns the address of the bytes of s.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestStringInterner(t *testing.T) {
+	i := NewStringInterner(2, 4)
+
+	a := i.Intern(string([]byte("abc")))
+	if e, a := stringData(a), stringData(i.Intern(string([]byte("abc")))); e != a {
+		t.Errorf("expect interned string shared")
+	}
+
+	long := string([]byte("abcde"))
+	if e, a := stringData(long), stringData(i.Intern(long)); e != a {
+		t.Errorf("expect long string not interned")
+	}
+
+	i.Intern("def")
+	i.Intern("ghi")
+	if e, a := 2, i.Len(); e != a {
+		t.Errorf("expect %v interned, got %v", e, a)
+	}
+
+	i = NewStringInterner(0, 0)
+	if e, a := DefaultInternMaxLength, i.maxLength; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := DefaultInternMaxStrings, i.maxStrings; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestDecodeInternStrings(t *testing.T) {
+	newItem := func() map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			string([]byte("status")): {S: aws.String(string([]byte("active")))},
+			string([]byte("attrs")): {M: map[string]*dynamodb.AttributeValue{
+				string([]byte("color")): {S: aws.String(string([]byte("red")))},
+			}},
+			string([]byte("description")): {S: aws.String(strings.Repeat("x", 100))},
+		}
+	}
+	items := []map[string]*dynamodb.AttributeValue{newItem(), newItem()}
+
+	type record struct {
+		Status      string                 `dynamodbav:"status"`
+		Attrs       map[string]interface{} `dynamodbav:"attrs"`
+		Description string                 `dynamodbav:"description"`
+	}
+
+	interner := NewStringInterner(0, 0)
+	var records []record
+	err := UnmarshalListOfMapsWithOptions(items, &records, func(d *Decoder) {
+		d.Interner = interner
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := stringData(records[0].Status), stringData(records[1].Status); e != a {
+		t.Errorf("expect status values shared")
+	}
+	keys := [2]string{}
+	for i, r := range records {
+		for k, v := range r.Attrs {
+			keys[i] = k
+			if e, a := "red", v; e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		}
+	}
+	if e, a := stringData(keys[0]), stringData(keys[1]); e != a {
+		t.Errorf("expect map keys shared")
+	}
+	if stringData(records[0].Description) == stringData(records[1].Description) {
+		t.Errorf("expect long value not interned")
+	}
+	if e, a := 3, interner.Len(); e != a {
+		t.Errorf("expect %v interned, got %v", e, a)
+	}
+}
@@ -0,0 +1,145 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ToJSON returns the item as an ordinary JSON object, without the data type
+// wrappers of the DynamoDB wire JSON. e.g. {"name":"abc","count":1}
+//
+// Numbers are written with their exact value, and binary values as base64
+// encoded strings. Sets are written as JSON arrays, and NULL as null.
+func ToJSON(item map[string]*dynamodb.AttributeValue) ([]byte, error) {
+	v, err := jsonValue(&dynamodb.AttributeValue{M: item})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// FromJSON returns the item of the JSON object, inferring the data type
+// of each value from its JSON type. Strings are S, numbers are N, booleans
+// are BOOL, null is NULL, arrays are L, and objects are M.
+//
+// Number values keep the exact value of the JSON number. Empty strings are
+// not valid values for DynamoDB, and are NULL, the same as Marshal.
+func FromJSON(b []byte) (map[string]*dynamodb.AttributeValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v map[string]interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, &UnmarshalTypeError{Value: "JSON null", Type: stringInterfaceMapType}
+	}
+
+	return jsonItem(v)
+}
+
+func jsonValue(av *dynamodb.AttributeValue) (interface{}, error) {
+	switch {
+	case av == nil || av.NULL != nil:
+		return nil, nil
+	case av.B != nil:
+		return av.B, nil
+	case av.BOOL != nil:
+		return *av.BOOL, nil
+	case av.N != nil:
+		return json.Number(*av.N), nil
+	case av.S != nil:
+		return *av.S, nil
+	case av.BS != nil:
+		return av.BS, nil
+	case av.NS != nil:
+		ns := make([]json.Number, len(av.NS))
+		for i, n := range av.NS {
+			ns[i] = json.Number(*n)
+		}
+		return ns, nil
+	case av.SS != nil:
+		ss := make([]string, len(av.SS))
+		for i, s := range av.SS {
+			ss[i] = *s
+		}
+		return ss, nil
+	case av.L != nil:
+		l := make([]interface{}, len(av.L))
+		for i, elem := range av.L {
+			v, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = v
+		}
+		return l, nil
+	case av.M != nil:
+		m := make(map[string]interface{}, len(av.M))
+		for k, elem := range av.M {
+			v, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	}
+
+	return nil, &InvalidMarshalError{msg: "attribute value has no data type"}
+}
+
+func jsonItem(m map[string]interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	item := make(map[string]*dynamodb.AttributeValue, len(m))
+	for k, v := range m {
+		av, err := jsonAttributeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		item[k] = av
+	}
+	return item, nil
+}
+
+func jsonAttributeValue(v interface{}) (*dynamodb.AttributeValue, error) {
+	av := &dynamodb.AttributeValue{}
+
+	switch tv := v.(type) {
+	case nil:
+		encodeNull(av)
+	case bool:
+		av.BOOL = &tv
+	case json.Number:
+		n := tv.String()
+		av.N = &n
+	case string:
+		if len(tv) == 0 {
+			encodeNull(av)
+		} else {
+			av.S = &tv
+		}
+	case []interface{}:
+		av.L = make([]*dynamodb.AttributeValue, len(tv))
+		for i, elem := range tv {
+			elemAV, err := jsonAttributeValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			av.L[i] = elemAV
+		}
+	case map[string]interface{}:
+		m, err := jsonItem(tv)
+		if err != nil {
+			return nil, err
+		}
+		av.M = m
+	default:
+		return nil, &InvalidMarshalError{msg: fmt.Sprintf("unsupported JSON value type %T", v)}
+	}
+
+	return av, nil
+}
@@ -0,0 +1,75 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestToJSON(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"b":     {B: []byte("hi")},
+		"big":   {N: aws.String("123456789012345678901234567890")},
+		"bool":  {BOOL: aws.Bool(true)},
+		"bs":    {BS: [][]byte{[]byte("x")}},
+		"l":     {L: []*dynamodb.AttributeValue{{NULL: aws.Bool(true)}, {S: aws.String("a")}}},
+		"m":     {M: map[string]*dynamodb.AttributeValue{"n": {N: aws.String("1.5")}}},
+		"ns":    {NS: []*string{aws.String("1"), aws.String("2")}},
+		"ss":    {SS: []*string{aws.String("a")}},
+		"empty": {M: map[string]*dynamodb.AttributeValue{}},
+	}
+	expect := `{"b":"aGk=","big":123456789012345678901234567890,"bool":true,"bs":["eA=="],` +
+		`"empty":{},"l":[null,"a"],"m":{"n":1.5},"ns":[1,2],"ss":["a"]}`
+
+	b, err := ToJSON(item)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect, string(b); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if _, err := ToJSON(map[string]*dynamodb.AttributeValue{"bad": {}}); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	in := `{"s":"abc","empty":"","big":123456789012345678901234567890,"f":1.5,` +
+		`"t":true,"null":null,"l":[1,"a",[]],"m":{"n":{"x":false}}}`
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"s":     {S: aws.String("abc")},
+		"empty": {NULL: aws.Bool(true)},
+		"big":   {N: aws.String("123456789012345678901234567890")},
+		"f":     {N: aws.String("1.5")},
+		"t":     {BOOL: aws.Bool(true)},
+		"null":  {NULL: aws.Bool(true)},
+		"l": {L: []*dynamodb.AttributeValue{
+			{N: aws.String("1")},
+			{S: aws.String("a")},
+			{L: []*dynamodb.AttributeValue{}},
+		}},
+		"m": {M: map[string]*dynamodb.AttributeValue{
+			"n": {M: map[string]*dynamodb.AttributeValue{
+				"x": {BOOL: aws.Bool(false)},
+			}},
+		}},
+	}
+
+	actual, err := FromJSON([]byte(in))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+
+	for i, c := range []string{`null`, `[1]`, `{"a":`} {
+		if _, err := FromJSON([]byte(c)); err == nil {
+			t.Errorf("%d, expect error, got none", i)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A KeyBuilder builds the key of an item, e.g. for a GetItem or DeleteItem
+// request. Use Key to create the builder with the partition key, and Sort
+// to optionally add the sort key.
+//
+//     key, err := dynamodbattribute.Key("CustomerID", "abc").Sort("OrderID", 2).Build()
+//     // key is {"CustomerID": {S: "abc"}, "OrderID": {N: "2"}}
+type KeyBuilder struct {
+	partitionKey   string
+	partitionValue interface{}
+
+	sortKey   string
+	sortValue interface{}
+}
+
+// Key returns a KeyBuilder for the key with the partition key attribute,
+// name, and its value.
+func Key(name string, value interface{}) KeyBuilder {
+	return KeyBuilder{partitionKey: name, partitionValue: value}
+}
+
+// Sort returns a copy of the KeyBuilder with the sort key attribute, name,
+// and its value.
+func (k KeyBuilder) Sort(name string, value interface{}) KeyBuilder {
+	k.sortKey = name
+	k.sortValue = value
+	return k
+}
+
+// Build returns the key's AttributeValue map. An error is returned if any
+// of the key values cannot be marshaled to a non-empty string, number, or
+// binary AttributeValue.
+func (k KeyBuilder) Build() (map[string]*dynamodb.AttributeValue, error) {
+	key := make(map[string]*dynamodb.AttributeValue, 2)
+	if err := addKeyAttribute(key, k.partitionKey, k.partitionValue); err != nil {
+		return nil, err
+	}
+	if len(k.sortKey) != 0 {
+		if err := addKeyAttribute(key, k.sortKey, k.sortValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// KeyOf returns the primary key attributes of item, a struct, marshaled
+// from its fields with the `hashkey` and `rangekey` struct tag options.
+// Only the key fields are marshaled, so KeyOf can be used with a value
+// which only has its key fields set, e.g. for a GetItem, DeleteItem, or
+// UpdateItem request.
+//
+//     type Order struct {
+//         CustomerID string `dynamodbav:",hashkey"`
+//         OrderID    int    `dynamodbav:",rangekey"`
+//         Total      int
+//     }
+//
+//     key, err := dynamodbattribute.KeyOf(Order{CustomerID: "abc", OrderID: 2})
+//     // key is {"CustomerID": {S: "abc"}, "OrderID": {N: "2"}}
+//
+// An error is returned if item does not have exactly one `hashkey` field,
+// has more than one `rangekey` field, or a key field cannot be marshaled to
+// a non-empty string, number, or binary AttributeValue.
+func KeyOf(item interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, &InvalidMarshalError{msg: fmt.Sprintf("key must be a struct, %v", reflect.TypeOf(item))}
+	}
+
+	hashKey, rangeKey, err := keyFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	if hashKey == nil {
+		return nil, &InvalidMarshalError{msg: "no hashkey field of " + v.Type().String()}
+	}
+
+	key := make(map[string]*dynamodb.AttributeValue, 2)
+	for _, f := range []*field{hashKey, rangeKey} {
+		if f == nil {
+			continue
+		}
+		fv, found := fieldByIndex(v, f.Index, func(v *reflect.Value) bool {
+			return false
+		})
+		av := &dynamodb.AttributeValue{}
+		if found {
+			if err := NewEncoder().encode(av, fv, f.tag); err != nil {
+				return nil, err
+			}
+		}
+		if err := checkKeyValue(f.Name, av); err != nil {
+			return nil, err
+		}
+		key[f.Name] = av
+	}
+
+	return key, nil
+}
+
+// KeyAttributeNames returns the attribute names of the fields of in, a
+// struct, with the `hashkey` and `rangekey` struct tag options. The names
+// are empty if in has no key fields. See KeyOf.
+func KeyAttributeNames(in interface{}) (hashKey, rangeKey string, err error) {
+	t := reflect.TypeOf(in)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", "", &InvalidMarshalError{msg: fmt.Sprintf("key must be a struct, %v", reflect.TypeOf(in))}
+	}
+
+	hash, rng, err := keyFields(t)
+	if err != nil || hash == nil {
+		return "", "", err
+	}
+	if rng != nil {
+		rangeKey = rng.Name
+	}
+	return hash.Name, rangeKey, nil
+}
+
+// KeyAttributeType returns the scalar attribute type, "S", "N", or "B",
+// the field of in, a struct, with the attribute name is marshaled to. Use
+// it for the AttributeDefinitions of a table's or index's key attributes.
+//
+//     typ, err := dynamodbattribute.KeyAttributeType(Order{}, "OrderID")
+//     // typ is "N"
+//
+// An error is returned if in has no field with the attribute name, or the
+// field is not marshaled to a string, number, or binary AttributeValue.
+func KeyAttributeType(in interface{}, name string) (string, error) {
+	t := reflect.TypeOf(in)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", &InvalidMarshalError{msg: fmt.Sprintf("key must be a struct, %v", reflect.TypeOf(in))}
+	}
+
+	for _, f := range unionStructFields(t, MarshalOptions{SupportJSONTags: true}) {
+		if f.Name != name {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case f.UUID:
+			return "B", nil
+		case f.AsString, ft == reflect.TypeOf(time.Time{}):
+			return "S", nil
+		}
+		switch ft.Kind() {
+		case reflect.String:
+			return "S", nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return "N", nil
+		case reflect.Slice, reflect.Array:
+			if ft.Elem().Kind() == reflect.Uint8 {
+				return "B", nil
+			}
+		}
+		return "", &InvalidMarshalError{
+			msg: "key attribute " + name + " of " + t.String() + " must be a string, number, or binary field",
+		}
+	}
+
+	return "", &InvalidMarshalError{msg: t.String() + " has no field for key attribute " + name}
+}
+
+// keyFields returns the `hashkey` and `rangekey` fields of the struct type,
+// or nil if the type has no key fields.
+func keyFields(t reflect.Type) (hashKey, rangeKey *field, err error) {
+	fields := unionStructFields(t, MarshalOptions{SupportJSONTags: true})
+	for i := range fields {
+		f := &fields[i]
+		switch {
+		case f.HashKey && f.RangeKey:
+			return nil, nil, &InvalidMarshalError{msg: "key field " + f.Name + " cannot be both hashkey and rangekey"}
+		case f.HashKey && hashKey != nil, f.RangeKey && rangeKey != nil:
+			return nil, nil, &InvalidMarshalError{msg: "duplicate key field " + f.Name + " of " + t.String()}
+		case f.HashKey:
+			hashKey = f
+		case f.RangeKey:
+			rangeKey = f
+		}
+	}
+	if hashKey == nil && rangeKey != nil {
+		return nil, nil, &InvalidMarshalError{msg: "rangekey field " + rangeKey.Name + " of " + t.String() + " has no hashkey field"}
+	}
+
+	return hashKey, rangeKey, nil
+}
+
+func addKeyAttribute(key map[string]*dynamodb.AttributeValue, name string, value interface{}) error {
+	if len(name) == 0 {
+		return &InvalidMarshalError{msg: "key attribute name cannot be empty"}
+	}
+	if _, ok := key[name]; ok {
+		return &InvalidMarshalError{msg: "duplicate key attribute " + name}
+	}
+
+	av, err := Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := checkKeyValue(name, av); err != nil {
+		return err
+	}
+
+	key[name] = av
+	return nil
+}
+
+// checkKeyValue returns an error if av is not a valid value of the key
+// attribute name.
+func checkKeyValue(name string, av *dynamodb.AttributeValue) error {
+	if av == nil || (av.S == nil && av.N == nil && av.B == nil) {
+		return &InvalidMarshalError{
+			msg: "key attribute " + name + " value must be a non-empty string, number, or binary",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestKeyBuild(t *testing.T) {
+	cases := []struct {
+		key    KeyBuilder
+		expect map[string]*dynamodb.AttributeValue
+	}{
+		{Key("ID", "abc"), map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String("abc")},
+		}},
+		{Key("CustomerID", "abc").Sort("OrderID", 2), map[string]*dynamodb.AttributeValue{
+			"CustomerID": {S: aws.String("abc")},
+			"OrderID":    {N: aws.String("2")},
+		}},
+		{Key("Hash", []byte{1}).Sort("Range", uint8(3)), map[string]*dynamodb.AttributeValue{
+			"Hash":  {B: []byte{1}},
+			"Range": {N: aws.String("3")},
+		}},
+	}
+
+	for i, c := range cases {
+		key, err := c.key.Build()
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, key; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestKeyBuildInvalid(t *testing.T) {
+	cases := []struct {
+		key    KeyBuilder
+		expect string
+	}{
+		{Key("ID", ""), "InvalidMarshalError: key attribute ID value must be a non-empty string, number, or binary"},
+		{Key("ID", "abc").Sort("Tags", []string{"a"}), "InvalidMarshalError: key attribute Tags value must be a non-empty string, number, or binary"},
+		{Key("ID", true), "InvalidMarshalError: key attribute ID value must be a non-empty string, number, or binary"},
+		{Key("", "abc"), "InvalidMarshalError: key attribute name cannot be empty"},
+		{Key("ID", "abc").Sort("ID", "def"), "InvalidMarshalError: duplicate key attribute ID"},
+	}
+
+	for i, c := range cases {
+		_, err := c.key.Build()
+		if err == nil {
+			t.Errorf("%d, expect error, got none", i)
+		} else if e, a := c.expect, err.Error(); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	type order struct {
+		CustomerID string `dynamodbav:",hashkey"`
+		OrderID    int    `dynamodbav:"order,rangekey,string"`
+		Total      int
+	}
+
+	key, err := KeyOf(&order{CustomerID: "abc", OrderID: 2, Total: 5})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{
+		"CustomerID": {S: aws.String("abc")},
+		"order":      {S: aws.String("2")},
+	}
+	if e, a := expect, key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	hashKey, rangeKey, err := KeyAttributeNames(order{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "CustomerID", hashKey; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "order", rangeKey; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Key fields must have values.
+	if _, err := KeyOf(order{OrderID: 2}); err == nil {
+		t.Errorf("expect error for empty hash key")
+	}
+
+	// Key fields are encoded, but not changed, by the program encoder.
+	av, err := MarshalMap(order{CustomerID: "abc", OrderID: 2})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "abc", aws.StringValue(av["CustomerID"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestKeyOfInvalid(t *testing.T) {
+	type noKey struct {
+		ID string
+	}
+	type rangeOnly struct {
+		ID string `dynamodbav:",rangekey"`
+	}
+	type duplicate struct {
+		A string `dynamodbav:",hashkey"`
+		B string `dynamodbav:",hashkey"`
+	}
+
+	cases := []interface{}{"abc", noKey{ID: "a"}, rangeOnly{ID: "a"}, duplicate{A: "a", B: "b"}}
+	for i, c := range cases {
+		if _, err := KeyOf(c); err == nil {
+			t.Errorf("%d, expect error", i)
+		}
+	}
+
+	if h, r, err := KeyAttributeNames(noKey{}); err != nil || len(h) != 0 || len(r) != 0 {
+		t.Errorf("expect no key names, got %q, %q, %v", h, r, err)
+	}
+	if _, _, err := KeyAttributeNames(duplicate{}); err == nil {
+		t.Errorf("expect error")
+	}
+}
+
+func TestKeyAttributeType(t *testing.T) {
+	type item struct {
+		ID       string `dynamodbav:",hashkey"`
+		Sort     *int64 `dynamodbav:"sort,rangekey"`
+		Created  time.Time
+		Count    uint8 `dynamodbav:",string"`
+		Data     []byte
+		Checksum [4]byte
+		Tags     []string
+		Nested   struct{ A string }
+	}
+
+	cases := map[string]string{
+		"ID":       "S",
+		"sort":     "N",
+		"Created":  "S",
+		"Count":    "S",
+		"Data":     "B",
+		"Checksum": "B",
+	}
+	for name, e := range cases {
+		a, err := KeyAttributeType(&item{}, name)
+		if err != nil {
+			t.Errorf("%s, expect no error, got %v", name, err)
+		}
+		if e != a {
+			t.Errorf("%s, expect %v, got %v", name, e, a)
+		}
+	}
+
+	for _, name := range []string{"Tags", "Nested", "Missing"} {
+		if _, err := KeyAttributeType(item{}, name); err == nil {
+			t.Errorf("%s, expect error", name)
+		}
+	}
+	if _, err := KeyAttributeType("abc", "ID"); err == nil {
+		t.Errorf("expect error for non-struct")
+	}
+}
@@ -0,0 +1,111 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Limits are the DynamoDB limits AttributeValues can be validated against
+// before they are sent to the service. Validating the values locally gives
+// an error naming the offending attribute instead of the service's
+// ValidationException. A zero value field disables that check.
+type Limits struct {
+	// Maximum length in bytes of attribute names, including the names of
+	// nested map attributes.
+	MaxAttributeNameLength int
+
+	// Maximum depth of nested maps and lists. The outermost value, such as
+	// the item, is not counted.
+	MaxNestingDepth int
+
+	// Maximum number of elements in a list, map, or set.
+	MaxElements int
+}
+
+// DefaultLimits are DynamoDB's limits on attribute names and the nesting of
+// document values. Key attribute names and the names of attributes projected
+// into an index are further limited to 255 bytes.
+var DefaultLimits = Limits{
+	MaxAttributeNameLength: 65535,
+	MaxNestingDepth:        32,
+}
+
+// Validate returns a LimitExceededError if the AttributeValue exceeds any
+// of the limits.
+func (l Limits) Validate(av *dynamodb.AttributeValue) error {
+	return l.validate(av, "", 0)
+}
+
+func (l Limits) validate(av *dynamodb.AttributeValue, path string, depth int) error {
+	if av == nil {
+		return nil
+	}
+
+	if av.L != nil || av.M != nil {
+		if l.MaxNestingDepth > 0 && depth > l.MaxNestingDepth {
+			return &LimitExceededError{Path: path, Limit: "nesting depth", Max: l.MaxNestingDepth, Actual: depth}
+		}
+	}
+
+	n := len(av.L) + len(av.M) + len(av.SS) + len(av.NS) + len(av.BS)
+	if l.MaxElements > 0 && n > l.MaxElements {
+		return &LimitExceededError{Path: path, Limit: "element count", Max: l.MaxElements, Actual: n}
+	}
+
+	for i, elem := range av.L {
+		if err := l.validate(elem, path+"["+strconv.Itoa(i)+"]", depth+1); err != nil {
+			return err
+		}
+	}
+	for name, elem := range av.M {
+		elemPath := joinAttributePath(path, name)
+		if l.MaxAttributeNameLength > 0 && len(name) > l.MaxAttributeNameLength {
+			return &LimitExceededError{Path: elemPath, Limit: "attribute name length", Max: l.MaxAttributeNameLength, Actual: len(name)}
+		}
+		if err := l.validate(elem, elemPath, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// A LimitExceededError is an error type representing an AttributeValue
+// which exceeds one of the Limits it was validated against.
+type LimitExceededError struct {
+	emptyOrigError
+
+	// Document path of the attribute which exceeded the limit, e.g.
+	// "Orders[2].Address". Empty for the outermost value.
+	Path string
+
+	// Name of the limit exceeded, e.g. "nesting depth".
+	Limit string
+
+	// The limit, and the value which exceeded it.
+	Max, Actual int
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *LimitExceededError) Code() string {
+	return "LimitExceededError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *LimitExceededError) Message() string {
+	msg := fmt.Sprintf("%s %d exceeds limit of %d", e.Limit, e.Actual, e.Max)
+	if len(e.Path) != 0 {
+		msg += ", " + e.Path
+	}
+	return msg
+}
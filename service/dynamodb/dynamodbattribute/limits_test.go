@@ -0,0 +1,104 @@
+package dynamodbattribute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func nestedLimitsValue(depth int) interface{} {
+	var v interface{} = "leaf"
+	for i := 0; i < depth; i++ {
+		v = map[string]interface{}{"a": v}
+	}
+	return v
+}
+
+func TestEncoderLimits(t *testing.T) {
+	cases := []struct {
+		in     interface{}
+		limits Limits
+		path   string
+		limit  string
+	}{
+		{
+			in:     map[string]interface{}{strings.Repeat("a", 11): 1},
+			limits: Limits{MaxAttributeNameLength: 10},
+			path:   strings.Repeat("a", 11),
+			limit:  "attribute name length",
+		},
+		{
+			in:     map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1, 2, 3}}},
+			limits: Limits{MaxElements: 2},
+			path:   "a.b",
+			limit:  "element count",
+		},
+		{
+			in:     map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b": map[string]interface{}{"c": 1}}}},
+			limits: Limits{MaxNestingDepth: 2},
+			path:   "a[1].b",
+			limit:  "nesting depth",
+		},
+		{
+			in:     nestedLimitsValue(34),
+			limits: DefaultLimits,
+			path:   strings.TrimSuffix(strings.Repeat("a.", 33), "."),
+			limit:  "nesting depth",
+		},
+		{
+			in:     nestedLimitsValue(33),
+			limits: DefaultLimits,
+		},
+		{
+			in:     map[string]interface{}{strings.Repeat("a", 11): []int{1, 2, 3}},
+			limits: Limits{},
+		},
+	}
+
+	for i, c := range cases {
+		e := NewEncoder(func(e *Encoder) {
+			e.Limits = c.limits
+		})
+		_, err := e.Encode(c.in)
+		if len(c.limit) == 0 {
+			if err != nil {
+				t.Errorf("%d, expect no error, got %v", i, err)
+			}
+			continue
+		}
+
+		limitErr, ok := err.(*LimitExceededError)
+		if !ok {
+			t.Errorf("%d, expect LimitExceededError, got %T, %v", i, err, err)
+			continue
+		}
+		if e, a := c.path, limitErr.Path; e != a {
+			t.Errorf("%d, expect %q path, got %q", i, e, a)
+		}
+		if e, a := c.limit, limitErr.Limit; e != a {
+			t.Errorf("%d, expect %q limit, got %q", i, e, a)
+		}
+	}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	av := &dynamodb.AttributeValue{
+		M: map[string]*dynamodb.AttributeValue{
+			"tags": {SS: []*string{aws.String("a"), aws.String("b"), aws.String("c")}},
+		},
+	}
+
+	err := Limits{MaxElements: 2}.Validate(av)
+	if err == nil {
+		t.Fatalf("expect error")
+	}
+	if e, a := "LimitExceededError: element count 3 exceeds limit of 2, tags", err.Error(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	if err := (Limits{MaxElements: 3}).Validate(av); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
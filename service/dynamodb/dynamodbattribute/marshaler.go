@@ -0,0 +1,79 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A MarshalerV2 is an interface to provide custom marshaling of Go value
+// types to AttributeValues, the same as Marshaler, with access to the
+// Encoder's options. Use this for custom types which should honor the
+// caller's configuration, such as NullEmptyString or NumbersAsStrings, or
+// which marshal nested values with the same Encoder.
+//
+//     type Money struct {
+//         Cents int64
+//     }
+//
+//     func (m Money) MarshalDynamoDBAttributeValueWithEncoder(e *Encoder, av *dynamodb.AttributeValue) error {
+//         n := strconv.FormatInt(m.Cents, 10)
+//         if e.NumbersAsStrings {
+//             av.S = &n
+//         } else {
+//             av.N = &n
+//         }
+//         return nil
+//     }
+//
+// The Encoder must not be modified. MarshalerV2 takes precedence over
+// Marshaler if both are implemented.
+type MarshalerV2 interface {
+	MarshalDynamoDBAttributeValueWithEncoder(*Encoder, *dynamodb.AttributeValue) error
+}
+
+// An UnmarshalerV2 is an interface to provide custom unmarshaling of
+// AttributeValues, the same as Unmarshaler, with access to the Decoder's
+// options. Use this for custom types which should honor the caller's
+// configuration, or which unmarshal nested values with the same Decoder.
+//
+// The Decoder must not be modified. UnmarshalerV2 takes precedence over
+// Unmarshaler if both are implemented.
+type UnmarshalerV2 interface {
+	UnmarshalDynamoDBAttributeValueWithDecoder(*Decoder, *dynamodb.AttributeValue) error
+}
+
+var (
+	marshalerV2Type   = reflect.TypeOf((*MarshalerV2)(nil)).Elem()
+	unmarshalerV2Type = reflect.TypeOf((*UnmarshalerV2)(nil)).Elem()
+)
+
+// marshalerEncoder returns the Encoder passed to MarshalerV2 values. The
+// IncludeFields and ExcludeFields options only apply to the outermost
+// struct, so they are cleared for values the marshaler encodes.
+func (e *Encoder) marshalerEncoder() *Encoder {
+	if e.IncludeFields == nil && e.ExcludeFields == nil {
+		return e
+	}
+
+	enc := *e
+	enc.IncludeFields, enc.ExcludeFields = nil, nil
+	return &enc
+}
+
+// unmarshal calls the Unmarshaler or UnmarshalerV2 u with the AttributeValue.
+func (d *Decoder) unmarshal(u interface{}, av *dynamodb.AttributeValue) error {
+	switch u := u.(type) {
+	case UnmarshalerV2:
+		dec := d
+		if d.projection != nil {
+			// DecodeFields only projects the outermost value's attributes.
+			copied := *d
+			copied.projection = nil
+			dec = &copied
+		}
+		return u.UnmarshalDynamoDBAttributeValueWithDecoder(dec, av)
+	default:
+		return u.(Unmarshaler).UnmarshalDynamoDBAttributeValue(av)
+	}
+}
@@ -0,0 +1,150 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m testMoney) MarshalDynamoDBAttributeValueWithEncoder(e *Encoder, av *dynamodb.AttributeValue) error {
+	n := strconv.FormatInt(m.Cents, 10)
+	if e.NumbersAsStrings {
+		av.S = &n
+	} else {
+		av.N = &n
+	}
+	return nil
+}
+
+// Marshaler is ignored in favor of MarshalerV2.
+func (m testMoney) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	return fmt.Errorf("expect MarshalerV2 to be used")
+}
+
+func (m *testMoney) UnmarshalDynamoDBAttributeValueWithDecoder(d *Decoder, av *dynamodb.AttributeValue) error {
+	if av.S != nil && !d.NumbersAsStrings {
+		return fmt.Errorf("expect number, got %v", av)
+	}
+	return d.Decode(av, &m.Cents)
+}
+
+type testEnvelope struct {
+	Payload testPayload
+}
+
+type testPayload struct {
+	A, B string
+}
+
+func (p testEnvelope) MarshalDynamoDBAttributeValueWithEncoder(e *Encoder, av *dynamodb.AttributeValue) error {
+	payload, err := e.Encode(p.Payload)
+	if err != nil {
+		return err
+	}
+	*av = *payload
+	return nil
+}
+
+func (p *testEnvelope) UnmarshalDynamoDBAttributeValueWithDecoder(d *Decoder, av *dynamodb.AttributeValue) error {
+	return d.Decode(av, &p.Payload)
+}
+
+func TestMarshalerV2(t *testing.T) {
+	type record struct {
+		Price testMoney
+		Ptr   *testMoney
+	}
+	in := record{Price: testMoney{Cents: 150}, Ptr: &testMoney{Cents: 2}}
+
+	av, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "150", aws.StringValue(av["Price"].N); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	var actual record
+	if err := UnmarshalMap(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// The marshalers see the Encoder and Decoder options.
+	av, err = MarshalMapWithOptions(in, func(e *Encoder) {
+		e.NumbersAsStrings = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "150", aws.StringValue(av["Price"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if err := UnmarshalMap(av, &actual); err == nil {
+		t.Errorf("expect error without NumbersAsStrings")
+	}
+	actual = record{}
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.NumbersAsStrings = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestMarshalerV2OutermostOptions(t *testing.T) {
+	type record struct {
+		ID       string
+		Envelope testEnvelope
+		Other    string
+	}
+	in := record{ID: "abc", Envelope: testEnvelope{Payload: testPayload{A: "a", B: "b"}}, Other: "x"}
+
+	// IncludeFields only applies to the outermost struct, not to the
+	// values marshaled by the MarshalerV2.
+	av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+		e.IncludeFields = []string{"ID", "Envelope", "A"}
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := av["Other"]; ok {
+		t.Errorf("expect Other excluded, got %v", av)
+	}
+	if e, a := 2, len(av["Envelope"].M); e != a {
+		t.Errorf("expect %d payload attributes, got %v", e, av["Envelope"])
+	}
+
+	var actual record
+	err = DefaultDecoder().DecodeFields(&dynamodb.AttributeValue{M: av}, &actual, "Envelope")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in.Envelope, actual.Envelope; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if len(actual.ID) != 0 {
+		t.Errorf("expect ID not decoded, got %v", actual.ID)
+	}
+}
+
+func TestMarshalerV2Schema(t *testing.T) {
+	if e, a := schemaCustomType, schemaAttrType(reflect.TypeOf(testMoney{}), tag{}); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if isFlattenedStruct(reflect.TypeOf(testEnvelope{})) {
+		t.Errorf("expect MarshalerV2 struct not flattened")
+	}
+}
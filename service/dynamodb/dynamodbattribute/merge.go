@@ -0,0 +1,43 @@
+package dynamodbattribute
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MergeItems returns the item base with the patch applied, in the manner
+// of an RFC 7386 JSON merge patch. Neither base nor patch is modified, the
+// returned item is a deep copy.
+//
+//     - A NULL attribute in patch removes the attribute from base.
+//     - A Map attribute in patch is merged recursively into the Map
+//       attribute of base, or into an empty Map if base's attribute is not
+//       a Map.
+//     - Any other attribute in patch, including Lists and Sets, replaces
+//       the attribute of base.
+//
+// Since NULL removes attributes, a patch cannot set an attribute to NULL.
+// Useful to build the item a partial update results in, or test fixtures
+// from a common base item.
+func MergeItems(base, patch map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	merged := CloneMap(base)
+	if merged == nil {
+		merged = make(map[string]*dynamodb.AttributeValue, len(patch))
+	}
+
+	for k, av := range patch {
+		switch {
+		case av == nil || av.NULL != nil:
+			delete(merged, k)
+		case av.M != nil:
+			var baseM map[string]*dynamodb.AttributeValue
+			if b := merged[k]; b != nil {
+				baseM = b.M
+			}
+			merged[k] = &dynamodb.AttributeValue{M: MergeItems(baseM, av.M)}
+		default:
+			merged[k] = Clone(av)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,68 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestMergeItems(t *testing.T) {
+	base := map[string]*dynamodb.AttributeValue{
+		"id":     {S: aws.String("abc")},
+		"status": {S: aws.String("new")},
+		"note":   {S: aws.String("remove me")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Seattle")},
+			"zip":  {S: aws.String("98101")},
+		}},
+		"tags":  {SS: []*string{aws.String("a"), aws.String("b")}},
+		"count": {N: aws.String("1")},
+	}
+	patch := map[string]*dynamodb.AttributeValue{
+		"status": {S: aws.String("shipped")},
+		"note":   {NULL: aws.Bool(true)},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"zip":    {NULL: aws.Bool(true)},
+			"street": {S: aws.String("1st Ave")},
+		}},
+		"tags": {SS: []*string{aws.String("c")}},
+		"count": {M: map[string]*dynamodb.AttributeValue{
+			"total": {N: aws.String("2")},
+			"none":  {NULL: aws.Bool(true)},
+		}},
+		"missing": {NULL: aws.Bool(true)},
+	}
+	baseCopy, patchCopy := CloneMap(base), CloneMap(patch)
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"id":     {S: aws.String("abc")},
+		"status": {S: aws.String("shipped")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city":   {S: aws.String("Seattle")},
+			"street": {S: aws.String("1st Ave")},
+		}},
+		"tags": {SS: []*string{aws.String("c")}},
+		"count": {M: map[string]*dynamodb.AttributeValue{
+			"total": {N: aws.String("2")},
+		}},
+	}
+	merged := MergeItems(base, patch)
+	if !reflect.DeepEqual(expect, merged) {
+		t.Errorf("expect %v, got %v", expect, merged)
+	}
+
+	if !reflect.DeepEqual(baseCopy, base) || !reflect.DeepEqual(patchCopy, patch) {
+		t.Errorf("expect base and patch not modified")
+	}
+	*merged["id"].S = "changed"
+	if e, a := "abc", *base["id"].S; e != a {
+		t.Errorf("expect merged item to be a copy, got %v", a)
+	}
+
+	if e, a := map[string]*dynamodb.AttributeValue{"id": {S: aws.String("x")}},
+		MergeItems(nil, map[string]*dynamodb.AttributeValue{"id": {S: aws.String("x")}}); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbattribute
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Null is a struct field value which reports whether its attribute was
+// present in the unmarshaled item, and whether it was NULL. Pointer fields
+// are nil for both a NULL and a missing attribute, which cannot be told
+// apart when applying a partial update.
+//
+//     type Patch struct {
+//         Nickname dynamodbattribute.Null[string] `dynamodbav:",omitempty"`
+//     }
+//
+//     switch {
+//     case !p.Nickname.Present:
+//         // leave unchanged
+//     case !p.Nickname.Valid:
+//         // clear the nickname
+//     default:
+//         // set to p.Nickname.Value
+//     }
+//
+// Values which are not Valid are marshaled as NULL. Use the omitempty tag
+// option to omit them from struct fields instead.
+type Null[T any] struct {
+	// The attribute's value, the zero value if not Valid.
+	Value T
+
+	// Set if the attribute was present and not NULL.
+	Valid bool
+
+	// Set if the attribute was present, including when NULL.
+	Present bool
+}
+
+// NullValue returns a Valid Null holding v.
+func NullValue[T any](v T) Null[T] {
+	return Null[T]{Value: v, Valid: true, Present: true}
+}
+
+// MarshalDynamoDBAttributeValue marshals the Value if Valid, or NULL
+// otherwise.
+func (n Null[T]) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if !n.Valid {
+		av.NULL = aws.Bool(true)
+		return nil
+	}
+
+	v, err := Marshal(n.Value)
+	if err != nil {
+		return err
+	}
+	*av = *v
+	return nil
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals the attribute into Value,
+// recording that it was present, and whether it was NULL.
+func (n *Null[T]) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	*n = Null[T]{Present: true}
+	if av == nil || av.NULL != nil {
+		return nil
+	}
+
+	if err := Unmarshal(av, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
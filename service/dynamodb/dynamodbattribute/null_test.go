@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestNullUnmarshal(t *testing.T) {
+	type patch struct {
+		Missing Null[string]
+		Cleared Null[string]
+		Set     Null[int]
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"Cleared": {NULL: aws.Bool(true)},
+		"Set":     {N: aws.String("5")},
+	}
+
+	actual := patch{Cleared: NullValue("old")}
+	if err := UnmarshalMap(item, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := patch{
+		Cleared: Null[string]{Present: true},
+		Set:     NullValue(5),
+	}
+	if e, a := expect, actual; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestNullMarshal(t *testing.T) {
+	type patch struct {
+		Omitted Null[string] `dynamodbav:",omitempty"`
+		Cleared Null[string]
+		Set     Null[[]string] `dynamodbav:",omitempty"`
+	}
+
+	actual, err := MarshalMap(patch{Set: NullValue([]string{"a"})})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"Cleared": {NULL: aws.Bool(true)},
+		"Set":     {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+	}
+	if e, a := expect, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestNullUnmarshalError(t *testing.T) {
+	var v struct{ N Null[int] }
+	err := UnmarshalMap(map[string]*dynamodb.AttributeValue{"N": {S: aws.String("abc")}}, &v)
+	if err == nil {
+		t.Fatalf("expect error")
+	}
+	if v.N.Valid || !v.N.Present {
+		t.Errorf("expect present invalid value, got %v", v.N)
+	}
+}
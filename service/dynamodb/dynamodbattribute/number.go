@@ -0,0 +1,105 @@
+package dynamodbattribute
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// A Number represents an AttributeValue number literal. Use it as a struct
+// field type to preserve the exact value of a number, which may exceed the
+// precision of Go's numeric types. Number values are marshaled as the N
+// AttributeValue type.
+//
+// Number values are also unmarshaled into interface{} values when the
+// Decoder's UseNumber option is enabled.
+type Number string
+
+var numberType = reflect.TypeOf(Number(""))
+
+// NewNumberInt returns the Number of the integer i.
+func NewNumberInt(i int64) Number {
+	return Number(encodeInt(i))
+}
+
+// NewNumberUint returns the Number of the unsigned integer u.
+func NewNumberUint(u uint64) Number {
+	return Number(encodeUint(u))
+}
+
+// NewNumberFloat returns the Number of the float f, formatted the same as
+// float64 values are marshaled. NaN and infinite values are not valid
+// numbers.
+func NewNumberFloat(f float64) Number {
+	return Number(encodeFloat(f, 64))
+}
+
+// NewNumberBig returns the Number of the integer i of arbitrary size.
+func NewNumberBig(i *big.Int) Number {
+	return Number(i.String())
+}
+
+// Float64 attempts to cast the number to a float64, returning
+// the result of the cast or error if the cast failed.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Int64 attempts to cast the number to a int64, returning
+// the result of the cast or error if the cast failed.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Uint64 attempts to cast the number to a uint64, returning
+// the result of the cast or error if the cast failed.
+func (n Number) Uint64() (uint64, error) {
+	return strconv.ParseUint(string(n), 10, 64)
+}
+
+// BigInt attempts to parse the number as an integer of arbitrary size,
+// returning an error if the number is not an integer.
+func (n Number) BigInt() (*big.Int, error) {
+	i, ok := new(big.Int).SetString(string(n), 10)
+	if !ok {
+		return nil, &strconv.NumError{Func: "BigInt", Num: string(n), Err: strconv.ErrSyntax}
+	}
+	return i, nil
+}
+
+// String returns the raw number represented as a string
+func (n Number) String() string {
+	return string(n)
+}
+
+// Compare compares the numbers by value without loss of precision,
+// returning -1 if n is less than o, 0 if they are equal, and +1 if n is
+// greater than o. An error is returned if either is not a valid number.
+func (n Number) Compare(o Number) (int, error) {
+	a, err := n.rat("Compare")
+	if err != nil {
+		return 0, err
+	}
+	b, err := o.rat("Compare")
+	if err != nil {
+		return 0, err
+	}
+	return a.Cmp(b), nil
+}
+
+// Equal returns if the numbers are equal in value without loss of
+// precision, so "1", "1.0", and "10E-1" are equal. Strings which are not
+// valid numbers are only equal if they are identical.
+func (n Number) Equal(o Number) bool {
+	return canonicalNumber(string(n)) == canonicalNumber(string(o))
+}
+
+// rat parses the number as an exact rational, returning an error naming
+// the function fn if it is not a valid number.
+func (n Number) rat(fn string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil, &strconv.NumError{Func: fn, Num: string(n), Err: strconv.ErrSyntax}
+	}
+	return r, nil
+}
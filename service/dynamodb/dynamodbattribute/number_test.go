@@ -0,0 +1,160 @@
+package dynamodbattribute
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestNumberConversions(t *testing.T) {
+	n := Number("123456789012345678901234567890")
+	if _, err := n.Int64(); err == nil {
+		t.Errorf("expect Int64 overflow error, got none")
+	}
+	i, err := n.BigInt()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expectInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if expectInt.Cmp(i) != 0 {
+		t.Errorf("expect %v, got %v", expectInt, i)
+	}
+
+	n = Number("12.5")
+	if f, err := n.Float64(); err != nil || f != 12.5 {
+		t.Errorf("expect 12.5, got %v, %v", f, err)
+	}
+	if _, err := n.BigInt(); err == nil {
+		t.Errorf("expect BigInt error, got none")
+	}
+	if e, a := "12.5", n.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	n = Number("42")
+	if v, err := n.Int64(); err != nil || v != 42 {
+		t.Errorf("expect 42, got %v, %v", v, err)
+	}
+	if v, err := n.Uint64(); err != nil || v != 42 {
+		t.Errorf("expect 42, got %v, %v", v, err)
+	}
+}
+
+func TestNumberConstructors(t *testing.T) {
+	expectInt, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	cases := []struct {
+		n      Number
+		expect string
+	}{
+		{NewNumberInt(-42), "-42"},
+		{NewNumberUint(18446744073709551615), "18446744073709551615"},
+		{NewNumberFloat(0.1), "0.1"},
+		{NewNumberFloat(1e21), "1000000000000000000000"},
+		{NewNumberBig(expectInt), "-123456789012345678901234567890"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, string(c.n); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestNumberCompare(t *testing.T) {
+	cases := []struct {
+		a, b   Number
+		expect int
+		equal  bool
+	}{
+		{"1", "1.0", 0, true},
+		{"10E-1", "1", 0, true},
+		{"-0", "0", 0, true},
+		{"2", "10", -1, false},
+		{"-2", "-10", 1, false},
+		{"9007199254740993", "9007199254740992", 1, false},
+		{"0.30000000000000000001", "0.3", 1, false},
+		{"12345678901234567890123456789012345678", "1.2345678901234567890123456789012345678E37", 0, true},
+	}
+
+	for i, c := range cases {
+		cmp, err := c.a.Compare(c.b)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, cmp; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+		if e, a := c.equal, c.a.Equal(c.b); e != a {
+			t.Errorf("%d, expect equal %t, got %t", i, e, a)
+		}
+	}
+
+	if _, err := Number("abc").Compare("1"); err == nil {
+		t.Errorf("expect error, got none")
+	}
+	if _, err := Number("1").Compare(""); err == nil {
+		t.Errorf("expect error, got none")
+	}
+	if Number("abc").Equal("ABC") {
+		t.Errorf("expect invalid numbers not equal")
+	}
+}
+
+func TestNumberMarshalUnmarshal(t *testing.T) {
+	type testRecord struct {
+		Exact  Number
+		AsStr  Number `dynamodbav:",string"`
+		Empty  Number
+		Set    []Number `dynamodbav:",numberset"`
+		Nested interface{}
+	}
+
+	in := testRecord{
+		Exact: "12345678901234567890.123456789",
+		AsStr: "42",
+		Set:   []Number{"1", "2.5"},
+	}
+
+	av, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{
+		"Exact":  {N: aws.String("12345678901234567890.123456789")},
+		"AsStr":  {S: aws.String("42")},
+		"Empty":  {NULL: aws.Bool(true)},
+		"Set":    {NS: []*string{aws.String("1"), aws.String("2.5")}},
+		"Nested": {NULL: aws.Bool(true)},
+	}
+	if !reflect.DeepEqual(expect, av) {
+		t.Errorf("expect %v, got %v", expect, av)
+	}
+
+	av["Nested"] = &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{
+		{N: aws.String("1.10")},
+		{NS: []*string{aws.String("3")}},
+	}}
+
+	var actual testRecord
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.UseNumber = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	in.Nested = []interface{}{Number("1.10"), []Number{"3"}}
+	if !reflect.DeepEqual(in, actual) {
+		t.Errorf("expect %v, got %v", in, actual)
+	}
+}
+
+func TestUnmarshalNumberIntoString(t *testing.T) {
+	var actual string
+	err := Unmarshal(&dynamodb.AttributeValue{N: aws.String("1")}, &actual)
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package dynamodbattribute
+
+import "github.com/aws/aws-sdk-go/service/dynamodb"
+
+// GetPath unmarshals the AttributeValue at the document path of the item,
+// such as "a.b[2].c", into out. Returns false if there is no attribute at
+// the path. An InvalidPathError is returned if the path is malformed.
+//
+//     var city string
+//     ok, err := dynamodbattribute.GetPath(item, "address.city", &city)
+//
+// Paths are made of attribute names separated by "." and list indexes
+// such as "[2]".
+func GetPath(item map[string]*dynamodb.AttributeValue, path string, out interface{}) (bool, error) {
+	return GetPathWithOptions(item, path, out)
+}
+
+// GetPathWithOptions is the same as GetPath, with the Decoder configured
+// by the `opts` functional options.
+func GetPathWithOptions(item map[string]*dynamodb.AttributeValue, path string, out interface{}, opts ...func(*Decoder)) (bool, error) {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return false, err
+	}
+
+	av, ok := lookupPath(&dynamodb.AttributeValue{M: item}, segments)
+	if !ok {
+		return false, nil
+	}
+	if err := UnmarshalWithOptions(av, out, opts...); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// SetPath marshals the value and sets it at the document path of the item,
+// such as "a.b[2].c". Maps along the path are added if not present. Lists
+// along the path must already have an element at the index. The item is
+// modified in place, including any maps and lists nested within it. An
+// InvalidPathError is returned if the path is malformed or cannot be set.
+func SetPath(item map[string]*dynamodb.AttributeValue, path string, v interface{}) error {
+	return SetPathWithOptions(item, path, v)
+}
+
+// SetPathWithOptions is the same as SetPath, with the Encoder configured
+// by the `opts` functional options.
+func SetPathWithOptions(item map[string]*dynamodb.AttributeValue, path string, v interface{}, opts ...func(*Encoder)) error {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return &InvalidPathError{Path: path, msg: "cannot set path of nil item"}
+	}
+
+	av, err := MarshalWithOptions(v, opts...)
+	if err != nil {
+		return err
+	}
+
+	parent, err := parentAtPath(item, path, segments, true)
+	if err != nil {
+		return err
+	}
+	last := segments[len(segments)-1]
+	if last.isIndex {
+		if last.index >= len(parent.L) {
+			return outOfRange(path, last, true)
+		}
+		parent.L[last.index] = av
+	} else {
+		parent.M[last.name] = av
+	}
+	return nil
+}
+
+// DeletePath removes the attribute at the document path of the item, such
+// as "a.b[2].c", returning false if there was no attribute at the path.
+// Elements removed from a list shift the elements after them down, as with
+// an UpdateExpression REMOVE action. The item is modified in place. An
+// InvalidPathError is returned if the path is malformed.
+func DeletePath(item map[string]*dynamodb.AttributeValue, path string) (bool, error) {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return false, err
+	}
+
+	parent, err := parentAtPath(item, path, segments, false)
+	if parent == nil || err != nil {
+		return false, nil
+	}
+
+	last := segments[len(segments)-1]
+	if last.isIndex {
+		if last.index >= len(parent.L) {
+			return false, nil
+		}
+		parent.L = append(parent.L[:last.index], parent.L[last.index+1:]...)
+		return true, nil
+	}
+	if _, ok := parent.M[last.name]; !ok {
+		return false, nil
+	}
+	delete(parent.M, last.name)
+	return true, nil
+}
+
+// lookupPath returns the AttributeValue at the path segments within av.
+func lookupPath(av *dynamodb.AttributeValue, segments []pathSegment) (*dynamodb.AttributeValue, bool) {
+	for _, s := range segments {
+		var ok bool
+		switch {
+		case av == nil:
+			return nil, false
+		case s.isIndex:
+			if ok = s.index < len(av.L); ok {
+				av = av.L[s.index]
+			}
+		default:
+			av, ok = av.M[s.name]
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return av, true
+}
+
+// parentAtPath returns the map or list AttributeValue holding the last
+// segment of the path. If create is set maps along the path are added if
+// not present, otherwise nil is returned if the parent is not present.
+func parentAtPath(item map[string]*dynamodb.AttributeValue, path string, segments []pathSegment, create bool) (*dynamodb.AttributeValue, error) {
+	parent := &dynamodb.AttributeValue{M: item}
+	for i, s := range segments {
+		switch {
+		case s.isIndex && parent.L == nil:
+			return nil, &InvalidPathError{Path: path, msg: "cannot index non-list attribute with " + s.String()}
+		case !s.isIndex && parent.M == nil:
+			return nil, &InvalidPathError{Path: path, msg: "cannot get attribute " + s.name + " of non-map attribute"}
+		case i == len(segments)-1:
+			return parent, nil
+		}
+
+		var child *dynamodb.AttributeValue
+		if s.isIndex {
+			if s.index >= len(parent.L) {
+				return nil, outOfRange(path, s, create)
+			}
+			child = parent.L[s.index]
+		} else {
+			child = parent.M[s.name]
+		}
+
+		if child == nil || child.NULL != nil {
+			if !create {
+				return nil, nil
+			}
+			if segments[i+1].isIndex {
+				return nil, &InvalidPathError{Path: path, msg: "list " + s.String() + " not present"}
+			}
+			child = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{}}
+			if s.isIndex {
+				parent.L[s.index] = child
+			} else {
+				parent.M[s.name] = child
+			}
+		}
+		parent = child
+	}
+
+	return parent, nil
+}
+
+// outOfRange returns the error for a list index out of range, or nil if
+// the path is only being looked up.
+func outOfRange(path string, s pathSegment, create bool) error {
+	if !create {
+		return nil
+	}
+	return &InvalidPathError{Path: path, msg: "list index " + s.String() + " out of range"}
+}
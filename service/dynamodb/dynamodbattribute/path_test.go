@@ -0,0 +1,145 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func newTestPathItem() map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String("abc")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Seattle")},
+		}},
+		"orders": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"total": {N: aws.String("10")}}},
+			{M: map[string]*dynamodb.AttributeValue{"total": {N: aws.String("20")}}},
+		}},
+		"none": {NULL: aws.Bool(true)},
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	item := newTestPathItem()
+
+	var city string
+	if ok, err := GetPath(item, "address.city", &city); !ok || err != nil {
+		t.Fatalf("expect found, got %t, %v", ok, err)
+	}
+	if e, a := "Seattle", city; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var total int
+	if ok, err := GetPath(item, "orders[1].total", &total); !ok || err != nil {
+		t.Fatalf("expect found, got %t, %v", ok, err)
+	}
+	if e, a := 20, total; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	for _, path := range []string{"missing", "orders[2].total", "address.zip", "id.x", "none.x", "address[0]"} {
+		var v interface{}
+		if ok, err := GetPath(item, path, &v); ok || err != nil {
+			t.Errorf("%s, expect not found, got %t, %v", path, ok, err)
+		}
+	}
+
+	var n int
+	if ok, err := GetPath(item, "id", &n); !ok || err == nil {
+		t.Errorf("expect found with unmarshal error, got %t, %v", ok, err)
+	}
+	if _, err := GetPath(item, "orders[", &n); err == nil {
+		t.Errorf("expect path error")
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	item := newTestPathItem()
+
+	sets := []struct {
+		path  string
+		value interface{}
+	}{
+		{"address.zip", "98101"},
+		{"orders[0].total", 15},
+		{"prefs.theme.color", "dark"},
+		{"none.x", true},
+	}
+	for _, s := range sets {
+		if err := SetPath(item, s.path, s.value); err != nil {
+			t.Fatalf("%s, expect no error, got %v", s.path, err)
+		}
+	}
+
+	expect := newTestPathItem()
+	expect["address"].M["zip"] = &dynamodb.AttributeValue{S: aws.String("98101")}
+	expect["orders"].L[0].M["total"] = &dynamodb.AttributeValue{N: aws.String("15")}
+	expect["prefs"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"theme": {M: map[string]*dynamodb.AttributeValue{"color": {S: aws.String("dark")}}},
+	}}
+	expect["none"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"x": {BOOL: aws.Bool(true)},
+	}}
+	if !reflect.DeepEqual(expect, item) {
+		t.Errorf("expect %v, got %v", expect, item)
+	}
+
+	cases := map[string]string{
+		"orders[2]":       `InvalidPathError: list index [2] out of range, "orders[2]"`,
+		"orders[2].total": `InvalidPathError: list index [2] out of range, "orders[2].total"`,
+		"id.x":            `InvalidPathError: cannot get attribute x of non-map attribute, "id.x"`,
+		"address[0]":      `InvalidPathError: cannot index non-list attribute with [0], "address[0]"`,
+		"tags[0]":         `InvalidPathError: list tags not present, "tags[0]"`,
+		"a..b":            `InvalidPathError: empty attribute name, "a..b"`,
+	}
+	for path, expect := range cases {
+		if e, a := expect, errString(SetPath(item, path, 1)); e != a {
+			t.Errorf("%s, expect %q, got %q", path, e, a)
+		}
+	}
+}
+
+func TestDeletePath(t *testing.T) {
+	item := newTestPathItem()
+
+	cases := []struct {
+		path    string
+		deleted bool
+	}{
+		{"address.city", true},
+		{"address.city", false},
+		{"orders[0]", true},
+		{"orders[1]", false},
+		{"missing.x", false},
+		{"id.x", false},
+		{"id", true},
+	}
+	for _, c := range cases {
+		deleted, err := DeletePath(item, c.path)
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", c.path, err)
+		}
+		if e, a := c.deleted, deleted; e != a {
+			t.Errorf("%s, expect deleted %t, got %t", c.path, e, a)
+		}
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"address": {M: map[string]*dynamodb.AttributeValue{}},
+		"orders": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"total": {N: aws.String("20")}}},
+		}},
+		"none": {NULL: aws.Bool(true)},
+	}
+	if !reflect.DeepEqual(expect, item) {
+		t.Errorf("expect %v, got %v", expect, item)
+	}
+
+	if _, err := DeletePath(item, "[0]"); err == nil {
+		t.Errorf("expect path error")
+	}
+}
@@ -0,0 +1,258 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// structProgramsEnabled allows the struct programs to be disabled, so
+// benchmarks can compare them to the reflection path.
+var structProgramsEnabled = true
+
+// A structProgram is the precompiled encoding and decoding of a struct
+// type whose fields are all scalars, strings, or byte slices, and which
+// have no tag options other than a name and omitempty. The fields'
+// encoders and decoders are selected once per type, instead of dispatching
+// on the kind, checking for Marshaler, Unmarshaler, and Validator
+// implementations, and parsing the tag options for every value.
+//
+// Structs with other fields are encoded and decoded by the reflection path,
+// encodeStruct and decodeStruct.
+type structProgram struct {
+	fields []fieldProgram
+}
+
+type fieldProgram struct {
+	tag
+	name  string
+	index int
+
+	// encode sets the AttributeValue of the field value.
+	encode func(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value)
+
+	// decode sets the field value from the AttributeValue, returning false
+	// if the AttributeValue is not of the field's type and must be decoded
+	// by the reflection path.
+	decode func(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool
+}
+
+var structProgramCache = struct {
+	sync.RWMutex
+	m map[fieldCacheKey]*structProgram
+}{m: map[fieldCacheKey]*structProgram{}}
+
+// cachedStructProgram returns the structProgram of the struct type t, or
+// nil if the type cannot be compiled into a program.
+func cachedStructProgram(t reflect.Type, opts MarshalOptions) *structProgram {
+	if !structProgramsEnabled {
+		return nil
+	}
+
+	key := fieldCacheKey{typ: t, opts: opts}
+
+	structProgramCache.RLock()
+	p, ok := structProgramCache.m[key]
+	structProgramCache.RUnlock()
+	if ok {
+		return p
+	}
+
+	p = compileStructProgram(t, opts)
+	structProgramCache.Lock()
+	structProgramCache.m[key] = p
+	structProgramCache.Unlock()
+
+	return p
+}
+
+func compileStructProgram(t reflect.Type, opts MarshalOptions) *structProgram {
+	fields := unionStructFields(t, opts)
+	p := &structProgram{fields: make([]fieldProgram, 0, len(fields))}
+
+	for _, f := range fields {
+		if len(f.Index) != 1 || len(f.Name) == 0 || !programTag(f.tag) {
+			return nil
+		}
+		ft := f.Type
+		if ft.NumMethod() != 0 || reflect.PtrTo(ft).NumMethod() != 0 {
+			// May implement Marshaler, Unmarshaler, Validator, or IsZero.
+			return nil
+		}
+
+		fp := fieldProgram{tag: f.tag, name: f.Name, index: f.Index[0]}
+		switch ft.Kind() {
+		case reflect.String:
+			fp.encode, fp.decode = encodeStringField, decodeStringField
+		case reflect.Bool:
+			fp.encode, fp.decode = encodeBoolField, decodeBoolField
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fp.encode, fp.decode = encodeIntField, decodeIntField
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			fp.encode, fp.decode = encodeUintField, decodeUintField
+		case reflect.Float32, reflect.Float64:
+			fp.encode, fp.decode = encodeFloatField, decodeFloatField
+		case reflect.Slice:
+			if ft.Elem().Kind() != reflect.Uint8 || ft.Elem().NumMethod() != 0 {
+				return nil
+			}
+			fp.encode, fp.decode = encodeBytesField, decodeBytesField
+		default:
+			return nil
+		}
+		p.fields = append(p.fields, fp)
+	}
+
+	return p
+}
+
+// programTag returns if the tag has no options other than omitempty, and
+// the key options which do not change how the field is encoded.
+func programTag(t tag) bool {
+	t.OmitEmpty = false
+	t.HashKey, t.RangeKey = false, false
+	return len(t.options()) == 0
+}
+
+// encodeStructProgram encodes the struct value v with the program, the
+// same as encodeStruct would.
+func (e *Encoder) encodeStructProgram(av *dynamodb.AttributeValue, v reflect.Value, p *structProgram) {
+	// Allocate the field AttributeValues together instead of individually.
+	elems := make([]dynamodb.AttributeValue, len(p.fields))
+	av.M = make(map[string]*dynamodb.AttributeValue, len(p.fields))
+	for i := range p.fields {
+		f := &p.fields[i]
+		fv := v.Field(f.index)
+		if f.OmitEmpty && emptyValue(fv) {
+			continue
+		}
+
+		elem := &elems[i]
+		f.encode(e, elem, fv)
+		if f.OmitEmpty && elem.NULL != nil {
+			continue
+		}
+		av.M[f.name] = elem
+	}
+	if len(av.M) == 0 {
+		encodeNull(av)
+	}
+}
+
+// decodeStructProgram decodes the AttributeValue map into the struct value
+// v with the program, the same as decodeStruct would.
+func (d *Decoder) decodeStructProgram(avMap map[string]*dynamodb.AttributeValue, v reflect.Value, p *structProgram) error {
+	for i := range p.fields {
+		f := &p.fields[i]
+		av, ok := attrByName(avMap, f.name)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(f.index)
+		if av != nil && f.decode(d, av, fv) {
+			continue
+		}
+		if err := d.decode(av, fv, f.tag); err != nil {
+			return prefixValidationPath(err, f.name)
+		}
+	}
+
+	return nil
+}
+
+func encodeStringField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	e.encodeString(av, v)
+}
+
+func decodeStringField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.S == nil {
+		return false
+	}
+	v.SetString(d.intern(*av.S))
+	return true
+}
+
+func encodeBoolField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	b := v.Bool()
+	av.BOOL = &b
+}
+
+func decodeBoolField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.BOOL == nil {
+		return false
+	}
+	v.SetBool(*av.BOOL)
+	return true
+}
+
+func encodeIntField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	n := encodeInt(v.Int())
+	av.N = &n
+}
+
+func decodeIntField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.N == nil {
+		return false
+	}
+	i, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil || v.OverflowInt(i) {
+		return false
+	}
+	v.SetInt(i)
+	return true
+}
+
+func encodeUintField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	n := encodeUint(v.Uint())
+	av.N = &n
+}
+
+func decodeUintField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.N == nil {
+		return false
+	}
+	i, err := strconv.ParseUint(*av.N, 10, 64)
+	if err != nil || v.OverflowUint(i) {
+		return false
+	}
+	v.SetUint(i)
+	return true
+}
+
+func encodeFloatField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	n := encodeFloat(v.Float(), v.Type().Bits())
+	av.N = &n
+}
+
+func decodeFloatField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.N == nil {
+		return false
+	}
+	f, err := strconv.ParseFloat(*av.N, 64)
+	if err != nil || v.OverflowFloat(f) {
+		return false
+	}
+	v.SetFloat(f)
+	return true
+}
+
+func encodeBytesField(e *Encoder, av *dynamodb.AttributeValue, v reflect.Value) {
+	if v.Len() == 0 {
+		encodeNull(av)
+		return
+	}
+	b := make([]byte, v.Len())
+	copy(b, v.Bytes())
+	av.B = b
+}
+
+func decodeBytesField(d *Decoder, av *dynamodb.AttributeValue, v reflect.Value) bool {
+	if av.B == nil {
+		return false
+	}
+	v.SetBytes(d.binaryBytes(av.B))
+	return true
+}
@@ -0,0 +1,181 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testProgramItem struct {
+	ID      string
+	Name    string `dynamodbav:"name,omitempty"`
+	Active  bool
+	Count   int
+	Small   int8
+	Size    uint32
+	Ratio   float32
+	Score   float64 `dynamodbav:",omitempty"`
+	Data    []byte
+	Ignored string `dynamodbav:"-"`
+}
+
+// withStructPrograms calls fn with the struct programs enabled and
+// disabled, returning the results of each.
+func withStructPrograms(fn func() interface{}) (program, reflection interface{}) {
+	defer func(enabled bool) {
+		structProgramsEnabled = enabled
+	}(structProgramsEnabled)
+
+	structProgramsEnabled = true
+	program = fn()
+	structProgramsEnabled = false
+	reflection = fn()
+
+	return program, reflection
+}
+
+func TestStructProgramCompile(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		compiled bool
+	}{
+		{testProgramItem{}, true},
+		{struct{ A *string }{}, false},
+		{struct{ A []string }{}, false},
+		{struct{ A Number }{}, false},
+		{struct {
+			A int `dynamodbav:",string"`
+		}{}, false},
+		{struct {
+			A string `dynamodbav:",alias=B"`
+		}{}, false},
+		{struct{ testProgramItem }{}, false},
+	}
+
+	for i, c := range cases {
+		p := compileStructProgram(reflect.TypeOf(c.value), MarshalOptions{SupportJSONTags: true})
+		if e, a := c.compiled, p != nil; e != a {
+			t.Errorf("%d, expect compiled %t, got %t", i, e, a)
+		}
+	}
+}
+
+func TestStructProgramMarshal(t *testing.T) {
+	cases := []interface{}{
+		testProgramItem{},
+		testProgramItem{ID: "abc", Name: "name", Active: true, Count: -12, Small: 3,
+			Size: 42, Ratio: 0.5, Score: 1.25, Data: []byte{1, 2, 3}, Ignored: "x"},
+		&testProgramItem{ID: "abc"},
+		map[string]testProgramItem{"a": {Count: 1}},
+	}
+
+	for i, c := range cases {
+		program, reflection := withStructPrograms(func() interface{} {
+			av, err := Marshal(c)
+			if err != nil {
+				t.Fatalf("%d, expect no error, got %v", i, err)
+			}
+			return av
+		})
+		if !reflect.DeepEqual(program, reflection) {
+			t.Errorf("%d, expect %v, got %v", i, reflection, program)
+		}
+	}
+}
+
+func TestStructProgramUnmarshal(t *testing.T) {
+	cases := []map[string]*dynamodb.AttributeValue{
+		{
+			"ID":     {S: aws.String("abc")},
+			"name":   {S: aws.String("name")},
+			"Active": {BOOL: aws.Bool(true)},
+			"Count":  {N: aws.String("-12")},
+			"Small":  {N: aws.String("3")},
+			"Size":   {N: aws.String("42")},
+			"Ratio":  {N: aws.String("0.5")},
+			"Score":  {N: aws.String("1.25")},
+			"Data":   {B: []byte{1, 2, 3}},
+		},
+		{
+			"id":     {S: aws.String("abc")},
+			"Active": {NULL: aws.Bool(true)},
+			"Data":   {NULL: aws.Bool(true)},
+		},
+		{"Small": {N: aws.String("300")}},
+		{"Size": {N: aws.String("-1")}},
+		{"Count": {N: aws.String("1.5")}},
+		{"Ratio": {N: aws.String("1e100")}},
+		{"ID": {N: aws.String("1")}},
+		{"Active": {S: aws.String("true")}},
+		{"Data": {S: aws.String("data")}},
+	}
+
+	type result struct {
+		Item testProgramItem
+		Err  string
+	}
+	for i, c := range cases {
+		program, reflection := withStructPrograms(func() interface{} {
+			r := result{Item: testProgramItem{Name: "keep"}}
+			r.Err = errString(UnmarshalMap(c, &r.Item))
+			return r
+		})
+		if !reflect.DeepEqual(program, reflection) {
+			t.Errorf("%d, expect %+v, got %+v", i, reflection, program)
+		}
+	}
+}
+
+func benchmarkStructProgram(b *testing.B, enabled bool, fn func() error) {
+	defer func(enabled bool) {
+		structProgramsEnabled = enabled
+	}(structProgramsEnabled)
+	structProgramsEnabled = enabled
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := fn(); err != nil {
+			b.Fatalf("expect no error, got %v", err)
+		}
+	}
+}
+
+var benchmarkProgramItem = testProgramItem{ID: "abc", Name: "name", Active: true, Count: -12,
+	Small: 3, Size: 42, Ratio: 0.5, Score: 1.25, Data: []byte{1, 2, 3}}
+
+func benchmarkMarshalStruct(b *testing.B, enabled bool) {
+	benchmarkStructProgram(b, enabled, func() error {
+		_, err := MarshalMap(benchmarkProgramItem)
+		return err
+	})
+}
+
+func BenchmarkMarshalStruct_Program(b *testing.B) {
+	benchmarkMarshalStruct(b, true)
+}
+
+func BenchmarkMarshalStruct_Reflection(b *testing.B) {
+	benchmarkMarshalStruct(b, false)
+}
+
+func benchmarkUnmarshalStruct(b *testing.B, enabled bool) {
+	av, err := MarshalMap(benchmarkProgramItem)
+	if err != nil {
+		b.Fatalf("expect no error, got %v", err)
+	}
+	benchmarkStructProgram(b, enabled, func() error {
+		var out testProgramItem
+		return UnmarshalMap(av, &out)
+	})
+}
+
+func BenchmarkUnmarshalStruct_Program(b *testing.B) {
+	benchmarkUnmarshalStruct(b, true)
+}
+
+func BenchmarkUnmarshalStruct_Reflection(b *testing.B) {
+	benchmarkUnmarshalStruct(b, false)
+}
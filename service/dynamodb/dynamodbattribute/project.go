@@ -0,0 +1,157 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Project copies the fields of the struct src into the struct dst points to,
+// matching fields by their attribute names. Use Project to convert decoded
+// model structs into API structs (DTOs) which only have the fields that may
+// be exposed, without hand-written copies.
+//
+//     type Account struct {
+//         ID           string `dynamodbav:"id"`
+//         Email        string `dynamodbav:"email"`
+//         PasswordHash []byte `dynamodbav:"pw"`
+//     }
+//
+//     type AccountDTO struct {
+//         ID    string `json:"id"`
+//         Email string `json:"email"`
+//     }
+//
+//     var dto AccountDTO
+//     err := dynamodbattribute.Project(account, &dto)
+//
+// Attribute names are determined the same as Marshal, from the `dynamodbav`
+// struct tag, falling back to the `json` tag. Fields of dst without a
+// matching src field are not modified, and src fields without a matching
+// dst field are ignored.
+//
+// Values assignable to the dst field's type are copied as is, sharing any
+// referenced maps, slices, or pointers. Numeric values are converted to
+// the dst field's numeric type. All other values are marshaled to an
+// AttributeValue and unmarshaled into the dst field, e.g. nested model
+// structs into nested DTO structs.
+//
+// The matching fields of each pair of types are cached.
+func Project(src, dst interface{}) error {
+	sv := reflect.ValueOf(src)
+	for sv.Kind() == reflect.Ptr && !sv.IsNil() {
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct {
+		return &InvalidMarshalError{msg: fmt.Sprintf("project source must be a struct, %v", reflect.TypeOf(src))}
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return &InvalidUnmarshalError{Type: reflect.TypeOf(dst)}
+	}
+	dv = dv.Elem()
+
+	for _, f := range projectionFields(sv.Type(), dv.Type()) {
+		fv, found := fieldByIndex(sv, f.src, func(v *reflect.Value) bool {
+			return false
+		})
+		if !found {
+			continue
+		}
+		dfv, _ := fieldByIndex(dv, f.dst, func(v *reflect.Value) bool {
+			v.Set(reflect.New(v.Type().Elem()))
+			return true
+		})
+
+		switch f.mode {
+		case projectAssign:
+			dfv.Set(fv)
+		case projectConvert:
+			dfv.Set(fv.Convert(dfv.Type()))
+		default:
+			av, err := Marshal(fv.Interface())
+			if err != nil {
+				return err
+			}
+			if err := NewDecoder().decode(av, dfv, tag{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type projectMode int
+
+const (
+	projectAssign projectMode = iota
+	projectConvert
+	projectMarshal
+)
+
+type projectionField struct {
+	src, dst []int
+	mode     projectMode
+}
+
+type projectionKey struct {
+	src, dst reflect.Type
+}
+
+var projectionCache = struct {
+	sync.RWMutex
+	m map[projectionKey][]projectionField
+}{m: map[projectionKey][]projectionField{}}
+
+// projectionFields returns the matching fields of the src and dst struct
+// types, and how the field values should be copied.
+func projectionFields(src, dst reflect.Type) []projectionField {
+	key := projectionKey{src: src, dst: dst}
+
+	projectionCache.RLock()
+	fields, ok := projectionCache.m[key]
+	projectionCache.RUnlock()
+	if ok {
+		return fields
+	}
+
+	opts := MarshalOptions{SupportJSONTags: true}
+	dstFields := map[string]field{}
+	for _, f := range unionStructFields(dst, opts) {
+		dstFields[f.Name] = f
+	}
+
+	for _, sf := range unionStructFields(src, opts) {
+		df, ok := dstFields[sf.Name]
+		if !ok {
+			continue
+		}
+
+		mode := projectMarshal
+		switch {
+		case sf.Type.AssignableTo(df.Type):
+			mode = projectAssign
+		case isNumberKind(sf.Type.Kind()) && isNumberKind(df.Type.Kind()):
+			mode = projectConvert
+		}
+		fields = append(fields, projectionField{src: sf.Index, dst: df.Index, mode: mode})
+	}
+
+	projectionCache.Lock()
+	projectionCache.m[key] = fields
+	projectionCache.Unlock()
+
+	return fields
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
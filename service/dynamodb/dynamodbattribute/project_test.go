@@ -0,0 +1,87 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testProjectAddress struct {
+	City   string `dynamodbav:"city"`
+	Secret string `dynamodbav:"secret"`
+}
+
+type testProjectAccount struct {
+	ID       string `dynamodbav:"id"`
+	Email    string `dynamodbav:"email"`
+	Password []byte `dynamodbav:"pw"`
+	Logins   int32  `dynamodbav:"logins"`
+	Tags     []string
+	Address  testProjectAddress `dynamodbav:"address"`
+}
+
+type testProjectAddressDTO struct {
+	City string `json:"city"`
+}
+
+type testProjectAccountDTO struct {
+	ID      string                 `json:"id"`
+	Email   string                 `json:"email"`
+	Logins  int64                  `json:"logins"`
+	Tags    []string               `json:"Tags"`
+	Address *testProjectAddressDTO `json:"address"`
+	Extra   string                 `json:"extra"`
+}
+
+func TestProject(t *testing.T) {
+	src := testProjectAccount{
+		ID:       "abc",
+		Email:    "a@example.com",
+		Password: []byte("hash"),
+		Logins:   42,
+		Tags:     []string{"a"},
+		Address:  testProjectAddress{City: "Seattle", Secret: "x"},
+	}
+
+	dst := testProjectAccountDTO{Extra: "keep"}
+	if err := Project(&src, &dst); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := testProjectAccountDTO{
+		ID:      "abc",
+		Email:   "a@example.com",
+		Logins:  42,
+		Tags:    []string{"a"},
+		Address: &testProjectAddressDTO{City: "Seattle"},
+		Extra:   "keep",
+	}
+	if !reflect.DeepEqual(expect, dst) {
+		t.Errorf("expect %v, got %v", expect, dst)
+	}
+
+	// Cached fields produce the same result.
+	var dst2 testProjectAccountDTO
+	if err := Project(src, &dst2); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect.Extra = ""
+	if !reflect.DeepEqual(expect, dst2) {
+		t.Errorf("expect %v, got %v", expect, dst2)
+	}
+}
+
+func TestProjectInvalid(t *testing.T) {
+	var dst testProjectAccountDTO
+	if err := Project("abc", &dst); err == nil {
+		t.Errorf("expect error for non-struct source, got none")
+	}
+	if err := Project(nil, &dst); err == nil {
+		t.Errorf("expect error for nil source, got none")
+	}
+	if err := Project(testProjectAccount{}, dst); err == nil {
+		t.Errorf("expect error for non-pointer destination, got none")
+	}
+	if err := Project(testProjectAccount{}, (*testProjectAccountDTO)(nil)); err == nil {
+		t.Errorf("expect error for nil destination, got none")
+	}
+}
@@ -0,0 +1,350 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SchemaChangeKind is the kind of breaking change found between two
+// versions of a Go model.
+type SchemaChangeKind int
+
+// Enumeration of the breaking changes CheckSchemaCompatibility reports.
+const (
+	// AttributeRemoved is an attribute which was present in the old model
+	// but is not in the new model. Renaming a field's attribute name is
+	// reported as the old name being removed, unless the new field has
+	// an alias tag option of the old name.
+	AttributeRemoved SchemaChangeKind = iota
+
+	// AttributeRequiredAdded is a new attribute marked as required. Items
+	// written by the old model will not contain the attribute.
+	AttributeRequiredAdded
+
+	// AttributeTypeChanged is an attribute whose AttributeValue type
+	// changed between the models, e.g. N to S.
+	AttributeTypeChanged
+
+	// AttributeSetChanged is an attribute which changed between a set
+	// (SS, NS, BS) and a list (L) AttributeValue type.
+	AttributeSetChanged
+)
+
+func (k SchemaChangeKind) String() string {
+	switch k {
+	case AttributeRemoved:
+		return "attribute removed"
+	case AttributeRequiredAdded:
+		return "required attribute added"
+	case AttributeTypeChanged:
+		return "attribute type changed"
+	case AttributeSetChanged:
+		return "attribute set type changed"
+	default:
+		return fmt.Sprintf("SchemaChangeKind(%d)", int(k))
+	}
+}
+
+// A SchemaChange is a breaking serialization change found between two
+// versions of a Go model.
+type SchemaChange struct {
+	Kind SchemaChangeKind
+
+	// Path of the attribute within the item. Nested attributes are
+	// separated by ".". Elements of lists are referenced with "[]",
+	// and values of maps with "{}".
+	Path string
+
+	// AttributeValue type of the attribute in the old and new model. Empty
+	// if the attribute is not present in the model.
+	OldType, NewType string
+}
+
+func (c SchemaChange) String() string {
+	switch c.Kind {
+	case AttributeRemoved, AttributeRequiredAdded:
+		return fmt.Sprintf("%s: %s", c.Kind, c.Path)
+	default:
+		return fmt.Sprintf("%s: %s, %s to %s", c.Kind, c.Path, c.OldType, c.NewType)
+	}
+}
+
+// CheckSchemaCompatibility compares two versions of a Go model struct and
+// returns the changes which would break reading items written by the old
+// model with the new model, or items written by the new model with the old.
+// Both oldModel and newModel must be a struct or pointer to a struct value,
+// e.g. the zero value of the model types.
+//
+// Changes are reported in path order. An empty slice is returned if the
+// models are compatible.
+//
+//     changes, err := dynamodbattribute.CheckSchemaCompatibility(v1.Record{}, v2.Record{})
+//     if err != nil {
+//         return err
+//     }
+//     for _, c := range changes {
+//         fmt.Println(c)
+//     }
+//
+// Fields of types implementing the Marshaler interface are opaque to the
+// checker, and are only reported if the Go type of the field changed.
+// Fields of interface type are compatible with any attribute type.
+func CheckSchemaCompatibility(oldModel, newModel interface{}) ([]SchemaChange, error) {
+	opts := MarshalOptions{SupportJSONTags: true}
+
+	oldType, err := schemaStructType(oldModel)
+	if err != nil {
+		return nil, err
+	}
+	newType, err := schemaStructType(newModel)
+	if err != nil {
+		return nil, err
+	}
+
+	c := schemaChecker{
+		opts:     opts,
+		visiting: map[[2]reflect.Type]bool{},
+	}
+	c.compareStructs("", oldType, newType)
+
+	sort.Sort(schemaChangesByPath(c.changes))
+	return c.changes, nil
+}
+
+// AttributeNames returns the names of the attributes the fields of the
+// struct value in are marshaled to, followed by any other names the fields
+// are unmarshaled from with the alias and readfrom tag options. Such as to
+// find the names which must be substituted with placeholders in
+// expressions. Nil is returned if in is not a struct.
+func AttributeNames(in interface{}) []string {
+	t, err := schemaStructType(in)
+	if err != nil || t == timeType {
+		return nil
+	}
+
+	fields := unionStructFields(t, MarshalOptions{SupportJSONTags: true})
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	for _, f := range fields {
+		if len(f.ReadFrom) != 0 {
+			names = append(names, f.ReadFrom)
+		}
+		names = append(names, f.Aliases...)
+	}
+
+	return names
+}
+
+func schemaStructType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, &InvalidMarshalError{
+			msg: fmt.Sprintf("schema compatibility requires struct types, %v", reflect.TypeOf(v)),
+		}
+	}
+	return t, nil
+}
+
+type schemaChecker struct {
+	opts     MarshalOptions
+	visiting map[[2]reflect.Type]bool
+	changes  []SchemaChange
+}
+
+func (c *schemaChecker) add(kind SchemaChangeKind, path, oldType, newType string) {
+	c.changes = append(c.changes, SchemaChange{
+		Kind:    kind,
+		Path:    path,
+		OldType: oldType,
+		NewType: newType,
+	})
+}
+
+func (c *schemaChecker) compareStructs(path string, oldType, newType reflect.Type) {
+	// Guard against recursive types, the same pair of types may still be
+	// compared at different paths.
+	key := [2]reflect.Type{oldType, newType}
+	if c.visiting[key] {
+		return
+	}
+	c.visiting[key] = true
+	defer delete(c.visiting, key)
+
+	newFields := map[string]field{}
+	newAliases := map[string]field{}
+	for _, f := range unionStructFields(newType, c.opts) {
+		newFields[f.Name] = f
+		for _, alias := range f.Aliases {
+			newAliases[alias] = f
+		}
+		if len(f.ReadFrom) != 0 {
+			newAliases[f.ReadFrom] = f
+		}
+	}
+
+	for _, oldField := range unionStructFields(oldType, c.opts) {
+		fieldPath := joinAttributePath(path, oldField.Name)
+
+		newField, ok := newFields[oldField.Name]
+		if !ok {
+			if aliasField, ok := newAliases[oldField.Name]; ok {
+				// Renamed attribute, still readable through the alias.
+				c.compareTypes(fieldPath, oldField.Type, oldField.tag, aliasField.Type, aliasField.tag)
+				continue
+			}
+			c.add(AttributeRemoved, fieldPath, schemaAttrType(oldField.Type, oldField.tag), "")
+			continue
+		}
+		delete(newFields, oldField.Name)
+
+		c.compareTypes(fieldPath, oldField.Type, oldField.tag, newField.Type, newField.tag)
+	}
+
+	for name, newField := range newFields {
+		if newField.Required {
+			c.add(AttributeRequiredAdded, joinAttributePath(path, name), "", schemaAttrType(newField.Type, newField.tag))
+		}
+	}
+}
+
+func (c *schemaChecker) compareTypes(path string, oldType reflect.Type, oldTag tag, newType reflect.Type, newTag tag) {
+	oldType, newType = schemaIndirect(oldType), schemaIndirect(newType)
+
+	oldAttr, newAttr := schemaAttrType(oldType, oldTag), schemaAttrType(newType, newTag)
+	if oldAttr == schemaAnyType || newAttr == schemaAnyType {
+		return
+	}
+	if oldAttr == schemaCustomType || newAttr == schemaCustomType {
+		if oldType != newType {
+			c.add(AttributeTypeChanged, path, oldType.String(), newType.String())
+		}
+		return
+	}
+
+	if oldAttr != newAttr {
+		kind := AttributeTypeChanged
+		if isSchemaSetType(oldAttr) && newAttr == "L" || oldAttr == "L" && isSchemaSetType(newAttr) {
+			kind = AttributeSetChanged
+		}
+		c.add(kind, path, oldAttr, newAttr)
+		return
+	}
+
+	switch oldAttr {
+	case "M":
+		if oldType.Kind() == reflect.Struct && newType.Kind() == reflect.Struct {
+			c.compareStructs(path, oldType, newType)
+		} else if oldType.Kind() == reflect.Map && newType.Kind() == reflect.Map {
+			c.compareTypes(path+"{}", oldType.Elem(), tag{}, newType.Elem(), tag{})
+		}
+	case "L":
+		c.compareTypes(path+"[]", oldType.Elem(), tag{}, newType.Elem(), tag{})
+	}
+}
+
+const (
+	schemaAnyType    = "any"
+	schemaCustomType = "custom"
+)
+
+// schemaAttrType returns the AttributeValue type the Go type t with field
+// tag ft would be marshaled as.
+func schemaAttrType(t reflect.Type, ft tag) string {
+	t = schemaIndirect(t)
+
+	if t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) ||
+		t.Implements(marshalerV2Type) || reflect.PtrTo(t).Implements(marshalerV2Type) ||
+		t.Implements(setMarshalerType) || reflect.PtrTo(t).Implements(setMarshalerType) {
+		return schemaCustomType
+	}
+	if t == timeType {
+		return "S"
+	}
+	if ft.UUID {
+		return "B"
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return schemaAnyType
+	case reflect.Map:
+		if isSetMapType(t) {
+			switch {
+			case ft.AsNumSet:
+				return "NS"
+			case ft.AsStrSet:
+				return "SS"
+			}
+		}
+		return "M"
+	case reflect.Struct:
+		return "M"
+	case reflect.Slice, reflect.Array:
+		switch {
+		case t.Elem().Kind() == reflect.Uint8:
+			return "B"
+		case ft.AsBinSet || t == byteSliceSlicetype:
+			return "BS"
+		case ft.AsNumSet:
+			return "NS"
+		case ft.AsStrSet:
+			return "SS"
+		}
+		return "L"
+	case reflect.Bool:
+		return "BOOL"
+	case reflect.String:
+		if t == numberType && !ft.AsString {
+			return "N"
+		}
+		return "S"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if ft.AsString {
+			return "S"
+		}
+		return "N"
+	}
+
+	return t.Kind().String()
+}
+
+func schemaIndirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isSchemaSetType(attr string) bool {
+	return attr == "SS" || attr == "NS" || attr == "BS"
+}
+
+func joinAttributePath(path, name string) string {
+	if len(path) == 0 {
+		return name
+	}
+	return path + "." + name
+}
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+type schemaChangesByPath []SchemaChange
+
+func (x schemaChangesByPath) Len() int { return len(x) }
+
+func (x schemaChangesByPath) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x schemaChangesByPath) Less(i, j int) bool {
+	if x[i].Path != x[j].Path {
+		return x[i].Path < x[j].Path
+	}
+	return x[i].Kind < x[j].Kind
+}
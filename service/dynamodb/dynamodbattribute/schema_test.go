@@ -0,0 +1,142 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaItemV1 struct {
+	ID      string `dynamodbav:"id"`
+	Count   int
+	Tags    []string `dynamodbav:",stringset"`
+	Created time.Time
+	Parts   []schemaPartV1
+	Props   map[string]schemaPartV1
+	Data    interface{}
+	Custom  marshalMarshaler
+	Legacy  string
+}
+
+type schemaPartV1 struct {
+	Name string
+	Size int
+}
+
+type schemaItemV2 struct {
+	ID      string `dynamodbav:"id"`
+	Count   string
+	Tags    []string
+	Created *time.Time
+	Parts   []*schemaPartV2
+	Props   map[string]schemaPartV2
+	Data    []byte
+	Custom  *marshalMarshaler
+	Owner   string `dynamodbav:",required"`
+	Note    string
+}
+
+type schemaPartV2 struct {
+	Name string
+	Size int64 `dynamodbav:",string"`
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	changes, err := CheckSchemaCompatibility(schemaItemV1{}, &schemaItemV2{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []SchemaChange{
+		{Kind: AttributeTypeChanged, Path: "Count", OldType: "N", NewType: "S"},
+		{Kind: AttributeRemoved, Path: "Legacy", OldType: "S"},
+		{Kind: AttributeRequiredAdded, Path: "Owner", NewType: "S"},
+		{Kind: AttributeTypeChanged, Path: "Parts[].Size", OldType: "N", NewType: "S"},
+		{Kind: AttributeTypeChanged, Path: "Props{}.Size", OldType: "N", NewType: "S"},
+		{Kind: AttributeSetChanged, Path: "Tags", OldType: "SS", NewType: "L"},
+	}
+	if !reflect.DeepEqual(expect, changes) {
+		t.Errorf("expect %v, got %v", expect, changes)
+	}
+}
+
+func TestCheckSchemaCompatibilityNoChanges(t *testing.T) {
+	changes, err := CheckSchemaCompatibility(schemaItemV1{}, schemaItemV1{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 0, len(changes); e != a {
+		t.Errorf("expect %v changes, got %v, %v", e, a, changes)
+	}
+}
+
+func TestCheckSchemaCompatibilityInvalidModel(t *testing.T) {
+	_, err := CheckSchemaCompatibility(schemaItemV1{}, "abc")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := err.(*InvalidMarshalError); !ok {
+		t.Errorf("expect InvalidMarshalError, got %T", err)
+	}
+}
+
+func TestSchemaChangeString(t *testing.T) {
+	cases := []struct {
+		change SchemaChange
+		expect string
+	}{
+		{SchemaChange{Kind: AttributeRemoved, Path: "a.b", OldType: "S"}, "attribute removed: a.b"},
+		{SchemaChange{Kind: AttributeSetChanged, Path: "a", OldType: "SS", NewType: "L"}, "attribute set type changed: a, SS to L"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, c.change.String(); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestCheckSchemaCompatibilityAlias(t *testing.T) {
+	type recordV1 struct {
+		Title string
+		Size  int
+	}
+	type recordV2 struct {
+		Name string `dynamodbav:",alias=Title"`
+		Len  string `dynamodbav:",alias=Size"`
+	}
+
+	changes, err := CheckSchemaCompatibility(recordV1{}, recordV2{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []SchemaChange{
+		{Kind: AttributeTypeChanged, Path: "Size", OldType: "N", NewType: "S"},
+	}
+	if !reflect.DeepEqual(expect, changes) {
+		t.Errorf("expect %v, got %v", expect, changes)
+	}
+}
+
+func TestAttributeNames(t *testing.T) {
+	type embedded struct {
+		Status string `dynamodbav:"status"`
+	}
+	type record struct {
+		embedded
+		ID      string
+		Name    string `dynamodbav:"name,alias=fullName"`
+		Size    int    `dynamodbav:",readfrom=length"`
+		Ignored string `dynamodbav:"-"`
+	}
+
+	expect := []string{"status", "ID", "name", "Size", "fullName", "length"}
+	if e, a := expect, AttributeNames(&record{}); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if a := AttributeNames("abc"); a != nil {
+		t.Errorf("expect nil, got %v", a)
+	}
+}
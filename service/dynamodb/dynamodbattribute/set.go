@@ -0,0 +1,226 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A SetType is the AttributeValue type of a Set.
+type SetType int
+
+// Enumeration of the AttributeValue set types.
+const (
+	StringSet SetType = iota + 1
+	NumberSet
+	BinarySet
+)
+
+// String returns the name of the AttributeValue type of the set.
+func (t SetType) String() string {
+	switch t {
+	case StringSet:
+		return "SS"
+	case NumberSet:
+		return "NS"
+	case BinarySet:
+		return "BS"
+	}
+	return "unknown set type"
+}
+
+// A Set holds the members of a String, Number, or Binary Set
+// AttributeValue, for custom set types implementing SetMarshaler and
+// SetUnmarshaler.
+type Set struct {
+	Type SetType
+
+	// Members of a String or Number Set. Numbers are in the AttributeValue
+	// number format.
+	Members []string
+
+	// Members of a Binary Set.
+	Binary [][]byte
+}
+
+// Len returns the number of members in the set.
+func (s Set) Len() int {
+	if s.Type == BinarySet {
+		return len(s.Binary)
+	}
+	return len(s.Members)
+}
+
+// A SetMarshaler is the interface implemented by custom set types, such as
+// bitsets or sorted sets, to marshal their members as a String, Number, or
+// Binary Set AttributeValue directly, instead of converting to a []string
+// or []int tagged as a set first.
+//
+//     type Bits uint64
+//
+//     func (b Bits) MarshalDynamoDBSet() (dynamodbattribute.Set, error) {
+//         s := dynamodbattribute.Set{Type: dynamodbattribute.NumberSet}
+//         for i := 0; i < 64; i++ {
+//             if b&(1<<uint(i)) != 0 {
+//                 s.Members = append(s.Members, strconv.Itoa(i))
+//             }
+//         }
+//         return s, nil
+//     }
+//
+// Sets without members are marshaled as NULL, since DynamoDB sets cannot
+// be empty. Marshaler takes precedence over SetMarshaler if both are
+// implemented.
+type SetMarshaler interface {
+	MarshalDynamoDBSet() (Set, error)
+}
+
+// A SetUnmarshaler is the interface implemented by custom set types to
+// unmarshal the members of a String, Number, or Binary Set AttributeValue
+// directly. NULL AttributeValues are unmarshaled as a Set without members
+// or type, the same as an empty set. Unmarshaler takes precedence over
+// SetUnmarshaler if both are implemented.
+type SetUnmarshaler interface {
+	UnmarshalDynamoDBSet(Set) error
+}
+
+var (
+	setMarshalerType   = reflect.TypeOf((*SetMarshaler)(nil)).Elem()
+	setUnmarshalerType = reflect.TypeOf((*SetUnmarshaler)(nil)).Elem()
+)
+
+// MarshalSet marshals in as a String, Number, or Binary Set AttributeValue,
+// the same as a struct field with the `stringset`, `numberset`, or
+// `binaryset` tag option. in must be a SetMarshaler, or a slice, array, or
+// set map of strings, numbers, or byte slices. The set's type is chosen by
+// the type of the members. Sets without members are marshaled as NULL.
+//
+//     av, err := dynamodbattribute.MarshalSet([]string{"red", "blue"})
+//     // av is {SS: ["red", "blue"]}
+func MarshalSet(in interface{}) (*dynamodb.AttributeValue, error) {
+	return defaultEncoder.EncodeSet(in)
+}
+
+// EncodeSet marshals in as a String, Number, or Binary Set AttributeValue.
+// See MarshalSet.
+func (e *Encoder) EncodeSet(in interface{}) (*dynamodb.AttributeValue, error) {
+	v := reflect.ValueOf(in)
+	if !v.IsValid() {
+		return nil, &InvalidMarshalError{msg: "cannot marshal nil as a set"}
+	}
+
+	var fieldTag tag
+	if !v.Type().Implements(setMarshalerType) {
+		var member reflect.Type
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			member = v.Type().Elem()
+		case reflect.Map:
+			member = v.Type().Key()
+		default:
+			return nil, &InvalidMarshalError{msg: "cannot marshal " + v.Type().String() + " as a set"}
+		}
+
+		switch member.Kind() {
+		case reflect.String:
+			fieldTag.AsStrSet = true
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			fieldTag.AsNumSet = true
+		case reflect.Slice:
+			if member.Elem().Kind() != reflect.Uint8 {
+				return nil, &InvalidMarshalError{msg: "cannot marshal " + v.Type().String() + " as a set"}
+			}
+			fieldTag.AsBinSet = true
+		default:
+			return nil, &InvalidMarshalError{msg: "cannot marshal " + v.Type().String() + " as a set"}
+		}
+	}
+
+	enc := *e
+	av := &dynamodb.AttributeValue{}
+	if err := enc.encode(av, v, fieldTag); err != nil {
+		e.ErrorSampler.Observe(err)
+		return nil, err
+	}
+	return av, nil
+}
+
+// encodeSet sets the AttributeValue to the set marshaled by m.
+func encodeSet(av *dynamodb.AttributeValue, m SetMarshaler) error {
+	s, err := m.MarshalDynamoDBSet()
+	if err != nil {
+		return err
+	}
+
+	switch s.Type {
+	case StringSet, NumberSet:
+		if len(s.Members) == 0 {
+			break
+		}
+		members := make([]*string, len(s.Members))
+		for i := range s.Members {
+			members[i] = &s.Members[i]
+		}
+		if s.Type == StringSet {
+			av.SS = members
+		} else {
+			av.NS = members
+		}
+		return nil
+	case BinarySet:
+		if len(s.Binary) == 0 {
+			break
+		}
+		av.BS = make([][]byte, len(s.Binary))
+		for i, b := range s.Binary {
+			av.BS[i] = append([]byte{}, b...)
+		}
+		return nil
+	default:
+		return &InvalidMarshalError{msg: "invalid set type " + s.Type.String() + " marshaled by " +
+			reflect.TypeOf(m).String()}
+	}
+
+	encodeNull(av)
+	return nil
+}
+
+// setUnmarshalerOf returns the SetUnmarshaler of v, or of a pointer to v,
+// if its type implements the interface.
+func setUnmarshalerOf(v reflect.Value) SetUnmarshaler {
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		v = v.Addr()
+	}
+	if !v.IsValid() || !v.CanInterface() || !v.Type().Implements(setUnmarshalerType) {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+
+	return v.Interface().(SetUnmarshaler)
+}
+
+// decodeSet unmarshals the set AttributeValue into the SetUnmarshaler.
+func (d *Decoder) decodeSet(av *dynamodb.AttributeValue, u SetUnmarshaler) error {
+	var s Set
+	switch {
+	case av == nil || av.NULL != nil:
+	case av.SS != nil:
+		s = Set{Type: StringSet, Members: aws.StringValueSlice(av.SS)}
+	case av.NS != nil:
+		s = Set{Type: NumberSet, Members: aws.StringValueSlice(av.NS)}
+	case av.BS != nil:
+		s = Set{Type: BinarySet, Binary: make([][]byte, len(av.BS))}
+		for i, b := range av.BS {
+			s.Binary[i] = d.binaryBytes(b)
+		}
+	default:
+		return &UnmarshalTypeError{Value: "non-set attribute value", Type: reflect.TypeOf(u)}
+	}
+
+	return u.UnmarshalDynamoDBSet(s)
+}
@@ -0,0 +1,168 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// testBitSet marshals the indexes of its set bits as a number set.
+type testBitSet uint64
+
+func (b testBitSet) MarshalDynamoDBSet() (Set, error) {
+	s := Set{Type: NumberSet}
+	for i := 0; i < 64; i++ {
+		if b&(1<<uint(i)) != 0 {
+			s.Members = append(s.Members, strconv.Itoa(i))
+		}
+	}
+	return s, nil
+}
+
+func (b *testBitSet) UnmarshalDynamoDBSet(s Set) error {
+	if s.Len() != 0 && s.Type != NumberSet {
+		return fmt.Errorf("expect number set, got %v", s.Type)
+	}
+	*b = 0
+	for _, m := range s.Members {
+		i, err := strconv.ParseUint(m, 10, 6)
+		if err != nil {
+			return err
+		}
+		*b |= 1 << i
+	}
+	return nil
+}
+
+// testSortedSet is a string set kept in sorted order.
+type testSortedSet struct {
+	members []string
+}
+
+func (s testSortedSet) MarshalDynamoDBSet() (Set, error) {
+	return Set{Type: StringSet, Members: s.members}, nil
+}
+
+func (s *testSortedSet) UnmarshalDynamoDBSet(set Set) error {
+	s.members = append([]string{}, set.Members...)
+	sort.Strings(s.members)
+	return nil
+}
+
+type testBinarySet [][]byte
+
+func (s testBinarySet) MarshalDynamoDBSet() (Set, error) {
+	return Set{Type: BinarySet, Binary: s}, nil
+}
+
+func (s *testBinarySet) UnmarshalDynamoDBSet(set Set) error {
+	*s = set.Binary
+	return nil
+}
+
+type testInvalidSet struct{}
+
+func (testInvalidSet) MarshalDynamoDBSet() (Set, error) {
+	return Set{}, nil
+}
+
+func TestSetMarshaler(t *testing.T) {
+	type testRecord struct {
+		Bits    testBitSet
+		Tags    testSortedSet
+		Blobs   testBinarySet
+		Empty   testBitSet
+		Pointer *testSortedSet
+	}
+
+	in := testRecord{
+		Bits:    testBitSet(1<<3 | 1<<10),
+		Tags:    testSortedSet{members: []string{"a", "b"}},
+		Blobs:   testBinarySet{{1}, {2, 3}},
+		Pointer: &testSortedSet{members: []string{"c"}},
+	}
+	av, err := MarshalMapWithOptions(in, func(e *Encoder) {
+		e.FlattenStructs = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"Bits":    {NS: []*string{aws.String("3"), aws.String("10")}},
+		"Tags":    {SS: []*string{aws.String("a"), aws.String("b")}},
+		"Blobs":   {BS: [][]byte{{1}, {2, 3}}},
+		"Empty":   {NULL: aws.Bool(true)},
+		"Pointer": {SS: []*string{aws.String("c")}},
+	}
+	if !reflect.DeepEqual(expect, av) {
+		t.Errorf("expect %v, got %v", expect, av)
+	}
+
+	av["Tags"] = &dynamodb.AttributeValue{SS: []*string{aws.String("b"), aws.String("a")}}
+	actual := testRecord{Empty: 1}
+	err = UnmarshalMapWithOptions(av, &actual, func(d *Decoder) {
+		d.FlattenStructs = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(in, actual) {
+		t.Errorf("expect %v, got %v", in, actual)
+	}
+}
+
+func TestSetUnmarshalerErrors(t *testing.T) {
+	var bits testBitSet
+	err := Unmarshal(&dynamodb.AttributeValue{SS: []*string{aws.String("a")}}, &bits)
+	if e, a := "expect number set, got SS", errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	err = Unmarshal(&dynamodb.AttributeValue{S: aws.String("a")}, &bits)
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %v", err)
+	}
+
+	_, err = Marshal(testInvalidSet{})
+	if _, ok := err.(*InvalidMarshalError); !ok {
+		t.Errorf("expect InvalidMarshalError, got %v", err)
+	}
+}
+
+func TestMarshalSet(t *testing.T) {
+	cases := []struct {
+		in     interface{}
+		expect *dynamodb.AttributeValue
+	}{
+		{[]string{"a", "b"}, &dynamodb.AttributeValue{SS: []*string{aws.String("a"), aws.String("b")}}},
+		{[2]int{1, 2}, &dynamodb.AttributeValue{NS: []*string{aws.String("1"), aws.String("2")}}},
+		{map[string]struct{}{"a": {}}, &dynamodb.AttributeValue{SS: []*string{aws.String("a")}}},
+		{map[int]bool{3: true, 4: false}, &dynamodb.AttributeValue{NS: []*string{aws.String("3")}}},
+		{[][]byte{{1}}, &dynamodb.AttributeValue{BS: [][]byte{{1}}}},
+		{testBitSet(1 << 2), &dynamodb.AttributeValue{NS: []*string{aws.String("2")}}},
+		{[]string{}, &dynamodb.AttributeValue{NULL: aws.Bool(true)}},
+	}
+
+	for i, c := range cases {
+		av, err := MarshalSet(c.in)
+		if err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+			continue
+		}
+		if e, a := c.expect, av; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	for i, in := range []interface{}{nil, "a", []bool{true}, [][]string{{"a"}}, map[bool]struct{}{}} {
+		if _, err := MarshalSet(in); err == nil {
+			t.Errorf("%d, expect error for %T", i, in)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MaxItemSize is the maximum size in bytes of a DynamoDB item, including
+// the lengths of its attribute names.
+const MaxItemSize = 400 * 1024
+
+// ItemSize returns the size in bytes DynamoDB counts the item as, the sum
+// of the lengths of its attribute names and the sizes of their values.
+func ItemSize(item map[string]*dynamodb.AttributeValue) int {
+	size := 0
+	for name, av := range item {
+		size += len(name) + AttributeValueSize(av)
+	}
+	return size
+}
+
+// AttributeValueSize returns the size in bytes DynamoDB counts the
+// AttributeValue as, not including the length of its attribute name.
+//
+// Strings and binary values are their length in bytes, and numbers are
+// one byte per two significant digits plus one byte. Lists and maps have
+// three bytes of overhead, and one byte per element, in addition to the
+// sizes of their elements and, for maps, element names. Booleans and nulls
+// are one byte.
+func AttributeValueSize(av *dynamodb.AttributeValue) int {
+	if av == nil {
+		return 0
+	}
+
+	switch {
+	case av.S != nil:
+		return len(*av.S)
+	case av.N != nil:
+		return numberSize(*av.N)
+	case av.B != nil:
+		return len(av.B)
+	case av.BOOL != nil, av.NULL != nil:
+		return 1
+	case av.SS != nil:
+		size := 0
+		for _, s := range av.SS {
+			if s != nil {
+				size += len(*s)
+			}
+		}
+		return size
+	case av.NS != nil:
+		size := 0
+		for _, n := range av.NS {
+			if n != nil {
+				size += numberSize(*n)
+			}
+		}
+		return size
+	case av.BS != nil:
+		size := 0
+		for _, b := range av.BS {
+			size += len(b)
+		}
+		return size
+	case av.L != nil:
+		size := 3
+		for _, elem := range av.L {
+			size += 1 + AttributeValueSize(elem)
+		}
+		return size
+	case av.M != nil:
+		size := 3
+		for name, elem := range av.M {
+			size += 1 + len(name) + AttributeValueSize(elem)
+		}
+		return size
+	}
+
+	return 0
+}
+
+// numberSize returns the size of a number, one byte per two significant
+// digits, plus one byte.
+func numberSize(n string) int {
+	n = strings.TrimLeft(n, "+-")
+	if i := strings.IndexAny(n, "eE"); i >= 0 {
+		n = n[:i]
+	}
+	n = strings.Replace(n, ".", "", 1)
+	n = strings.Trim(n, "0")
+
+	return (len(n)+1)/2 + 1
+}
+
+// ValidateItemSize returns an ItemSizeError if the item is larger than
+// MaxItemSize, the largest item DynamoDB accepts. Validating the size
+// before the item is written gives an error naming the largest attributes,
+// instead of the service's ValidationException.
+//
+//     item, err := dynamodbattribute.MarshalMap(order)
+//     if err == nil {
+//         err = dynamodbattribute.ValidateItemSize(item)
+//     }
+func ValidateItemSize(item map[string]*dynamodb.AttributeValue) error {
+	attrs := make(attributeSizes, 0, len(item))
+	size := 0
+	for name, av := range item {
+		attrSize := len(name) + AttributeValueSize(av)
+		attrs = append(attrs, AttributeSize{Name: name, Size: attrSize})
+		size += attrSize
+	}
+	if size <= MaxItemSize {
+		return nil
+	}
+
+	sort.Sort(attrs)
+	return &ItemSizeError{Size: size, Max: MaxItemSize, Attributes: attrs}
+}
+
+// An AttributeSize is the size in bytes of an attribute, including the
+// length of its name.
+type AttributeSize struct {
+	Name string
+	Size int
+}
+
+// attributeSizes sorts AttributeSizes largest first, and by name for
+// attributes of the same size.
+type attributeSizes []AttributeSize
+
+func (s attributeSizes) Len() int      { return len(s) }
+func (s attributeSizes) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s attributeSizes) Less(i, j int) bool {
+	if s[i].Size != s[j].Size {
+		return s[i].Size > s[j].Size
+	}
+	return s[i].Name < s[j].Name
+}
+
+// An ItemSizeError is an error type representing an item larger than
+// DynamoDB's maximum item size.
+type ItemSizeError struct {
+	emptyOrigError
+
+	// Size of the item, and the maximum size, in bytes.
+	Size, Max int
+
+	// Sizes of the item's attributes, largest first.
+	Attributes []AttributeSize
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *ItemSizeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *ItemSizeError) Code() string {
+	return "ItemSizeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface. The three largest attributes are listed.
+func (e *ItemSizeError) Message() string {
+	msg := fmt.Sprintf("item size %d bytes exceeds limit of %d bytes", e.Size, e.Max)
+
+	largest := e.Attributes
+	if len(largest) > 3 {
+		largest = largest[:3]
+	}
+	for i, attr := range largest {
+		if i == 0 {
+			msg += ", largest attributes "
+		} else {
+			msg += ", "
+		}
+		msg += fmt.Sprintf("%s (%d bytes)", attr.Name, attr.Size)
+	}
+
+	return msg
+}
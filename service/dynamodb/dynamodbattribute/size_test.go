@@ -0,0 +1,78 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestAttributeValueSize(t *testing.T) {
+	cases := []struct {
+		av     *dynamodb.AttributeValue
+		expect int
+	}{
+		{nil, 0},
+		{&dynamodb.AttributeValue{S: aws.String("abc")}, 3},
+		{&dynamodb.AttributeValue{S: aws.String("é")}, 2},
+		{&dynamodb.AttributeValue{N: aws.String("0")}, 1},
+		{&dynamodb.AttributeValue{N: aws.String("12345")}, 4},
+		{&dynamodb.AttributeValue{N: aws.String("-1.2300")}, 3},
+		{&dynamodb.AttributeValue{N: aws.String("1000E10")}, 2},
+		{&dynamodb.AttributeValue{B: []byte{1, 2}}, 2},
+		{&dynamodb.AttributeValue{BOOL: aws.Bool(false)}, 1},
+		{&dynamodb.AttributeValue{NULL: aws.Bool(true)}, 1},
+		{&dynamodb.AttributeValue{SS: []*string{aws.String("a"), aws.String("bc")}}, 3},
+		{&dynamodb.AttributeValue{NS: []*string{aws.String("1"), aws.String("22")}}, 4},
+		{&dynamodb.AttributeValue{BS: [][]byte{{1}, {2, 3}}}, 3},
+		{&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}, 3},
+		{&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("ab")}}}, 6},
+		{&dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"key": {S: aws.String("ab")}}}, 9},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, AttributeValueSize(c.av); e != a {
+			t.Errorf("%d, expect size %d, got %d", i, e, a)
+		}
+	}
+}
+
+func TestValidateItemSize(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"ID":   {S: aws.String("abc")},
+		"Body": {S: aws.String(strings.Repeat("a", MaxItemSize))},
+		"Tags": {SS: []*string{aws.String(strings.Repeat("b", 100))}},
+		"Sum":  {N: aws.String("1")},
+	}
+
+	if e, a := MaxItemSize+118, ItemSize(item); e != a {
+		t.Errorf("expect size %d, got %d", e, a)
+	}
+
+	err := ValidateItemSize(item)
+	var _ awserr.Error = (*ItemSizeError)(nil)
+	se, ok := err.(*ItemSizeError)
+	if !ok {
+		t.Fatalf("expect *ItemSizeError, got %T, %v", err, err)
+	}
+	expect := []AttributeSize{
+		{"Body", MaxItemSize + 4},
+		{"Tags", 104},
+		{"ID", 5},
+		{"Sum", 5},
+	}
+	if e, a := expect, se.Attributes; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "ItemSizeError: item size 409718 bytes exceeds limit of 409600 bytes, largest attributes Body (409604 bytes), Tags (104 bytes), ID (5 bytes)", err.Error(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	delete(item, "Body")
+	if err := ValidateItemSize(item); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
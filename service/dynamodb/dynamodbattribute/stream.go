@@ -0,0 +1,61 @@
+package dynamodbattribute
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+// StreamImage selects one of the item images of a DynamoDB Streams record.
+type StreamImage int
+
+// Enumeration of the images of a dynamodbstreams.StreamRecord.
+const (
+	// StreamKeys is the primary key attributes of the modified item.
+	StreamKeys StreamImage = iota
+
+	// StreamNewImage is the item as it appeared after it was modified.
+	StreamNewImage
+
+	// StreamOldImage is the item as it appeared before it was modified.
+	StreamOldImage
+)
+
+// UnmarshalStreamImage unmarshals the image of the DynamoDB Streams record
+// selected into out. The bool returned is false, and out is not modified,
+// if the record does not include the image. Which images are included
+// depends on the stream's view type and the event, e.g. an INSERT event
+// has no old image.
+//
+//     for _, r := range out.Records {
+//         var item Record
+//         ok, err := dynamodbattribute.UnmarshalStreamImage(r.Dynamodb,
+//             dynamodbattribute.StreamNewImage, &item)
+//         if err != nil {
+//             return err
+//         }
+//         if ok {
+//             // process item
+//         }
+//     }
+//
+// The output value provided must be a non-nil pointer
+func UnmarshalStreamImage(record *dynamodbstreams.StreamRecord, image StreamImage, out interface{}, opts ...func(*Decoder)) (bool, error) {
+	if record == nil {
+		return false, nil
+	}
+
+	var m map[string]*dynamodb.AttributeValue
+	switch image {
+	case StreamKeys:
+		m = record.Keys
+	case StreamNewImage:
+		m = record.NewImage
+	case StreamOldImage:
+		m = record.OldImage
+	}
+	if m == nil {
+		return false, nil
+	}
+
+	return true, UnmarshalMapWithOptions(m, out, opts...)
+}
@@ -0,0 +1,58 @@
+package dynamodbattribute
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+func TestUnmarshalStreamImage(t *testing.T) {
+	type item struct {
+		ID    string
+		Count int
+	}
+
+	record := &dynamodbstreams.StreamRecord{
+		Keys: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String("abc")},
+		},
+		NewImage: map[string]*dynamodb.AttributeValue{
+			"ID":    {S: aws.String("abc")},
+			"Count": {N: aws.String("2")},
+		},
+	}
+
+	var keys, newImage, oldImage item
+	ok, err := UnmarshalStreamImage(record, StreamKeys, &keys)
+	if err != nil || !ok {
+		t.Fatalf("expect keys, got %v, %v", ok, err)
+	}
+	if e, a := (item{ID: "abc"}), keys; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	ok, err = UnmarshalStreamImage(record, StreamNewImage, &newImage)
+	if err != nil || !ok {
+		t.Fatalf("expect new image, got %v, %v", ok, err)
+	}
+	if e, a := (item{ID: "abc", Count: 2}), newImage; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	ok, err = UnmarshalStreamImage(record, StreamOldImage, &oldImage)
+	if err != nil || ok {
+		t.Fatalf("expect no old image, got %v, %v", ok, err)
+	}
+
+	ok, err = UnmarshalStreamImage(nil, StreamNewImage, &newImage)
+	if err != nil || ok {
+		t.Fatalf("expect no image, got %v, %v", ok, err)
+	}
+
+	ok, err = UnmarshalStreamImage(record, StreamNewImage, newImage)
+	if _, isErr := err.(*InvalidUnmarshalError); !isErr || !ok {
+		t.Errorf("expect InvalidUnmarshalError, got %v, %T", ok, err)
+	}
+}
@@ -0,0 +1,154 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+)
+
+type tag struct {
+	Name                         string
+	Ignore                       bool
+	OmitEmpty                    bool
+	OmitEmptyElem                bool
+	OmitZero                     bool
+	AsString                     bool
+	AsBinSet, AsNumSet, AsStrSet bool
+	Required                     bool
+	Immutable, WriteOnce         bool
+	Version                      bool
+	Compress                     bool
+	Encrypted                    bool
+	UUID                         bool
+	HashKey, RangeKey            bool
+
+	// Aliases are alternate attribute names the field will be decoded
+	// from, in order, if the attribute for the field's name is not present.
+	Aliases []string
+
+	// ReadFrom is the attribute name the field will be decoded from if
+	// the attribute for the field's name is not present, before Aliases.
+	ReadFrom string
+
+	// Union is the discriminator attribute selecting the member of a
+	// registered union the field is decoded into.
+	Union string
+}
+
+// options returns the tag options which are set, in the form they are
+// written in the struct tag.
+func (t tag) options() []string {
+	var opts []string
+	add := func(set bool, opt string) {
+		if set {
+			opts = append(opts, opt)
+		}
+	}
+	add(t.OmitEmpty, "omitempty")
+	add(t.OmitEmptyElem, "omitemptyelem")
+	add(t.OmitZero, "omitzero")
+	add(t.AsString, "string")
+	add(t.AsBinSet, "binaryset")
+	add(t.AsNumSet, "numberset")
+	add(t.AsStrSet, "stringset")
+	add(t.Required, "required")
+	add(t.Immutable, "immutable")
+	add(t.WriteOnce, "writeonce")
+	add(t.Version, "version")
+	add(t.Compress, "compress")
+	add(t.Encrypted, "encrypted")
+	add(t.UUID, "uuid")
+	add(t.HashKey, "hashkey")
+	add(t.RangeKey, "rangekey")
+	for _, alias := range t.Aliases {
+		opts = append(opts, "alias="+alias)
+	}
+	add(len(t.ReadFrom) != 0, "readfrom="+t.ReadFrom)
+	add(len(t.Union) != 0, "union="+t.Union)
+
+	return opts
+}
+
+// parseAVTag parses the `dynamodbav` struct tag, returning true if the tag
+// was present.
+func (t *tag) parseAVTag(structTag reflect.StructTag) bool {
+	tagStr := structTag.Get("dynamodbav")
+	if len(tagStr) == 0 {
+		return false
+	}
+
+	t.parseTagStr(tagStr)
+	return true
+}
+
+// parseJSONTag parses the `json` struct tag, returning true if the tag
+// was present.
+func (t *tag) parseJSONTag(structTag reflect.StructTag) bool {
+	tagStr := structTag.Get("json")
+	if len(tagStr) == 0 {
+		return false
+	}
+
+	t.parseTagStr(tagStr)
+	return true
+}
+
+func (t *tag) parseTagStr(tagStr string) {
+	parts := strings.Split(tagStr, ",")
+	if len(parts) == 0 {
+		return
+	}
+
+	if name := parts[0]; name == "-" {
+		t.Name = ""
+		t.Ignore = true
+	} else {
+		t.Name = name
+		t.Ignore = false
+	}
+
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			t.OmitEmpty = true
+		case "omitemptyelem":
+			t.OmitEmptyElem = true
+		case "omitzero":
+			t.OmitZero = true
+		case "string":
+			t.AsString = true
+		case "binaryset":
+			t.AsBinSet = true
+		case "numberset":
+			t.AsNumSet = true
+		case "stringset":
+			t.AsStrSet = true
+		case "required":
+			t.Required = true
+		case "immutable":
+			t.Immutable = true
+		case "writeonce":
+			t.WriteOnce = true
+		case "version":
+			t.Version = true
+		case "compress":
+			t.Compress = true
+		case "encrypted":
+			t.Encrypted = true
+		case "uuid":
+			t.UUID = true
+		case "hashkey":
+			t.HashKey = true
+		case "rangekey":
+			t.RangeKey = true
+		default:
+			switch {
+			case strings.HasPrefix(opt, "alias="):
+				t.Aliases = append(t.Aliases, strings.TrimPrefix(opt, "alias="))
+			case strings.HasPrefix(opt, "readfrom="):
+				t.ReadFrom = strings.TrimPrefix(opt, "readfrom=")
+			case strings.HasPrefix(opt, "union="):
+				t.Union = strings.TrimPrefix(opt, "union=")
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagParse(t *testing.T) {
+	cases := []struct {
+		in     reflect.StructTag
+		json   bool
+		av     bool
+		found  bool
+		expect tag
+	}{
+		{`json:""`, true, false, false, tag{}},
+		{`json:"name"`, true, false, true, tag{Name: "name"}},
+		{`json:"name,omitempty"`, true, false, true, tag{Name: "name", OmitEmpty: true}},
+		{`json:"-"`, true, false, true, tag{Ignore: true}},
+		{`json:",omitempty"`, true, false, true, tag{OmitEmpty: true}},
+		{`json:",string"`, true, false, true, tag{AsString: true}},
+		{`dynamodbav:""`, false, true, false, tag{}},
+		{`dynamodbav:","`, false, true, true, tag{}},
+		{`dynamodbav:"name"`, false, true, true, tag{Name: "name"}},
+		{`dynamodbav:"name"`, false, true, true, tag{Name: "name"}},
+		{`dynamodbav:"-"`, false, true, true, tag{Ignore: true}},
+		{`dynamodbav:",omitempty"`, false, true, true, tag{OmitEmpty: true}},
+		{`dynamodbav:",omitemptyelem"`, false, true, true, tag{OmitEmptyElem: true}},
+		{`dynamodbav:",omitzero"`, false, true, true, tag{OmitZero: true}},
+		{`dynamodbav:",string"`, false, true, true, tag{AsString: true}},
+		{`dynamodbav:",binaryset"`, false, true, true, tag{AsBinSet: true}},
+		{`dynamodbav:",numberset"`, false, true, true, tag{AsNumSet: true}},
+		{`dynamodbav:",stringset"`, false, true, true, tag{AsStrSet: true}},
+		{`dynamodbav:"email,required"`, false, true, true, tag{Name: "email", Required: true}},
+		{`dynamodbav:"created,immutable"`, false, true, true, tag{Name: "created", Immutable: true}},
+		{`dynamodbav:",writeonce"`, false, true, true, tag{WriteOnce: true}},
+		{`dynamodbav:"id,uuid"`, false, true, true, tag{Name: "id", UUID: true}},
+		{`dynamodbav:"id,hashkey"`, false, true, true, tag{Name: "id", HashKey: true}},
+		{`dynamodbav:"created,rangekey"`, false, true, true, tag{Name: "created", RangeKey: true}},
+		{`dynamodbav:"name,alias=oldName"`, false, true, true, tag{Name: "name", Aliases: []string{"oldName"}}},
+		{`dynamodbav:"newName,readfrom=oldName"`, false, true, true, tag{Name: "newName", ReadFrom: "oldName"}},
+		{`dynamodbav:"email,alias=email_address,alias=Email"`, false, true, true, tag{Name: "email", Aliases: []string{"email_address", "Email"}}},
+		{`json:"name,alias=oldName,omitempty"`, true, false, true, tag{Name: "name", Aliases: []string{"oldName"}, OmitEmpty: true}},
+		{`dynamodbav:",stringset,omitemptyelem"`, false, true, true, tag{AsStrSet: true, OmitEmptyElem: true}},
+		{`dynamodbav:"name,stringset,omitemptyelem"`, false, true, true, tag{Name: "name", AsStrSet: true, OmitEmptyElem: true}},
+	}
+
+	for i, c := range cases {
+		actual := tag{}
+		var found bool
+		if c.json {
+			found = actual.parseJSONTag(c.in)
+		}
+		if c.av {
+			found = actual.parseAVTag(c.in)
+		}
+		if e, a := c.found, found; e != a {
+			t.Errorf("case %d, expect found %v, got %v", i, e, a)
+		}
+		if e, a := c.expect, actual; !reflect.DeepEqual(e, a) {
+			t.Errorf("case %d, expect %v, got %v", i, e, a)
+		}
+	}
+}
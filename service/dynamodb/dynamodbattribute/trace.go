@@ -0,0 +1,210 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TraceOutcome is what happened to a struct field, or an attribute, while
+// decoding with a DecodeTrace.
+type TraceOutcome int
+
+// Enumeration of the outcomes recorded by a DecodeTrace.
+const (
+	// TraceDecoded is a field which was decoded from its attribute.
+	TraceDecoded TraceOutcome = iota
+
+	// TraceNull is a field whose attribute was NULL, and was set to its
+	// zero value.
+	TraceNull
+
+	// TraceMissing is a field with no matching attribute in the item, and
+	// was not modified.
+	TraceMissing
+
+	// TraceFailed is a field whose attribute could not be decoded into the
+	// field, e.g. a type mismatch.
+	TraceFailed
+
+	// TraceUnknownAttribute is an attribute of the item which did not match
+	// any field of the struct, and was skipped.
+	TraceUnknownAttribute
+)
+
+func (o TraceOutcome) String() string {
+	switch o {
+	case TraceDecoded:
+		return "decoded"
+	case TraceNull:
+		return "null"
+	case TraceMissing:
+		return "missing"
+	case TraceFailed:
+		return "failed"
+	case TraceUnknownAttribute:
+		return "unknown attribute"
+	default:
+		return fmt.Sprintf("TraceOutcome(%d)", int(o))
+	}
+}
+
+// A FieldTrace records how a single struct field, or attribute, was handled
+// by the Decoder.
+type FieldTrace struct {
+	// Document path of the field's attribute, e.g. "Orders[2].Address".
+	Path string
+
+	// Go name of the struct field. Empty for unknown attributes.
+	Field string
+
+	// Name of the attribute the field was decoded from, which may be the
+	// field's alias. Empty if no attribute matched.
+	Attribute string
+
+	// Tag options which applied to the field, e.g. "required", "alias=ID".
+	Options []string
+
+	Outcome TraceOutcome
+
+	// The error decoding the field, if the outcome is TraceFailed.
+	Err error
+}
+
+func (f FieldTrace) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(f.Path)
+	if len(f.Field) != 0 {
+		fmt.Fprintf(&buf, " (%s)", f.Field)
+	}
+	fmt.Fprintf(&buf, ": %s", f.Outcome)
+	if len(f.Attribute) != 0 && f.Attribute != lastPathSegment(f.Path) {
+		fmt.Fprintf(&buf, " from %s", f.Attribute)
+	}
+	if len(f.Options) != 0 {
+		fmt.Fprintf(&buf, " [%s]", strings.Join(f.Options, ","))
+	}
+	if f.Err != nil {
+		fmt.Fprintf(&buf, ", %v", f.Err)
+	}
+	return buf.String()
+}
+
+// A DecodeTrace records the decisions a Decoder made for every struct field
+// it decoded, for diagnosing fields which were unexpectedly left zero.
+//
+//     trace := &dynamodbattribute.DecodeTrace{}
+//     err := dynamodbattribute.UnmarshalMapWithOptions(item, &v, func(d *dynamodbattribute.Decoder) {
+//         d.Trace = trace
+//     })
+//     fmt.Println(trace)
+//
+// A DecodeTrace is not safe for concurrent use, and lists are decoded
+// serially while tracing regardless of the Decoder's Concurrency.
+type DecodeTrace struct {
+	// Fields recorded, in the order they were decoded.
+	Fields []FieldTrace
+
+	path []string
+}
+
+// String returns the trace, one field per line.
+func (t *DecodeTrace) String() string {
+	var buf bytes.Buffer
+	for _, f := range t.Fields {
+		buf.WriteString(f.String())
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func (t *DecodeTrace) push(segment string) {
+	t.path = append(t.path, segment)
+}
+
+func (t *DecodeTrace) pop() {
+	t.path = t.path[:len(t.path)-1]
+}
+
+func (t *DecodeTrace) fieldPath(name string) string {
+	path := ""
+	for _, segment := range t.path {
+		if strings.HasPrefix(segment, "[") {
+			path += segment
+		} else {
+			path = joinAttributePath(path, segment)
+		}
+	}
+	return joinAttributePath(path, name)
+}
+
+func (t *DecodeTrace) record(f field, goName, attr string, outcome TraceOutcome, err error) {
+	t.Fields = append(t.Fields, FieldTrace{
+		Path:      t.fieldPath(f.Name),
+		Field:     goName,
+		Attribute: attr,
+		Options:   f.tag.options(),
+		Outcome:   outcome,
+		Err:       err,
+	})
+}
+
+// containsFold returns if the list contains s, compared case insensitively.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUnknown records the attributes of avMap which did not match any of
+// the fields.
+func (t *DecodeTrace) recordUnknown(avMap map[string]*dynamodb.AttributeValue, fields []field) {
+	var unknown []string
+	for k := range avMap {
+		matched := false
+		for _, f := range fields {
+			if strings.EqualFold(k, f.Name) || strings.EqualFold(k, f.ReadFrom) || containsFold(f.Aliases, k) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, k := range unknown {
+		t.Fields = append(t.Fields, FieldTrace{
+			Path:      t.fieldPath(k),
+			Attribute: k,
+			Outcome:   TraceUnknownAttribute,
+		})
+	}
+}
+
+// attrName returns the key of avMap attrByName would match for name.
+func attrName(avMap map[string]*dynamodb.AttributeValue, name string) string {
+	if _, ok := avMap[name]; ok {
+		return name
+	}
+	for k := range avMap {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return ""
+}
+
+func lastPathSegment(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
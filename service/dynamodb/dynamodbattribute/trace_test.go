@@ -0,0 +1,94 @@
+package dynamodbattribute
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestDecodeTrace(t *testing.T) {
+	type line struct {
+		SKU string
+		Qty int
+	}
+	type order struct {
+		ID       string `dynamodbav:"id,alias=OrderID"`
+		Customer string `dynamodbav:",required"`
+		Note     *string
+		Lines    []line
+	}
+
+	item := map[string]*dynamodb.AttributeValue{
+		"OrderID": {S: aws.String("abc")},
+		"Note":    {NULL: aws.Bool(true)},
+		"Lines": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{
+				"sku": {S: aws.String("a-1")},
+				"Qty": {S: aws.String("2")},
+			}},
+		}},
+		"Extra": {S: aws.String("x")},
+	}
+
+	trace := &DecodeTrace{}
+	var v order
+	err := UnmarshalMapWithOptions(item, &v, func(d *Decoder) {
+		d.Trace = trace
+		d.Concurrency = 4
+	})
+	if err == nil {
+		t.Fatalf("expect error")
+	}
+
+	expect := []struct {
+		path, field, attr string
+		outcome           TraceOutcome
+	}{
+		{"id", "ID", "OrderID", TraceDecoded},
+		{"Customer", "Customer", "", TraceMissing},
+		{"Note", "Note", "Note", TraceNull},
+		{"Lines", "Lines", "Lines", TraceFailed},
+		{"Lines[0].SKU", "SKU", "sku", TraceDecoded},
+		{"Lines[0].Qty", "Qty", "Qty", TraceFailed},
+	}
+	if e, a := len(expect), len(trace.Fields); e != a {
+		t.Fatalf("expect %d fields, got %d\n%v", e, a, trace)
+	}
+	for i, e := range expect {
+		a := trace.Fields[i]
+		if e.path != a.Path || e.field != a.Field || e.attr != a.Attribute || e.outcome != a.Outcome {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+	if e, a := "required", trace.Fields[1].Options; len(a) != 1 || a[0] != e {
+		t.Errorf("expect %v options, got %v", e, a)
+	}
+	if trace.Fields[5].Err == nil {
+		t.Errorf("expect field error")
+	}
+
+	if e, a := "id (ID): decoded from OrderID [alias=OrderID]", trace.Fields[0].String(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestDecodeTraceUnknownAttribute(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"Name":  {S: aws.String("abc")},
+		"Extra": {S: aws.String("x")},
+	}
+
+	trace := &DecodeTrace{}
+	var v struct{ Name string }
+	err := UnmarshalMapWithOptions(item, &v, func(d *Decoder) {
+		d.Trace = trace
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "Name (Name): decoded\nExtra: unknown attribute\n", trace.String(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
@@ -0,0 +1,115 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+var typeRegistry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}{
+	types: map[string]reflect.Type{},
+	names: map[reflect.Type]string{},
+}
+
+// RegisterType registers the Go type t with name, so values of the type
+// held by interface{} fields can be unmarshaled back into their original
+// type instead of a generic map. The name is stored in the AttributeValue
+// map's MarshalOptions.TypeAttribute attribute when marshaling, and read
+// from it when unmarshaling.
+//
+//     dynamodbattribute.RegisterType("user", reflect.TypeOf(User{}))
+//
+//     av, err := dynamodbattribute.MarshalWithOptions(Event{Payload: User{}}, func(e *dynamodbattribute.Encoder) {
+//         e.TypeAttribute = "__type"
+//     })
+//
+//     var event Event
+//     err = dynamodbattribute.UnmarshalWithOptions(av, &event, func(d *dynamodbattribute.Decoder) {
+//         d.TypeAttribute = "__type"
+//     })
+//
+// Only values marshaled as AttributeValue maps, such as structs, carry the
+// type attribute. An error is returned if the name or type is already
+// registered as a different type or name.
+func RegisterType(name string, t reflect.Type) error {
+	if len(name) == 0 || t == nil {
+		return &InvalidMarshalError{msg: "registered type name and type cannot be empty"}
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	if rt, ok := typeRegistry.types[name]; ok && rt != t {
+		return &InvalidMarshalError{
+			msg: fmt.Sprintf("type name %q already registered as %v", name, rt),
+		}
+	}
+	if rn, ok := typeRegistry.names[t]; ok && rn != name {
+		return &InvalidMarshalError{
+			msg: fmt.Sprintf("type %v already registered as %q", t, rn),
+		}
+	}
+	typeRegistry.types[name] = t
+	typeRegistry.names[t] = name
+
+	return nil
+}
+
+func registeredType(name string) (reflect.Type, bool) {
+	typeRegistry.RLock()
+	t, ok := typeRegistry.types[name]
+	typeRegistry.RUnlock()
+
+	return t, ok
+}
+
+func registeredTypeName(t reflect.Type) (string, bool) {
+	typeRegistry.RLock()
+	name, ok := typeRegistry.names[t]
+	typeRegistry.RUnlock()
+
+	return name, ok
+}
+
+// decodeRegisteredType decodes the AttributeValue map into a new value of
+// the type named by its type attribute, and sets v, an interface{} value,
+// to it. The bool returned is false if the map has no type attribute.
+func (d *Decoder) decodeRegisteredType(av *dynamodb.AttributeValue, v reflect.Value) (bool, error) {
+	nameAV, ok := av.M[d.TypeAttribute]
+	if !ok || nameAV == nil || nameAV.S == nil {
+		return false, nil
+	}
+	t, ok := registeredType(*nameAV.S)
+	if !ok {
+		return true, &UnmarshalTypeError{Value: "unregistered type " + *nameAV.S, Type: v.Type()}
+	}
+
+	rv := reflect.New(t).Elem()
+	if err := d.decode(av, rv, tag{}); err != nil {
+		return true, err
+	}
+	v.Set(rv)
+
+	return true, nil
+}
+
+// encodeTypeAttribute adds the type attribute to av if v, an interface{}
+// value, holds a value of a registered type.
+func (e *Encoder) encodeTypeAttribute(av *dynamodb.AttributeValue, v reflect.Value) {
+	if av.M == nil || v.IsNil() {
+		return
+	}
+	name, ok := registeredTypeName(v.Elem().Type())
+	if !ok {
+		return
+	}
+	if _, ok := av.M[e.TypeAttribute]; !ok {
+		av.M[e.TypeAttribute] = &dynamodb.AttributeValue{S: &name}
+	}
+}
@@ -0,0 +1,93 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testRegisteredUser struct {
+	ID   string
+	Name string
+}
+
+type testRegisteredEvent struct {
+	Payload interface{}
+	Extra   []interface{}
+}
+
+func TestRegisteredTypeRoundTrip(t *testing.T) {
+	if err := RegisterType("testUser", reflect.TypeOf(testRegisteredUser{})); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	in := testRegisteredEvent{
+		Payload: testRegisteredUser{ID: "u1", Name: "abc"},
+		Extra:   []interface{}{testRegisteredUser{ID: "u2"}, "plain"},
+	}
+	av, err := MarshalWithOptions(in, func(e *Encoder) {
+		e.TypeAttribute = "__type"
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "testUser", av.M["Payload"].M["__type"]; a == nil || *a.S != e {
+		t.Errorf("expect %q type attribute, got %v", e, a)
+	}
+
+	var actual testRegisteredEvent
+	err = UnmarshalWithOptions(av, &actual, func(d *Decoder) {
+		d.TypeAttribute = "__type"
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Without the type attribute enabled maps decode as generic maps.
+	var generic testRegisteredEvent
+	if err := Unmarshal(av, &generic); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := generic.Payload.(map[string]interface{}); !ok {
+		t.Errorf("expect generic map, got %T", generic.Payload)
+	}
+}
+
+func TestRegisteredTypeUnknownName(t *testing.T) {
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"__type": {S: aws.String("testUnknown")},
+	}}
+
+	var actual interface{}
+	err := UnmarshalWithOptions(av, &actual, func(d *Decoder) {
+		d.TypeAttribute = "__type"
+	})
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %T, %v", err, err)
+	}
+}
+
+func TestRegisterTypeError(t *testing.T) {
+	if err := RegisterType("testConflict", reflect.TypeOf(testRegisteredEvent{})); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	cases := []struct {
+		name string
+		t    reflect.Type
+	}{
+		{"", reflect.TypeOf(testRegisteredEvent{})},
+		{"testConflict", reflect.TypeOf(testRegisteredUser{})},
+		{"testConflict2", reflect.TypeOf(testRegisteredEvent{})},
+	}
+	for i, c := range cases {
+		if err := RegisterType(c.name, c.t); err == nil {
+			t.Errorf("%d, expect error", i)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbattribute
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MarshalAs marshals the Go value of type T into an AttributeValue, the
+// same as MarshalWithOptions.
+func MarshalAs[T any](in T, opts ...func(*Encoder)) (*dynamodb.AttributeValue, error) {
+	return MarshalWithOptions(in, opts...)
+}
+
+// MarshalSlice marshals each Go value of type T into a map of
+// AttributeValues, such as the items of a BatchWriteItem request.
+//
+//     items, err := dynamodbattribute.MarshalSlice(orders)
+func MarshalSlice[T any](in []T, opts ...func(*Encoder)) ([]map[string]*dynamodb.AttributeValue, error) {
+	items := make([]map[string]*dynamodb.AttributeValue, len(in))
+	for i := range in {
+		item, err := MarshalMapWithOptions(in[i], opts...)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// UnmarshalAs unmarshals the AttributeValue into a new value of type T,
+// returning the value instead of requiring a pointer to unmarshal into.
+//
+//     order, err := dynamodbattribute.UnmarshalAs[Order](av)
+func UnmarshalAs[T any](av *dynamodb.AttributeValue, opts ...func(*Decoder)) (T, error) {
+	var out T
+	err := UnmarshalWithOptions(av, &out, opts...)
+	return out, err
+}
+
+// UnmarshalMapAs unmarshals the map of AttributeValues, such as the Item of
+// a GetItem response, into a new value of type T.
+//
+//     order, err := dynamodbattribute.UnmarshalMapAs[Order](resp.Item)
+func UnmarshalMapAs[T any](m map[string]*dynamodb.AttributeValue, opts ...func(*Decoder)) (T, error) {
+	var out T
+	err := UnmarshalMapWithOptions(m, &out, opts...)
+	return out, err
+}
+
+// UnmarshalSlice unmarshals the slice of AttributeValue maps, such as the
+// Items of a Query response, into a new slice of T values.
+//
+//     orders, err := dynamodbattribute.UnmarshalSlice[Order](resp.Items)
+func UnmarshalSlice[T any](items []map[string]*dynamodb.AttributeValue, opts ...func(*Decoder)) ([]T, error) {
+	var out []T
+	if err := UnmarshalListOfMapsWithOptions(items, &out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ValidateAgainst checks the AttributeValue types of the item's attributes
+// against the fields of the struct type T, without unmarshaling the item.
+// See ValidateItemSchema.
+//
+//     for _, item := range resp.Items {
+//         if err := dynamodbattribute.ValidateAgainst[Order](item); err != nil {
+//             fmt.Println(err)
+//         }
+//     }
+func ValidateAgainst[T any](item map[string]*dynamodb.AttributeValue) error {
+	var model T
+	return ValidateItemSchema(model, item)
+}
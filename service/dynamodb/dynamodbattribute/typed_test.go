@@ -0,0 +1,103 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testTypedOrder struct {
+	ID       string `dynamodbav:"id"`
+	Quantity int
+}
+
+func TestTypedRoundTrip(t *testing.T) {
+	orders := []testTypedOrder{{"a", 1}, {"b", 2}}
+
+	items, err := MarshalSlice(orders)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("a")}, "Quantity": {N: aws.String("1")}},
+		{"id": {S: aws.String("b")}, "Quantity": {N: aws.String("2")}},
+	}
+	if !reflect.DeepEqual(expect, items) {
+		t.Errorf("expect %v, got %v", expect, items)
+	}
+
+	actual, err := UnmarshalSlice[testTypedOrder](items)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(orders, actual) {
+		t.Errorf("expect %v, got %v", orders, actual)
+	}
+
+	order, err := UnmarshalMapAs[testTypedOrder](items[1])
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := orders[1], order; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av, err := MarshalAs(orders[0])
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	order, err = UnmarshalAs[testTypedOrder](av)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := orders[0], order; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUnmarshalAsError(t *testing.T) {
+	_, err := UnmarshalAs[int](&dynamodb.AttributeValue{S: aws.String("abc")})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := err.(*UnmarshalTypeError); !ok {
+		t.Errorf("expect UnmarshalTypeError, got %T", err)
+	}
+
+	items, err := UnmarshalSlice[testTypedOrder]([]map[string]*dynamodb.AttributeValue{
+		{"Quantity": {S: aws.String("abc")}},
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if items != nil {
+		t.Errorf("expect no items, got %v", items)
+	}
+}
+
+func TestValidateAgainst(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"ID":    {S: aws.String("abc")},
+		"Total": {S: aws.String("1.5")},
+	}
+
+	err := ValidateAgainst[testSchemaOrder](item)
+	verr, ok := err.(*SchemaValidationError)
+	if !ok {
+		t.Fatalf("expect SchemaValidationError, got %v", err)
+	}
+	expect := []SchemaMismatch{{Path: "Total", Expected: "N", Actual: "S"}}
+	if !reflect.DeepEqual(expect, verr.Mismatches) {
+		t.Errorf("expect %v, got %v", expect, verr.Mismatches)
+	}
+
+	item["Total"] = &dynamodb.AttributeValue{N: aws.String("1.5")}
+	if err := ValidateAgainst[*testSchemaOrder](item); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A union is the set of concrete types registered for an interface type.
+type union struct {
+	discriminator string
+	members       map[string]reflect.Type
+	names         map[reflect.Type]string
+}
+
+var unionRegistry = struct {
+	sync.RWMutex
+	unions map[reflect.Type]*union
+}{unions: map[reflect.Type]*union{}}
+
+// RegisterUnion registers the concrete types values of an interface type
+// are unmarshaled into. The string value of the discriminator attribute of
+// an AttributeValue map selects which of the members it is decoded into.
+// This allows items of several shapes stored in a single table to be
+// decoded into a single slice or field of interface type.
+//
+// iface must be a nil pointer to the interface type, and members maps each
+// discriminator value to a value of the concrete type, which must
+// implement the interface.
+//
+//     type Entity interface{}
+//
+//     err := dynamodbattribute.RegisterUnion((*Entity)(nil), "type", map[string]interface{}{
+//         "user":  &User{},
+//         "order": &Order{},
+//     })
+//
+//     var entities []Entity
+//     err = dynamodbattribute.UnmarshalListOfMaps(out.Items, &entities)
+//
+// A struct field can override the discriminator attribute with the
+// `union` tag option, e.g. `dynamodbav:",union=kind"`.
+//
+// When marshaling a value of a registered member type held by the interface,
+// the discriminator attribute is added to the AttributeValue map if the
+// member does not already set it.
+//
+// Registering the same interface type again replaces its members.
+func RegisterUnion(iface interface{}, discriminator string, members map[string]interface{}) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return &InvalidMarshalError{
+			msg: fmt.Sprintf("union must be registered with a pointer to an interface type, %v", t),
+		}
+	}
+	t = t.Elem()
+	if t.NumMethod() == 0 {
+		return &InvalidMarshalError{
+			msg: fmt.Sprintf("union interface must have methods, %v", t),
+		}
+	}
+	if len(discriminator) == 0 {
+		return &InvalidMarshalError{msg: "union discriminator attribute cannot be empty"}
+	}
+
+	u := &union{
+		discriminator: discriminator,
+		members:       make(map[string]reflect.Type, len(members)),
+		names:         make(map[reflect.Type]string, len(members)),
+	}
+	for name, member := range members {
+		mt := reflect.TypeOf(member)
+		if mt == nil || !mt.Implements(t) {
+			return &InvalidMarshalError{
+				msg: fmt.Sprintf("union member %q, %v does not implement %v", name, mt, t),
+			}
+		}
+		u.members[name] = mt
+		u.names[mt] = name
+	}
+
+	unionRegistry.Lock()
+	unionRegistry.unions[t] = u
+	unionRegistry.Unlock()
+
+	return nil
+}
+
+func lookupUnion(t reflect.Type) (*union, bool) {
+	if t.Kind() != reflect.Interface || t.NumMethod() == 0 {
+		return nil, false
+	}
+
+	unionRegistry.RLock()
+	u, ok := unionRegistry.unions[t]
+	unionRegistry.RUnlock()
+
+	return u, ok
+}
+
+// discriminatorAttr returns the discriminator attribute of the union,
+// overridden by the field's tag if set.
+func (u *union) discriminatorAttr(fieldTag tag) string {
+	if len(fieldTag.Union) != 0 {
+		return fieldTag.Union
+	}
+	return u.discriminator
+}
+
+// decodeUnion decodes the AttributeValue map into the member of the union
+// selected by its discriminator attribute, and sets v, the interface value,
+// to it.
+func (d *Decoder) decodeUnion(av *dynamodb.AttributeValue, v reflect.Value, u *union, fieldTag tag) error {
+	attr := u.discriminatorAttr(fieldTag)
+
+	disc, ok := attrByName(av.M, attr)
+	if !ok || disc == nil || disc.S == nil {
+		return &UnmarshalTypeError{Value: "map without " + attr + " string attribute", Type: v.Type()}
+	}
+	mt, ok := u.members[*disc.S]
+	if !ok {
+		return &UnmarshalTypeError{Value: "unknown " + attr + " " + *disc.S, Type: v.Type()}
+	}
+
+	member := reflect.New(mt).Elem()
+	if err := d.decode(av, member, tag{}); err != nil {
+		return err
+	}
+	v.Set(member)
+
+	return nil
+}
+
+// encodeUnionDiscriminator adds the discriminator attribute to av if v, an
+// interface value, holds a registered member of its union.
+func encodeUnionDiscriminator(av *dynamodb.AttributeValue, v reflect.Value, fieldTag tag) {
+	if av.M == nil || v.IsNil() {
+		return
+	}
+	u, ok := lookupUnion(v.Type())
+	if !ok {
+		return
+	}
+	name, ok := u.names[v.Elem().Type()]
+	if !ok {
+		return
+	}
+
+	attr := u.discriminatorAttr(fieldTag)
+	if _, ok := av.M[attr]; !ok {
+		av.M[attr] = &dynamodb.AttributeValue{S: &name}
+	}
+}
@@ -0,0 +1,150 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testUnionEntity interface {
+	entityID() string
+}
+
+type testUnionUser struct {
+	ID   string
+	Name string
+}
+
+func (u *testUnionUser) entityID() string { return u.ID }
+
+type testUnionOrder struct {
+	ID    string
+	Kind  string
+	Total int
+}
+
+func (o testUnionOrder) entityID() string { return o.ID }
+
+func registerTestUnion(t *testing.T) {
+	err := RegisterUnion((*testUnionEntity)(nil), "Kind", map[string]interface{}{
+		"user":  &testUnionUser{},
+		"order": testUnionOrder{},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+}
+
+func TestUnionUnmarshal(t *testing.T) {
+	registerTestUnion(t)
+
+	items := []map[string]*dynamodb.AttributeValue{
+		{"Kind": {S: aws.String("user")}, "ID": {S: aws.String("u1")}, "Name": {S: aws.String("abc")}},
+		{"Kind": {S: aws.String("order")}, "ID": {S: aws.String("o1")}, "Total": {N: aws.String("5")}},
+	}
+
+	var actual []testUnionEntity
+	if err := UnmarshalListOfMaps(items, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []testUnionEntity{
+		&testUnionUser{ID: "u1", Name: "abc"},
+		testUnionOrder{ID: "o1", Kind: "order", Total: 5},
+	}
+	if e, a := expect, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUnionUnmarshalTaggedField(t *testing.T) {
+	registerTestUnion(t)
+
+	type container struct {
+		Entity testUnionEntity `dynamodbav:",union=type"`
+	}
+
+	av := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"Entity": {M: map[string]*dynamodb.AttributeValue{
+			"type": {S: aws.String("user")},
+			"ID":   {S: aws.String("u1")},
+		}},
+	}}
+
+	var actual container
+	if err := Unmarshal(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := (&testUnionUser{ID: "u1"}), actual.Entity; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Round trips with the field's discriminator attribute.
+	marshaled, err := Marshal(actual)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "user", marshaled.M["Entity"].M["type"]; a == nil || a.S == nil || *a.S != e {
+		t.Errorf("expect %q discriminator, got %v", e, a)
+	}
+}
+
+func TestUnionUnmarshalError(t *testing.T) {
+	registerTestUnion(t)
+
+	cases := []map[string]*dynamodb.AttributeValue{
+		{"ID": {S: aws.String("u1")}},
+		{"Kind": {S: aws.String("other")}},
+		{"Kind": {N: aws.String("1")}},
+	}
+
+	for i, c := range cases {
+		var actual testUnionEntity
+		err := UnmarshalMap(c, &actual)
+		if _, ok := err.(*UnmarshalTypeError); !ok {
+			t.Errorf("%d, expect UnmarshalTypeError, got %T, %v", i, err, err)
+		}
+	}
+}
+
+func TestUnionMarshal(t *testing.T) {
+	registerTestUnion(t)
+
+	in := []testUnionEntity{
+		&testUnionUser{ID: "u1"},
+		testUnionOrder{ID: "o1", Kind: "legacy"},
+	}
+	av, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "user", av.L[0].M["Kind"]; a == nil || *a.S != e {
+		t.Errorf("expect %q, got %v", e, a)
+	}
+	// Members setting the discriminator themselves are not overwritten.
+	if e, a := "legacy", av.L[1].M["Kind"]; a == nil || *a.S != e {
+		t.Errorf("expect %q, got %v", e, a)
+	}
+}
+
+func TestRegisterUnionError(t *testing.T) {
+	cases := []struct {
+		iface   interface{}
+		disc    string
+		members map[string]interface{}
+	}{
+		{testUnionUser{}, "Kind", nil},
+		{(*interface{})(nil), "Kind", nil},
+		{(*testUnionEntity)(nil), "", nil},
+		{(*testUnionEntity)(nil), "Kind", map[string]interface{}{"user": testUnionUser{}}},
+	}
+
+	for i, c := range cases {
+		if err := RegisterUnion(c.iface, c.disc, c.members); err == nil {
+			t.Errorf("%d, expect error", i)
+		}
+	}
+}
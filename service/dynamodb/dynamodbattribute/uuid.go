@@ -0,0 +1,102 @@
+package dynamodbattribute
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// isUUIDArrayType returns if t is a [16]byte array type, such as the UUID
+// types of the popular uuid packages.
+func isUUIDArrayType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+// encodeUUID marshals the [16]byte or string value of a field with the
+// uuid tag option as its 16 byte binary representation. Empty strings are
+// marshaled as NULL.
+func encodeUUID(av *dynamodb.AttributeValue, v reflect.Value) error {
+	switch {
+	case isUUIDArrayType(v.Type()):
+		b := make([]byte, 16)
+		reflect.Copy(reflect.ValueOf(b), v)
+		av.B = b
+	case v.Kind() == reflect.String:
+		if v.Len() == 0 {
+			encodeNull(av)
+			return nil
+		}
+		b, ok := parseUUID(v.String())
+		if !ok {
+			return &InvalidMarshalError{msg: fmt.Sprintf("invalid UUID %q", v.String())}
+		}
+		av.B = b
+	default:
+		return &InvalidMarshalError{msg: "uuid tag option requires a [16]byte or string, got " + v.Type().String()}
+	}
+
+	return nil
+}
+
+// decodeUUID unmarshals the 16 byte binary representation of a UUID into
+// a [16]byte or string value. The string representation is also accepted,
+// so attributes written before the uuid tag option was added can be read.
+func decodeUUID(av *dynamodb.AttributeValue, v reflect.Value) error {
+	var b []byte
+	switch {
+	case av.B != nil:
+		if len(av.B) != 16 {
+			return &UnmarshalTypeError{Value: fmt.Sprintf("UUID binary of length %d", len(av.B)), Type: v.Type()}
+		}
+		b = av.B
+	case av.S != nil:
+		var ok bool
+		if b, ok = parseUUID(*av.S); !ok {
+			return &UnmarshalTypeError{Value: fmt.Sprintf("invalid UUID string %q", *av.S), Type: v.Type()}
+		}
+	default:
+		return &UnmarshalTypeError{Value: "non-UUID attribute value", Type: v.Type()}
+	}
+
+	switch {
+	case isUUIDArrayType(v.Type()):
+		reflect.Copy(v, reflect.ValueOf(b))
+	case v.Kind() == reflect.String:
+		v.SetString(formatUUID(b))
+	case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+		v.Set(reflect.ValueOf(formatUUID(b)))
+	default:
+		return &UnmarshalTypeError{Value: "UUID", Type: v.Type()}
+	}
+
+	return nil
+}
+
+// parseUUID parses the canonical 36 character form of a UUID, or its 32
+// hex digits without hyphens, returning false if s is malformed.
+func parseUUID(s string) ([]byte, bool) {
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return nil, false
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return nil, false
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// formatUUID returns the canonical lower case form of the 16 byte UUID.
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
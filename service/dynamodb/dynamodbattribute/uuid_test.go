@@ -0,0 +1,107 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testUUID [16]byte
+
+func TestUUIDRoundTrip(t *testing.T) {
+	type testRecord struct {
+		ID      testUUID  `dynamodbav:"id,uuid"`
+		Parent  *testUUID `dynamodbav:",uuid"`
+		Text    string    `dynamodbav:",uuid"`
+		Missing string    `dynamodbav:",uuid"`
+	}
+
+	id := testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	in := testRecord{
+		ID:   id,
+		Text: "123e4567-e89b-12d3-a456-426614174000",
+	}
+
+	av, err := MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{
+		"id":      {B: id[:]},
+		"Parent":  {NULL: aws.Bool(true)},
+		"Text":    {B: id[:]},
+		"Missing": {NULL: aws.Bool(true)},
+	}
+	if !reflect.DeepEqual(expect, av) {
+		t.Errorf("expect %v, got %v", expect, av)
+	}
+
+	var actual testRecord
+	if err := UnmarshalMap(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(in, actual) {
+		t.Errorf("expect %v, got %v", in, actual)
+	}
+
+	// String attributes written before the tag was added are accepted.
+	av = map[string]*dynamodb.AttributeValue{
+		"id":     {S: aws.String("123E4567-E89B-12D3-A456-426614174000")},
+		"Parent": {S: aws.String("123e4567e89b12d3a456426614174000")},
+	}
+	actual = testRecord{}
+	if err := UnmarshalMap(av, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := id, actual.ID; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if actual.Parent == nil || *actual.Parent != id {
+		t.Errorf("expect %v, got %v", id, actual.Parent)
+	}
+}
+
+func TestUUIDErrors(t *testing.T) {
+	_, err := Marshal(struct {
+		ID string `dynamodbav:",uuid"`
+	}{ID: "123e4567-e89b-12d3-a456-42661417400z"})
+	if e, a := `InvalidMarshalError: invalid UUID "123e4567-e89b-12d3-a456-42661417400z"`, errString(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	_, err = Marshal(struct {
+		ID int `dynamodbav:",uuid"`
+	}{ID: 1})
+	if _, ok := err.(*InvalidMarshalError); !ok {
+		t.Errorf("expect InvalidMarshalError, got %v", err)
+	}
+
+	var out struct {
+		ID testUUID `dynamodbav:",uuid"`
+	}
+	cases := []struct {
+		av     *dynamodb.AttributeValue
+		expect string
+	}{
+		{
+			&dynamodb.AttributeValue{B: []byte{1, 2, 3}},
+			"UnmarshalTypeError: cannot unmarshal UUID binary of length 3 into Go value of type dynamodbattribute.testUUID",
+		},
+		{
+			&dynamodb.AttributeValue{S: aws.String("123e4567-e89b")},
+			`UnmarshalTypeError: cannot unmarshal invalid UUID string "123e4567-e89b" into Go value of type dynamodbattribute.testUUID`,
+		},
+		{
+			&dynamodb.AttributeValue{N: aws.String("1")},
+			"UnmarshalTypeError: cannot unmarshal non-UUID attribute value into Go value of type dynamodbattribute.testUUID",
+		},
+	}
+	for i, c := range cases {
+		err := UnmarshalMap(map[string]*dynamodb.AttributeValue{"ID": c.av}, &out)
+		if e, a := c.expect, errString(err); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A SchemaMismatch is an attribute of an item whose AttributeValue type
+// does not match the type of the struct field it would be unmarshaled
+// into, or a required attribute which is missing.
+type SchemaMismatch struct {
+	// Path of the attribute within the item, e.g. "Orders[2].Total".
+	Path string
+
+	// AttributeValue type expected by the struct field, e.g. "N".
+	Expected string
+
+	// AttributeValue type of the attribute, e.g. "S". Empty if the
+	// required attribute is missing.
+	Actual string
+}
+
+func (m SchemaMismatch) String() string {
+	if len(m.Actual) == 0 {
+		return fmt.Sprintf("%s: missing required %s", m.Path, m.Expected)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", m.Path, m.Expected, m.Actual)
+}
+
+// ValidateItemSchema checks the AttributeValue types of the item's
+// attributes against the types and tags of the fields of the struct model,
+// without unmarshaling the item. Every mismatch found is reported by the
+// returned SchemaValidationError, such as for auditing the items of a Scan
+// for data quality. The model must be a struct or pointer to a struct
+// value, e.g. the zero value of the model type.
+//
+// NULL attributes, attributes without a matching field, and fields of
+// interface or custom Marshaler types are not checked. Lists and sets are
+// interchangeable, as both can be unmarshaled into slices.
+func ValidateItemSchema(model interface{}, item map[string]*dynamodb.AttributeValue) error {
+	t, err := schemaStructType(model)
+	if err != nil {
+		return err
+	}
+
+	v := schemaValidator{opts: MarshalOptions{SupportJSONTags: true}}
+	v.validateStruct("", t, item)
+	if len(v.mismatches) == 0 {
+		return nil
+	}
+
+	sort.Stable(schemaMismatchesByPath(v.mismatches))
+	return &SchemaValidationError{Type: t, Mismatches: v.mismatches}
+}
+
+type schemaValidator struct {
+	opts       MarshalOptions
+	mismatches []SchemaMismatch
+}
+
+func (v *schemaValidator) validateStruct(path string, t reflect.Type, item map[string]*dynamodb.AttributeValue) {
+	for _, f := range unionStructFields(t, v.opts) {
+		name := f.Name
+		av, ok := attrByName(item, name)
+		if !ok && len(f.ReadFrom) != 0 {
+			name = f.ReadFrom
+			av, ok = attrByName(item, name)
+		}
+		for i := 0; !ok && i < len(f.Aliases); i++ {
+			name = f.Aliases[i]
+			av, ok = attrByName(item, name)
+		}
+
+		fieldPath := joinAttributePath(path, name)
+		if !ok {
+			if f.Required {
+				v.mismatches = append(v.mismatches, SchemaMismatch{
+					Path: fieldPath, Expected: schemaAttrType(f.Type, f.tag),
+				})
+			}
+			continue
+		}
+		if f.Compress || f.Encrypted {
+			// May be stored as binary, or as the field's type.
+			continue
+		}
+
+		v.validateValue(fieldPath, f.Type, f.tag, av)
+	}
+}
+
+func (v *schemaValidator) validateValue(path string, t reflect.Type, ft tag, av *dynamodb.AttributeValue) {
+	if av == nil || av.NULL != nil {
+		return
+	}
+
+	t = schemaIndirect(t)
+	expected := schemaAttrType(t, ft)
+	if expected == schemaAnyType || expected == schemaCustomType {
+		return
+	}
+
+	actual := attributeValueTypeName(av)
+	if !schemaTypesCompatible(expected, actual) {
+		v.mismatches = append(v.mismatches, SchemaMismatch{Path: path, Expected: expected, Actual: actual})
+		return
+	}
+
+	switch {
+	case actual == "M" && t.Kind() == reflect.Struct:
+		v.validateStruct(path, t, av.M)
+	case actual == "M" && t.Kind() == reflect.Map:
+		for k, elem := range av.M {
+			v.validateValue(joinAttributePath(path, k), t.Elem(), tag{}, elem)
+		}
+	case actual == "L":
+		for i, elem := range av.L {
+			v.validateValue(path+"["+strconv.Itoa(i)+"]", t.Elem(), tag{}, elem)
+		}
+	}
+}
+
+// schemaTypesCompatible returns if an attribute of the actual type can be
+// unmarshaled into a field expecting the expected type.
+func schemaTypesCompatible(expected, actual string) bool {
+	switch {
+	case expected == actual:
+		return true
+	case expected == "L" || isSchemaSetType(expected):
+		return actual == "L" || isSchemaSetType(actual)
+	}
+	return false
+}
+
+// attributeValueTypeName returns the name of the AttributeValue's type, e.g.
+// "S" or "BOOL".
+func attributeValueTypeName(av *dynamodb.AttributeValue) string {
+	switch {
+	case av.B != nil:
+		return "B"
+	case av.BOOL != nil:
+		return "BOOL"
+	case av.BS != nil:
+		return "BS"
+	case av.L != nil:
+		return "L"
+	case av.M != nil:
+		return "M"
+	case av.N != nil:
+		return "N"
+	case av.NS != nil:
+		return "NS"
+	case av.NULL != nil:
+		return "NULL"
+	case av.S != nil:
+		return "S"
+	case av.SS != nil:
+		return "SS"
+	}
+	return "unknown"
+}
+
+type schemaMismatchesByPath []SchemaMismatch
+
+func (x schemaMismatchesByPath) Len() int { return len(x) }
+
+func (x schemaMismatchesByPath) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+
+func (x schemaMismatchesByPath) Less(i, j int) bool { return x[i].Path < x[j].Path }
+
+// A SchemaValidationError is an error type representing the attributes of
+// an item which do not match the struct type it was validated against.
+type SchemaValidationError struct {
+	emptyOrigError
+
+	// Go value type the item was validated against.
+	Type reflect.Type
+
+	// Attributes which did not match, in path order.
+	Mismatches []SchemaMismatch
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *SchemaValidationError) Code() string {
+	return "SchemaValidationError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *SchemaValidationError) Message() string {
+	msg := fmt.Sprintf("%d attributes do not match Go value of type %s", len(e.Mismatches), e.Type)
+	for _, m := range e.Mismatches {
+		msg += ", " + m.String()
+	}
+	return msg
+}
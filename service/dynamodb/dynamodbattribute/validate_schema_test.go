@@ -0,0 +1,94 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testSchemaLine struct {
+	SKU      string
+	Quantity int
+}
+
+type testSchemaOrder struct {
+	ID       string `dynamodbav:",required"`
+	Total    float64
+	Code     int `dynamodbav:",string"`
+	Created  time.Time
+	Shipped  bool
+	Lines    []testSchemaLine
+	Tags     []string `dynamodbav:",stringset"`
+	Counts   map[string]int
+	Customer string `dynamodbav:"customer,alias=cust"`
+	Any      interface{}
+	Note     *string
+}
+
+func TestValidateItemSchema(t *testing.T) {
+	valid := map[string]*dynamodb.AttributeValue{
+		"ID":      {S: aws.String("abc")},
+		"Total":   {N: aws.String("1.5")},
+		"Code":    {S: aws.String("12")},
+		"Created": {S: aws.String("2016-01-01T00:00:00Z")},
+		"Shipped": {BOOL: aws.Bool(true)},
+		"Lines": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"SKU": {S: aws.String("x")}, "Quantity": {N: aws.String("1")}}},
+		}},
+		"Tags":    {L: []*dynamodb.AttributeValue{{S: aws.String("a")}}},
+		"Counts":  {M: map[string]*dynamodb.AttributeValue{"a": {N: aws.String("1")}}},
+		"cust":    {S: aws.String("bob")},
+		"Any":     {M: map[string]*dynamodb.AttributeValue{}},
+		"Note":    {NULL: aws.Bool(true)},
+		"Unknown": {N: aws.String("1")},
+	}
+	if err := ValidateItemSchema(testSchemaOrder{}, valid); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+
+	invalid := map[string]*dynamodb.AttributeValue{
+		"Total":   {S: aws.String("1.5")},
+		"Code":    {N: aws.String("12")},
+		"Shipped": {S: aws.String("true")},
+		"Lines": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"Quantity": {N: aws.String("1")}}},
+			{M: map[string]*dynamodb.AttributeValue{"Quantity": {S: aws.String("1")}}},
+			{S: aws.String("x")},
+		}},
+		"Counts":   {M: map[string]*dynamodb.AttributeValue{"a": {BOOL: aws.Bool(true)}}},
+		"customer": {N: aws.String("1")},
+		"Note":     {N: aws.String("1")},
+	}
+	err := ValidateItemSchema(&testSchemaOrder{}, invalid)
+	verr, ok := err.(*SchemaValidationError)
+	if !ok {
+		t.Fatalf("expect SchemaValidationError, got %v", err)
+	}
+
+	expect := []SchemaMismatch{
+		{Path: "Code", Expected: "S", Actual: "N"},
+		{Path: "Counts.a", Expected: "N", Actual: "BOOL"},
+		{Path: "ID", Expected: "S"},
+		{Path: "Lines[1].Quantity", Expected: "N", Actual: "S"},
+		{Path: "Lines[2]", Expected: "M", Actual: "S"},
+		{Path: "Note", Expected: "S", Actual: "N"},
+		{Path: "Shipped", Expected: "BOOL", Actual: "S"},
+		{Path: "Total", Expected: "N", Actual: "S"},
+		{Path: "customer", Expected: "S", Actual: "N"},
+	}
+	if !reflect.DeepEqual(expect, verr.Mismatches) {
+		t.Errorf("expect %v, got %v", expect, verr.Mismatches)
+	}
+	if e, a := "SchemaValidationError: 9 attributes do not match Go value of type "+
+		"dynamodbattribute.testSchemaOrder, Code: expected S, got N, ", verr.Error(); !strings.HasPrefix(a, e) {
+		t.Errorf("expect %q prefix, got %q", e, a)
+	}
+
+	if err := ValidateItemSchema("abc", valid); err == nil {
+		t.Errorf("expect error for non-struct model")
+	}
+}
@@ -0,0 +1,138 @@
+package dynamodbattribute
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// testVector is an item written by another SDK's mapper for a model
+// equivalent to one of the Go models in testVectorModels.
+type testVector struct {
+	Name      string
+	Source    string
+	RoundTrip bool
+	Notes     string
+	Item      json.RawMessage
+}
+
+type testVectorAddress struct {
+	City string `dynamodbav:"city"`
+	Zip  string `dynamodbav:"zip"`
+}
+
+type testVectorLine struct {
+	SKU string
+	Qty int
+}
+
+// testVectorModels are the Go values expected to be decoded from each
+// test vector's item.
+var testVectorModels = map[string]interface{}{
+	"scalars": &struct {
+		ID       string
+		Age      int
+		Score    float64
+		Active   bool
+		Avatar   []byte
+		Nickname *string
+	}{
+		ID:     "user#1",
+		Age:    42,
+		Score:  1.5,
+		Active: true,
+		Avatar: []byte{1, 2, 3},
+	},
+	"sets": &struct {
+		ID    string
+		Tags  []string  `dynamodbav:",stringset"`
+		Ranks []float64 `dynamodbav:",numberset"`
+		Keys  [][]byte
+	}{
+		ID:    "user#2",
+		Tags:  []string{"b", "a"},
+		Ranks: []float64{3, 1.25, -2},
+		Keys:  [][]byte{{1}, {2}},
+	},
+	"document": &struct {
+		ID    string
+		Lines []testVectorLine
+		Meta  map[string]interface{}
+	}{
+		ID: "order#1",
+		Lines: []testVectorLine{
+			{SKU: "a-1", Qty: 2},
+			{SKU: "b-2", Qty: 1},
+		},
+		Meta: map[string]interface{}{"channel": "web", "retries": float64(0)},
+	},
+	"mapper": &struct {
+		ID      string             `dynamodbav:"id"`
+		Version int64              `dynamodbav:"version"`
+		Enabled bool               `dynamodbav:"enabled"`
+		Emails  []string           `dynamodbav:"emails,stringset"`
+		Address *testVectorAddress `dynamodbav:"address"`
+	}{
+		ID:      "user#3",
+		Version: 7,
+		Emails:  []string{"a@example.com"},
+		Address: &testVectorAddress{City: "Seattle", Zip: "98101"},
+	},
+	"mapperDates": &struct {
+		ID      string    `dynamodbav:"id"`
+		Created time.Time `dynamodbav:"created"`
+	}{
+		ID:      "user#4",
+		Created: time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC),
+	},
+}
+
+func TestVectors(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("expect no error reading vectors, got %v", err)
+	}
+	var vectors []testVector
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatalf("expect no error parsing vectors, got %v", err)
+	}
+
+	for _, v := range vectors {
+		expect, ok := testVectorModels[v.Name]
+		if !ok {
+			t.Errorf("%s, expect model for vector", v.Name)
+			continue
+		}
+
+		item, err := UnmarshalWireJSONMap(v.Item)
+		if err != nil {
+			t.Errorf("%s, expect no error parsing item, got %v", v.Name, err)
+			continue
+		}
+
+		actual := reflect.New(reflect.TypeOf(expect).Elem())
+		if err := UnmarshalMap(item, actual.Interface()); err != nil {
+			t.Errorf("%s, expect no error unmarshaling %s item, got %v", v.Name, v.Source, err)
+			continue
+		}
+		if e, a := expect, actual.Interface(); !reflect.DeepEqual(e, a) {
+			t.Errorf("%s, expect %v, got %v", v.Name, e, a)
+		}
+
+		if !v.RoundTrip {
+			continue
+		}
+		marshaled, err := MarshalMap(actual.Interface())
+		if err != nil {
+			t.Errorf("%s, expect no error marshaling, got %v", v.Name, err)
+			continue
+		}
+		if e, a := (&dynamodb.AttributeValue{M: item}), (&dynamodb.AttributeValue{M: marshaled}); !Equal(e, a) {
+			t.Errorf("%s, expect %s item, got %v", v.Name, v.Source, a)
+		}
+	}
+}
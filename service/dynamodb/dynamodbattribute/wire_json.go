@@ -0,0 +1,92 @@
+package dynamodbattribute
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MarshalWireJSON returns the DynamoDB wire JSON of the AttributeValue, the
+// same representation used by the low level API, table exports, and the
+// AWS CLI. e.g. {"M":{"name":{"S":"abc"},"count":{"N":"1"}}}
+func MarshalWireJSON(av *dynamodb.AttributeValue) ([]byte, error) {
+	return jsonutil.BuildJSON(av)
+}
+
+// MarshalWireJSONMap returns the DynamoDB wire JSON of the item. e.g.
+// {"name":{"S":"abc"},"count":{"N":"1"}}
+func MarshalWireJSONMap(item map[string]*dynamodb.AttributeValue) ([]byte, error) {
+	return jsonutil.BuildJSON(item)
+}
+
+// UnmarshalWireJSON parses the DynamoDB wire JSON of an AttributeValue, such
+// as a value from a test fixture or log. An error is returned if any of the
+// values do not have exactly one data type.
+func UnmarshalWireJSON(b []byte) (*dynamodb.AttributeValue, error) {
+	av := &dynamodb.AttributeValue{}
+	if err := jsonutil.UnmarshalJSON(av, bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	if err := validateWireValue(av); err != nil {
+		return nil, err
+	}
+
+	return av, nil
+}
+
+// UnmarshalWireJSONMap parses the DynamoDB wire JSON of an item, such as
+// a line of a table export's Item. See UnmarshalWireJSON.
+func UnmarshalWireJSONMap(b []byte) (map[string]*dynamodb.AttributeValue, error) {
+	// Parse the item as the members of a map AttributeValue.
+	wrapped := make([]byte, 0, len(b)+6)
+	wrapped = append(wrapped, `{"M":`...)
+	wrapped = append(wrapped, b...)
+	wrapped = append(wrapped, '}')
+
+	av, err := UnmarshalWireJSON(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return av.M, nil
+}
+
+var attributeValueType = reflect.TypeOf(dynamodb.AttributeValue{})
+
+// validateWireValue returns an error if av, or any value nested within it,
+// does not have exactly one data type set.
+func validateWireValue(av *dynamodb.AttributeValue) error {
+	if av == nil {
+		return &UnmarshalTypeError{Value: "null", Type: attributeValueType}
+	}
+
+	n := 0
+	for _, set := range []bool{
+		av.B != nil, av.BOOL != nil, av.BS != nil, av.L != nil, av.M != nil,
+		av.N != nil, av.NS != nil, av.NULL != nil, av.S != nil, av.SS != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	switch {
+	case n == 0:
+		return &UnmarshalTypeError{Value: "value with no data type", Type: attributeValueType}
+	case n > 1:
+		return &UnmarshalTypeError{Value: "value with multiple data types", Type: attributeValueType}
+	}
+
+	for _, v := range av.L {
+		if err := validateWireValue(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range av.M {
+		if err := validateWireValue(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestWireJSON(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"b":    {B: []byte("hi")},
+		"bool": {BOOL: aws.Bool(true)},
+		"bs":   {BS: [][]byte{[]byte("x")}},
+		"l":    {L: []*dynamodb.AttributeValue{{NULL: aws.Bool(true)}, {S: aws.String("a")}}},
+		"m":    {M: map[string]*dynamodb.AttributeValue{"n": {N: aws.String("1.5")}}},
+		"ns":   {NS: []*string{aws.String("1")}},
+		"ss":   {SS: []*string{aws.String("a"), aws.String("b")}},
+	}
+	expect := `{"b":{"B":"aGk="},"bool":{"BOOL":true},"bs":{"BS":["eA=="]},` +
+		`"l":{"L":[{"NULL":true},{"S":"a"}]},"m":{"M":{"n":{"N":"1.5"}}},` +
+		`"ns":{"NS":["1"]},"ss":{"SS":["a","b"]}}`
+
+	b, err := MarshalWireJSONMap(item)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect, string(b); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	actual, err := UnmarshalWireJSONMap(b)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(item, actual) {
+		t.Errorf("expect %v, got %v", item, actual)
+	}
+
+	av := &dynamodb.AttributeValue{M: item}
+	b, err = MarshalWireJSON(av)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := `{"M":`+expect+`}`, string(b); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	actualAV, err := UnmarshalWireJSON(b)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(av, actualAV) {
+		t.Errorf("expect %v, got %v", av, actualAV)
+	}
+}
+
+func TestUnmarshalWireJSONInvalid(t *testing.T) {
+	cases := []string{
+		`{}`,
+		`{"X":"abc"}`,
+		`{"S":"abc","N":"1"}`,
+		`{"L":[{"S":"a"},{}]}`,
+		`{"M":{"a":{}}}`,
+		`{"S":`,
+	}
+
+	for i, c := range cases {
+		if _, err := UnmarshalWireJSON([]byte(c)); err == nil {
+			t.Errorf("%d, expect error, got none", i)
+		}
+	}
+
+	if _, err := UnmarshalWireJSONMap([]byte(`{"a":{"S":"abc"},"b":{}}`)); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
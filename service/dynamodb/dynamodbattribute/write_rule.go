@@ -0,0 +1,141 @@
+package dynamodbattribute
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// WriteRuleKind is the kind of constraint a WriteRule places on writes to
+// an attribute.
+type WriteRuleKind int
+
+// Enumeration of write constraints set by struct tag options.
+const (
+	// WriteRuleImmutable is set by the `immutable` tag option. The
+	// attribute may be written if it is not set, or if the value written
+	// is the same as the existing value.
+	WriteRuleImmutable WriteRuleKind = iota
+
+	// WriteRuleWriteOnce is set by the `writeonce` tag option. The
+	// attribute may only be written if it is not set.
+	WriteRuleWriteOnce
+
+	// WriteRuleVersion is set by the `version` tag option. The item may
+	// only be written if the attribute is equal to the field's value, the
+	// version of the item when it was read. A zero version requires that
+	// the item does not exist yet.
+	WriteRuleVersion
+)
+
+// A WriteRule is a constraint on writes to a struct field's attribute.
+type WriteRule struct {
+	// Name of the attribute.
+	Name string
+
+	Kind WriteRuleKind
+
+	// Expected value of the attribute for WriteRuleVersion rules. Nil if
+	// the version is zero and the attribute must not exist.
+	Expected *dynamodb.AttributeValue
+}
+
+// WriteRules returns the write rules set by the `immutable`, `writeonce`,
+// and `version` struct tag options of the fields of in, a struct or pointer
+// to a struct. Nil is returned for values of other types.
+//
+//     type Account struct {
+//         ID      string    `dynamodbav:"id"`
+//         Created time.Time `dynamodbav:"created,immutable"`
+//         Owner   string    `dynamodbav:"owner,writeonce"`
+//         Version int64     `dynamodbav:"version,version"`
+//     }
+//
+// Write rules are not enforced by Marshal. Use expression.WriteCondition to
+// build the condition expression which enforces them for a write. The
+// expected value of version rules is the field's current value, so the item
+// should be marshaled with Encoder.IncrementVersion.
+func WriteRules(in interface{}) []WriteRule {
+	v := reflect.ValueOf(in)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	t := reflect.TypeOf(in)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+
+	var rules []WriteRule
+	for _, f := range unionStructFields(t, MarshalOptions{SupportJSONTags: true}) {
+		switch {
+		case f.WriteOnce:
+			rules = append(rules, WriteRule{Name: f.Name, Kind: WriteRuleWriteOnce})
+		case f.Immutable:
+			rules = append(rules, WriteRule{Name: f.Name, Kind: WriteRuleImmutable})
+		case f.Version:
+			rules = append(rules, WriteRule{Name: f.Name, Kind: WriteRuleVersion, Expected: expectedVersion(v, f)})
+		}
+	}
+
+	return rules
+}
+
+// expectedVersion returns the current value of the version field f of v,
+// or nil if v is not a struct value or the version is zero.
+func expectedVersion(v reflect.Value, f field) *dynamodb.AttributeValue {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	fv, found := fieldByIndex(v, f.Index, func(v *reflect.Value) bool {
+		return false
+	})
+	if !found {
+		return nil
+	}
+	fv = valueElem(fv)
+	if !fv.IsValid() || emptyValue(fv) {
+		return nil
+	}
+
+	av := &dynamodb.AttributeValue{}
+	if err := NewEncoder().encode(av, fv, f.tag); err != nil {
+		return nil
+	}
+	return av
+}
+
+// nextVersion returns the value of the `version` field v incremented by
+// one. Nil pointers are treated as version zero.
+func nextVersion(v reflect.Value) (reflect.Value, error) {
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	v = valueElem(v)
+
+	next := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.IsValid() {
+			next.SetInt(v.Int() + 1)
+		} else {
+			next.SetInt(1)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.IsValid() {
+			next.SetUint(v.Uint() + 1)
+		} else {
+			next.SetUint(1)
+		}
+	default:
+		return reflect.Value{}, &InvalidMarshalError{
+			msg: fmt.Sprintf("version field must be an integer type, %v", t),
+		}
+	}
+
+	return next, nil
+}
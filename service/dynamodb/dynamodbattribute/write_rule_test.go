@@ -0,0 +1,130 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestWriteRules(t *testing.T) {
+	type embedded struct {
+		Owner string `dynamodbav:"owner,writeonce"`
+	}
+	type account struct {
+		embedded
+		ID      string    `dynamodbav:"id"`
+		Created time.Time `dynamodbav:"created,immutable"`
+		Region  string    `json:"region,immutable"`
+	}
+
+	cases := []struct {
+		in     interface{}
+		expect []WriteRule
+	}{
+		{
+			in: &account{},
+			expect: []WriteRule{
+				{Name: "owner", Kind: WriteRuleWriteOnce},
+				{Name: "created", Kind: WriteRuleImmutable},
+				{Name: "region", Kind: WriteRuleImmutable},
+			},
+		},
+		{in: struct{ ID string }{}, expect: nil},
+		{in: "abc", expect: nil},
+		{in: nil, expect: nil},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, WriteRules(c.in); !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestWriteRulesVersion(t *testing.T) {
+	type item struct {
+		ID      string
+		Version int64 `dynamodbav:"v,version"`
+	}
+	type stringVersion struct {
+		Version *uint `dynamodbav:",version,string"`
+	}
+	version := uint(3)
+
+	cases := []struct {
+		in     interface{}
+		expect []WriteRule
+	}{
+		{
+			in:     item{Version: 2},
+			expect: []WriteRule{{Name: "v", Kind: WriteRuleVersion, Expected: &dynamodb.AttributeValue{N: aws.String("2")}}},
+		},
+		{
+			in:     &item{},
+			expect: []WriteRule{{Name: "v", Kind: WriteRuleVersion}},
+		},
+		{
+			in:     (*item)(nil),
+			expect: []WriteRule{{Name: "v", Kind: WriteRuleVersion}},
+		},
+		{
+			in:     stringVersion{Version: &version},
+			expect: []WriteRule{{Name: "Version", Kind: WriteRuleVersion, Expected: &dynamodb.AttributeValue{S: aws.String("3")}}},
+		},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, WriteRules(c.in); !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestEncoderIncrementVersion(t *testing.T) {
+	type item struct {
+		Version  int32 `dynamodbav:",version"`
+		PVersion *uint `dynamodbav:",version"`
+		Other    int
+	}
+
+	in := item{Version: 4, Other: 7}
+	av, err := MarshalWithOptions(in, func(e *Encoder) {
+		e.IncrementVersion = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"Version":  {N: aws.String("5")},
+		"PVersion": {N: aws.String("1")},
+		"Other":    {N: aws.String("7")},
+	}}
+	if e, a := expect, av; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := int32(4), in.Version; e != a {
+		t.Errorf("expect value unmodified %d, got %d", e, a)
+	}
+
+	// Versions are only incremented when enabled.
+	av, err = Marshal(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "4", aws.StringValue(av.M["Version"].N); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	_, err = MarshalWithOptions(struct {
+		Version string `dynamodbav:",version"`
+	}{}, func(e *Encoder) {
+		e.IncrementVersion = true
+	})
+	if _, ok := err.(*InvalidMarshalError); !ok {
+		t.Errorf("expect InvalidMarshalError, got %T, %v", err, err)
+	}
+}
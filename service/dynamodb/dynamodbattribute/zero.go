@@ -0,0 +1,68 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+)
+
+// An isZeroer reports if it is its type's zero value, e.g. time.Time.
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+
+// isZeroByMethod returns the result of the value's IsZero method, and true,
+// if the value, or its address, implements isZeroer. Nil pointers are zero
+// without calling the method.
+func isZeroByMethod(v reflect.Value) (zero, ok bool) {
+	if !v.IsValid() {
+		return false, false
+	}
+
+	if !v.Type().Implements(isZeroerType) {
+		if !v.CanAddr() || !reflect.PtrTo(v.Type()).Implements(isZeroerType) {
+			return false, false
+		}
+		v = v.Addr()
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return true, true
+	}
+
+	return v.Interface().(isZeroer).IsZero(), true
+}
+
+// zeroValue returns if the value is zero for the omitzero tag option, the
+// zero value of its type, or a value whose IsZero method returns true.
+func zeroValue(v reflect.Value) bool {
+	if zero, ok := isZeroByMethod(v); ok {
+		return zero
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !zeroValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !zeroValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.UnsafePointer:
+		return v.Pointer() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	}
+
+	return emptyValue(v)
+}
@@ -0,0 +1,102 @@
+package dynamodbattribute
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testZeroer struct {
+	Value int
+}
+
+// IsZero treats negative values as unset.
+func (z testZeroer) IsZero() bool {
+	return z.Value < 0
+}
+
+type testPtrZeroer struct {
+	Value string
+}
+
+func (z *testPtrZeroer) IsZero() bool {
+	return z.Value == "unset"
+}
+
+func TestEncodeOmitEmptyIsZero(t *testing.T) {
+	type record struct {
+		Created time.Time     `dynamodbav:",omitempty"`
+		Custom  testZeroer    `dynamodbav:",omitempty"`
+		Ptr     *time.Time    `dynamodbav:",omitempty"`
+		Addr    testPtrZeroer `dynamodbav:",omitempty"`
+	}
+
+	zero := time.Time{}
+	av, err := MarshalMap(&record{Custom: testZeroer{-1}, Ptr: &zero, Addr: testPtrZeroer{"unset"}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := []string{"Ptr"}
+	if e, a := expect, attributeNames(av); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av, err = MarshalMap(&record{Created: time.Unix(0, 0).UTC(), Custom: testZeroer{0}, Addr: testPtrZeroer{"set"}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect = []string{"Addr", "Created", "Custom"}
+	if e, a := expect, attributeNames(av); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestEncodeOmitZero(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	type record struct {
+		ID      string
+		Point   point      `dynamodbav:",omitzero"`
+		Tags    []string   `dynamodbav:",omitzero"`
+		Counts  [2]int     `dynamodbav:",omitzero"`
+		Custom  testZeroer `dynamodbav:",omitzero"`
+		Created time.Time  `dynamodbav:",omitzero"`
+		Ref     *point     `dynamodbav:",omitzero"`
+	}
+
+	av, err := MarshalMap(record{ID: "abc", Custom: testZeroer{-1}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"ID"}, attributeNames(av); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	av, err = MarshalMap(record{
+		Point:   point{Y: 1},
+		Tags:    []string{},
+		Counts:  [2]int{0, 1},
+		Created: time.Unix(0, 0).UTC(),
+		Ref:     &point{},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := []string{"Counts", "Created", "Custom", "ID", "Point", "Ref", "Tags"}
+	if e, a := expect, attributeNames(av); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func attributeNames(item map[string]*dynamodb.AttributeValue) []string {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
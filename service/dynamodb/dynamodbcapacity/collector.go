@@ -0,0 +1,82 @@
+package dynamodbcapacity
+
+import "sync"
+
+// A Key identifies the capacity totals of a table and operation.
+type Key struct {
+	TableName string
+	Operation string
+}
+
+// A Total is the capacity consumed by the requests of a table and
+// operation.
+type Total struct {
+	ReadCapacityUnits  float64
+	WriteCapacityUnits float64
+
+	// Number of requests which consumed the capacity.
+	Requests int
+}
+
+// A Collector is a Sink totaling the capacity consumed per table and
+// operation. A Collector is safe for concurrent use.
+type Collector struct {
+	mu     sync.Mutex
+	totals map[Key]Total
+}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{totals: map[Key]Total{}}
+}
+
+// AddCapacity adds the capacity consumed by a request to the totals of the
+// table and operation, satisfying the Sink interface.
+func (c *Collector) AddCapacity(tableName, operation string, readUnits, writeUnits float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := Key{TableName: tableName, Operation: operation}
+	t := c.totals[key]
+	t.ReadCapacityUnits += readUnits
+	t.WriteCapacityUnits += writeUnits
+	t.Requests++
+	c.totals[key] = t
+}
+
+// Totals returns a copy of the capacity totals per table and operation.
+func (c *Collector) Totals() map[Key]Total {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	totals := make(map[Key]Total, len(c.totals))
+	for k, t := range c.totals {
+		totals[k] = t
+	}
+	return totals
+}
+
+// Table returns the sum of the capacity totals of the table's operations.
+func (c *Collector) Table(tableName string) Total {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sum Total
+	for k, t := range c.totals {
+		if k.TableName != tableName {
+			continue
+		}
+		sum.ReadCapacityUnits += t.ReadCapacityUnits
+		sum.WriteCapacityUnits += t.WriteCapacityUnits
+		sum.Requests += t.Requests
+	}
+	return sum
+}
+
+// Reset clears the capacity totals.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.totals = map[Key]Total{}
+}
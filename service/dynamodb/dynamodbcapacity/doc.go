@@ -0,0 +1,19 @@
+// Package dynamodbcapacity provides request handlers which request the
+// capacity consumed by a DynamoDB client's item operations, and report it
+// to a Sink, such as a Collector totaling the read and write capacity
+// units consumed per table and operation.
+//
+//     svc := dynamodb.New(sess)
+//     usage := dynamodbcapacity.NewCollector()
+//     dynamodbcapacity.AddHandlers(&svc.Handlers, usage)
+//
+//     // ... requests made with svc
+//
+//     for key, total := range usage.Totals() {
+//         fmt.Println(key.TableName, key.Operation, total.ReadCapacityUnits, total.WriteCapacityUnits)
+//     }
+//
+// Implement Sink to publish the capacity to a metrics system instead. Add
+// the handlers to a client used for a single table to only measure that
+// table's usage.
+package dynamodbcapacity
@@ -0,0 +1,117 @@
+package dynamodbcapacity
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A Sink receives the capacity consumed by each DynamoDB request. Sinks
+// must be safe for concurrent use.
+type Sink interface {
+	// AddCapacity is called with the capacity units one request consumed
+	// on a table. Batch requests call AddCapacity once for each table.
+	AddCapacity(tableName, operation string, readUnits, writeUnits float64)
+}
+
+// RequestHandlerName is the name of the handler setting the
+// ReturnConsumedCapacity parameter of requests.
+const RequestHandlerName = "dynamodbcapacity.RequestHandler"
+
+// ReportHandlerName is the name of the handler reporting the capacity
+// consumed by requests to the Sink.
+const ReportHandlerName = "dynamodbcapacity.ReportHandler"
+
+// AddHandlers adds handlers to the DynamoDB client handlers which set the
+// ReturnConsumedCapacity parameter of item operations to TOTAL, if it is
+// not already set, and report the capacity each request consumed to the
+// sink. Capacity is reported as read units for GetItem, BatchGetItem,
+// Query, and Scan, and as write units for the other operations.
+func AddHandlers(handlers *request.Handlers, sink Sink) {
+	handlers.Build.PushFrontNamed(request.NamedHandler{Name: RequestHandlerName, Fn: requestCapacity})
+	handlers.Unmarshal.PushBackNamed(request.NamedHandler{Name: ReportHandlerName, Fn: func(r *request.Request) {
+		reportCapacity(r, sink)
+	}})
+}
+
+// requestCapacity sets the request's ReturnConsumedCapacity parameter, if
+// the operation has one and it is not set. The parameter is set on a
+// shallow copy of the input, so the caller's input is not modified.
+func requestCapacity(r *request.Request) {
+	var params interface{}
+	var p **string
+	switch in := r.Params.(type) {
+	case *dynamodb.GetItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.PutItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.UpdateItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.DeleteItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.BatchGetItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.BatchWriteItemInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.QueryInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	case *dynamodb.ScanInput:
+		c := *in
+		params, p = &c, &c.ReturnConsumedCapacity
+	default:
+		return
+	}
+	if *p == nil {
+		*p = aws.String(dynamodb.ReturnConsumedCapacityTotal)
+		r.Params = params
+	}
+}
+
+// reportCapacity reports the capacity consumed by the request to the sink.
+func reportCapacity(r *request.Request, sink Sink) {
+	if r.Error != nil {
+		return
+	}
+
+	var consumed []*dynamodb.ConsumedCapacity
+	read := false
+	switch out := r.Data.(type) {
+	case *dynamodb.GetItemOutput:
+		consumed, read = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}, true
+	case *dynamodb.PutItemOutput:
+		consumed = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}
+	case *dynamodb.UpdateItemOutput:
+		consumed = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}
+	case *dynamodb.DeleteItemOutput:
+		consumed = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}
+	case *dynamodb.BatchGetItemOutput:
+		consumed, read = out.ConsumedCapacity, true
+	case *dynamodb.BatchWriteItemOutput:
+		consumed = out.ConsumedCapacity
+	case *dynamodb.QueryOutput:
+		consumed, read = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}, true
+	case *dynamodb.ScanOutput:
+		consumed, read = []*dynamodb.ConsumedCapacity{out.ConsumedCapacity}, true
+	default:
+		return
+	}
+
+	for _, c := range consumed {
+		if c == nil || c.CapacityUnits == nil {
+			continue
+		}
+		units := *c.CapacityUnits
+		if read {
+			sink.AddCapacity(aws.StringValue(c.TableName), r.Operation.Name, units, 0)
+		} else {
+			sink.AddCapacity(aws.StringValue(c.TableName), r.Operation.Name, 0, units)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package dynamodbcapacity
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// newMockClient returns a client with the capacity handlers, responding to
+// every request with the body, and recording the requests' parameters.
+func newMockClient(sink Sink, status int, body string) (*dynamodb.DynamoDB, *[]interface{}) {
+	svc := dynamodb.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	AddHandlers(&svc.Handlers, sink)
+
+	var params []interface{}
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		params = append(params, r.Params)
+		r.HTTPResponse = &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			Header:     http.Header{},
+		}
+	})
+	return svc, &params
+}
+
+var testKey = map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("a")}}
+
+func TestHandlersRequestAndReport(t *testing.T) {
+	c := NewCollector()
+	svc, params := newMockClient(c, 200, `{"ConsumedCapacity":{"TableName":"orders","CapacityUnits":1.5}}`)
+
+	get := &dynamodb.GetItemInput{TableName: aws.String("orders"), Key: testKey}
+	if _, err := svc.GetItem(get); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "TOTAL", aws.StringValue((*params)[0].(*dynamodb.GetItemInput).ReturnConsumedCapacity); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if get.ReturnConsumedCapacity != nil {
+		t.Errorf("expect caller's input not modified, got %v", get)
+	}
+	put := &dynamodb.PutItemInput{TableName: aws.String("orders"), Item: testKey, ReturnConsumedCapacity: aws.String("INDEXES")}
+	if _, err := svc.PutItem(put); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "INDEXES", aws.StringValue(put.ReturnConsumedCapacity); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if _, err := svc.GetItem(&dynamodb.GetItemInput{TableName: aws.String("orders"), Key: testKey}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	totals := c.Totals()
+	if e, a := (Total{ReadCapacityUnits: 3, Requests: 2}), totals[Key{"orders", "GetItem"}]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := (Total{WriteCapacityUnits: 1.5, Requests: 1}), totals[Key{"orders", "PutItem"}]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := (Total{ReadCapacityUnits: 3, WriteCapacityUnits: 1.5, Requests: 3}), c.Table("orders"); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	c.Reset()
+	if e, a := 0, len(c.Totals()); e != a {
+		t.Errorf("expect %d totals, got %d", e, a)
+	}
+}
+
+func TestHandlersBatch(t *testing.T) {
+	c := NewCollector()
+	svc, _ := newMockClient(c, 200, `{"ConsumedCapacity":[
+		{"TableName":"orders","CapacityUnits":2},
+		{"TableName":"customers","CapacityUnits":1}
+	]}`)
+
+	_, err := svc.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"orders": {{DeleteRequest: &dynamodb.DeleteRequest{Key: testKey}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	totals := c.Totals()
+	if e, a := 2, len(totals); e != a {
+		t.Fatalf("expect %d totals, got %v", e, totals)
+	}
+	if e, a := float64(2), totals[Key{"orders", "BatchWriteItem"}].WriteCapacityUnits; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := float64(1), totals[Key{"customers", "BatchWriteItem"}].WriteCapacityUnits; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestHandlersIgnoreErrorsAndOtherOperations(t *testing.T) {
+	c := NewCollector()
+	svc, _ := newMockClient(c, 400, `{"__type":"com.amazonaws.dynamodb.v20120810#ValidationException","message":"bad"}`)
+
+	if _, err := svc.Query(&dynamodb.QueryInput{TableName: aws.String("orders")}); err == nil {
+		t.Fatalf("expect error")
+	}
+
+	svc, params := newMockClient(c, 200, `{"TableNames":[]}`)
+	if _, err := svc.ListTables(&dynamodb.ListTablesInput{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(*params); e != a {
+		t.Errorf("expect %d requests, got %d", e, a)
+	}
+	if e, a := 0, len(c.Totals()); e != a {
+		t.Errorf("expect no totals, got %v", c.Totals())
+	}
+}
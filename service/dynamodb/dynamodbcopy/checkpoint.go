@@ -0,0 +1,106 @@
+package dynamodbcopy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// A Checkpoint is the position of each segment of a copy's parallel Scan.
+type Checkpoint struct {
+	Segments []SegmentCheckpoint
+}
+
+// A SegmentCheckpoint is the position of a segment of a copy's parallel
+// Scan.
+type SegmentCheckpoint struct {
+	// The ExclusiveStartKey of the segment's next Scan request. Nil if the
+	// segment has not been scanned.
+	StartKey map[string]*dynamodb.AttributeValue
+
+	// The segment has been copied.
+	Done bool
+}
+
+// A Checkpointer loads and saves the Checkpoint of a copy. Save is called
+// with the Checkpoint after each page of items is written, and is not
+// called concurrently.
+type Checkpointer interface {
+	// Load returns the Checkpoint saved, or nil if there is none.
+	Load() (*Checkpoint, error)
+
+	// Save saves the Checkpoint, replacing the Checkpoint saved before.
+	Save(*Checkpoint) error
+}
+
+// FileCheckpointer returns a Checkpointer saving the Checkpoint to the
+// file at path as JSON.
+func FileCheckpointer(path string) Checkpointer {
+	return fileCheckpointer(path)
+}
+
+type fileCheckpointer string
+
+// fileSegment is the JSON of a SegmentCheckpoint, with the start key in
+// the DynamoDB wire JSON format.
+type fileSegment struct {
+	StartKey json.RawMessage `json:",omitempty"`
+	Done     bool
+}
+
+func (path fileCheckpointer) Load() (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(string(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var segments []fileSegment
+	if err := json.Unmarshal(b, &segments); err != nil {
+		return nil, err
+	}
+
+	cp := &Checkpoint{Segments: make([]SegmentCheckpoint, len(segments))}
+	for i, s := range segments {
+		cp.Segments[i].Done = s.Done
+		if len(s.StartKey) == 0 {
+			continue
+		}
+		if cp.Segments[i].StartKey, err = dynamodbattribute.UnmarshalWireJSONMap(s.StartKey); err != nil {
+			return nil, err
+		}
+	}
+	return cp, nil
+}
+
+func (path fileCheckpointer) Save(cp *Checkpoint) error {
+	segments := make([]fileSegment, len(cp.Segments))
+	for i, s := range cp.Segments {
+		segments[i].Done = s.Done
+		if s.StartKey == nil {
+			continue
+		}
+		b, err := dynamodbattribute.MarshalWireJSONMap(s.StartKey)
+		if err != nil {
+			return err
+		}
+		segments[i].StartKey = b
+	}
+
+	b, err := json.Marshal(segments)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file renamed over the checkpoint, so an
+	// interrupted save does not leave a partial checkpoint.
+	tmp := string(path) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, string(path))
+}
@@ -0,0 +1,362 @@
+package dynamodbcopy
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// maxBatchWriteItems is the maximum number of requests of a
+// BatchWriteItem request.
+const maxBatchWriteItems = 25
+
+// Default Copier configuration.
+const (
+	DefaultSegments        = 4
+	DefaultMaxBatchRetries = 10
+)
+
+// A Copier copies the items of a source table to a destination table.
+type Copier struct {
+	// The DynamoDB client the source table is scanned with.
+	Source dynamodbiface.DynamoDBAPI
+
+	// Name of the table copied.
+	SourceTable string
+
+	// The DynamoDB client the destination table is written with, which
+	// may be of another region or account than Source.
+	Destination dynamodbiface.DynamoDBAPI
+
+	// Name of the table items are copied to.
+	DestinationTable string
+
+	// Number of segments of the parallel Scan of the source table, each
+	// scanned and written by its own goroutine.
+	//
+	// Defaults to DefaultSegments.
+	Segments int
+
+	// Maximum write capacity units a second the writes to the destination
+	// table consume, estimated from the size of the items. Zero does not
+	// limit the rate of writes.
+	//
+	// Defaults to zero.
+	WriteCapacityUnits float64
+
+	// Maximum number of times unprocessed items of a BatchWriteItem
+	// request are retried, backing off between retries.
+	//
+	// Defaults to DefaultMaxBatchRetries.
+	MaxBatchRetries int
+
+	// The Checkpointer the position of the copy is saved with, and loaded
+	// from to resume a copy. Nil copies the whole table.
+	//
+	// Defaults to nil.
+	Checkpointer Checkpointer
+
+	// Function called with the progress of the copy after each page of
+	// items is written. Calls are not concurrent.
+	//
+	// Defaults to nil.
+	Progress func(Progress)
+
+	// Function used to sleep before retries, and to limit the rate of
+	// writes.
+	//
+	// Defaults to time.Sleep.
+	SleepDelay func(time.Duration)
+}
+
+// Progress is the progress of a copy.
+type Progress struct {
+	// Number of items read from the source table, and written to the
+	// destination table.
+	Scanned int64
+	Written int64
+
+	// Estimated write capacity units consumed by the writes.
+	WriteCapacityUnits float64
+
+	// Number of segments of the Scan completely copied.
+	SegmentsDone int
+}
+
+// New creates a new Copier of the items of the source table to the
+// destination table. Use the `opts` functional options to override the
+// default configuration.
+func New(source dynamodbiface.DynamoDBAPI, sourceTable string, destination dynamodbiface.DynamoDBAPI, destinationTable string, opts ...func(*Copier)) *Copier {
+	c := &Copier{
+		Source:           source,
+		SourceTable:      sourceTable,
+		Destination:      destination,
+		DestinationTable: destinationTable,
+		Segments:         DefaultSegments,
+		MaxBatchRetries:  DefaultMaxBatchRetries,
+		SleepDelay:       time.Sleep,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// copyState is the state of a copy shared by its segments.
+type copyState struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+	progress   Progress
+	err        error
+
+	limiter *rateLimiter
+}
+
+// Copy copies the items, returning the first error of any segment. If the
+// Copier has a Checkpointer with a saved Checkpoint, the copy resumes from
+// it, and it must have been saved by a Copier with the same number of
+// Segments.
+func (c *Copier) Copy() error {
+	if c.Segments < 1 {
+		return fmt.Errorf("invalid number of segments, %d", c.Segments)
+	}
+
+	s := &copyState{}
+	if c.Checkpointer != nil {
+		cp, err := c.Checkpointer.Load()
+		if err != nil {
+			return err
+		}
+		if cp != nil && len(cp.Segments) != c.Segments {
+			return fmt.Errorf("checkpoint has %d segments, copier has %d",
+				len(cp.Segments), c.Segments)
+		}
+		s.checkpoint = cp
+	}
+	if s.checkpoint == nil {
+		s.checkpoint = &Checkpoint{Segments: make([]SegmentCheckpoint, c.Segments)}
+	}
+	if c.WriteCapacityUnits > 0 {
+		s.limiter = &rateLimiter{rate: c.WriteCapacityUnits}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.Segments; i++ {
+		if s.checkpoint.Segments[i].Done {
+			s.progress.SegmentsDone++
+			continue
+		}
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			if err := c.copySegment(s, segment); err != nil {
+				s.mu.Lock()
+				if s.err == nil {
+					s.err = err
+				}
+				s.mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return s.err
+}
+
+// copySegment scans the segment's pages, writing the items of each page
+// before the next is read.
+func (c *Copier) copySegment(s *copyState, segment int) error {
+	input := &dynamodb.ScanInput{TableName: aws.String(c.SourceTable)}
+	if c.Segments > 1 {
+		input.Segment = aws.Int64(int64(segment))
+		input.TotalSegments = aws.Int64(int64(c.Segments))
+	}
+	s.mu.Lock()
+	input.ExclusiveStartKey = s.checkpoint.Segments[segment].StartKey
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		stopped := s.err != nil
+		s.mu.Unlock()
+		if stopped {
+			return nil
+		}
+
+		resp, err := c.Source.Scan(input)
+		if err != nil {
+			return err
+		}
+		units, err := c.write(s, resp.Items)
+		if err != nil {
+			return err
+		}
+
+		done := len(resp.LastEvaluatedKey) == 0
+		if err := c.update(s, segment, resp, units, done); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// update records the page written in the copy's progress and checkpoint.
+func (c *Copier) update(s *copyState, segment int, resp *dynamodb.ScanOutput, units float64, done bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress.Scanned += int64(len(resp.Items))
+	s.progress.Written += int64(len(resp.Items))
+	s.progress.WriteCapacityUnits += units
+	if done {
+		s.progress.SegmentsDone++
+	}
+	s.checkpoint.Segments[segment] = SegmentCheckpoint{StartKey: resp.LastEvaluatedKey, Done: done}
+
+	if c.Checkpointer != nil {
+		if err := c.Checkpointer.Save(s.checkpoint); err != nil {
+			return err
+		}
+	}
+	if c.Progress != nil {
+		c.Progress(s.progress)
+	}
+	return nil
+}
+
+// write writes the items to the destination table in batches, returning
+// the estimated write capacity units consumed.
+func (c *Copier) write(s *copyState, items []map[string]*dynamodb.AttributeValue) (float64, error) {
+	var total float64
+	for start := 0; start < len(items); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(items) {
+			end = len(items)
+		}
+
+		reqs := make([]*dynamodb.WriteRequest, 0, end-start)
+		var units float64
+		for _, item := range items[start:end] {
+			reqs = append(reqs, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+			units += writeUnits(item)
+		}
+		if s.limiter != nil {
+			if wait := s.limiter.reserve(units); wait > 0 {
+				c.SleepDelay(wait)
+			}
+		}
+		if err := c.batchWrite(reqs); err != nil {
+			return total, err
+		}
+		total += units
+	}
+	return total, nil
+}
+
+// batchWrite sends the write requests, retrying unprocessed items.
+func (c *Copier) batchWrite(reqs []*dynamodb.WriteRequest) error {
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{c.DestinationTable: reqs},
+	}
+	for retry := 0; ; retry++ {
+		resp, err := c.Destination.BatchWriteItem(input)
+		if err != nil {
+			return err
+		}
+
+		left := resp.UnprocessedItems[c.DestinationTable]
+		if len(left) == 0 {
+			return nil
+		}
+		if retry >= c.MaxBatchRetries {
+			return &UnprocessedItemsError{
+				TableName: c.DestinationTable,
+				Items:     len(left),
+				Retries:   c.MaxBatchRetries,
+			}
+		}
+		c.SleepDelay(backoff(retry))
+		input.RequestItems = resp.UnprocessedItems
+	}
+}
+
+// writeUnits returns the write capacity units of a put of the item, a
+// unit for each 1KB of the item's size.
+func writeUnits(item map[string]*dynamodb.AttributeValue) float64 {
+	return float64((dynamodbattribute.ItemSize(item) + 1023) / 1024)
+}
+
+// backoff returns the delay before a retry of unprocessed items.
+func backoff(retry int) time.Duration {
+	// Set the upper limit of delay in retrying at ~five minutes
+	if retry > 13 {
+		retry = 13
+	}
+	delay := (1 << uint(retry)) * (rand.Intn(30) + 30)
+	return time.Duration(delay) * time.Millisecond
+}
+
+// A rateLimiter spaces out the consumption of units to a rate a second.
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate float64
+	next time.Time
+}
+
+// reserve consumes the units, returning how long to wait before using
+// them.
+func (l *rateLimiter) reserve(units float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(units / l.rate * float64(time.Second)))
+	return wait
+}
+
+// An UnprocessedItemsError is an error type representing items of a copy
+// which were not processed by DynamoDB, even after retrying them.
+type UnprocessedItemsError struct {
+	TableName string
+	Items     int
+	Retries   int
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnprocessedItemsError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnprocessedItemsError) Code() string {
+	return "UnprocessedItemsError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnprocessedItemsError) Message() string {
+	return strconv.Itoa(e.Items) + " items of table " + e.TableName +
+		" unprocessed after " + strconv.Itoa(e.Retries) + " retries"
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *UnprocessedItemsError) OrigErr() error {
+	return nil
+}
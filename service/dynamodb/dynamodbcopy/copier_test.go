@@ -0,0 +1,266 @@
+package dynamodbcopy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// mockSource pages the items of each scan segment, pageSize at a time.
+// Items are assigned to segments by their ID modulo the total segments.
+type mockSource struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu       sync.Mutex
+	items    int
+	pageSize int
+	failAt   int
+	scans    []*dynamodb.ScanInput
+}
+
+func (m *mockSource) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	in := *input
+	m.scans = append(m.scans, &in)
+	if m.failAt > 0 && len(m.scans) == m.failAt {
+		return nil, awserr.New("InternalServerError", "failed", nil)
+	}
+
+	segment, total := aws.Int64Value(input.Segment), aws.Int64Value(input.TotalSegments)
+	if total == 0 {
+		total = 1
+	}
+	start := 0
+	if input.ExclusiveStartKey != nil {
+		start, _ = strconv.Atoi(*input.ExclusiveStartKey["ID"].N)
+		start++
+	}
+
+	resp := &dynamodb.ScanOutput{}
+	for id := start; id < m.items; id++ {
+		if int64(id)%total != segment {
+			continue
+		}
+		if len(resp.Items) == m.pageSize {
+			resp.LastEvaluatedKey = resp.Items[len(resp.Items)-1]
+			break
+		}
+		resp.Items = append(resp.Items, map[string]*dynamodb.AttributeValue{
+			"ID":   {N: aws.String(strconv.Itoa(id))},
+			"Body": {S: aws.String("body")},
+		})
+	}
+	return resp, nil
+}
+
+// mockDestination stores the IDs of the items written, leaving the last
+// item of the first unprocessed batches unprocessed.
+type mockDestination struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu          sync.Mutex
+	written     map[string]int
+	batches     int
+	unprocessed int
+}
+
+func (m *mockDestination) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches++
+
+	reqs := input.RequestItems["dest"]
+	resp := &dynamodb.BatchWriteItemOutput{}
+	if m.unprocessed > 0 {
+		m.unprocessed--
+		resp.UnprocessedItems = map[string][]*dynamodb.WriteRequest{"dest": reqs[len(reqs)-1:]}
+		reqs = reqs[:len(reqs)-1]
+	}
+	for _, req := range reqs {
+		m.written[*req.PutRequest.Item["ID"].N]++
+	}
+	return resp, nil
+}
+
+func newMockDestination() *mockDestination {
+	return &mockDestination{written: map[string]int{}}
+}
+
+func noSleep(time.Duration) {}
+
+func TestCopy(t *testing.T) {
+	src := &mockSource{items: 100, pageSize: 10}
+	dst := newMockDestination()
+	dst.unprocessed = 2
+
+	var progress []Progress
+	c := New(src, "src", dst, "dest", func(c *Copier) {
+		c.Segments = 3
+		c.SleepDelay = noSleep
+		c.Progress = func(p Progress) { progress = append(progress, p) }
+	})
+	if err := c.Copy(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 100, len(dst.written); e != a {
+		t.Errorf("expect %d items written, got %d", e, a)
+	}
+	for id, n := range dst.written {
+		if n != 1 {
+			t.Errorf("expect item %s written once, got %d", id, n)
+		}
+	}
+	last := progress[len(progress)-1]
+	if e, a := (Progress{Scanned: 100, Written: 100, WriteCapacityUnits: 100, SegmentsDone: 3}), last; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	for _, scan := range src.scans {
+		if e, a := int64(3), aws.Int64Value(scan.TotalSegments); e != a {
+			t.Errorf("expect %d total segments, got %d", e, a)
+		}
+	}
+}
+
+func TestCopyUnprocessed(t *testing.T) {
+	dst := newMockDestination()
+	dst.unprocessed = 100
+
+	c := New(&mockSource{items: 5, pageSize: 10}, "src", dst, "dest", func(c *Copier) {
+		c.Segments = 1
+		c.MaxBatchRetries = 2
+		c.SleepDelay = noSleep
+	})
+	err := c.Copy()
+	if err == nil {
+		t.Fatalf("expect error")
+	}
+	if e, a := "UnprocessedItemsError", err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 3, dst.batches; e != a {
+		t.Errorf("expect %d batches, got %d", e, a)
+	}
+}
+
+func TestCopyRateLimit(t *testing.T) {
+	var waits []time.Duration
+	c := New(&mockSource{items: 75, pageSize: 100}, "src", newMockDestination(), "dest", func(c *Copier) {
+		c.Segments = 1
+		c.WriteCapacityUnits = 10
+		c.SleepDelay = func(d time.Duration) { waits = append(waits, d) }
+	})
+	if err := c.Copy(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Three batches of 25 one unit items, the first written immediately,
+	// and the others 2.5 seconds apart. The waits are from when the first
+	// batch was written, as the sleeps return immediately.
+	if e, a := 2, len(waits); e != a {
+		t.Fatalf("expect %d waits, got %v", e, waits)
+	}
+	for i, wait := range waits {
+		expect := time.Duration(i+1) * 2500 * time.Millisecond
+		if wait > expect || wait < expect-time.Second {
+			t.Errorf("%d, expect wait of about %v, got %v", i, expect, wait)
+		}
+	}
+}
+
+func TestCopyResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dynamodbcopy")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	// The third scan fails, after the first segment's first page, and the
+	// second segment's first page, are written.
+	src := &mockSource{items: 40, pageSize: 5, failAt: 3}
+	dst := newMockDestination()
+	opts := func(c *Copier) {
+		c.Segments = 2
+		c.SleepDelay = noSleep
+		c.Checkpointer = FileCheckpointer(path)
+	}
+	if err := New(src, "src", dst, "dest", opts).Copy(); err == nil {
+		t.Fatalf("expect error")
+	}
+
+	cp, err := FileCheckpointer(path).Load()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if cp == nil || len(cp.Segments) != 2 {
+		t.Fatalf("expect checkpoint of 2 segments, got %v", cp)
+	}
+
+	src.failAt = 0
+	if err := New(src, "src", dst, "dest", opts).Copy(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 40, len(dst.written); e != a {
+		t.Errorf("expect %d items written, got %d", e, a)
+	}
+
+	// Only the page being written when the copy failed may be written
+	// twice.
+	var twice []string
+	for id, n := range dst.written {
+		if n > 1 {
+			twice = append(twice, id)
+		}
+	}
+	sort.Strings(twice)
+	if len(twice) > 5 {
+		t.Errorf("expect at most a page written twice, got %s", strings.Join(twice, ","))
+	}
+
+	cp, err = FileCheckpointer(path).Load()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for i, s := range cp.Segments {
+		if !s.Done || s.StartKey != nil {
+			t.Errorf("%d, expect segment done, got %v", i, s)
+		}
+	}
+
+	// A finished copy is not copied again.
+	scans := len(src.scans)
+	if err := New(src, "src", dst, "dest", opts).Copy(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := scans, len(src.scans); e != a {
+		t.Errorf("expect no scans, got %d", a-e)
+	}
+
+	opts2 := func(c *Copier) {
+		opts(c)
+		c.Segments = 3
+	}
+	if err := New(src, "src", dst, "dest", opts2).Copy(); err == nil {
+		t.Errorf("expect error for checkpoint of other segments")
+	}
+}
+
+func TestFileCheckpointerMissing(t *testing.T) {
+	cp, err := FileCheckpointer(filepath.Join(os.TempDir(), "dynamodbcopy-missing.json")).Load()
+	if err != nil || cp != nil {
+		t.Errorf("expect no checkpoint, got %v, %v", cp, err)
+	}
+}
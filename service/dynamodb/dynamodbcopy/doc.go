@@ -0,0 +1,28 @@
+// Package dynamodbcopy provides copying the items of a DynamoDB table to
+// another table, which may be in another region or account.
+//
+// The source table is read with a parallel Scan, and the items are written
+// to the destination table with BatchWriteItem requests, retrying
+// unprocessed items. The destination table must already exist, with the
+// same key schema as the source table.
+//
+//     source := dynamodb.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+//     destination := dynamodb.New(sess, aws.NewConfig().WithRegion("eu-west-1"))
+//
+//     c := dynamodbcopy.New(source, "orders", destination, "orders", func(c *dynamodbcopy.Copier) {
+//         c.Segments = 8
+//         c.WriteCapacityUnits = 500
+//         c.Checkpointer = dynamodbcopy.FileCheckpointer("orders-copy.json")
+//         c.Progress = func(p dynamodbcopy.Progress) {
+//             log.Printf("copied %d of %d items scanned", p.Written, p.Scanned)
+//         }
+//     })
+//     err := c.Copy()
+//
+// Writes are limited to WriteCapacityUnits a second, estimated from the
+// size of the items, so the copy does not consume the capacity the
+// destination table's other clients need. With a Checkpointer, the
+// position of each scan segment is saved once its items are written, so a
+// failed or interrupted copy can be run again and resumes where it
+// stopped. Items written after the last checkpoint are written again.
+package dynamodbcopy
@@ -0,0 +1,135 @@
+package dynamodbdax
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// A Transport sends the item operations DAX supports. The DynamoDB client
+// satisfies the interface, so a Transport can fall back to DynamoDB.
+type Transport interface {
+	GetItem(*dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error)
+	PutItem(*dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error)
+	UpdateItem(*dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(*dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error)
+	BatchGetItem(*dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error)
+	BatchWriteItem(*dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+	Query(*dynamodb.QueryInput) (*dynamodb.QueryOutput, error)
+	Scan(*dynamodb.ScanInput) (*dynamodb.ScanOutput, error)
+}
+
+var _ Transport = (*dynamodb.DynamoDB)(nil)
+
+// A Client is a dynamodbiface.DynamoDBAPI which sends item operations to
+// its Transport, and every other operation to DynamoDB.
+type Client struct {
+	// The DynamoDB client operations the Transport does not support are
+	// sent with.
+	dynamodbiface.DynamoDBAPI
+
+	// The Transport item operations are sent with.
+	Transport Transport
+}
+
+var _ dynamodbiface.DynamoDBAPI = (*Client)(nil)
+
+// New creates a new Client sending item operations with the transport,
+// and other operations with the DynamoDB client svc.
+func New(svc dynamodbiface.DynamoDBAPI, transport Transport) *Client {
+	return &Client{DynamoDBAPI: svc, Transport: transport}
+}
+
+// GetItem sends the GetItem operation with the Transport.
+func (c *Client) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return c.Transport.GetItem(input)
+}
+
+// PutItem sends the PutItem operation with the Transport.
+func (c *Client) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	return c.Transport.PutItem(input)
+}
+
+// UpdateItem sends the UpdateItem operation with the Transport.
+func (c *Client) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	return c.Transport.UpdateItem(input)
+}
+
+// DeleteItem sends the DeleteItem operation with the Transport.
+func (c *Client) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	return c.Transport.DeleteItem(input)
+}
+
+// BatchGetItem sends the BatchGetItem operation with the Transport.
+func (c *Client) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	return c.Transport.BatchGetItem(input)
+}
+
+// BatchWriteItem sends the BatchWriteItem operation with the Transport.
+func (c *Client) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	return c.Transport.BatchWriteItem(input)
+}
+
+// Query sends the Query operation with the Transport.
+func (c *Client) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return c.Transport.Query(input)
+}
+
+// Scan sends the Scan operation with the Transport.
+func (c *Client) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	return c.Transport.Scan(input)
+}
+
+// BatchGetItemPages sends BatchGetItem operations with the Transport,
+// requesting the unprocessed keys of each page, calling fn with each page
+// until there are no unprocessed keys or fn returns false. The input is
+// not modified.
+func (c *Client) BatchGetItemPages(input *dynamodb.BatchGetItemInput, fn func(*dynamodb.BatchGetItemOutput, bool) bool) error {
+	in := *input
+	for {
+		resp, err := c.Transport.BatchGetItem(&in)
+		if err != nil {
+			return err
+		}
+		lastPage := len(resp.UnprocessedKeys) == 0
+		if !fn(resp, lastPage) || lastPage {
+			return nil
+		}
+		in.RequestItems = resp.UnprocessedKeys
+	}
+}
+
+// QueryPages sends Query operations with the Transport, calling fn with
+// each page until the last page or fn returns false. The input is not
+// modified.
+func (c *Client) QueryPages(input *dynamodb.QueryInput, fn func(*dynamodb.QueryOutput, bool) bool) error {
+	in := *input
+	for {
+		resp, err := c.Transport.Query(&in)
+		if err != nil {
+			return err
+		}
+		lastPage := len(resp.LastEvaluatedKey) == 0
+		if !fn(resp, lastPage) || lastPage {
+			return nil
+		}
+		in.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// ScanPages sends Scan operations with the Transport, calling fn with each
+// page until the last page or fn returns false. The input is not
+// modified.
+func (c *Client) ScanPages(input *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool) error {
+	in := *input
+	for {
+		resp, err := c.Transport.Scan(&in)
+		if err != nil {
+			return err
+		}
+		lastPage := len(resp.LastEvaluatedKey) == 0
+		if !fn(resp, lastPage) || lastPage {
+			return nil
+		}
+		in.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,195 @@
+package dynamodbdax
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// mockDynamoDB records the operations sent to DynamoDB.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	ops []string
+}
+
+func (m *mockDynamoDB) GetItem(*dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	m.ops = append(m.ops, "GetItem")
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) DescribeTable(*dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	m.ops = append(m.ops, "DescribeTable")
+	return &dynamodb.DescribeTableOutput{}, nil
+}
+
+// mockTransport records the operations sent to it, and pages Query and
+// Scan results one item at a time from its items.
+type mockTransport struct {
+	Transport
+	ops   []string
+	items []map[string]*dynamodb.AttributeValue
+}
+
+func (m *mockTransport) GetItem(*dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	m.ops = append(m.ops, "GetItem")
+	return &dynamodb.GetItemOutput{Item: m.items[0]}, nil
+}
+
+func (m *mockTransport) PutItem(*dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	m.ops = append(m.ops, "PutItem")
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockTransport) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	m.ops = append(m.ops, "Query")
+	items, last := m.page(input.ExclusiveStartKey)
+	return &dynamodb.QueryOutput{Items: items, LastEvaluatedKey: last}, nil
+}
+
+func (m *mockTransport) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	m.ops = append(m.ops, "Scan")
+	items, last := m.page(input.ExclusiveStartKey)
+	return &dynamodb.ScanOutput{Items: items, LastEvaluatedKey: last}, nil
+}
+
+func (m *mockTransport) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	m.ops = append(m.ops, "BatchGetItem")
+	keys := input.RequestItems["t"].Keys
+	resp := &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{"t": keys[:1]},
+	}
+	if len(keys) > 1 {
+		resp.UnprocessedKeys = map[string]*dynamodb.KeysAndAttributes{"t": {Keys: keys[1:]}}
+	}
+	return resp, nil
+}
+
+func (m *mockTransport) page(start map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue) {
+	i := 0
+	if start != nil {
+		i, _ = strconv.Atoi(*start["ID"].N)
+		i++
+	}
+	var last map[string]*dynamodb.AttributeValue
+	if i < len(m.items)-1 {
+		last = m.items[i]
+	}
+	return m.items[i : i+1], last
+}
+
+func testItems(n int) []map[string]*dynamodb.AttributeValue {
+	items := make([]map[string]*dynamodb.AttributeValue, n)
+	for i := range items {
+		items[i] = map[string]*dynamodb.AttributeValue{"ID": {N: aws.String(strconv.Itoa(i))}}
+	}
+	return items
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestClientRoutesOperations(t *testing.T) {
+	svc := &mockDynamoDB{}
+	transport := &mockTransport{items: testItems(1)}
+	c := New(svc, transport)
+
+	resp, err := c.GetItem(&dynamodb.GetItemInput{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if resp.Item == nil {
+		t.Errorf("expect item from the transport")
+	}
+	if _, err := c.PutItem(&dynamodb.PutItemInput{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, err := c.DescribeTable(&dynamodb.DescribeTableInput{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"GetItem", "PutItem"}, transport.ops; !stringsEqual(e, a) {
+		t.Errorf("expect transport ops %v, got %v", e, a)
+	}
+	if e, a := []string{"DescribeTable"}, svc.ops; !stringsEqual(e, a) {
+		t.Errorf("expect DynamoDB ops %v, got %v", e, a)
+	}
+}
+
+func TestClientQueryPages(t *testing.T) {
+	transport := &mockTransport{items: testItems(3)}
+	c := New(&mockDynamoDB{}, transport)
+
+	input := &dynamodb.QueryInput{TableName: aws.String("t")}
+	var ids []string
+	var lastPages []bool
+	err := c.QueryPages(input, func(p *dynamodb.QueryOutput, lastPage bool) bool {
+		ids = append(ids, *p.Items[0]["ID"].N)
+		lastPages = append(lastPages, lastPage)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"0", "1", "2"}, ids; !stringsEqual(e, a) {
+		t.Errorf("expect items %v, got %v", e, a)
+	}
+	if len(lastPages) != 3 || lastPages[0] || lastPages[1] || !lastPages[2] {
+		t.Errorf("expect only the last page to be last, got %v", lastPages)
+	}
+	if input.ExclusiveStartKey != nil {
+		t.Errorf("expect input not modified, got %v", input.ExclusiveStartKey)
+	}
+}
+
+func TestClientScanPagesStops(t *testing.T) {
+	transport := &mockTransport{items: testItems(3)}
+	c := New(&mockDynamoDB{}, transport)
+
+	pages := 0
+	err := c.ScanPages(&dynamodb.ScanInput{}, func(p *dynamodb.ScanOutput, lastPage bool) bool {
+		pages++
+		return false
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, pages; e != a {
+		t.Errorf("expect %v pages, got %v", e, a)
+	}
+	if e, a := []string{"Scan"}, transport.ops; !stringsEqual(e, a) {
+		t.Errorf("expect transport ops %v, got %v", e, a)
+	}
+}
+
+func TestClientBatchGetItemPages(t *testing.T) {
+	transport := &mockTransport{}
+	c := New(&mockDynamoDB{}, transport)
+
+	var ids []string
+	err := c.BatchGetItemPages(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{"t": {Keys: testItems(3)}},
+	}, func(p *dynamodb.BatchGetItemOutput, lastPage bool) bool {
+		for _, item := range p.Responses["t"] {
+			ids = append(ids, *item["ID"].N)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"0", "1", "2"}, ids; !stringsEqual(e, a) {
+		t.Errorf("expect items %v, got %v", e, a)
+	}
+}
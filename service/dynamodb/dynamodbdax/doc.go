@@ -0,0 +1,28 @@
+// Package dynamodbdax provides a DynamoDB client which sends the item
+// operations DynamoDB Accelerator (DAX) supports to a pluggable Transport,
+// such as a client of a DAX cluster, so code written against the
+// dynamodbiface.DynamoDBAPI interface can read through the cache without
+// changes.
+//
+// The SDK does not implement the DAX wire protocol. A Transport is any
+// type with the item operation methods of the DynamoDB client, so a DAX
+// client library, or an adapter around one, can be plugged in. Table
+// operations, and the other operations DAX does not support, are sent to
+// DynamoDB.
+//
+//     svc := dynamodbdax.New(dynamodb.New(sess), daxClient)
+//
+//     // Reads and writes of items go through DAX.
+//     resp, err := svc.GetItem(&dynamodb.GetItemInput{
+//         TableName: aws.String("orders"),
+//         Key:       key,
+//     })
+//
+//     // DescribeTable is sent to DynamoDB.
+//     desc, err := svc.DescribeTable(&dynamodb.DescribeTableInput{
+//         TableName: aws.String("orders"),
+//     })
+//
+// The Request methods, such as GetItemRequest, build requests of the
+// DynamoDB client, so are always sent to DynamoDB.
+package dynamodbdax
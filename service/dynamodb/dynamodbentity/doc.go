@@ -0,0 +1,34 @@
+// Package dynamodbentity provides storing items of several Go struct types
+// in a single DynamoDB table, following the single-table design pattern.
+//
+// Each struct type is registered as an Entity with templates of its
+// partition and sort key values, such as "USER#{id}" and
+// "ORDER#{id}#{created}". The {name} placeholders of a template are
+// replaced with the values of the item's attributes, as marshaled by the
+// dynamodbattribute package. Marshaled items also get a type attribute
+// naming their entity, which routes items read back to their Go type.
+//
+//     entities := dynamodbentity.NewRegistry()
+//     err := entities.Register(User{}, dynamodbentity.Entity{
+//         Type:         "User",
+//         PartitionKey: "USER#{id}",
+//         SortKey:      "PROFILE",
+//     })
+//     err = entities.Register(Order{}, dynamodbentity.Entity{
+//         Type:         "Order",
+//         PartitionKey: "USER#{userId}",
+//         SortKey:      "ORDER#{id}",
+//     })
+//
+//     item, err := entities.MarshalItem(&order)
+//     // item has PK "USER#u1", SK "ORDER#o1", and Type "Order"
+//
+//     // read the items of the user's partition with Query
+//     values, err := entities.UnmarshalItems(resp.Items)
+//     for _, v := range values {
+//         switch v := v.(type) {
+//         case *User:
+//         case *Order:
+//         }
+//     }
+package dynamodbentity
@@ -0,0 +1,99 @@
+package dynamodbentity
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// An InvalidEntityError is an error type representing an Entity which
+// cannot be registered, or a value of a type which is not registered.
+type InvalidEntityError struct {
+	Type reflect.Type
+	msg  string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidEntityError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidEntityError) Code() string {
+	return "InvalidEntityError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidEntityError) Message() string {
+	return fmt.Sprintf("%s, %v", e.msg, e.Type)
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *InvalidEntityError) OrigErr() error {
+	return nil
+}
+
+// A MissingAttributeError is an error type representing an item without a
+// string, number, or binary value for an attribute of a key template.
+type MissingAttributeError struct {
+	Type      reflect.Type
+	Attribute string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *MissingAttributeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *MissingAttributeError) Code() string {
+	return "MissingAttributeError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *MissingAttributeError) Message() string {
+	return "missing key template attribute " + e.Attribute + " of " + e.Type.String()
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *MissingAttributeError) OrigErr() error {
+	return nil
+}
+
+// An UnknownEntityError is an error type representing an item whose type
+// attribute does not name a registered Entity.
+type UnknownEntityError struct {
+	// The item's entity type, empty if the item has no type attribute.
+	Type string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnknownEntityError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnknownEntityError) Code() string {
+	return "UnknownEntityError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnknownEntityError) Message() string {
+	if len(e.Type) == 0 {
+		return "item has no entity type"
+	}
+	return "unknown entity type " + e.Type
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *UnknownEntityError) OrigErr() error {
+	return nil
+}
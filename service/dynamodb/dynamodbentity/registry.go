@@ -0,0 +1,350 @@
+package dynamodbentity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Default Registry attribute names.
+const (
+	DefaultPartitionKeyAttribute = "PK"
+	DefaultSortKeyAttribute      = "SK"
+	DefaultTypeAttribute         = "Type"
+)
+
+// An Entity describes how a Go struct type is stored in a single table
+// shared with other entities.
+type Entity struct {
+	// Name of the entity, stored in the type attribute of its items.
+	// Required.
+	Type string
+
+	// Template of the partition key value. Required.
+	PartitionKey string
+
+	// Template of the sort key value, if the table has a sort key.
+	SortKey string
+
+	// Templates of other attributes set on the entity's items, by
+	// attribute name, such as the keys of secondary indexes.
+	Attributes map[string]string
+}
+
+// An entity is a registered Entity with its parsed templates.
+type entity struct {
+	Entity
+	typ reflect.Type
+
+	partitionKey, sortKey template
+	attributes            map[string]template
+}
+
+// A Registry maps Go struct types to the Entity describing how they are
+// stored in a single table. It is safe to use a Registry concurrently
+// across goroutines once its attribute names are configured.
+type Registry struct {
+	// Names of the table's partition and sort key attributes, and of the
+	// attribute naming the entity of an item.
+	//
+	// Default to DefaultPartitionKeyAttribute, DefaultSortKeyAttribute,
+	// and DefaultTypeAttribute.
+	PartitionKeyAttribute string
+	SortKeyAttribute      string
+	TypeAttribute         string
+
+	mu     sync.RWMutex
+	byType map[reflect.Type]*entity
+	byName map[string]*entity
+}
+
+// NewRegistry returns an empty Registry. Use the `opts` functional
+// options to override the default configuration.
+func NewRegistry(opts ...func(*Registry)) *Registry {
+	r := &Registry{
+		PartitionKeyAttribute: DefaultPartitionKeyAttribute,
+		SortKeyAttribute:      DefaultSortKeyAttribute,
+		TypeAttribute:         DefaultTypeAttribute,
+		byType:                map[reflect.Type]*entity{},
+		byName:                map[string]*entity{},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// Register registers the Entity for the struct type of v. v can be a value
+// or pointer of the struct type, and is only used for its type.
+// Registering a type, or an Entity Type name, which is already registered
+// replaces its Entity.
+func (r *Registry) Register(v interface{}, e Entity) error {
+	t, err := structType(v)
+	if err != nil {
+		return err
+	}
+	if len(e.Type) == 0 || len(e.PartitionKey) == 0 {
+		return &InvalidEntityError{Type: t, msg: "entity type and partition key must not be empty"}
+	}
+
+	ent := &entity{Entity: e, typ: t, attributes: map[string]template{}}
+	if ent.partitionKey, err = parseTemplate(e.PartitionKey); err != nil {
+		return &InvalidEntityError{Type: t, msg: err.Error()}
+	}
+	if ent.sortKey, err = parseTemplate(e.SortKey); err != nil {
+		return &InvalidEntityError{Type: t, msg: err.Error()}
+	}
+	for name, tmpl := range e.Attributes {
+		if ent.attributes[name], err = parseTemplate(tmpl); err != nil {
+			return &InvalidEntityError{Type: t, msg: err.Error()}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.byType[t]; ok {
+		delete(r.byName, old.Type)
+	}
+	if old, ok := r.byName[e.Type]; ok {
+		delete(r.byType, old.typ)
+	}
+	r.byType[t] = ent
+	r.byName[e.Type] = ent
+
+	return nil
+}
+
+// Entity returns the Entity registered for the struct type of v.
+func (r *Registry) Entity(v interface{}) (Entity, error) {
+	e, err := r.entity(v)
+	if err != nil {
+		return Entity{}, err
+	}
+	return e.Entity, nil
+}
+
+// MarshalItem returns the item marshaled into an AttributeValue map, with
+// the key attributes and other attributes generated from its Entity's
+// templates, and the type attribute set to the Entity's Type.
+func (r *Registry) MarshalItem(item interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	e, err := r.entity(item)
+	if err != nil {
+		return nil, err
+	}
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(e.attributes))
+	for name := range e.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	generated := map[string]*dynamodb.AttributeValue{}
+	if err := r.addKey(generated, e, av); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		s, err := e.attributes[name].execute(e, av)
+		if err != nil {
+			return nil, err
+		}
+		generated[name] = &dynamodb.AttributeValue{S: aws.String(s)}
+	}
+	generated[r.TypeAttribute] = &dynamodb.AttributeValue{S: aws.String(e.Type)}
+
+	for name, v := range generated {
+		av[name] = v
+	}
+	return av, nil
+}
+
+// Key returns the primary key attributes of item, generated from its
+// Entity's key templates. Only the fields the templates refer to need to
+// be set, such as for a GetItem or DeleteItem request.
+func (r *Registry) Key(item interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	e, err := r.entity(item)
+	if err != nil {
+		return nil, err
+	}
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+
+	key := map[string]*dynamodb.AttributeValue{}
+	if err := r.addKey(key, e, av); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SortKeyPrefix returns the literal prefix of the sort key template of the
+// Entity named entityType, up to its first placeholder, such as "ORDER#"
+// for "ORDER#{id}". Use it with a begins_with key condition to query the
+// items of one entity in a partition.
+func (r *Registry) SortKeyPrefix(entityType string) (string, error) {
+	r.mu.RLock()
+	e, ok := r.byName[entityType]
+	r.mu.RUnlock()
+	if !ok {
+		return "", &UnknownEntityError{Type: entityType}
+	}
+	return e.sortKey.prefix(), nil
+}
+
+// UnmarshalItem unmarshals the item into a new value of the Go type of the
+// Entity named by its type attribute, returning a pointer to the value.
+func (r *Registry) UnmarshalItem(item map[string]*dynamodb.AttributeValue) (interface{}, error) {
+	av, ok := item[r.TypeAttribute]
+	if !ok || av.S == nil {
+		return nil, &UnknownEntityError{}
+	}
+
+	r.mu.RLock()
+	e, ok := r.byName[*av.S]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &UnknownEntityError{Type: *av.S}
+	}
+
+	v := reflect.New(e.typ)
+	if err := dynamodbattribute.UnmarshalMap(item, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// UnmarshalItems unmarshals the items, such as the items of a Query of a
+// partition, with UnmarshalItem.
+func (r *Registry) UnmarshalItems(items []map[string]*dynamodb.AttributeValue) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		v, err := r.UnmarshalItem(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// addKey adds the key attributes generated from the entity's key templates
+// and the item's attributes to key.
+func (r *Registry) addKey(key map[string]*dynamodb.AttributeValue, e *entity, item map[string]*dynamodb.AttributeValue) error {
+	pk, err := e.partitionKey.execute(e, item)
+	if err != nil {
+		return err
+	}
+	key[r.PartitionKeyAttribute] = &dynamodb.AttributeValue{S: aws.String(pk)}
+
+	if len(e.SortKey) != 0 {
+		sk, err := e.sortKey.execute(e, item)
+		if err != nil {
+			return err
+		}
+		key[r.SortKeyAttribute] = &dynamodb.AttributeValue{S: aws.String(sk)}
+	}
+	return nil
+}
+
+func (r *Registry) entity(v interface{}) (*entity, error) {
+	t, err := structType(v)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byType[t]
+	if !ok {
+		return nil, &InvalidEntityError{Type: t, msg: "type is not registered"}
+	}
+	return e, nil
+}
+
+func structType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, &InvalidEntityError{Type: t, msg: "entity must be a struct type"}
+	}
+
+	return t, nil
+}
+
+// A template is a parsed key template. The parts at odd indexes are the
+// names of the attributes of the placeholders, the others are literals.
+type template []string
+
+// parseTemplate parses the key template.
+func parseTemplate(s string) (template, error) {
+	var t template
+	for {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			if strings.IndexByte(s, '}') >= 0 {
+				return nil, fmt.Errorf("unmatched } in key template")
+			}
+			return append(t, s), nil
+		}
+		end := strings.IndexByte(s[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unmatched { in key template")
+		}
+		name := s[open+1 : open+end]
+		if len(name) == 0 || strings.IndexByte(name, '{') >= 0 || strings.IndexByte(s[:open], '}') >= 0 {
+			return nil, fmt.Errorf("invalid placeholder in key template")
+		}
+		t = append(t, s[:open], name)
+		s = s[open+end+1:]
+	}
+}
+
+// prefix returns the literal prefix of the template.
+func (t template) prefix() string {
+	if len(t) == 0 {
+		return ""
+	}
+	return t[0]
+}
+
+// execute returns the template with its placeholders replaced by the
+// values of the item's string, number, and binary attributes. Binary
+// values are base64 encoded.
+func (t template) execute(e *entity, item map[string]*dynamodb.AttributeValue) (string, error) {
+	var b []byte
+	for i, part := range t {
+		if i%2 == 0 {
+			b = append(b, part...)
+			continue
+		}
+
+		av := item[part]
+		switch {
+		case av == nil:
+			return "", &MissingAttributeError{Type: e.typ, Attribute: part}
+		case av.S != nil:
+			b = append(b, *av.S...)
+		case av.N != nil:
+			b = append(b, *av.N...)
+		case av.B != nil:
+			b = append(b, base64.StdEncoding.EncodeToString(av.B)...)
+		default:
+			return "", &MissingAttributeError{Type: e.typ, Attribute: part}
+		}
+	}
+	return string(b), nil
+}
@@ -0,0 +1,203 @@
+package dynamodbentity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testUser struct {
+	ID   string `dynamodbav:"id"`
+	Name string `dynamodbav:"name"`
+}
+
+type testOrder struct {
+	UserID  string `dynamodbav:"userId"`
+	ID      string `dynamodbav:"id"`
+	Created int64  `dynamodbav:"created"`
+	Status  string `dynamodbav:"status"`
+}
+
+func testRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	if err := r.Register(testUser{}, Entity{
+		Type:         "User",
+		PartitionKey: "USER#{id}",
+		SortKey:      "PROFILE",
+	}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := r.Register(&testOrder{}, Entity{
+		Type:         "Order",
+		PartitionKey: "USER#{userId}",
+		SortKey:      "ORDER#{id}#{created}",
+		Attributes:   map[string]string{"GSI1PK": "STATUS#{status}"},
+	}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return r
+}
+
+func TestRegistryMarshalItem(t *testing.T) {
+	r := testRegistry(t)
+
+	item, err := r.MarshalItem(testOrder{UserID: "u1", ID: "o1", Created: 1700000000, Status: "NEW"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"userId":  {S: aws.String("u1")},
+		"id":      {S: aws.String("o1")},
+		"created": {N: aws.String("1700000000")},
+		"status":  {S: aws.String("NEW")},
+		"PK":      {S: aws.String("USER#u1")},
+		"SK":      {S: aws.String("ORDER#o1#1700000000")},
+		"GSI1PK":  {S: aws.String("STATUS#NEW")},
+		"Type":    {S: aws.String("Order")},
+	}
+	if e, a := expect, item; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryKey(t *testing.T) {
+	r := testRegistry(t)
+
+	key, err := r.Key(&testUser{ID: "u1"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("USER#u1")},
+		"SK": {S: aws.String("PROFILE")},
+	}
+	if e, a := expect, key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryKeyMissingAttribute(t *testing.T) {
+	r := testRegistry(t)
+
+	_, err := r.Key(testUser{Name: "no id"})
+	if _, ok := err.(*MissingAttributeError); !ok {
+		t.Fatalf("expect MissingAttributeError, got %v", err)
+	}
+	if e, a := "MissingAttributeError: missing key template attribute id of dynamodbentity.testUser", err.Error(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestRegistryUnmarshalItems(t *testing.T) {
+	r := testRegistry(t)
+
+	var items []map[string]*dynamodb.AttributeValue
+	for _, v := range []interface{}{
+		testUser{ID: "u1", Name: "Alice"},
+		testOrder{UserID: "u1", ID: "o1", Created: 1, Status: "NEW"},
+	} {
+		item, err := r.MarshalItem(v)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		items = append(items, item)
+	}
+
+	values, err := r.UnmarshalItems(items)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []interface{}{
+		&testUser{ID: "u1", Name: "Alice"},
+		&testOrder{UserID: "u1", ID: "o1", Created: 1, Status: "NEW"},
+	}
+	if e, a := expect, values; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryUnmarshalItemUnknown(t *testing.T) {
+	r := testRegistry(t)
+
+	cases := []struct {
+		item   map[string]*dynamodb.AttributeValue
+		expect string
+	}{
+		{
+			item:   map[string]*dynamodb.AttributeValue{"PK": {S: aws.String("X")}},
+			expect: "UnknownEntityError: item has no entity type",
+		},
+		{
+			item:   map[string]*dynamodb.AttributeValue{"Type": {S: aws.String("Invoice")}},
+			expect: "UnknownEntityError: unknown entity type Invoice",
+		},
+	}
+
+	for i, c := range cases {
+		_, err := r.UnmarshalItem(c.item)
+		if _, ok := err.(*UnknownEntityError); !ok {
+			t.Fatalf("%d, expect UnknownEntityError, got %v", i, err)
+		}
+		if e, a := c.expect, err.Error(); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestRegistrySortKeyPrefix(t *testing.T) {
+	r := testRegistry(t)
+
+	cases := map[string]string{
+		"User":  "PROFILE",
+		"Order": "ORDER#",
+	}
+	for typ, expect := range cases {
+		prefix, err := r.SortKeyPrefix(typ)
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", typ, err)
+		}
+		if e, a := expect, prefix; e != a {
+			t.Errorf("%s, expect %q, got %q", typ, e, a)
+		}
+	}
+
+	if _, err := r.SortKeyPrefix("Invoice"); err == nil {
+		t.Errorf("expect error for unknown entity")
+	}
+}
+
+func TestRegistryRegisterInvalid(t *testing.T) {
+	cases := []struct {
+		v interface{}
+		e Entity
+	}{
+		{v: "not a struct", e: Entity{Type: "User", PartitionKey: "USER#{id}"}},
+		{v: testUser{}, e: Entity{PartitionKey: "USER#{id}"}},
+		{v: testUser{}, e: Entity{Type: "User"}},
+		{v: testUser{}, e: Entity{Type: "User", PartitionKey: "USER#{id"}},
+		{v: testUser{}, e: Entity{Type: "User", PartitionKey: "USER#id}"}},
+		{v: testUser{}, e: Entity{Type: "User", PartitionKey: "USER#{}"}},
+		{v: testUser{}, e: Entity{Type: "User", PartitionKey: "USER", SortKey: "{a{b}"}},
+	}
+
+	r := NewRegistry()
+	for i, c := range cases {
+		err := r.Register(c.v, c.e)
+		if _, ok := err.(*InvalidEntityError); !ok {
+			t.Errorf("%d, expect InvalidEntityError, got %v", i, err)
+		}
+	}
+}
+
+func TestRegistryUnregisteredType(t *testing.T) {
+	r := NewRegistry()
+
+	if _, err := r.MarshalItem(testUser{ID: "u1"}); err == nil {
+		t.Errorf("expect error for unregistered type")
+	}
+}
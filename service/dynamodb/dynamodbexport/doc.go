@@ -0,0 +1,20 @@
+// Package dynamodbexport provides reading the items of a DynamoDB table
+// export to Amazon S3, in either the DYNAMODB_JSON or ION output format.
+//
+// The export's manifest files are read to find its data files, which are
+// streamed from S3 and decompressed one at a time. Each item is unmarshaled
+// with a dynamodbattribute Decoder.
+//
+//     r := dynamodbexport.NewReader(s3.New(sess), "bucket",
+//         "exports/AWSDynamoDB/01234567890123-abcdefgh/manifest-summary.json")
+//     defer r.Close()
+//     for {
+//         var v Record
+//         if err := r.Decode(&v); err == io.EOF {
+//             break
+//         } else if err != nil {
+//             return err
+//         }
+//         // process v
+//     }
+package dynamodbexport
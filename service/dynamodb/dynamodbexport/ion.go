@@ -0,0 +1,432 @@
+package dynamodbexport
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Ion annotations the export uses for the DynamoDB set types, which are
+// otherwise written as Ion lists.
+const (
+	ionStringSet = "$dynamodb_SS"
+	ionNumberSet = "$dynamodb_NS"
+	ionBinarySet = "$dynamodb_BS"
+)
+
+// ionDecoder reads the items of an export data file in the ION format.
+// Only the subset of the Ion text format used by exports is supported,
+// one item per line, e.g.
+//
+//     $ion_1_0 {Item:{id:"abc",count:2.,tags:$dynamodb_SS::["a","b"]}}
+type ionDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+func newIonDecoder(r io.Reader) *ionDecoder {
+	return &ionDecoder{r: bufio.NewReader(r)}
+}
+
+func (d *ionDecoder) DecodeItem() (map[string]*dynamodb.AttributeValue, error) {
+	for {
+		b, err := d.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(b) == 0) {
+			return nil, err
+		}
+		d.line++
+
+		p := &ionParser{b: b}
+		for {
+			p.skipSpace()
+			if p.done() {
+				break
+			}
+
+			v, err := p.value()
+			if err != nil {
+				return nil, fmt.Errorf("ion line %d, %v", d.line, err)
+			}
+			if v.symbol == "$ion_1_0" {
+				continue
+			}
+
+			item := v.av.M["Item"]
+			if item == nil || item.M == nil {
+				return nil, fmt.Errorf("ion line %d, missing Item", d.line)
+			}
+			return item.M, nil
+		}
+	}
+}
+
+type ionValue struct {
+	av *dynamodb.AttributeValue
+
+	// Set if the value is an unannotated symbol, such as the version
+	// marker.
+	symbol string
+}
+
+type ionParser struct {
+	b []byte
+	i int
+}
+
+func (p *ionParser) done() bool {
+	return p.i >= len(p.b)
+}
+
+func (p *ionParser) skipSpace() {
+	for !p.done() {
+		switch p.b[p.i] {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+func (p *ionParser) peek(s string) bool {
+	return strings.HasPrefix(string(p.b[p.i:]), s)
+}
+
+func (p *ionParser) expect(s string) error {
+	p.skipSpace()
+	if !p.peek(s) {
+		return p.errorf("expected %q", s)
+	}
+	p.i += len(s)
+	return nil
+}
+
+func (p *ionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d, "+format, append([]interface{}{p.i}, args...)...)
+}
+
+func (p *ionParser) value() (ionValue, error) {
+	var annotation string
+	for {
+		p.skipSpace()
+		if p.done() {
+			return ionValue{}, p.errorf("unexpected end of value")
+		}
+		c := p.b[p.i]
+		if !isIonSymbolStart(c) && c != '\'' {
+			break
+		}
+
+		start := p.i
+		sym, err := p.symbol()
+		if err != nil {
+			return ionValue{}, err
+		}
+		p.skipSpace()
+		if !p.peek("::") {
+			if len(annotation) != 0 {
+				return ionValue{}, p.errorf("annotation on symbol value")
+			}
+			if c == '\'' {
+				return ionValue{symbol: sym}, nil
+			}
+			p.i = start
+			break
+		}
+		p.i += 2
+		annotation = sym
+	}
+
+	c := p.b[p.i]
+	switch {
+	case p.peek("{{"):
+		b, err := p.blob()
+		return ionValue{av: &dynamodb.AttributeValue{B: b}}, err
+	case c == '{':
+		m, err := p.structValue()
+		return ionValue{av: &dynamodb.AttributeValue{M: m}}, err
+	case c == '[':
+		av, err := p.list(annotation)
+		return ionValue{av: av}, err
+	case c == '"':
+		s, err := p.str()
+		return ionValue{av: &dynamodb.AttributeValue{S: &s}}, err
+	case c == '-' || c == '+' || c >= '0' && c <= '9':
+		n, err := p.number()
+		return ionValue{av: &dynamodb.AttributeValue{N: &n}}, err
+	}
+
+	sym, err := p.symbol()
+	if err != nil {
+		return ionValue{}, err
+	}
+	switch {
+	case sym == "true" || sym == "false":
+		return ionValue{av: &dynamodb.AttributeValue{BOOL: aws.Bool(sym == "true")}}, nil
+	case sym == "null" || strings.HasPrefix(sym, "null."):
+		return ionValue{av: &dynamodb.AttributeValue{NULL: aws.Bool(true)}}, nil
+	}
+	return ionValue{symbol: sym}, nil
+}
+
+func isIonSymbolStart(c byte) bool {
+	return c == '$' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIonSymbolChar(c byte) bool {
+	return isIonSymbolStart(c) || c >= '0' && c <= '9' || c == '.'
+}
+
+func (p *ionParser) symbol() (string, error) {
+	if p.b[p.i] == '\'' {
+		return p.quoted('\'')
+	}
+
+	start := p.i
+	for !p.done() && isIonSymbolChar(p.b[p.i]) {
+		p.i++
+	}
+	if start == p.i {
+		return "", p.errorf("unexpected %q", p.b[p.i])
+	}
+	return string(p.b[start:p.i]), nil
+}
+
+func (p *ionParser) structValue() (map[string]*dynamodb.AttributeValue, error) {
+	p.i++ // {
+	m := map[string]*dynamodb.AttributeValue{}
+	for {
+		p.skipSpace()
+		if p.done() {
+			return nil, p.errorf("unterminated struct")
+		}
+		if p.b[p.i] == '}' {
+			p.i++
+			return m, nil
+		}
+
+		var name string
+		var err error
+		if p.b[p.i] == '"' {
+			name, err = p.str()
+		} else {
+			name, err = p.symbol()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if v.av == nil {
+			return nil, p.errorf("unsupported symbol value %q", v.symbol)
+		}
+		m[name] = v.av
+
+		if err := p.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *ionParser) list(annotation string) (*dynamodb.AttributeValue, error) {
+	p.i++ // [
+	var elems []*dynamodb.AttributeValue
+	for {
+		p.skipSpace()
+		if p.done() {
+			return nil, p.errorf("unterminated list")
+		}
+		if p.b[p.i] == ']' {
+			p.i++
+			break
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if v.av == nil {
+			return nil, p.errorf("unsupported symbol value %q", v.symbol)
+		}
+		elems = append(elems, v.av)
+
+		if err := p.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+
+	av := &dynamodb.AttributeValue{}
+	switch annotation {
+	case ionStringSet:
+		for _, e := range elems {
+			if e.S == nil {
+				return nil, p.errorf("string set contains non string value")
+			}
+			av.SS = append(av.SS, e.S)
+		}
+	case ionNumberSet:
+		for _, e := range elems {
+			if e.N == nil {
+				return nil, p.errorf("number set contains non number value")
+			}
+			av.NS = append(av.NS, e.N)
+		}
+	case ionBinarySet:
+		for _, e := range elems {
+			if e.B == nil {
+				return nil, p.errorf("binary set contains non blob value")
+			}
+			av.BS = append(av.BS, e.B)
+		}
+	default:
+		if elems == nil {
+			elems = []*dynamodb.AttributeValue{}
+		}
+		av.L = elems
+	}
+	return av, nil
+}
+
+// separator consumes the comma between struct fields or list elements,
+// which is optional before the closing delimiter.
+func (p *ionParser) separator(end byte) error {
+	p.skipSpace()
+	if p.done() {
+		return p.errorf("unexpected end of value")
+	}
+	switch p.b[p.i] {
+	case ',':
+		p.i++
+		return nil
+	case end:
+		return nil
+	}
+	return p.errorf("unexpected %q", p.b[p.i])
+}
+
+func (p *ionParser) str() (string, error) {
+	return p.quoted('"')
+}
+
+func (p *ionParser) quoted(q byte) (string, error) {
+	p.i++ // opening quote
+	var buf []byte
+	for {
+		if p.done() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.b[p.i]
+		p.i++
+		switch c {
+		case q:
+			return string(buf), nil
+		case '\\':
+			if p.done() {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.b[p.i]
+			p.i++
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 't':
+				buf = append(buf, '\t')
+			case 'r':
+				buf = append(buf, '\r')
+			case '0':
+				buf = append(buf, 0)
+			case 'a':
+				buf = append(buf, '\a')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case 'v':
+				buf = append(buf, '\v')
+			case 'x', 'u', 'U':
+				n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+				if p.i+n > len(p.b) {
+					return "", p.errorf("invalid escape")
+				}
+				r, err := strconv.ParseUint(string(p.b[p.i:p.i+n]), 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid escape, %v", err)
+				}
+				p.i += n
+				var rb [utf8.UTFMax]byte
+				buf = append(buf, rb[:utf8.EncodeRune(rb[:], rune(r))]...)
+			default:
+				// \" \' \\ \/ and \?
+				buf = append(buf, e)
+			}
+		default:
+			buf = append(buf, c)
+		}
+	}
+}
+
+// number returns the Ion int, decimal, or float as a DynamoDB number
+// string.
+func (p *ionParser) number() (string, error) {
+	start := p.i
+	for !p.done() {
+		c := p.b[p.i]
+		if c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-' || c == '+' ||
+			c == 'd' || c == 'D' || c == 'e' || c == 'E' {
+			p.i++
+			continue
+		}
+		break
+	}
+
+	n := strings.Replace(string(p.b[start:p.i]), "_", "", -1)
+	n = strings.NewReplacer("d", "E", "D", "E").Replace(n)
+	if i := strings.Index(n, "E"); i > 0 && n[i-1] == '.' {
+		n = n[:i-1] + n[i:]
+	} else if strings.HasSuffix(n, ".") {
+		n = n[:len(n)-1]
+	}
+	n = strings.TrimPrefix(n, "+")
+
+	if _, err := strconv.ParseFloat(n, 64); err != nil {
+		if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+			return "", p.errorf("invalid number %q", n)
+		}
+	}
+	return n, nil
+}
+
+func (p *ionParser) blob() ([]byte, error) {
+	p.i += 2 // {{
+	end := strings.Index(string(p.b[p.i:]), "}}")
+	if end < 0 {
+		return nil, p.errorf("unterminated blob")
+	}
+	s := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, string(p.b[p.i:p.i+end]))
+	p.i += end + 2
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, p.errorf("invalid blob, %v", err)
+	}
+	return b, nil
+}
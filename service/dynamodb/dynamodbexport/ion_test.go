@@ -0,0 +1,58 @@
+package dynamodbexport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestIonDecoder(t *testing.T) {
+	ion := `$ion_1_0 {Item:{'id':"a\"bé",n:-1.50,e:12d-1,i:1_000,f:true,z:null,t:null.string,` +
+		`b:{{ AQI= }},l:[1.,"x",[]],m:{"k":false},ns:$dynamodb_NS::[1.,2.5],bs:$dynamodb_BS::[{{AQ==}}]}}`
+
+	item, err := newIonDecoder(strings.NewReader(ion)).DecodeItem()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String("a\"bé")},
+		"n":  {N: aws.String("-1.50")},
+		"e":  {N: aws.String("12E-1")},
+		"i":  {N: aws.String("1000")},
+		"f":  {BOOL: aws.Bool(true)},
+		"z":  {NULL: aws.Bool(true)},
+		"t":  {NULL: aws.Bool(true)},
+		"b":  {B: []byte{1, 2}},
+		"l": {L: []*dynamodb.AttributeValue{
+			{N: aws.String("1")},
+			{S: aws.String("x")},
+			{L: []*dynamodb.AttributeValue{}},
+		}},
+		"m":  {M: map[string]*dynamodb.AttributeValue{"k": {BOOL: aws.Bool(false)}}},
+		"ns": {NS: []*string{aws.String("1"), aws.String("2.5")}},
+		"bs": {BS: [][]byte{{1}}},
+	}}
+	if a := (&dynamodb.AttributeValue{M: item}); !dynamodbattribute.Equal(expect, a) {
+		t.Errorf("expect %v, got %v", expect, a)
+	}
+}
+
+func TestIonDecoderErrors(t *testing.T) {
+	cases := []string{
+		`{Item:{a:"abc}}`,
+		`{Item:{a:1 b:2}}`,
+		`{Item:{a:$dynamodb_SS::[1.]}}`,
+		`{Other:{}}`,
+		`{Item:{a:{{!!}}}}`,
+	}
+
+	for i, c := range cases {
+		if _, err := newIonDecoder(strings.NewReader(c)).DecodeItem(); err == nil {
+			t.Errorf("%d, expect error", i)
+		}
+	}
+}
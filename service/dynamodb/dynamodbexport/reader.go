@@ -0,0 +1,253 @@
+package dynamodbexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// Output formats of a table export.
+const (
+	FormatDynamoDBJSON = "DYNAMODB_JSON"
+	FormatIon          = "ION"
+)
+
+// A ManifestSummary is the manifest-summary.json file written to S3 by a
+// table export.
+type ManifestSummary struct {
+	ExportArn          string `json:"exportArn"`
+	TableArn           string `json:"tableArn"`
+	ExportTime         string `json:"exportTime"`
+	ItemCount          int64  `json:"itemCount"`
+	OutputFormat       string `json:"outputFormat"`
+	ManifestFilesS3Key string `json:"manifestFilesS3Key"`
+}
+
+// A ManifestFile is an entry of the manifest-files.json file written to S3
+// by a table export, describing one of the export's data files.
+type ManifestFile struct {
+	ItemCount     int64  `json:"itemCount"`
+	MD5Checksum   string `json:"md5Checksum"`
+	ETag          string `json:"etag"`
+	DataFileS3Key string `json:"dataFileS3Key"`
+}
+
+// A Reader reads the items of a table export from S3. The data files are
+// streamed one at a time, so only a single item is held in memory.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	// The S3 client used to read the export.
+	S3 s3iface.S3API
+
+	// Bucket and key of the export's manifest-summary.json file.
+	Bucket             string
+	ManifestSummaryKey string
+
+	// Options for the Decoder items are unmarshaled with.
+	DecoderOptions []func(*dynamodbattribute.Decoder)
+
+	summary *ManifestSummary
+	files   []ManifestFile
+	next    int
+
+	body    io.ReadCloser
+	items   itemDecoder
+	decoder *dynamodbattribute.Decoder
+}
+
+type itemDecoder interface {
+	DecodeItem() (map[string]*dynamodb.AttributeValue, error)
+}
+
+// NewReader creates a new Reader for the export whose manifest summary is
+// at bucket and key. Use the `opts` functional options to override the
+// default configuration.
+func NewReader(client s3iface.S3API, bucket, manifestSummaryKey string, opts ...func(*Reader)) *Reader {
+	r := &Reader{
+		S3:                 client,
+		Bucket:             bucket,
+		ManifestSummaryKey: manifestSummaryKey,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// Summary returns the export's manifest summary, reading it from S3 if it
+// has not already been read.
+func (r *Reader) Summary() (*ManifestSummary, error) {
+	if r.summary != nil {
+		return r.summary, nil
+	}
+
+	body, err := r.getObject(r.ManifestSummaryKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	summary := &ManifestSummary{}
+	if err := json.NewDecoder(body).Decode(summary); err != nil {
+		return nil, fmt.Errorf("failed to read manifest summary %s, %v", r.ManifestSummaryKey, err)
+	}
+	switch summary.OutputFormat {
+	case FormatDynamoDBJSON, FormatIon:
+	default:
+		return nil, fmt.Errorf("unsupported export output format %q", summary.OutputFormat)
+	}
+
+	r.summary = summary
+	return summary, nil
+}
+
+// Files returns the export's data files, reading the manifest from S3 if it
+// has not already been read.
+func (r *Reader) Files() ([]ManifestFile, error) {
+	if r.files != nil {
+		return r.files, nil
+	}
+
+	summary, err := r.Summary()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := r.getObject(summary.ManifestFilesS3Key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	files := []ManifestFile{}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var f ManifestFile
+		if err := json.Unmarshal(line, &f); err != nil {
+			return nil, fmt.Errorf("failed to read manifest %s, %v", summary.ManifestFilesS3Key, err)
+		}
+		files = append(files, f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s, %v", summary.ManifestFilesS3Key, err)
+	}
+
+	r.files = files
+	return files, nil
+}
+
+// Decode reads the next item of the export and unmarshals it into out.
+// io.EOF is returned when there are no more items.
+//
+// The output value provided must be a non-nil pointer
+func (r *Reader) Decode(out interface{}) error {
+	item, err := r.DecodeItem()
+	if err != nil {
+		return err
+	}
+
+	if r.decoder == nil {
+		r.decoder = dynamodbattribute.NewDecoder(r.DecoderOptions...)
+	}
+	return r.decoder.Decode(&dynamodb.AttributeValue{M: item}, out)
+}
+
+// DecodeItem reads the next item of the export. io.EOF is returned when
+// there are no more items.
+func (r *Reader) DecodeItem() (map[string]*dynamodb.AttributeValue, error) {
+	for {
+		if r.items == nil {
+			if err := r.openNext(); err != nil {
+				return nil, err
+			}
+		}
+
+		item, err := r.items.DecodeItem()
+		if err == io.EOF {
+			r.closeFile()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+}
+
+// Close closes the data file currently being read.
+func (r *Reader) Close() error {
+	return r.closeFile()
+}
+
+func (r *Reader) openNext() error {
+	files, err := r.Files()
+	if err != nil {
+		return err
+	}
+	if r.next >= len(files) {
+		return io.EOF
+	}
+	key := files[r.next].DataFileS3Key
+	r.next++
+
+	body, err := r.getObject(key)
+	if err != nil {
+		return err
+	}
+
+	var data io.Reader = body
+	if strings.HasSuffix(key, ".gz") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return fmt.Errorf("failed to read data file %s, %v", key, err)
+		}
+		data = gz
+	}
+
+	r.body = body
+	if r.summary.OutputFormat == FormatIon {
+		r.items = newIonDecoder(data)
+	} else {
+		r.items = dynamodbattribute.NewExportDecoder(data)
+	}
+	return nil
+}
+
+func (r *Reader) closeFile() error {
+	r.items = nil
+	if r.body == nil {
+		return nil
+	}
+
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
+
+func (r *Reader) getObject(key string) (io.ReadCloser, error) {
+	out, err := r.S3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
@@ -0,0 +1,138 @@
+package dynamodbexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type mockS3 struct {
+	s3iface.S3API
+	objects map[string][]byte
+}
+
+func (m *mockS3) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	b, ok := m.objects[*input.Key]
+	if !ok {
+		return nil, awserr.New("NoSuchKey", "key not found", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func gzipData(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return buf.Bytes()
+}
+
+type exportItem struct {
+	ID   string `dynamodbav:"id"`
+	Qty  int
+	Tags []string `dynamodbav:",stringset"`
+}
+
+func testExport(t *testing.T, format string, files ...string) *mockS3 {
+	m := &mockS3{objects: map[string][]byte{
+		"export/manifest-summary.json": []byte(`{"exportArn":"arn","itemCount":3,"outputFormat":"` + format +
+			`","manifestFilesS3Key":"export/manifest-files.json"}`),
+	}}
+
+	var manifest bytes.Buffer
+	for i, f := range files {
+		key := "export/data/" + string('a'+rune(i)) + ".gz"
+		m.objects[key] = gzipData(t, f)
+		manifest.WriteString(`{"itemCount":1,"dataFileS3Key":"` + key + `"}` + "\n")
+	}
+	m.objects["export/manifest-files.json"] = manifest.Bytes()
+
+	return m
+}
+
+func readExport(t *testing.T, r *Reader) []exportItem {
+	var items []exportItem
+	for {
+		var v exportItem
+		err := r.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		items = append(items, v)
+	}
+	return items
+}
+
+var expectExportItems = []exportItem{
+	{ID: "a", Qty: 1, Tags: []string{"x", "y"}},
+	{ID: "b", Qty: 2},
+	{ID: "c"},
+}
+
+func TestReaderDynamoDBJSON(t *testing.T) {
+	client := testExport(t, FormatDynamoDBJSON,
+		`{"Item":{"id":{"S":"a"},"Qty":{"N":"1"},"Tags":{"SS":["x","y"]}}}
+{"Item":{"id":{"S":"b"},"Qty":{"N":"2"}}}
+`,
+		`{"Item":{"id":{"S":"c"}}}`,
+	)
+
+	r := NewReader(client, "bucket", "export/manifest-summary.json")
+	defer r.Close()
+
+	if e, a := expectExportItems, readExport(t, r); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	summary, err := r.Summary()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(3), summary.ItemCount; e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+}
+
+func TestReaderIon(t *testing.T) {
+	client := testExport(t, FormatIon,
+		`$ion_1_0 {Item:{id:"a",Qty:1.,Tags:$dynamodb_SS::["x","y"]}}
+{Item:{id:"b",Qty:2.}}
+`,
+		`$ion_1_0 {Item:{id:"c"}}`,
+	)
+
+	r := NewReader(client, "bucket", "export/manifest-summary.json")
+	defer r.Close()
+
+	if e, a := expectExportItems, readExport(t, r); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	client := testExport(t, "CSV")
+	r := NewReader(client, "bucket", "export/manifest-summary.json")
+	if _, err := r.DecodeItem(); err == nil {
+		t.Errorf("expect unsupported format error")
+	}
+
+	r = NewReader(client, "bucket", "missing/manifest-summary.json")
+	_, err := r.DecodeItem()
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchKey" {
+		t.Errorf("expect NoSuchKey error, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package dynamodblock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// Default configuration of a Client.
+const (
+	DefaultPartitionKey    = "LockName"
+	DefaultLeaseDuration   = 20 * time.Second
+	DefaultHeartbeatPeriod = 5 * time.Second
+)
+
+// Names of the lock item's attributes, other than the partition key.
+const (
+	ownerAttribute   = "Owner"
+	tokenAttribute   = "Token"
+	expiresAttribute = "Expires"
+)
+
+// The update and condition expressions of the lock item's writes. Expires
+// is the end of the lease, in milliseconds since the Unix epoch. The
+// item is not deleted when the lock is released, so the Token keeps
+// increasing across acquisitions.
+const (
+	acquireUpdate    = "SET #owner = :owner, #expires = :expires ADD #token :one"
+	acquireCondition = "attribute_not_exists(#owner) OR #expires < :now"
+	renewUpdate      = "SET #expires = :expires"
+	releaseUpdate    = "REMOVE #owner, #expires"
+	heldCondition    = "#owner = :owner AND #token = :token"
+)
+
+// conditionalCheckFailed is the error code of a write whose
+// ConditionExpression was not satisfied.
+const conditionalCheckFailed = "ConditionalCheckFailedException"
+
+// A Client acquires locks stored in a DynamoDB table. A Client is safe for
+// concurrent use once configured.
+type Client struct {
+	// The DynamoDB client the lock table is written with.
+	DynamoDB dynamodbiface.DynamoDBAPI
+
+	// Name of the lock table.
+	TableName string
+
+	// Name of the lock table's partition key attribute.
+	//
+	// Defaults to DefaultPartitionKey.
+	PartitionKey string
+
+	// Identifies the owner of the locks acquired by the Client in the lock
+	// items. Must be unique to the Client.
+	//
+	// Defaults to a random identifier.
+	Owner string
+
+	// Duration of a lock's lease, after which it may be acquired by
+	// another owner unless it is renewed.
+	//
+	// Defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
+	// Period the leases of acquired locks are renewed at, which must be
+	// shorter than the LeaseDuration. Zero disables the heartbeat, and
+	// locks must be renewed with their Renew method.
+	//
+	// Defaults to DefaultHeartbeatPeriod.
+	HeartbeatPeriod time.Duration
+
+	// Function returning the current time leases are computed from.
+	//
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// New creates a new Client acquiring locks stored in the table. Use the
+// `opts` functional options to override the default configuration.
+func New(svc dynamodbiface.DynamoDBAPI, tableName string, opts ...func(*Client)) *Client {
+	c := &Client{
+		DynamoDB:        svc,
+		TableName:       tableName,
+		PartitionKey:    DefaultPartitionKey,
+		Owner:           newOwner(),
+		LeaseDuration:   DefaultLeaseDuration,
+		HeartbeatPeriod: DefaultHeartbeatPeriod,
+		Now:             time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// Acquire acquires the lock with the name, returning a LockHeldError if
+// another owner holds it and its lease has not expired. The lock's lease is
+// renewed by a heartbeat until it is released, if the Client's
+// HeartbeatPeriod is not zero.
+//
+// Acquire does not wait for a held lock to be released. Callers which need
+// to wait should retry after the LockHeldError's Expires time.
+func (c *Client) Acquire(name string) (*Lock, error) {
+	now := c.Now()
+	expires := now.Add(c.LeaseDuration)
+
+	resp, err := c.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                aws.String(c.TableName),
+		Key:                      c.key(name),
+		UpdateExpression:         aws.String(acquireUpdate),
+		ConditionExpression:      aws.String(acquireCondition),
+		ExpressionAttributeNames: c.names(),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner":   {S: aws.String(c.Owner)},
+			":expires": millis(expires),
+			":now":     millis(now),
+			":one":     {N: aws.String("1")},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	})
+	if err != nil {
+		if isConditionFailed(err) {
+			return nil, c.heldError(name, err)
+		}
+		return nil, err
+	}
+
+	token, err := strconv.ParseInt(aws.StringValue(resp.Attributes[tokenAttribute].N), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lock{
+		Name:    name,
+		Token:   token,
+		client:  c,
+		expires: expires,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+	if c.HeartbeatPeriod > 0 {
+		go l.heartbeat(c.HeartbeatPeriod)
+	}
+
+	return l, nil
+}
+
+// heldError returns the LockHeldError of the lock, with the owner and
+// lease of the lock item if it can be read.
+func (c *Client) heldError(name string, err error) error {
+	herr := &LockHeldError{Name: name, Err: err}
+
+	resp, gerr := c.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(c.TableName),
+		Key:            c.key(name),
+		ConsistentRead: aws.Bool(true),
+	})
+	if gerr != nil || resp.Item == nil {
+		return herr
+	}
+	if owner := resp.Item[ownerAttribute]; owner != nil {
+		herr.Owner = aws.StringValue(owner.S)
+	}
+	if expires := resp.Item[expiresAttribute]; expires != nil {
+		if ms, err := strconv.ParseInt(aws.StringValue(expires.N), 10, 64); err == nil {
+			herr.Expires = time.Unix(0, ms*int64(time.Millisecond))
+		}
+	}
+	return herr
+}
+
+// key returns the key of the lock item.
+func (c *Client) key(name string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		c.PartitionKey: {S: aws.String(name)},
+	}
+}
+
+// names returns the ExpressionAttributeNames of the lock item's writes.
+func (c *Client) names() map[string]*string {
+	return map[string]*string{
+		"#owner":   aws.String(ownerAttribute),
+		"#token":   aws.String(tokenAttribute),
+		"#expires": aws.String(expiresAttribute),
+	}
+}
+
+// millis returns the Number AttributeValue of t in milliseconds since the
+// Unix epoch.
+func millis(t time.Time) *dynamodb.AttributeValue {
+	ms := t.UnixNano() / int64(time.Millisecond)
+	return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(ms, 10))}
+}
+
+func isConditionFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == conditionalCheckFailed
+}
+
+// newOwner returns a random owner identifier, or one made from the process
+// ID and time if random bytes cannot be read.
+func newOwner() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	return hex.EncodeToString(b)
+}
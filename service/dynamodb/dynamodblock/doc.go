@@ -0,0 +1,30 @@
+// Package dynamodblock provides distributed locks stored in a DynamoDB
+// table, for leader election and mutual exclusion between processes, such
+// as preventing a scheduled job running on more than one host.
+//
+// A lock is an item of the lock table, written with conditional updates.
+// Acquiring a lock succeeds if no other owner holds it, or the other
+// owner's lease has expired. The owner must renew the lease before it
+// expires, which the Client does periodically with a heartbeat by default,
+// or another owner may acquire the lock.
+//
+//     locks := dynamodblock.New(dynamodb.New(sess), "locks")
+//
+//     lock, err := locks.Acquire("nightly-report")
+//     if err != nil {
+//         return err
+//     }
+//     defer lock.Release()
+//
+//     // do the work, passing lock.Token to the systems written to
+//
+// Each acquisition of a lock is given a fencing token, which is greater
+// than the token of every previous acquisition of the lock. A lease can
+// expire while its owner is paused, so systems protected by the lock
+// should reject writes with a token lower than one they have already seen.
+//
+// The lock table's partition key must be a string attribute, named
+// "LockName" by default. Leases are compared against the clocks of the
+// hosts acquiring locks, so the clocks must be kept synchronized to within
+// a small fraction of the lease duration.
+package dynamodblock
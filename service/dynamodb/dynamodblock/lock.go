@@ -0,0 +1,210 @@
+package dynamodblock
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A Lock is a lock acquired by a Client. A Lock is safe for concurrent use.
+type Lock struct {
+	// Name of the lock.
+	Name string
+
+	// Fencing token of the acquisition, which is greater than the tokens
+	// of every previous acquisition of the lock.
+	Token int64
+
+	client *Client
+
+	mu       sync.Mutex
+	expires  time.Time
+	released bool
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+}
+
+// Expires returns the time the lock's lease expires, unless it is renewed.
+func (l *Lock) Expires() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.expires
+}
+
+// Lost returns a channel which is closed when the lock is found to be held
+// by another owner, or the heartbeat fails to renew the lease before it
+// expires. Work protected by the lock should stop when it is closed.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Renew extends the lock's lease by the Client's LeaseDuration from now. A
+// LockLostError is returned if the lock is no longer held, as it was
+// released, or its lease expired and another owner acquired it.
+func (l *Lock) Renew() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released {
+		return &LockLostError{Name: l.Name}
+	}
+
+	expires := l.client.Now().Add(l.client.LeaseDuration)
+	if err := l.write(renewUpdate, map[string]*dynamodb.AttributeValue{":expires": millis(expires)}); err != nil {
+		return err
+	}
+	l.expires = expires
+
+	return nil
+}
+
+// Release releases the lock, so it can be acquired by another owner, and
+// stops its heartbeat. A LockLostError is returned if the lock was no
+// longer held. Releasing a lock more than once is not an error.
+func (l *Lock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.released {
+		return nil
+	}
+	l.released = true
+	close(l.stop)
+
+	return l.write(releaseUpdate, nil)
+}
+
+// write updates the lock item, conditional on the lock still being held
+// by the acquisition. The caller must hold l.mu.
+func (l *Lock) write(update string, values map[string]*dynamodb.AttributeValue) error {
+	c := l.client
+	if values == nil {
+		values = map[string]*dynamodb.AttributeValue{}
+	}
+	values[":owner"] = &dynamodb.AttributeValue{S: aws.String(c.Owner)}
+	values[":token"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(l.Token, 10))}
+
+	_, err := c.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(c.TableName),
+		Key:                       c.key(l.Name),
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String(heldCondition),
+		ExpressionAttributeNames:  c.names(),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil && isConditionFailed(err) {
+		l.markLost()
+		return &LockLostError{Name: l.Name, Err: err}
+	}
+	return err
+}
+
+// heartbeat renews the lock's lease every period until it is released. If
+// the lease cannot be renewed before it expires, the lock is marked lost.
+func (l *Lock) heartbeat(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := l.Renew()
+		if _, ok := err.(*LockLostError); ok {
+			return
+		}
+		if err != nil && !l.client.Now().Before(l.Expires()) {
+			l.markLost()
+			return
+		}
+	}
+}
+
+func (l *Lock) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+// A LockHeldError is an error type representing a lock which could not be
+// acquired because another owner holds it.
+type LockHeldError struct {
+	Name string
+
+	// Owner of the lock and the time its lease expires, if they could be
+	// read.
+	Owner   string
+	Expires time.Time
+
+	// The write's ConditionalCheckFailedException.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *LockHeldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *LockHeldError) Code() string {
+	return "LockHeldError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *LockHeldError) Message() string {
+	if len(e.Owner) == 0 {
+		return "lock " + e.Name + " is held by another owner"
+	}
+	return "lock " + e.Name + " is held by " + e.Owner
+}
+
+// OrigErr returns the write's ConditionalCheckFailedException, satisfying
+// the awserr.Error interface.
+func (e *LockHeldError) OrigErr() error {
+	return e.Err
+}
+
+// A LockLostError is an error type representing a lock which is no longer
+// held by the owner that acquired it.
+type LockLostError struct {
+	Name string
+
+	// The write's ConditionalCheckFailedException, if the loss was
+	// detected by a write.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *LockLostError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *LockLostError) Code() string {
+	return "LockLostError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *LockLostError) Message() string {
+	return "lock " + e.Name + " is no longer held"
+}
+
+// OrigErr returns the write's ConditionalCheckFailedException, satisfying
+// the awserr.Error interface.
+func (e *LockLostError) OrigErr() error {
+	return e.Err
+}
@@ -0,0 +1,249 @@
+package dynamodblock
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// mockDynamoDB stores lock items in memory, evaluating the lock writes'
+// update and condition expressions.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu     sync.Mutex
+	items  map[string]map[string]*dynamodb.AttributeValue
+	writes int
+}
+
+func newMockDynamoDB() *mockDynamoDB {
+	return &mockDynamoDB{items: map[string]map[string]*dynamodb.AttributeValue{}}
+}
+
+func (m *mockDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+
+	for alias, name := range input.ExpressionAttributeNames {
+		if *name != map[string]string{"#owner": ownerAttribute, "#token": tokenAttribute, "#expires": expiresAttribute}[alias] {
+			panic("unexpected name " + alias)
+		}
+	}
+
+	name := *input.Key[DefaultPartitionKey].S
+	item := m.items[name]
+	if item == nil {
+		item = map[string]*dynamodb.AttributeValue{DefaultPartitionKey: input.Key[DefaultPartitionKey]}
+	}
+	values := input.ExpressionAttributeValues
+
+	var ok bool
+	switch *input.ConditionExpression {
+	case acquireCondition:
+		ok = item[ownerAttribute] == nil || number(item[expiresAttribute]) < number(values[":now"])
+	case heldCondition:
+		ok = item[ownerAttribute] != nil && *item[ownerAttribute].S == *values[":owner"].S &&
+			number(item[tokenAttribute]) == number(values[":token"])
+	default:
+		panic("unexpected condition " + *input.ConditionExpression)
+	}
+	if !ok {
+		return nil, awserr.New(conditionalCheckFailed, "The conditional request failed", nil)
+	}
+
+	switch *input.UpdateExpression {
+	case acquireUpdate:
+		item[ownerAttribute] = values[":owner"]
+		item[expiresAttribute] = values[":expires"]
+		token := number(item[tokenAttribute]) + 1
+		item[tokenAttribute] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(token, 10))}
+	case renewUpdate:
+		item[expiresAttribute] = values[":expires"]
+	case releaseUpdate:
+		delete(item, ownerAttribute)
+		delete(item, expiresAttribute)
+	default:
+		panic("unexpected update " + *input.UpdateExpression)
+	}
+	m.items[name] = item
+
+	return &dynamodb.UpdateItemOutput{Attributes: item}, nil
+}
+
+func (m *mockDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return &dynamodb.GetItemOutput{Item: m.items[*input.Key[DefaultPartitionKey].S]}, nil
+}
+
+func number(av *dynamodb.AttributeValue) int64 {
+	if av == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// testClock is a manually advanced clock.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestClient(svc dynamodbiface.DynamoDBAPI, clock *testClock, owner string) *Client {
+	return New(svc, "locks", func(c *Client) {
+		c.Owner = owner
+		c.Now = clock.Now
+		c.HeartbeatPeriod = 0
+	})
+}
+
+func TestAcquireRelease(t *testing.T) {
+	svc := newMockDynamoDB()
+	clock := &testClock{now: time.Unix(1000, 0)}
+	a := newTestClient(svc, clock, "a")
+	b := newTestClient(svc, clock, "b")
+
+	lock, err := a.Acquire("job")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(1), lock.Token; e != a {
+		t.Errorf("expect token %d, got %d", e, a)
+	}
+	if e, a := clock.Now().Add(DefaultLeaseDuration), lock.Expires(); !e.Equal(a) {
+		t.Errorf("expect lease to expire at %v, got %v", e, a)
+	}
+
+	_, err = b.Acquire("job")
+	herr, ok := err.(*LockHeldError)
+	if !ok {
+		t.Fatalf("expect LockHeldError, got %v", err)
+	}
+	if e, a := "a", herr.Owner; e != a {
+		t.Errorf("expect owner %v, got %v", e, a)
+	}
+	if e, a := lock.Expires(), herr.Expires; !e.Equal(a) {
+		t.Errorf("expect expires %v, got %v", e, a)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("expect no error releasing twice, got %v", err)
+	}
+	if _, ok := lock.Renew().(*LockLostError); !ok {
+		t.Errorf("expect LockLostError renewing released lock")
+	}
+
+	lock, err = b.Acquire("job")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(2), lock.Token; e != a {
+		t.Errorf("expect token %d, got %d", e, a)
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	svc := newMockDynamoDB()
+	clock := &testClock{now: time.Unix(1000, 0)}
+	a := newTestClient(svc, clock, "a")
+	b := newTestClient(svc, clock, "b")
+
+	lockA, err := a.Acquire("job")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Renewing extends the lease.
+	clock.Advance(DefaultLeaseDuration - time.Second)
+	if err := lockA.Renew(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	clock.Advance(2 * time.Second)
+	if _, err := b.Acquire("job"); err == nil {
+		t.Fatalf("expect error acquiring renewed lock")
+	}
+
+	clock.Advance(DefaultLeaseDuration)
+	lockB, err := b.Acquire("job")
+	if err != nil {
+		t.Fatalf("expect no error acquiring expired lock, got %v", err)
+	}
+	if lockB.Token <= lockA.Token {
+		t.Errorf("expect token greater than %d, got %d", lockA.Token, lockB.Token)
+	}
+
+	if _, ok := lockA.Renew().(*LockLostError); !ok {
+		t.Errorf("expect LockLostError renewing lock acquired by another owner")
+	}
+	select {
+	case <-lockA.Lost():
+	default:
+		t.Errorf("expect lock marked lost")
+	}
+	if _, ok := lockA.Release().(*LockLostError); !ok {
+		t.Errorf("expect LockLostError releasing lock acquired by another owner")
+	}
+	if _, err := a.Acquire("job"); err == nil {
+		t.Errorf("expect lock still held by b")
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	svc := newMockDynamoDB()
+	c := New(svc, "locks", func(c *Client) {
+		c.HeartbeatPeriod = time.Millisecond
+	})
+
+	lock, err := c.Acquire("job")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for i := 0; ; i++ {
+		svc.mu.Lock()
+		writes := svc.writes
+		svc.mu.Unlock()
+		if writes >= 3 {
+			break
+		}
+		if i == 1000 {
+			t.Fatalf("expect heartbeat to renew lease")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	select {
+	case <-lock.Lost():
+		t.Errorf("expect released lock not lost")
+	default:
+	}
+}
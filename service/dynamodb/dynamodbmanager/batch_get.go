@@ -0,0 +1,267 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// maxBatchGetKeys is the maximum number of keys of a BatchGetItem request.
+const maxBatchGetKeys = 100
+
+// BatchGet reads the items with the primary keys of keys, a slice of the
+// Table's struct type or pointers to it, with BatchGetItem requests of up
+// to 100 keys. Only the key fields of the keys need to be set. Unprocessed
+// keys are retried, backing off between retries, up to the Table's
+// MaxBatchRetries times.
+//
+// out must be a pointer to a slice or a map. Items are appended to a slice
+// of the Table's struct type, or pointers to it, in the order of keys.
+// Keys of items which do not exist, and repeated keys, are skipped.
+//
+//     var orders []Order
+//     err := table.BatchGet([]Order{{CustomerID: "abc", OrderID: 1}, {CustomerID: "abc", OrderID: 2}}, &orders)
+//
+// Items are set in a map by their primary key. If the map's key type is a
+// struct, the key attributes are unmarshaled into it, otherwise the map's
+// key is the partition key attribute, and the table must not have a sort
+// key.
+//
+//     customers := map[string]Customer{}
+//     err := table.BatchGet(keys, &customers)
+//
+// If keys are still unprocessed once the retries are exhausted, the items
+// read are set in out and an UnprocessedKeysError is returned.
+//
+// Set the Table's BatchGrouper to spread the keys of each partition across
+// the requests, and BatchGroupLatency to observe the latency of the
+// requests for each group.
+func (t *Table) BatchGet(keys interface{}, out interface{}) error {
+	keysV := reflect.ValueOf(keys)
+	if keysV.Kind() != reflect.Slice {
+		return &InvalidModelError{Type: reflect.TypeOf(keys), msg: "batch get keys must be a slice"}
+	}
+	outV := reflect.ValueOf(out)
+	if outV.Kind() != reflect.Ptr || outV.IsNil() {
+		return &InvalidModelError{Type: reflect.TypeOf(out), msg: "items must be read into a pointer to a slice or map"}
+	}
+	isMap := outV.Elem().Kind() == reflect.Map
+	if isMap {
+		if err := t.checkMap(outV.Elem().Type()); err != nil {
+			return err
+		}
+	} else if err := t.checkSlice(out); err != nil {
+		return err
+	}
+
+	// The marshaled keys in the order requested, without repeats, which
+	// BatchGetItem rejects.
+	var requested []map[string]*dynamodb.AttributeValue
+	var ids []string
+	seen := map[string]bool{}
+	for i := 0; i < keysV.Len(); i++ {
+		key := keysV.Index(i).Interface()
+		if err := t.checkType(key); err != nil {
+			return err
+		}
+		av, err := t.registry.Key(key)
+		if err != nil {
+			return err
+		}
+		id, err := keyID(av)
+		if err != nil {
+			return err
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		requested = append(requested, av)
+		ids = append(ids, id)
+	}
+
+	var groups []string
+	if t.BatchGrouper != nil {
+		requested, groups = t.groupKeys(requested)
+	}
+
+	items := map[string]map[string]*dynamodb.AttributeValue{}
+	var unprocessed []map[string]*dynamodb.AttributeValue
+	for start := 0; start < len(requested); start += maxBatchGetKeys {
+		end := start + maxBatchGetKeys
+		if end > len(requested) {
+			end = len(requested)
+		}
+		began := time.Now()
+		read, left, err := t.batchGet(requested[start:end])
+		if err != nil {
+			return err
+		}
+		if groups != nil {
+			t.reportGroupLatency(groups[start:end], time.Since(began))
+		}
+		for _, item := range read {
+			key, err := t.model.key(item)
+			if err != nil {
+				return err
+			}
+			id, err := keyID(key)
+			if err != nil {
+				return err
+			}
+			items[id] = item
+		}
+		unprocessed = append(unprocessed, left...)
+	}
+
+	var found []map[string]*dynamodb.AttributeValue
+	for _, id := range ids {
+		if item, ok := items[id]; ok {
+			found = append(found, item)
+		}
+	}
+	var err error
+	if isMap {
+		err = t.setMapItems(found, outV.Elem())
+	} else {
+		err = t.appendItems(found, out)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(unprocessed) != 0 {
+		return &UnprocessedKeysError{
+			TableName: t.model.TableName,
+			Keys:      unprocessed,
+			Retries:   t.MaxBatchRetries,
+		}
+	}
+	return nil
+}
+
+// batchGet reads the items of the keys with a BatchGetItem request,
+// retrying unprocessed keys. The keys still unprocessed once the retries
+// are exhausted are returned.
+func (t *Table) batchGet(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, []map[string]*dynamodb.AttributeValue, error) {
+	req := &dynamodb.KeysAndAttributes{Keys: keys}
+	if t.ConsistentRead {
+		req.ConsistentRead = aws.Bool(true)
+	}
+	input := &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{t.model.TableName: req},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	for retry := 0; ; retry++ {
+		resp, err := t.svc.BatchGetItem(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, resp.Responses[t.model.TableName]...)
+
+		left := resp.UnprocessedKeys[t.model.TableName]
+		if left == nil || len(left.Keys) == 0 {
+			return items, nil, nil
+		}
+		if retry >= t.MaxBatchRetries {
+			return items, left.Keys, nil
+		}
+		t.sleepBeforeRetry(retry)
+		input.RequestItems = resp.UnprocessedKeys
+	}
+}
+
+// checkMap returns an error if typ is not a map whose elements are the
+// Table's struct type, or pointers to it, and whose key type can hold the
+// Table's primary key.
+func (t *Table) checkMap(typ reflect.Type) error {
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem != t.typ {
+		return &InvalidModelError{Type: elem, msg: "items must be read into a map of the table's type " + t.typ.String()}
+	}
+	if typ.Key().Kind() != reflect.Struct && t.model.RangeKey != "" {
+		return &InvalidModelError{Type: typ.Key(), msg: "map key must be a struct for a table with a sort key"}
+	}
+	return nil
+}
+
+// setMapItems unmarshals the items, setting them in the map m by their
+// primary key, and calls the AfterLoad hook of each item.
+func (t *Table) setMapItems(items []map[string]*dynamodb.AttributeValue, m reflect.Value) error {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	for _, item := range items {
+		key := reflect.New(m.Type().Key())
+		var err error
+		if key.Elem().Kind() == reflect.Struct {
+			err = dynamodbattribute.UnmarshalMap(item, key.Interface())
+		} else {
+			err = dynamodbattribute.Unmarshal(item[t.model.HashKey], key.Interface())
+		}
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(t.typ)
+		if err := t.registry.UnmarshalItem(item, elem.Interface()); err != nil {
+			return err
+		}
+		if m.Type().Elem().Kind() != reflect.Ptr {
+			elem = elem.Elem()
+		}
+		m.SetMapIndex(key.Elem(), elem)
+	}
+
+	return nil
+}
+
+// keyID returns a string identifying the primary key, which is the same
+// for equal keys.
+func keyID(key map[string]*dynamodb.AttributeValue) (string, error) {
+	b, err := dynamodbattribute.MarshalWireJSONMap(key)
+	return string(b), err
+}
+
+// An UnprocessedKeysError is an error type representing the keys of a
+// batch read which were not processed by DynamoDB, even after retrying
+// them.
+type UnprocessedKeysError struct {
+	TableName string
+	Keys      []map[string]*dynamodb.AttributeValue
+	Retries   int
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnprocessedKeysError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnprocessedKeysError) Code() string {
+	return "UnprocessedKeysError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnprocessedKeysError) Message() string {
+	return strconv.Itoa(len(e.Keys)) + " keys of table " + e.TableName +
+		" unprocessed after " + strconv.Itoa(e.Retries) + " retries"
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *UnprocessedKeysError) OrigErr() error {
+	return nil
+}
@@ -0,0 +1,177 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestTableBatchGet(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	var keys []testDocument
+	var expect []testDocument
+	for i := 0; i < 250; i++ {
+		id := strconv.Itoa(i)
+		keys = append(keys, testDocument{ID: id})
+		if i%2 == 1 {
+			continue
+		}
+		if err := table.Put(&testDocument{ID: id}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		expect = append(expect, testDocument{ID: id, Version: 1, Loaded: true})
+	}
+	// Repeated keys are only requested once.
+	keys = append(keys, testDocument{ID: "0"})
+
+	var out []testDocument
+	if err := table.BatchGet(keys, &out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect, out; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect items in request order, got %v", a)
+	}
+
+	var sizes []int
+	for _, input := range svc.batchGets {
+		sizes = append(sizes, len(input.RequestItems["documents"].Keys))
+	}
+	if e, a := []int{100, 100, 50}, sizes; !intsEqual(e, a) {
+		t.Errorf("expect request sizes %v, got %v", e, a)
+	}
+
+	byID := map[string]*testDocument{}
+	if err := table.BatchGet(keys[:4], &byID); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(byID); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	if e, a := expect[1], *byID["2"]; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestTableBatchGetUnprocessed(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+	var delays []time.Duration
+	table.SleepDelay = func(d time.Duration) {
+		delays = append(delays, d)
+	}
+	table.MaxBatchRetries = 2
+
+	var keys []testDocument
+	for i := 0; i < 3; i++ {
+		doc := testDocument{ID: strconv.Itoa(i)}
+		if err := table.Put(&doc); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		keys = append(keys, doc)
+	}
+
+	// One key is left unprocessed by each request, so the final key is
+	// unprocessed once the retries are exhausted.
+	svc.unprocessed = 1
+	var out []testDocument
+	err := table.BatchGet(keys, &out)
+	uerr, ok := err.(*UnprocessedKeysError)
+	if !ok {
+		t.Fatalf("expect UnprocessedKeysError, got %v", err)
+	}
+	if e, a := 1, len(uerr.Keys); e != a {
+		t.Errorf("expect %d unprocessed keys, got %d", e, a)
+	}
+	if e, a := 2, len(out); e != a {
+		t.Errorf("expect %d items read, got %d", e, a)
+	}
+	if e, a := 2, len(delays); e != a {
+		t.Fatalf("expect %d retries, got %d", e, a)
+	}
+	if delays[1] <= delays[0] {
+		t.Errorf("expect increasing delays, got %v", delays)
+	}
+}
+
+func TestTableBatchGetGrouped(t *testing.T) {
+	svc := newMockDynamoDB("CustomerID", "OrderID")
+	table := newTestOrderTable(t, svc)
+	table.BatchGrouper = PartitionKeyGrouper(table.Model().HashKey)
+
+	type latency struct {
+		group string
+		keys  int
+	}
+	var latencies []latency
+	table.BatchGroupLatency = func(group string, keys int, d time.Duration) {
+		latencies = append(latencies, latency{group, keys})
+	}
+
+	var keys []testOrder
+	for i, customer := range []string{"a", "a", "a", "b", "b", "c"} {
+		order := testOrder{CustomerID: customer, OrderID: i}
+		if err := table.Put(&order); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		keys = append(keys, order)
+	}
+
+	var out []testOrder
+	if err := table.BatchGet(keys, &out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := len(keys), len(out); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	for i := range keys {
+		if e, a := keys[i].OrderID, out[i].OrderID; e != a {
+			t.Errorf("%d, expect items in request order, got order %d", i, a)
+		}
+	}
+
+	// Keys are requested taking one from each partition in turn.
+	var requested []string
+	for _, key := range svc.batchGets[0].RequestItems["orders"].Keys {
+		requested = append(requested, *key["CustomerID"].S+*key["OrderID"].N)
+	}
+	if e, a := "[a0 b3 c5 a1 b4 a2]", fmt.Sprint(requested); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 3, len(latencies); e != a {
+		t.Fatalf("expect %d group latencies, got %d", e, a)
+	}
+	if e, a := 3, latencies[0].keys; e != a {
+		t.Errorf("expect %d keys in first group, got %d", e, a)
+	}
+}
+
+func TestHashGrouper(t *testing.T) {
+	g := HashGrouper("ID", 4)
+
+	key := func(id string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"ID": {S: aws.String(id)}, "Sort": {N: aws.String(id)}}
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := strconv.Itoa(i)
+		group := g(key(id))
+		if e, a := group, g(key(id)); e != a {
+			t.Errorf("expect key in the same group, got %v and %v", e, a)
+		}
+		if n, err := strconv.Atoi(group); err != nil || n < 0 || n >= 4 {
+			t.Errorf("expect shard group, got %q", group)
+		}
+		seen[group] = true
+	}
+	if e, a := 4, len(seen); e != a {
+		t.Errorf("expect keys in %d groups, got %d", e, a)
+	}
+}
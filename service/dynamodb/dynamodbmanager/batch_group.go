@@ -0,0 +1,92 @@
+package dynamodbmanager
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A BatchGrouper returns the group of a key read by BatchGet, such as the
+// partition its item is expected to be stored in. Set a Table's
+// BatchGrouper to spread the keys of each group across the BatchGetItem
+// requests of a BatchGet, so no request concentrates its reads on a hot
+// partition.
+type BatchGrouper func(key map[string]*dynamodb.AttributeValue) string
+
+// PartitionKeyGrouper returns a BatchGrouper which groups keys by the value
+// of their partition key attribute, name.
+//
+//     table.BatchGrouper = dynamodbmanager.PartitionKeyGrouper(table.Model().HashKey)
+func PartitionKeyGrouper(name string) BatchGrouper {
+	return func(key map[string]*dynamodb.AttributeValue) string {
+		id, _ := keyID(map[string]*dynamodb.AttributeValue{name: key[name]})
+		return id
+	}
+}
+
+// HashGrouper returns a BatchGrouper which groups keys into shards by a
+// hash of the value of their partition key attribute, name. A value is
+// always in the same shard, so keys of the same partition are in the same
+// group, without a group for each partition key value. The groups are
+// named by the number of the shard, from "0".
+func HashGrouper(name string, shards int) BatchGrouper {
+	if shards < 1 {
+		shards = 1
+	}
+	return func(key map[string]*dynamodb.AttributeValue) string {
+		id, _ := keyID(map[string]*dynamodb.AttributeValue{name: key[name]})
+		h := fnv.New32a()
+		h.Write([]byte(id))
+		return strconv.Itoa(int(h.Sum32() % uint32(shards)))
+	}
+}
+
+// groupKeys returns the keys ordered by taking a key from each group in
+// turn, in the order of the groups' first keys, and the group of each
+// ordered key.
+func (t *Table) groupKeys(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, []string) {
+	var names []string
+	byGroup := map[string][]map[string]*dynamodb.AttributeValue{}
+	for _, key := range keys {
+		group := t.BatchGrouper(key)
+		if _, ok := byGroup[group]; !ok {
+			names = append(names, group)
+		}
+		byGroup[group] = append(byGroup[group], key)
+	}
+
+	ordered := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	groups := make([]string, 0, len(keys))
+	for len(ordered) < len(keys) {
+		for _, group := range names {
+			if left := byGroup[group]; len(left) != 0 {
+				ordered = append(ordered, left[0])
+				groups = append(groups, group)
+				byGroup[group] = left[1:]
+			}
+		}
+	}
+	return ordered, groups
+}
+
+// reportGroupLatency calls the Table's BatchGroupLatency with the latency
+// of a BatchGetItem request for each group of the request's keys.
+func (t *Table) reportGroupLatency(groups []string, latency time.Duration) {
+	if t.BatchGroupLatency == nil {
+		return
+	}
+
+	var names []string
+	counts := map[string]int{}
+	for _, group := range groups {
+		if counts[group] == 0 {
+			names = append(names, group)
+		}
+		counts[group]++
+	}
+	for _, group := range names {
+		t.BatchGroupLatency(group, counts[group], latency)
+	}
+}
@@ -0,0 +1,36 @@
+package dynamodbmanager
+
+import (
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// BatchWriteItemRequest returns a BatchWriteItem request whose body is the
+// request body written by a dynamodbattribute.BatchWriteEncoder, instead of
+// a body marshaled from a BatchWriteItemInput. The output's UnprocessedItems
+// are populated once the request is sent, the same as for a request built
+// from an input.
+//
+//     var body bytes.Buffer
+//     enc := dynamodbattribute.NewBatchWriteEncoder(&body)
+//     // add items to enc, then close it
+//
+//     req, out := dynamodbmanager.BatchWriteItemRequest(svc, body.Bytes())
+//     if err := req.Send(); err != nil {
+//         return err
+//     }
+//
+// The body is not validated before it is sent.
+func BatchWriteItemRequest(svc dynamodbiface.DynamoDBAPI, body []byte) (*request.Request, *dynamodb.BatchWriteItemOutput) {
+	req, out := svc.BatchWriteItemRequest(&dynamodb.BatchWriteItemInput{})
+
+	// The empty input's required RequestItems are written in the body.
+	req.Handlers.Validate.Remove(corehandlers.ValidateParametersHandler)
+	req.Handlers.Build.PushBack(func(r *request.Request) {
+		r.SetBufferBody(body)
+	})
+
+	return req, out
+}
@@ -0,0 +1,52 @@
+package dynamodbmanager
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestBatchWriteItemRequest(t *testing.T) {
+	var body bytes.Buffer
+	enc := dynamodbattribute.NewBatchWriteEncoder(&body)
+	if err := enc.Put("Orders", testOrder{CustomerID: "c1", OrderID: 1, Status: "new"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	req, _ := BatchWriteItemRequest(dynamodb.New(unit.Session), body.Bytes())
+	if err := req.Build(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, err := ioutil.ReadAll(req.HTTPRequest.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := body.String(), string(b); e != a {
+		t.Errorf("expect body %s, got %s", e, a)
+	}
+	if e, a := "DynamoDB_20120810.BatchWriteItem", req.HTTPRequest.Header.Get("X-Amz-Target"); e != a {
+		t.Errorf("expect %v target, got %v", e, a)
+	}
+
+	var input dynamodb.BatchWriteItemInput
+	if err := jsonutil.UnmarshalJSON(&input, bytes.NewReader(b)); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect, err := dynamodbattribute.MarshalMap(testOrder{CustomerID: "c1", OrderID: 1, Status: "new"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect, input.RequestItems["Orders"][0].PutRequest.Item; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,280 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// maxBatchWriteItems is the maximum number of requests of a
+// BatchWriteItem request.
+const maxBatchWriteItems = 25
+
+// A BatchWriter writes puts and deletes of a Table's items with
+// BatchWriteItem requests of up to 25 items. Items are buffered until 25
+// are added, or Flush or Close is called, and the writes are sent by the
+// call adding the item, so callers are held back while DynamoDB catches up.
+// Unprocessed items are retried, backing off between retries, up to the
+// Table's MaxBatchRetries times. Create a BatchWriter with the Table's
+// BatchWriter method.
+//
+//     w := table.BatchWriter()
+//     for i := range orders {
+//         if err := w.Put(&orders[i]); err != nil {
+//             return err
+//         }
+//     }
+//     if err := w.Close(); err != nil {
+//         return err
+//     }
+//
+// BatchWriteItem does not support condition expressions, so items whose
+// fields have write rules, such as the `version` struct tag option, cannot
+// be written by a BatchWriter.
+//
+// A BatchWriter is not safe for concurrent use.
+type BatchWriter struct {
+	table   *Table
+	pending []batchWrite
+	keys    map[string]bool
+	closed  bool
+}
+
+// batchWrite is a write request buffered by a BatchWriter, along with the
+// item or key it was created from.
+type batchWrite struct {
+	id      string
+	item    interface{}
+	request *dynamodb.WriteRequest
+}
+
+// BatchWriter returns a BatchWriter writing the table's items.
+func (t *Table) BatchWriter() *BatchWriter {
+	return &BatchWriter{table: t, keys: map[string]bool{}}
+}
+
+// Put adds a write of item, replacing the item with the same primary key.
+// The item's BeforeSave hook is called.
+func (w *BatchWriter) Put(item interface{}) error {
+	if err := w.check(item); err != nil {
+		return err
+	}
+	if len(dynamodbattribute.WriteRules(item)) != 0 {
+		return &InvalidModelError{Type: w.table.typ, msg: "batch writes cannot enforce the write rules of items"}
+	}
+
+	av, m, err := w.table.registry.MarshalItem(item)
+	if err != nil {
+		return err
+	}
+	key, err := m.key(av)
+	if err != nil {
+		return err
+	}
+
+	return w.add(key, item, &dynamodb.WriteRequest{
+		PutRequest: &dynamodb.PutRequest{Item: av},
+	})
+}
+
+// Delete adds a delete of the item with the same primary key as key. Only
+// the key fields of key need to be set.
+func (w *BatchWriter) Delete(key interface{}) error {
+	if err := w.check(key); err != nil {
+		return err
+	}
+
+	av, err := w.table.registry.Key(key)
+	if err != nil {
+		return err
+	}
+
+	return w.add(av, key, &dynamodb.WriteRequest{
+		DeleteRequest: &dynamodb.DeleteRequest{Key: av},
+	})
+}
+
+// Len returns the number of writes buffered.
+func (w *BatchWriter) Len() int {
+	return len(w.pending)
+}
+
+// Flush sends the buffered writes. If items are still unprocessed once the
+// retries are exhausted, an UnprocessedItemsError listing them is returned,
+// and they are no longer buffered. If a request fails, its writes, the
+// writes after them, and the writes left unprocessed by earlier requests
+// remain buffered, so Flush can be called again.
+func (w *BatchWriter) Flush() error {
+	var unprocessed []batchWrite
+	for len(w.pending) != 0 {
+		n := len(w.pending)
+		if n > maxBatchWriteItems {
+			n = maxBatchWriteItems
+		}
+		left, err := w.write(w.pending[:n])
+		if err != nil {
+			for _, write := range unprocessed {
+				w.keys[write.id] = true
+			}
+			w.pending = append(unprocessed, w.pending...)
+			return err
+		}
+		unprocessed = append(unprocessed, left...)
+
+		for _, write := range w.pending[:n] {
+			delete(w.keys, write.id)
+		}
+		w.pending = w.pending[n:]
+	}
+	w.pending = nil
+
+	if len(unprocessed) != 0 {
+		items := make([]interface{}, len(unprocessed))
+		for i, write := range unprocessed {
+			items[i] = write.item
+		}
+		return &UnprocessedItemsError{
+			TableName: w.table.model.TableName,
+			Items:     items,
+			Retries:   w.table.MaxBatchRetries,
+		}
+	}
+	return nil
+}
+
+// Close flushes the buffered writes. No writes can be added once the
+// BatchWriter is closed.
+func (w *BatchWriter) Close() error {
+	w.closed = true
+	return w.Flush()
+}
+
+// check returns an error if v cannot be added to the BatchWriter.
+func (w *BatchWriter) check(v interface{}) error {
+	if w.closed {
+		return &InvalidModelError{msg: "batch writer is closed"}
+	}
+	return w.table.checkType(v)
+}
+
+// add buffers the write request, flushing the buffer first if it already
+// has a write of the same key, as BatchWriteItem rejects requests with
+// repeated keys, and after if it is full.
+func (w *BatchWriter) add(key map[string]*dynamodb.AttributeValue, item interface{}, req *dynamodb.WriteRequest) error {
+	id, err := keyID(key)
+	if err != nil {
+		return err
+	}
+	if w.keys[id] {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	w.pending = append(w.pending, batchWrite{id: id, item: item, request: req})
+	w.keys[id] = true
+
+	if len(w.pending) >= maxBatchWriteItems {
+		return w.Flush()
+	}
+	return nil
+}
+
+// write sends the writes with a BatchWriteItem request, retrying
+// unprocessed items. The writes still unprocessed once the retries are
+// exhausted are returned.
+func (w *BatchWriter) write(writes []batchWrite) ([]batchWrite, error) {
+	t := w.table
+	reqs := make([]*dynamodb.WriteRequest, len(writes))
+	for i, write := range writes {
+		reqs[i] = write.request
+	}
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{t.model.TableName: reqs},
+	}
+
+	for retry := 0; ; retry++ {
+		resp, err := t.svc.BatchWriteItem(input)
+		if err != nil {
+			return nil, err
+		}
+
+		left := resp.UnprocessedItems[t.model.TableName]
+		if len(left) == 0 {
+			return nil, nil
+		}
+		if retry >= t.MaxBatchRetries {
+			return w.unprocessedWrites(writes, left)
+		}
+		t.sleepBeforeRetry(retry)
+		input.RequestItems = resp.UnprocessedItems
+	}
+}
+
+// unprocessedWrites returns the writes of the unprocessed requests, found
+// by the requests' keys.
+func (w *BatchWriter) unprocessedWrites(writes []batchWrite, reqs []*dynamodb.WriteRequest) ([]batchWrite, error) {
+	byID := make(map[string]batchWrite, len(writes))
+	for _, write := range writes {
+		byID[write.id] = write
+	}
+
+	left := make([]batchWrite, 0, len(reqs))
+	for _, req := range reqs {
+		var key map[string]*dynamodb.AttributeValue
+		if req.DeleteRequest != nil {
+			key = req.DeleteRequest.Key
+		} else if req.PutRequest != nil {
+			var err error
+			if key, err = w.table.model.key(req.PutRequest.Item); err != nil {
+				return nil, err
+			}
+		}
+		id, err := keyID(key)
+		if err != nil {
+			return nil, err
+		}
+		if write, ok := byID[id]; ok {
+			left = append(left, write)
+		}
+	}
+	return left, nil
+}
+
+// An UnprocessedItemsError is an error type representing the writes of a
+// batch write which were not processed by DynamoDB, even after retrying
+// them.
+type UnprocessedItemsError struct {
+	TableName string
+
+	// The items of the puts, and keys of the deletes, not written.
+	Items []interface{}
+
+	Retries int
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnprocessedItemsError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnprocessedItemsError) Code() string {
+	return "UnprocessedItemsError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnprocessedItemsError) Message() string {
+	return strconv.Itoa(len(e.Items)) + " items of table " + e.TableName +
+		" unprocessed after " + strconv.Itoa(e.Retries) + " retries"
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *UnprocessedItemsError) OrigErr() error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func newTestOrderTable(t *testing.T, svc *mockDynamoDB) *Table {
+	table, err := NewTable(svc, newTestRegistry(t), testOrder{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	table.SleepDelay = func(time.Duration) {}
+	return table
+}
+
+func TestBatchWriter(t *testing.T) {
+	svc := newMockDynamoDB("CustomerID", "OrderID")
+	table := newTestOrderTable(t, svc)
+
+	w := table.BatchWriter()
+	for i := 0; i < 30; i++ {
+		if err := w.Put(&testOrder{CustomerID: "abc", OrderID: i}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if e, a := 1, len(svc.batchWrites); e != a {
+		t.Errorf("expect %d request once 25 items are added, got %d", e, a)
+	}
+	if e, a := 5, w.Len(); e != a {
+		t.Errorf("expect %d items buffered, got %d", e, a)
+	}
+
+	// A repeated key flushes the buffered writes first.
+	if err := w.Delete(testOrder{CustomerID: "abc", OrderID: 29}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(svc.batchWrites); e != a {
+		t.Errorf("expect %d requests, got %d", e, a)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 29, len(svc.items); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+	if e, a := "NEW", *svc.batchWrites[0].RequestItems["orders"][0].PutRequest.Item["Status"].S; e != a {
+		t.Errorf("expect BeforeSave called, got %v", a)
+	}
+
+	if err := w.Put(&testOrder{CustomerID: "abc"}); err == nil {
+		t.Errorf("expect error once closed")
+	}
+}
+
+func TestBatchWriterUnprocessed(t *testing.T) {
+	svc := newMockDynamoDB("CustomerID", "OrderID")
+	table := newTestOrderTable(t, svc)
+	table.MaxBatchRetries = 1
+	svc.unprocessed = 2
+
+	w := table.BatchWriter()
+	for i := 0; i < 4; i++ {
+		if err := w.Put(&testOrder{CustomerID: "abc", OrderID: i}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	err := w.Flush()
+	uerr, ok := err.(*UnprocessedItemsError)
+	if !ok {
+		t.Fatalf("expect UnprocessedItemsError, got %v", err)
+	}
+	if e, a := 2, len(uerr.Items); e != a {
+		t.Fatalf("expect %d unprocessed items, got %d", e, a)
+	}
+	if e, a := 2, uerr.Items[0].(*testOrder).OrderID; e != a {
+		t.Errorf("expect unprocessed order %d, got %d", e, a)
+	}
+	if e, a := 2, len(svc.batchWrites); e != a {
+		t.Errorf("expect %d requests, got %d", e, a)
+	}
+	if e, a := 0, w.Len(); e != a {
+		t.Errorf("expect %d items buffered, got %d", e, a)
+	}
+}
+
+func TestBatchWriterFlushErrorKeepsUnprocessed(t *testing.T) {
+	svc := newMockDynamoDB("CustomerID", "OrderID")
+	table := newTestOrderTable(t, svc)
+	table.MaxBatchRetries = 0
+	svc.unprocessed = 2
+	svc.batchWriteErrs = []error{nil, fmt.Errorf("request failed")}
+
+	// Buffer more writes than one request holds, as Flush does after an
+	// earlier failed Flush.
+	w := table.BatchWriter()
+	for i := 0; i < 30; i++ {
+		order := &testOrder{CustomerID: "abc", OrderID: i}
+		av, _, err := table.registry.MarshalItem(order)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		key, err := table.registry.Key(order)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		id, err := keyID(key)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		w.pending = append(w.pending, batchWrite{id: id, item: order, request: &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: av},
+		}})
+		w.keys[id] = true
+	}
+
+	if err := w.Flush(); err == nil || err.Error() != "request failed" {
+		t.Fatalf("expect request error, got %v", err)
+	}
+	// The 2 writes left unprocessed by the first request, and the 5 writes
+	// of the failed request.
+	if e, a := 7, w.Len(); e != a {
+		t.Fatalf("expect %d items buffered, got %d", e, a)
+	}
+	if e, a := 23, w.pending[0].item.(*testOrder).OrderID; e != a {
+		t.Errorf("expect buffered order %d, got %d", e, a)
+	}
+
+	svc.unprocessed = 0
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 30, len(svc.items); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+	if e, a := 0, len(w.keys); e != a {
+		t.Errorf("expect %d keys buffered, got %d", e, a)
+	}
+}
+
+func TestBatchWriterWriteRules(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	w := newTestTable(t, svc).BatchWriter()
+
+	if err := w.Put(&testDocument{ID: "a"}); err == nil {
+		t.Errorf("expect error for item with write rules")
+	}
+	if err := w.Delete(testDocument{ID: "a"}); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package dynamodbmanager provides higher level utilities for storing Go
+// value types in DynamoDB tables, built on top of the dynamodbattribute
+// marshaling utilities.
+package dynamodbmanager
@@ -0,0 +1,292 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// An Iterator reads the items of a Query or Scan one at a time, reading
+// the next page of results when the items of the current page have been
+// read, so large results can be processed without holding them in memory.
+// Create an Iterator with the Iter method of a Query or Scan.
+//
+//     it := table.Query().KeyEqual("CustomerID", "abc").Iter().Prefetch(1)
+//     defer it.Close()
+//
+//     for it.Next() {
+//         var order Order
+//         if err := it.Item(&order); err != nil {
+//             return err
+//         }
+//         process(order)
+//     }
+//     if err := it.Err(); err != nil {
+//         return err
+//     }
+//
+// An Iterator is not safe for concurrent use.
+type Iterator struct {
+	table    *Table
+	fetch    func(limit int64) ([]map[string]*dynamodb.AttributeValue, bool, error)
+	prefetch int
+
+	// The deadline of an adaptive page size, and the time taken by, and
+	// items read by, the pages read so far.
+	deadline time.Time
+	elapsed  time.Duration
+	read     int64
+	now      func() time.Time
+
+	pages chan iteratorPage
+	done  chan struct{}
+
+	items    []map[string]*dynamodb.AttributeValue
+	item     map[string]*dynamodb.AttributeValue
+	lastPage bool
+	err      error
+	closed   bool
+}
+
+// iteratorPage is a page of results read ahead by an Iterator.
+type iteratorPage struct {
+	items    []map[string]*dynamodb.AttributeValue
+	lastPage bool
+	err      error
+}
+
+// adaptiveInitialLimit is the Limit of the first page read by an Iterator
+// with a Deadline, before the rate items are read at is known.
+const adaptiveInitialLimit = 100
+
+// Iter returns an Iterator of the Query's results. If a Limit is set, no
+// more than Limit items are read.
+func (q *Query) Iter() *Iterator {
+	var input *dynamodb.QueryInput
+	remaining := q.limit
+	return &Iterator{table: q.table, fetch: func(limit int64) ([]map[string]*dynamodb.AttributeValue, bool, error) {
+		if input == nil {
+			var err error
+			if input, err = q.input(); err != nil {
+				return nil, true, err
+			}
+		}
+		if q.limit > 0 && (limit <= 0 || remaining < limit) {
+			limit = remaining
+		}
+		input.Limit = nil
+		if limit > 0 {
+			input.Limit = aws.Int64(limit)
+		}
+		resp, err := q.table.svc.Query(input)
+		if err != nil {
+			return nil, true, err
+		}
+		remaining -= int64(len(resp.Items))
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+		return resp.Items, len(resp.LastEvaluatedKey) == 0 || (q.limit > 0 && remaining <= 0), nil
+	}}
+}
+
+// Iter returns an Iterator of the Scan's results.
+func (s *Scan) Iter() *Iterator {
+	var input *dynamodb.ScanInput
+	return &Iterator{table: s.table, fetch: func(limit int64) ([]map[string]*dynamodb.AttributeValue, bool, error) {
+		if input == nil {
+			var err error
+			if input, err = s.input(); err != nil {
+				return nil, true, err
+			}
+		}
+		input.Limit = nil
+		if limit > 0 {
+			input.Limit = aws.Int64(limit)
+		}
+		resp, err := s.table.svc.Scan(input)
+		if err != nil {
+			return nil, true, err
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+		return resp.Items, len(resp.LastEvaluatedKey) == 0, nil
+	}}
+}
+
+// Prefetch sets the number of pages the Iterator reads ahead, in a
+// goroutine, while the items of the current page are processed. Zero, the
+// default, reads each page when it is needed. Prefetch must be called
+// before Next. Close the Iterator to stop the goroutine if the items are
+// not all read.
+func (it *Iterator) Prefetch(pages int) *Iterator {
+	it.prefetch = pages
+	return it
+}
+
+// Deadline sets an adaptive page size, so the Iterator's pages are read
+// before the deadline t, such as a request handler's timeout. Before each
+// page is read, its Limit is set to the number of items expected to be
+// read in half of the time left, at the rate the earlier pages were read.
+// The first page is limited to 100 items. Once t has passed, no more pages
+// are read, and Err returns a DeadlineExceededError after the items already
+// read. Deadline must be called before Next.
+//
+//     it := table.Scan().Iter().Deadline(time.Now().Add(2 * time.Second))
+func (it *Iterator) Deadline(t time.Time) *Iterator {
+	it.deadline = t
+	return it
+}
+
+// Next advances the Iterator to the next item, returning false when there
+// are no more items, an error occurred, or the Iterator is closed.
+func (it *Iterator) Next() bool {
+	it.item = nil
+	for len(it.items) == 0 {
+		if it.err != nil || it.lastPage || it.closed {
+			return false
+		}
+		it.items, it.lastPage, it.err = it.nextPage()
+	}
+
+	it.item = it.items[0]
+	it.items[0] = nil
+	it.items = it.items[1:]
+	return true
+}
+
+// Item unmarshals the current item into out, a pointer to the Table's
+// struct type, and calls its AfterLoad hook.
+func (it *Iterator) Item(out interface{}) error {
+	if it.item == nil {
+		return &InvalidModelError{Type: it.table.typ, msg: "iterator has no current item"}
+	}
+	if err := it.table.checkType(out); err != nil {
+		return err
+	}
+	return it.table.registry.UnmarshalItem(it.item, out)
+}
+
+// Err returns the error which stopped the Iterator, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the Iterator, and the goroutine reading pages ahead, if
+// Prefetch is set. Close is safe to call more than once.
+func (it *Iterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.items, it.item = nil, nil
+	if it.done != nil {
+		close(it.done)
+	}
+}
+
+// nextPage returns the next page of results, read directly, or from the
+// goroutine reading pages ahead.
+func (it *Iterator) nextPage() ([]map[string]*dynamodb.AttributeValue, bool, error) {
+	if it.prefetch <= 0 {
+		return it.fetchPage()
+	}
+
+	if it.pages == nil {
+		it.pages = make(chan iteratorPage, it.prefetch)
+		it.done = make(chan struct{})
+		go it.readAhead(it.pages, it.done)
+	}
+	p, ok := <-it.pages
+	if !ok {
+		return nil, true, nil
+	}
+	return p.items, p.lastPage, p.err
+}
+
+// readAhead reads pages into the channel until the last page is read, an
+// error occurs, or done is closed.
+func (it *Iterator) readAhead(pages chan<- iteratorPage, done <-chan struct{}) {
+	defer close(pages)
+	for {
+		items, lastPage, err := it.fetchPage()
+		select {
+		case pages <- iteratorPage{items: items, lastPage: lastPage, err: err}:
+		case <-done:
+			return
+		}
+		if err != nil || lastPage {
+			return
+		}
+	}
+}
+
+// fetchPage reads the next page of results, limiting its size to the
+// number of items which can be read before the Iterator's deadline, if it
+// has one.
+func (it *Iterator) fetchPage() ([]map[string]*dynamodb.AttributeValue, bool, error) {
+	if it.deadline.IsZero() {
+		return it.fetch(0)
+	}
+	if it.now == nil {
+		it.now = time.Now
+	}
+
+	start := it.now()
+	left := it.deadline.Sub(start)
+	if left <= 0 {
+		return nil, true, &DeadlineExceededError{TableName: it.table.model.TableName, Read: it.read}
+	}
+
+	limit := int64(adaptiveInitialLimit)
+	if it.read > 0 && it.elapsed > 0 {
+		// The items expected to be read in half the time left.
+		n := float64(it.read) * float64(left/2) / float64(it.elapsed)
+		switch {
+		case n < 1:
+			limit = 1
+		case n > math.MaxInt32:
+			limit = math.MaxInt32
+		default:
+			limit = int64(n)
+		}
+	}
+
+	items, lastPage, err := it.fetch(limit)
+	it.elapsed += it.now().Sub(start)
+	it.read += int64(len(items))
+	return items, lastPage, err
+}
+
+// A DeadlineExceededError is an error type representing an Iterator which
+// stopped reading pages as its deadline passed, before the last page was
+// read.
+type DeadlineExceededError struct {
+	TableName string
+
+	// The number of items read before the deadline.
+	Read int64
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *DeadlineExceededError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *DeadlineExceededError) Code() string {
+	return "DeadlineExceededError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *DeadlineExceededError) Message() string {
+	return fmt.Sprintf("deadline passed after reading %d items of table %s", e.Read, e.TableName)
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *DeadlineExceededError) OrigErr() error {
+	return nil
+}
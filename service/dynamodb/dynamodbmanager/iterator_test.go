@@ -0,0 +1,179 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func newTestIteratorTable(t *testing.T, n int) (*Table, *mockDynamoDB) {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = 2
+	table := newTestTable(t, svc)
+
+	for i := 0; i < n; i++ {
+		if err := table.Put(&testDocument{ID: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	return table, svc
+}
+
+func iterIDs(t *testing.T, it *Iterator) []string {
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		var doc testDocument
+		if err := it.Item(&doc); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if !doc.Loaded {
+			t.Errorf("expect AfterLoad called")
+		}
+		ids = append(ids, doc.ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return ids
+}
+
+func TestQueryIter(t *testing.T) {
+	for _, prefetch := range []int{0, 1, 3} {
+		table, svc := newTestIteratorTable(t, 5)
+
+		ids := iterIDs(t, table.Query().KeyEqual("ID", "1").Iter().Prefetch(prefetch))
+		if e, a := "[0 1 2 3 4]", fmt.Sprint(ids); e != a {
+			t.Errorf("prefetch %d, expect %v, got %v", prefetch, e, a)
+		}
+		if e, a := 3, len(svc.queries); e != a {
+			t.Errorf("prefetch %d, expect %d pages, got %d", prefetch, e, a)
+		}
+	}
+}
+
+func TestQueryIterLimit(t *testing.T) {
+	table, _ := newTestIteratorTable(t, 5)
+
+	ids := iterIDs(t, table.Query().KeyEqual("ID", "1").Limit(3).Iter())
+	if e, a := "[0 1 2]", fmt.Sprint(ids); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestScanIter(t *testing.T) {
+	table, svc := newTestIteratorTable(t, 4)
+
+	ids := iterIDs(t, table.Scan().ConsistentRead().Iter().Prefetch(2))
+	if e, a := "[0 1 2 3]", fmt.Sprint(ids); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 2, len(svc.scans); e != a {
+		t.Errorf("expect %d pages, got %d", e, a)
+	}
+}
+
+func TestIterClose(t *testing.T) {
+	table, _ := newTestIteratorTable(t, 5)
+
+	it := table.Scan().Iter().Prefetch(1)
+	if !it.Next() {
+		t.Fatalf("expect an item, got %v", it.Err())
+	}
+	it.Close()
+	it.Close()
+	if it.Next() {
+		t.Errorf("expect no items after close")
+	}
+	var doc testDocument
+	if err := it.Item(&doc); err == nil {
+		t.Errorf("expect error without a current item")
+	}
+}
+
+func TestIterErrors(t *testing.T) {
+	table, svc := newTestIteratorTable(t, 1)
+
+	it := table.Query().Iter()
+	if it.Next() {
+		t.Errorf("expect no items without a key condition")
+	}
+	if it.Err() == nil {
+		t.Errorf("expect error without a key condition")
+	}
+	if e, a := 0, len(svc.queries); e != a {
+		t.Errorf("expect %d queries, got %d", e, a)
+	}
+
+	it = table.Scan().Iter()
+	defer it.Close()
+	if !it.Next() {
+		t.Fatalf("expect an item, got %v", it.Err())
+	}
+	var order testOrder
+	if err := it.Item(&order); err == nil {
+		t.Errorf("expect error for another type")
+	}
+}
+
+func TestIterDeadline(t *testing.T) {
+	table, svc := newTestIteratorTable(t, 5)
+	svc.pageSize = 0
+
+	var limits []int64
+	svc.onScan = func(input *dynamodb.ScanInput) {
+		limits = append(limits, aws.Int64Value(input.Limit))
+	}
+
+	it := table.Scan().Iter().Deadline(time.Now().Add(time.Hour))
+	ids := iterIDs(t, it)
+	if e, a := "[0 1 2 3 4]", fmt.Sprint(ids); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	// The first page is limited to 100 items, but only 5 exist.
+	if e, a := "[100]", fmt.Sprint(limits); e != a {
+		t.Errorf("expect limits %v, got %v", e, a)
+	}
+}
+
+func TestIterDeadlineShrinksPages(t *testing.T) {
+	table, svc := newTestIteratorTable(t, 1000)
+	svc.pageSize = 0
+
+	// Each item read takes 10 milliseconds.
+	start := time.Unix(0, 0)
+	now := start
+	var limits []int64
+	svc.onQuery = func(input *dynamodb.QueryInput) {
+		limits = append(limits, aws.Int64Value(input.Limit))
+		now = now.Add(time.Duration(aws.Int64Value(input.Limit)) * 10 * time.Millisecond)
+	}
+
+	it := table.Query().KeyEqual("ID", "1").Iter().Deadline(start.Add(3 * time.Second))
+	it.now = func() time.Time { return now }
+	defer it.Close()
+
+	n := 0
+	for it.Next() {
+		n++
+	}
+	derr, ok := it.Err().(*DeadlineExceededError)
+	if !ok {
+		t.Fatalf("expect DeadlineExceededError, got %v", it.Err())
+	}
+	if e, a := int64(n), derr.Read; e != a {
+		t.Errorf("expect %d items read, got %d", e, a)
+	}
+	if e, a := 3*time.Second, now.Sub(start); a > e {
+		t.Errorf("expect pages read before the deadline, took %v", a)
+	}
+	// Each page reads the items expected in half of the time left.
+	if e, a := "[100 100 50 25 12 6 3 2 1 1]", fmt.Sprint(limits); e != a {
+		t.Errorf("expect limits %v, got %v", e, a)
+	}
+}
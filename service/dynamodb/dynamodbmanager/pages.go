@@ -0,0 +1,60 @@
+package dynamodbmanager
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// QueryPages iterates over the pages of a Query operation the same as
+// DynamoDB.QueryPages, but unmarshals each page's items, appending them to
+// out, a pointer to a slice, before calling fn. Iteration stops when fn
+// returns false, the last page has been read, or an item cannot be
+// unmarshaled. fn may be nil to read every page.
+//
+//     var orders []Order
+//     err := dynamodbmanager.QueryPages(svc, input, &orders, func(lastPage bool) bool {
+//         process(orders)
+//         orders = orders[:0]
+//         return true
+//     })
+//
+// Unlike Table.Query, the Model hooks of the items are not called.
+func QueryPages(svc dynamodbiface.DynamoDBAPI, input *dynamodb.QueryInput, out interface{}, fn func(lastPage bool) bool) error {
+	var err error
+	pagesErr := svc.QueryPages(input, func(p *dynamodb.QueryOutput, lastPage bool) bool {
+		if err = appendPage(p.Items, out); err != nil {
+			return false
+		}
+		return fn == nil || fn(lastPage)
+	})
+	if pagesErr != nil {
+		return pagesErr
+	}
+	return err
+}
+
+// ScanPages iterates over the pages of a Scan operation the same as
+// DynamoDB.ScanPages, but unmarshals each page's items, appending them to
+// out, a pointer to a slice, before calling fn. See QueryPages.
+func ScanPages(svc dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput, out interface{}, fn func(lastPage bool) bool) error {
+	var err error
+	pagesErr := svc.ScanPages(input, func(p *dynamodb.ScanOutput, lastPage bool) bool {
+		if err = appendPage(p.Items, out); err != nil {
+			return false
+		}
+		return fn == nil || fn(lastPage)
+	})
+	if pagesErr != nil {
+		return pagesErr
+	}
+	return err
+}
+
+// appendPage unmarshals the items of a page, appending them to the slice
+// out points to.
+func appendPage(items []map[string]*dynamodb.AttributeValue, out interface{}) error {
+	return dynamodbattribute.UnmarshalListOfMapsWithOptions(items, out, func(d *dynamodbattribute.Decoder) {
+		d.AppendSlices = true
+	})
+}
@@ -0,0 +1,120 @@
+package dynamodbmanager
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func newPagedMockDynamoDB(n, pageSize int) *mockDynamoDB {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = pageSize
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		svc.items[`{"ID":{"S":"`+id+`"}}`] = map[string]*dynamodb.AttributeValue{
+			"ID":   {S: aws.String(id)},
+			"Body": {S: aws.String("body " + id)},
+		}
+	}
+	return svc
+}
+
+func TestQueryPages(t *testing.T) {
+	svc := newPagedMockDynamoDB(5, 2)
+
+	var items []testDocument
+	var sizes []int
+	var last []bool
+	err := QueryPages(svc, &dynamodb.QueryInput{}, &items, func(lastPage bool) bool {
+		sizes = append(sizes, len(items))
+		last = append(last, lastPage)
+		items = items[:0]
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []int{2, 2, 1}, sizes; !intsEqual(e, a) {
+		t.Errorf("expect page sizes %v, got %v", e, a)
+	}
+	if !last[2] || last[0] || last[1] {
+		t.Errorf("expect only the final page to be last, got %v", last)
+	}
+
+	// A nil callback reads and appends every page.
+	var all []testDocument
+	if err := ScanPages(svc, &dynamodb.ScanInput{}, &all, nil); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 5, len(all); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+	if e, a := "body 4", all[4].Body; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestQueryPagesStop(t *testing.T) {
+	svc := newPagedMockDynamoDB(5, 2)
+
+	var items []testDocument
+	err := ScanPages(svc, &dynamodb.ScanInput{}, &items, func(lastPage bool) bool {
+		return false
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(items); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+
+	// Unmarshal errors stop iteration.
+	var wrong []int
+	if err := QueryPages(svc, &dynamodb.QueryInput{}, &wrong, nil); err == nil {
+		t.Errorf("expect unmarshal error")
+	}
+	if e, a := 1, len(svc.queries); e != a {
+		t.Errorf("expect %d page read, got %d", e, a)
+	}
+}
+
+func TestTableQueryPages(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = 2
+	table := newTestTable(t, svc)
+	for i := 0; i < 5; i++ {
+		if err := table.Put(&testDocument{ID: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	var items []testDocument
+	pages := 0
+	err := table.Query().KeyEqual("ID", "0").Pages(&items, func(lastPage bool) bool {
+		pages++
+		return pages < 2
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 4, len(items); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+	if !items[0].Loaded {
+		t.Errorf("expect AfterLoad called")
+	}
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbmanager
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// QueryPagesOf iterates over the pages of a Query operation, calling fn
+// with each page's items unmarshaled into a new slice of T. See QueryPages.
+//
+//     err := dynamodbmanager.QueryPagesOf(svc, input, func(orders []Order, lastPage bool) bool {
+//         process(orders)
+//         return true
+//     })
+func QueryPagesOf[T any](svc dynamodbiface.DynamoDBAPI, input *dynamodb.QueryInput, fn func(items []T, lastPage bool) bool) error {
+	var items []T
+	return QueryPages(svc, input, &items, func(lastPage bool) bool {
+		page := items
+		items = nil
+		return fn(page, lastPage)
+	})
+}
+
+// ScanPagesOf iterates over the pages of a Scan operation, calling fn with
+// each page's items unmarshaled into a new slice of T. See ScanPages.
+func ScanPagesOf[T any](svc dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput, fn func(items []T, lastPage bool) bool) error {
+	var items []T
+	return ScanPages(svc, input, &items, func(lastPage bool) bool {
+		page := items
+		items = nil
+		return fn(page, lastPage)
+	})
+}
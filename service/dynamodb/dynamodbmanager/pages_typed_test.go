@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbmanager
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestQueryPagesOf(t *testing.T) {
+	svc := newPagedMockDynamoDB(5, 2)
+
+	var pages [][]testDocument
+	err := QueryPagesOf(svc, &dynamodb.QueryInput{}, func(items []testDocument, lastPage bool) bool {
+		pages = append(pages, items)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3, len(pages); e != a {
+		t.Fatalf("expect %d pages, got %d", e, a)
+	}
+	if e, a := "0", pages[0][0].ID; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "2", pages[1][0].ID; e != a {
+		t.Errorf("expect page not overwritten, expect %v, got %v", e, a)
+	}
+
+	table, err := NewTypedTable[testDocument](svc, newTestTable(t, svc).registry)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	n := 0
+	err = table.ScanPages(func(items []testDocument, lastPage bool) bool {
+		n += len(items)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 5, n; e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+}
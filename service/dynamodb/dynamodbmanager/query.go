@@ -0,0 +1,294 @@
+package dynamodbmanager
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// A Query builds and runs a Query of a Table, unmarshaling the items read
+// into values of the Table's struct type. Create a Query with the Table's
+// Query method.
+//
+//     var orders []Order
+//     err := table.Query().
+//         KeyEqual("CustomerID", "abc").
+//         SortBetween("OrderID", 100, 200).
+//         Limit(10).
+//         All(&orders)
+type Query struct {
+	table *Table
+	key   *expression.KeyCondition
+	sort  *expression.SortCondition
+	index string
+	limit int64
+}
+
+// Query returns a Query of the table's items.
+func (t *Table) Query() *Query {
+	return &Query{table: t}
+}
+
+// KeyCondition sets the key condition selecting the items queried. Either
+// KeyCondition or KeyEqual is required.
+func (q *Query) KeyCondition(cond expression.KeyCondition) *Query {
+	q.key = &cond
+	return q
+}
+
+// KeyEqual sets the key condition to select the items whose partition key
+// attribute, name, is equal to value. See expression.KeyEqual.
+func (q *Query) KeyEqual(name string, value interface{}) *Query {
+	return q.KeyCondition(expression.KeyEqual(name, value))
+}
+
+// Sort sets the condition the items' sort key must satisfy, replacing any
+// sort condition of the key condition.
+func (q *Query) Sort(cond expression.SortCondition) *Query {
+	q.sort = &cond
+	return q
+}
+
+// SortBetween selects the items whose sort key attribute, name, is between
+// lower and upper inclusive. See expression.SortKeyBetween.
+func (q *Query) SortBetween(name string, lower, upper interface{}) *Query {
+	return q.Sort(expression.SortKeyBetween(name, lower, upper))
+}
+
+// SortBeginsWith selects the items whose string sort key attribute, name,
+// begins with prefix. See expression.SortKeyBeginsWith.
+func (q *Query) SortBeginsWith(name string, prefix string) *Query {
+	return q.Sort(expression.SortKeyBeginsWith(name, prefix))
+}
+
+// Index sets the name of the secondary index to query instead of the
+// table.
+func (q *Query) Index(name string) *Query {
+	q.index = name
+	return q
+}
+
+// Limit sets the maximum number of items All reads. Zero, the default, is
+// no limit.
+func (q *Query) Limit(n int64) *Query {
+	q.limit = n
+	return q
+}
+
+// input returns the QueryInput of the Query.
+func (q *Query) input() (*dynamodb.QueryInput, error) {
+	if q.key == nil {
+		return nil, &InvalidModelError{Type: q.table.typ, msg: "query requires a key condition"}
+	}
+	key := *q.key
+	if q.sort != nil {
+		key = key.AndSort(*q.sort)
+	}
+	expr, err := key.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(q.table.model.TableName),
+		KeyConditionExpression:    aws.String(expr.Expression),
+		ExpressionAttributeNames:  expr.Names,
+		ExpressionAttributeValues: expr.Values,
+	}
+	if q.index != "" {
+		input.IndexName = aws.String(q.index)
+	}
+	return input, nil
+}
+
+// All reads every page of the Query's results, appending the items to out,
+// a pointer to a slice of the Table's struct type or pointers to it. If a
+// Limit is set, no more than Limit items are read.
+func (q *Query) All(out interface{}) error {
+	return q.Pages(out, nil)
+}
+
+// Pages reads the pages of the Query's results, appending each page's
+// items to out, the same as All, and then calling fn. Iteration stops when
+// fn returns false or the last page has been read. fn may reslice the slice
+// out points to, such as to its first zero elements to only hold one page
+// of items at a time.
+//
+//     var page []Order
+//     err := table.Query().KeyEqual("CustomerID", "abc").Pages(&page, func(lastPage bool) bool {
+//         process(page)
+//         page = page[:0]
+//         return true
+//     })
+func (q *Query) Pages(out interface{}, fn func(lastPage bool) bool) error {
+	input, err := q.input()
+	if err != nil {
+		return err
+	}
+	if err := q.table.checkSlice(out); err != nil {
+		return err
+	}
+
+	remaining := q.limit
+	for {
+		if q.limit > 0 {
+			input.Limit = aws.Int64(remaining)
+		}
+		resp, err := q.table.svc.Query(input)
+		if err != nil {
+			return err
+		}
+		if err := q.table.appendItems(resp.Items, out); err != nil {
+			return err
+		}
+		remaining -= int64(len(resp.Items))
+		lastPage := len(resp.LastEvaluatedKey) == 0 || (q.limit > 0 && remaining <= 0)
+		if fn != nil && !fn(lastPage) || lastPage {
+			return nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// A Scan builds and runs a Scan of a Table, unmarshaling the items read
+// into values of the Table's struct type. Create a Scan with the Table's
+// Scan method.
+//
+//     var orders []Order
+//     err := table.Scan().
+//         Filter(expression.Expression{
+//             Expression: "#status = :status",
+//             Names:      map[string]*string{"#status": aws.String("Status")},
+//             Values: map[string]*dynamodb.AttributeValue{
+//                 ":status": {S: aws.String("shipped")},
+//             },
+//         }).
+//         ConsistentRead().
+//         All(&orders)
+type Scan struct {
+	table      *Table
+	filter     *expression.Expression
+	consistent bool
+}
+
+// Scan returns a Scan of the table's items.
+func (t *Table) Scan() *Scan {
+	return &Scan{table: t}
+}
+
+// Filter sets the filter expression items must match to be returned. The
+// expression's Names and Values are the Scan's ExpressionAttributeNames and
+// ExpressionAttributeValues. Items are filtered after they are read, so
+// they still consume read capacity.
+func (s *Scan) Filter(expr expression.Expression) *Scan {
+	s.filter = &expr
+	return s
+}
+
+// ConsistentRead sets the Scan to use strongly consistent reads.
+func (s *Scan) ConsistentRead() *Scan {
+	s.consistent = true
+	return s
+}
+
+// input returns the ScanInput of the Scan.
+func (s *Scan) input() (*dynamodb.ScanInput, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(s.table.model.TableName),
+	}
+	if s.filter != nil {
+		if s.filter.Expression == "" {
+			return nil, &InvalidModelError{Type: s.table.typ, msg: "scan filter expression is empty"}
+		}
+		input.FilterExpression = aws.String(s.filter.Expression)
+		input.ExpressionAttributeNames = s.filter.Names
+		input.ExpressionAttributeValues = s.filter.Values
+	}
+	if s.consistent {
+		input.ConsistentRead = aws.Bool(true)
+	}
+	return input, nil
+}
+
+// All reads every page of the Scan's results, appending the items to out,
+// a pointer to a slice of the Table's struct type or pointers to it.
+func (s *Scan) All(out interface{}) error {
+	return s.Pages(out, nil)
+}
+
+// Pages reads the pages of the Scan's results, appending each page's items
+// to out, the same as All, and then calling fn. Iteration stops when fn
+// returns false or the last page has been read. See Query.Pages.
+func (s *Scan) Pages(out interface{}, fn func(lastPage bool) bool) error {
+	input, err := s.input()
+	if err != nil {
+		return err
+	}
+	if err := s.table.checkSlice(out); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := s.table.svc.Scan(input)
+		if err != nil {
+			return err
+		}
+		if err := s.table.appendItems(resp.Items, out); err != nil {
+			return err
+		}
+		lastPage := len(resp.LastEvaluatedKey) == 0
+		if fn != nil && !fn(lastPage) || lastPage {
+			return nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// checkSlice returns an error if out is not a pointer to a slice of the
+// Table's struct type, or of pointers to it.
+func (t *Table) checkSlice(out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return &InvalidModelError{Type: reflect.TypeOf(out), msg: "items must be read into a pointer to a slice"}
+	}
+	elem := v.Elem().Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem != t.typ {
+		return &InvalidModelError{Type: elem, msg: "items must be read into a slice of the table's type " + t.typ.String()}
+	}
+	return nil
+}
+
+// appendItems unmarshals the items, appending them to the slice out points
+// to, and calls the AfterLoad hook of each item appended.
+func (t *Table) appendItems(items []map[string]*dynamodb.AttributeValue, out interface{}) error {
+	slice := reflect.ValueOf(out).Elem()
+	n := slice.Len()
+
+	err := dynamodbattribute.UnmarshalListOfMapsWithOptions(items, out, func(d *dynamodbattribute.Decoder) {
+		d.AppendSlices = true
+	})
+	if err != nil {
+		return err
+	}
+
+	if t.model.AfterLoad == nil {
+		return nil
+	}
+	for i := n; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		if err := t.model.AfterLoad(elem.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,607 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// A Model describes how a Go struct type is stored in a DynamoDB table.
+// Models are registered once with a Registry, and helpers then resolve the
+// table name, key schema, and hooks from the Registry instead of requiring
+// them for every call.
+type Model struct {
+	// Name of the table items of the model are stored in. Required.
+	TableName string
+
+	// Attribute name of the table's partition key. Required.
+	HashKey string
+
+	// Attribute name of the table's sort key, if the table has one.
+	RangeKey string
+
+	// Secondary indexes of the table.
+	Indexes []Index
+
+	// Called with a pointer to the item before it is marshaled to be
+	// written to the table. Returning an error prevents the write.
+	BeforeSave func(item interface{}) error
+
+	// Called with a pointer to the item after it has been unmarshaled
+	// from the table. Returning an error fails the read.
+	AfterLoad func(item interface{}) error
+}
+
+// An Index describes a secondary index of a Model's table.
+type Index struct {
+	// Name of the index.
+	Name string
+
+	// Attribute name of the index's partition key.
+	HashKey string
+
+	// Attribute name of the index's sort key, if the index has one.
+	RangeKey string
+}
+
+// Index returns the Model's secondary index with the name provided.
+func (m Model) Index(name string) (Index, bool) {
+	for _, idx := range m.Indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+
+	return Index{}, false
+}
+
+// A Registry maps Go struct types to the Model describing how they are
+// stored. It is safe to use a Registry concurrently across goroutines.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[reflect.Type]Model
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{models: map[reflect.Type]Model{}}
+}
+
+// Register registers the Model for the struct type of v. v can be a value
+// or pointer of the struct type, and is only used for its type. Registering
+// a type which is already registered replaces its Model.
+//
+// If the Model's HashKey is empty, the HashKey and RangeKey are the names
+// of the type's fields with the `hashkey` and `rangekey` struct tag
+// options. See dynamodbattribute.KeyOf.
+//
+// Example:
+//     registry := dynamodbmanager.NewRegistry()
+//     err := registry.Register(Order{}, dynamodbmanager.Model{
+//         TableName: "orders",
+//         HashKey:   "CustomerID",
+//         RangeKey:  "OrderID",
+//     })
+func (r *Registry) Register(v interface{}, m Model) error {
+	t, err := structType(v)
+	if err != nil {
+		return err
+	}
+	if len(m.TableName) == 0 {
+		return &InvalidModelError{Type: t, msg: "table name must not be empty"}
+	}
+	if len(m.HashKey) == 0 {
+		hashKey, rangeKey, err := dynamodbattribute.KeyAttributeNames(v)
+		if err != nil {
+			return &InvalidModelError{Type: t, msg: err.Error()}
+		}
+		m.HashKey, m.RangeKey = hashKey, rangeKey
+	}
+	if len(m.HashKey) == 0 {
+		return &InvalidModelError{Type: t, msg: "hash key must not be empty"}
+	}
+	for _, idx := range m.Indexes {
+		if len(idx.Name) == 0 || len(idx.HashKey) == 0 {
+			return &InvalidModelError{Type: t, msg: "index name and hash key must not be empty"}
+		}
+	}
+
+	m.Indexes = append([]Index{}, m.Indexes...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models[t] = m
+
+	return nil
+}
+
+// Model returns the Model registered for the struct type of v.
+func (r *Registry) Model(v interface{}) (Model, error) {
+	t, err := structType(v)
+	if err != nil {
+		return Model{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.models[t]
+	if !ok {
+		return Model{}, &UnregisteredModelError{Type: t}
+	}
+
+	return m, nil
+}
+
+// MarshalItem calls the BeforeSave hook of item's Model, and returns the
+// item marshaled into an AttributeValue map along with the Model. item
+// must be a pointer so BeforeSave can modify it.
+func (r *Registry) MarshalItem(item interface{}) (map[string]*dynamodb.AttributeValue, Model, error) {
+	return r.marshalItem(item)
+}
+
+func (r *Registry) marshalItem(item interface{}, opts ...func(*dynamodbattribute.Encoder)) (map[string]*dynamodb.AttributeValue, Model, error) {
+	m, err := r.Model(item)
+	if err != nil {
+		return nil, Model{}, err
+	}
+
+	if m.BeforeSave != nil {
+		if err := m.BeforeSave(item); err != nil {
+			return nil, Model{}, err
+		}
+	}
+
+	av, err := dynamodbattribute.MarshalMapWithOptions(item, opts...)
+	if err != nil {
+		return nil, Model{}, err
+	}
+
+	return av, m, nil
+}
+
+// UnmarshalItem unmarshals the AttributeValue map into out, and calls the
+// AfterLoad hook of out's Model. out must be a pointer to a registered
+// struct type.
+func (r *Registry) UnmarshalItem(item map[string]*dynamodb.AttributeValue, out interface{}) error {
+	m, err := r.Model(out)
+	if err != nil {
+		return err
+	}
+
+	if err := dynamodbattribute.UnmarshalMap(item, out); err != nil {
+		return err
+	}
+
+	if m.AfterLoad != nil {
+		return m.AfterLoad(out)
+	}
+
+	return nil
+}
+
+// Key returns the primary key attributes of item, as defined by its Model.
+func (r *Registry) Key(item interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	m, err := r.Model(item)
+	if err != nil {
+		return nil, err
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.key(av)
+}
+
+// PutItemInput returns the PutItemInput for writing item to its Model's
+// table. The item's BeforeSave hook is called first.
+//
+// If item's fields have the `immutable` or `writeonce` struct tag options
+// the input's ConditionExpression enforces them. A field with the `version`
+// tag option is written incremented by one, and the write is conditional
+// on the stored version being the field's current value. Increment the
+// field once the write succeeds.
+func (r *Registry) PutItemInput(item interface{}) (*dynamodb.PutItemInput, error) {
+	av, m, err := r.marshalItem(item, func(e *dynamodbattribute.Encoder) {
+		e.IncrementVersion = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.key(av); err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(m.TableName),
+		Item:      av,
+	}
+
+	if cond := expression.WriteCondition(av, dynamodbattribute.WriteRules(item)); len(cond.Expression) != 0 {
+		input.ConditionExpression = aws.String(cond.Expression)
+		input.ExpressionAttributeNames = cond.Names
+		input.ExpressionAttributeValues = cond.Values
+	}
+
+	return input, nil
+}
+
+// UpdateItemInput returns the UpdateItemInput for writing the changes from
+// oldItem, the item as it was read from the table, to newItem. The input's
+// UpdateExpression only SETs the attributes which were added or changed,
+// and REMOVEs the attributes which were removed, instead of rewriting the
+// whole item. newItem's BeforeSave hook is called first. The items must be
+// of the same type, and have the same primary key.
+//
+// The input's UpdateExpression is nil if the items do not differ. The
+// write rules of newItem's fields are enforced by the input's
+// ConditionExpression the same as by PutItemInput, except `immutable` and
+// `writeonce` fields are only checked if they are updated.
+func (r *Registry) UpdateItemInput(oldItem, newItem interface{}) (*dynamodb.UpdateItemInput, error) {
+	input, _, _, err := r.updateItemInput(oldItem, newItem)
+	return input, err
+}
+
+// updateItemInput returns the UpdateItemInput, newItem marshaled as it will
+// be written, and the write rules enforced by the input's condition.
+func (r *Registry) updateItemInput(oldItem, newItem interface{}) (*dynamodb.UpdateItemInput, map[string]*dynamodb.AttributeValue, []dynamodbattribute.WriteRule, error) {
+	oldType, err := structType(oldItem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if newType, err := structType(newItem); err != nil {
+		return nil, nil, nil, err
+	} else if oldType != newType {
+		return nil, nil, nil, &InvalidModelError{Type: newType, msg: "updated item must be the same type as " + oldType.String()}
+	}
+
+	oldAV, err := dynamodbattribute.MarshalMap(oldItem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	newAV, m, err := r.marshalItem(newItem, func(e *dynamodbattribute.Encoder) {
+		e.IncrementVersion = true
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := m.key(newAV)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	versions := map[string]bool{}
+	for _, rule := range dynamodbattribute.WriteRules(newItem) {
+		if rule.Kind == dynamodbattribute.WriteRuleVersion {
+			versions[rule.Name] = true
+		}
+	}
+
+	// The version attributes always differ, as newItem's are incremented,
+	// so they are only written along with other changes.
+	var diffs, versionDiffs []dynamodbattribute.AttributeDiff
+	updated := map[string]bool{}
+	for _, d := range dynamodbattribute.Diff(oldAV, newAV) {
+		if _, ok := key[d.Path[0]]; ok {
+			return nil, nil, nil, &KeyChangedError{TableName: m.TableName, Attribute: d.Path[0]}
+		}
+		if versions[d.Path[0]] {
+			versionDiffs = append(versionDiffs, d)
+			continue
+		}
+		diffs = append(diffs, d)
+		updated[d.Path[0]] = true
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.TableName),
+		Key:       key,
+	}
+	if len(diffs) == 0 {
+		return input, newAV, nil, nil
+	}
+	diffs = append(diffs, versionDiffs...)
+
+	update := expression.UpdateFromDiff(diffs)
+	input.UpdateExpression = aws.String(update.Expression)
+	input.ExpressionAttributeNames = update.Names
+	input.ExpressionAttributeValues = update.Values
+
+	var rules []dynamodbattribute.WriteRule
+	for _, rule := range dynamodbattribute.WriteRules(newItem) {
+		if rule.Kind == dynamodbattribute.WriteRuleVersion || updated[rule.Name] {
+			rules = append(rules, rule)
+		}
+	}
+	if cond := expression.WriteCondition(newAV, rules); len(cond.Expression) != 0 {
+		input.ConditionExpression = aws.String(cond.Expression)
+		for k, v := range cond.Names {
+			input.ExpressionAttributeNames[k] = v
+		}
+		if len(cond.Values) != 0 && input.ExpressionAttributeValues == nil {
+			input.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{}
+		}
+		for k, v := range cond.Values {
+			input.ExpressionAttributeValues[k] = v
+		}
+	}
+
+	return input, newAV, rules, nil
+}
+
+// GetItemInput returns the GetItemInput for reading the item with the same
+// primary key as key from its Model's table. Only the key attributes of
+// key need to be set.
+func (r *Registry) GetItemInput(key interface{}) (*dynamodb.GetItemInput, error) {
+	m, err := r.Model(key)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := r.Key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.GetItemInput{
+		TableName: aws.String(m.TableName),
+		Key:       k,
+	}, nil
+}
+
+// DeleteItemInput returns the DeleteItemInput for deleting the item with
+// the same primary key as key from its Model's table. Only the key
+// attributes of key need to be set.
+func (r *Registry) DeleteItemInput(key interface{}) (*dynamodb.DeleteItemInput, error) {
+	m, err := r.Model(key)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := r.Key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.TableName),
+		Key:       k,
+	}, nil
+}
+
+// CreateTableInput returns the CreateTableInput for creating the table of
+// the Model registered for the struct type of v, with the Model's key
+// schema, and a global secondary index projecting all attributes for each
+// of the Model's Indexes. The types of the key attributes are those of the
+// struct fields they are marshaled from. See
+// dynamodbattribute.KeyAttributeType.
+//
+// The table and its indexes are provisioned with 1 read and 1 write
+// capacity unit, which suits tests against DynamoDB Local. Change the
+// input's ProvisionedThroughput for other uses.
+func (r *Registry) CreateTableInput(v interface{}) (*dynamodb.CreateTableInput, error) {
+	m, err := r.Model(v)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName:             aws.String(m.TableName),
+		ProvisionedThroughput: testThroughput(),
+	}
+	defined := map[string]bool{}
+	keySchema := func(hashKey, rangeKey string) ([]*dynamodb.KeySchemaElement, error) {
+		var schema []*dynamodb.KeySchemaElement
+		for _, k := range []struct{ name, keyType string }{
+			{hashKey, dynamodb.KeyTypeHash},
+			{rangeKey, dynamodb.KeyTypeRange},
+		} {
+			if len(k.name) == 0 {
+				continue
+			}
+			schema = append(schema, &dynamodb.KeySchemaElement{
+				AttributeName: aws.String(k.name),
+				KeyType:       aws.String(k.keyType),
+			})
+			if defined[k.name] {
+				continue
+			}
+			typ, err := dynamodbattribute.KeyAttributeType(v, k.name)
+			if err != nil {
+				return nil, err
+			}
+			input.AttributeDefinitions = append(input.AttributeDefinitions, &dynamodb.AttributeDefinition{
+				AttributeName: aws.String(k.name),
+				AttributeType: aws.String(typ),
+			})
+			defined[k.name] = true
+		}
+		return schema, nil
+	}
+
+	if input.KeySchema, err = keySchema(m.HashKey, m.RangeKey); err != nil {
+		return nil, err
+	}
+	for _, idx := range m.Indexes {
+		schema, err := keySchema(idx.HashKey, idx.RangeKey)
+		if err != nil {
+			return nil, err
+		}
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndex{
+			IndexName:             aws.String(idx.Name),
+			KeySchema:             schema,
+			Projection:            &dynamodb.Projection{ProjectionType: aws.String(dynamodb.ProjectionTypeAll)},
+			ProvisionedThroughput: testThroughput(),
+		})
+	}
+
+	return input, nil
+}
+
+func testThroughput() *dynamodb.ProvisionedThroughput {
+	return &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(1),
+		WriteCapacityUnits: aws.Int64(1),
+	}
+}
+
+func (m Model) key(item map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	key := map[string]*dynamodb.AttributeValue{}
+	for _, name := range []string{m.HashKey, m.RangeKey} {
+		if len(name) == 0 {
+			continue
+		}
+
+		av, ok := item[name]
+		if !ok || (av.S == nil && av.N == nil && av.B == nil) {
+			return nil, &MissingKeyError{TableName: m.TableName, Attribute: name}
+		}
+		key[name] = av
+	}
+
+	return key, nil
+}
+
+func structType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, &InvalidModelError{Type: t, msg: "model must be a struct type"}
+	}
+
+	return t, nil
+}
+
+// An InvalidModelError is an error type representing a Model which cannot
+// be registered.
+type InvalidModelError struct {
+	Type reflect.Type
+	msg  string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidModelError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidModelError) Code() string {
+	return "InvalidModelError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidModelError) Message() string {
+	if e.Type == nil {
+		return e.msg
+	}
+	return e.msg + ", " + e.Type.String()
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *InvalidModelError) OrigErr() error {
+	return nil
+}
+
+// An UnregisteredModelError is an error type representing a Go value type
+// which has no Model registered.
+type UnregisteredModelError struct {
+	Type reflect.Type
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *UnregisteredModelError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *UnregisteredModelError) Code() string {
+	return "UnregisteredModelError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *UnregisteredModelError) Message() string {
+	return "no model registered for Go value type " + e.Type.String()
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *UnregisteredModelError) OrigErr() error {
+	return nil
+}
+
+// A MissingKeyError is an error type representing an item which does not
+// have a non-empty value for one of its table's key attributes.
+type MissingKeyError struct {
+	TableName string
+	Attribute string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *MissingKeyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *MissingKeyError) Code() string {
+	return "MissingKeyError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *MissingKeyError) Message() string {
+	return "item for table " + e.TableName + " is missing key attribute " + e.Attribute
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *MissingKeyError) OrigErr() error {
+	return nil
+}
+
+// A KeyChangedError is an error type representing an update to an item
+// which changes one of its table's key attributes. Key attributes cannot be
+// updated, the item must be deleted and put with the new key instead.
+type KeyChangedError struct {
+	TableName string
+	Attribute string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *KeyChangedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *KeyChangedError) Code() string {
+	return "KeyChangedError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *KeyChangedError) Message() string {
+	return "update of item for table " + e.TableName + " changes key attribute " + e.Attribute
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *KeyChangedError) OrigErr() error {
+	return nil
+}
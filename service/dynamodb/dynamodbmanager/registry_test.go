@@ -0,0 +1,410 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type testOrder struct {
+	CustomerID string
+	OrderID    int
+	Status     string `dynamodbav:",omitempty"`
+	Loaded     bool   `dynamodbav:"-"`
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	err := r.Register(&testOrder{}, Model{
+		TableName: "orders",
+		HashKey:   "CustomerID",
+		RangeKey:  "OrderID",
+		Indexes: []Index{
+			{Name: "status-index", HashKey: "Status"},
+		},
+		BeforeSave: func(item interface{}) error {
+			o := item.(*testOrder)
+			if len(o.Status) == 0 {
+				o.Status = "NEW"
+			}
+			return nil
+		},
+		AfterLoad: func(item interface{}) error {
+			item.(*testOrder).Loaded = true
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	return r
+}
+
+func TestRegistryRegisterInvalid(t *testing.T) {
+	cases := []struct {
+		v      interface{}
+		m      Model
+		expect string
+	}{
+		{"abc", Model{TableName: "t", HashKey: "k"}, "InvalidModelError: model must be a struct type, string"},
+		{nil, Model{TableName: "t", HashKey: "k"}, "InvalidModelError: model must be a struct type"},
+		{testOrder{}, Model{HashKey: "k"}, "InvalidModelError: table name must not be empty, dynamodbmanager.testOrder"},
+		{testOrder{}, Model{TableName: "t"}, "InvalidModelError: hash key must not be empty, dynamodbmanager.testOrder"},
+		{testOrder{}, Model{TableName: "t", HashKey: "k", Indexes: []Index{{Name: "i"}}},
+			"InvalidModelError: index name and hash key must not be empty, dynamodbmanager.testOrder"},
+	}
+
+	for i, c := range cases {
+		err := NewRegistry().Register(c.v, c.m)
+		if err == nil {
+			t.Errorf("case %d, expect error, got none", i)
+		} else if e, a := c.expect, err.Error(); e != a {
+			t.Errorf("case %d, expect %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestRegistryModel(t *testing.T) {
+	r := newTestRegistry(t)
+
+	m, err := r.Model(testOrder{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "orders", m.TableName; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	idx, ok := m.Index("status-index")
+	if !ok {
+		t.Fatalf("expect index to be found")
+	}
+	if e, a := "Status", idx.HashKey; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	_, err = r.Model(struct{ A int }{})
+	if _, ok := err.(*UnregisteredModelError); !ok {
+		t.Errorf("expect *UnregisteredModelError, got %T", err)
+	}
+}
+
+func TestRegistryPutItemInput(t *testing.T) {
+	r := newTestRegistry(t)
+
+	o := &testOrder{CustomerID: "abc", OrderID: 1}
+	input, err := r.PutItemInput(o)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := &dynamodb.PutItemInput{
+		TableName: aws.String("orders"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"CustomerID": {S: aws.String("abc")},
+			"OrderID":    {N: aws.String("1")},
+			"Status":     {S: aws.String("NEW")},
+		},
+	}
+	if !reflect.DeepEqual(expect, input) {
+		t.Errorf("expect %v, got %v", expect, input)
+	}
+	if e, a := "NEW", o.Status; e != a {
+		t.Errorf("expect BeforeSave to modify item, %v, got %v", e, a)
+	}
+
+	_, err = r.PutItemInput(&testOrder{OrderID: 1})
+	if e, a := "MissingKeyError: item for table orders is missing key attribute CustomerID", fmt.Sprint(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestRegistryBeforeSaveError(t *testing.T) {
+	r := NewRegistry()
+	r.Register(testOrder{}, Model{
+		TableName: "orders", HashKey: "CustomerID",
+		BeforeSave: func(interface{}) error { return fmt.Errorf("rejected") },
+	})
+
+	if _, err := r.PutItemInput(&testOrder{CustomerID: "abc"}); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
+
+func TestRegistryKeyInputs(t *testing.T) {
+	r := newTestRegistry(t)
+
+	expectKey := map[string]*dynamodb.AttributeValue{
+		"CustomerID": {S: aws.String("abc")},
+		"OrderID":    {N: aws.String("2")},
+	}
+
+	get, err := r.GetItemInput(testOrder{CustomerID: "abc", OrderID: 2, Status: "DONE"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "orders", *get.TableName; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := expectKey, get.Key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	del, err := r.DeleteItemInput(&testOrder{CustomerID: "abc", OrderID: 2})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expectKey, del.Key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryUnmarshalItem(t *testing.T) {
+	r := newTestRegistry(t)
+
+	var o testOrder
+	err := r.UnmarshalItem(map[string]*dynamodb.AttributeValue{
+		"CustomerID": {S: aws.String("abc")},
+		"OrderID":    {N: aws.String("2")},
+	}, &o)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := testOrder{CustomerID: "abc", OrderID: 2, Loaded: true}
+	if !reflect.DeepEqual(expect, o) {
+		t.Errorf("expect %v, got %v", expect, o)
+	}
+}
+
+func TestRegistryPutItemInputWriteRules(t *testing.T) {
+	type account struct {
+		ID      string
+		Created string `dynamodbav:",immutable"`
+		Owner   string `dynamodbav:",writeonce"`
+	}
+
+	r := NewRegistry()
+	if err := r.Register(account{}, Model{TableName: "accounts", HashKey: "ID"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	input, err := r.PutItemInput(account{ID: "abc", Created: "today", Owner: "bob"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "(attribute_not_exists(#w0) OR #w0 = :w0) AND attribute_not_exists(#w1)", aws.StringValue(input.ConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectNames := map[string]*string{"#w0": aws.String("Created"), "#w1": aws.String("Owner")}
+	if e, a := expectNames, input.ExpressionAttributeNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectValues := map[string]*dynamodb.AttributeValue{":w0": {S: aws.String("today")}}
+	if e, a := expectValues, input.ExpressionAttributeValues; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryPutItemInputVersion(t *testing.T) {
+	type document struct {
+		ID      string
+		Version int `dynamodbav:",version"`
+	}
+
+	r := NewRegistry()
+	if err := r.Register(document{}, Model{TableName: "documents", HashKey: "ID"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	input, err := r.PutItemInput(&document{ID: "abc", Version: 1})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "2", aws.StringValue(input.Item["Version"].N); e != a {
+		t.Errorf("expect version %v, got %v", e, a)
+	}
+	if e, a := "#w0 = :w0", aws.StringValue(input.ConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectValues := map[string]*dynamodb.AttributeValue{":w0": {N: aws.String("1")}}
+	if e, a := expectValues, input.ExpressionAttributeValues; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryUpdateItemInput(t *testing.T) {
+	r := newTestRegistry(t)
+
+	oldItem := testOrder{CustomerID: "abc", OrderID: 1, Status: "NEW"}
+	newItem := oldItem
+	newItem.Status = "SHIPPED"
+
+	input, err := r.UpdateItemInput(oldItem, &newItem)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := &dynamodb.UpdateItemInput{
+		TableName: aws.String("orders"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"CustomerID": {S: aws.String("abc")},
+			"OrderID":    {N: aws.String("1")},
+		},
+		UpdateExpression:          aws.String("SET #u0 = :u0"),
+		ExpressionAttributeNames:  map[string]*string{"#u0": aws.String("Status")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":u0": {S: aws.String("SHIPPED")}},
+	}
+	if e, a := expect, input; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Unchanged items have no update expression.
+	input, err = r.UpdateItemInput(oldItem, &oldItem)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if input.UpdateExpression != nil {
+		t.Errorf("expect no update expression, got %v", *input.UpdateExpression)
+	}
+
+	newItem = oldItem
+	newItem.OrderID = 2
+	_, err = r.UpdateItemInput(oldItem, &newItem)
+	if e, a := "KeyChangedError: update of item for table orders changes key attribute OrderID", fmt.Sprint(err); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	type other struct{ CustomerID string }
+	if _, err := r.UpdateItemInput(oldItem, &other{}); err == nil {
+		t.Errorf("expect error for mismatched types")
+	}
+}
+
+func TestRegistryUpdateItemInputWriteRules(t *testing.T) {
+	type document struct {
+		ID      string
+		Created string `dynamodbav:",immutable"`
+		Owner   string `dynamodbav:",writeonce"`
+		Body    string
+		Version int `dynamodbav:",version"`
+	}
+
+	r := NewRegistry()
+	if err := r.Register(document{}, Model{TableName: "documents", HashKey: "ID"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	oldItem := document{ID: "abc", Created: "today", Owner: "bob", Body: "a", Version: 1}
+	newItem := oldItem
+	newItem.Body = "b"
+	newItem.Owner = "alice"
+
+	input, err := r.UpdateItemInput(oldItem, &newItem)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Only the updated writeonce field and the version are conditions.
+	if e, a := "SET #u0 = :u0, #u1 = :u1, #u2 = :u2", aws.StringValue(input.UpdateExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "attribute_not_exists(#w0) AND #w1 = :w0", aws.StringValue(input.ConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectNames := map[string]*string{
+		"#u0": aws.String("Body"), "#u1": aws.String("Owner"), "#u2": aws.String("Version"),
+		"#w0": aws.String("Owner"), "#w1": aws.String("Version"),
+	}
+	if e, a := expectNames, input.ExpressionAttributeNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectValues := map[string]*dynamodb.AttributeValue{
+		":u0": {S: aws.String("b")}, ":u1": {S: aws.String("alice")}, ":u2": {N: aws.String("2")},
+		":w0": {N: aws.String("1")},
+	}
+	if e, a := expectValues, input.ExpressionAttributeValues; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryRegisterKeyTags(t *testing.T) {
+	type event struct {
+		Stream  string `dynamodbav:"stream,hashkey"`
+		Created int64  `dynamodbav:"created,rangekey"`
+		Body    string
+	}
+
+	r := NewRegistry()
+	if err := r.Register(event{}, Model{TableName: "events"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	m, err := r.Model(event{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "stream", m.HashKey; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "created", m.RangeKey; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	input, err := r.DeleteItemInput(event{Stream: "a", Created: 5})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]*dynamodb.AttributeValue{
+		"stream":  {S: aws.String("a")},
+		"created": {N: aws.String("5")},
+	}
+	if e, a := expect, input.Key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegistryCreateTableInput(t *testing.T) {
+	r := newTestRegistry(t)
+
+	input, err := r.CreateTableInput(testOrder{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "orders", aws.StringValue(input.TableName); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var schema []string
+	for _, k := range input.KeySchema {
+		schema = append(schema, *k.AttributeName+" "+*k.KeyType)
+	}
+	if e, a := []string{"CustomerID HASH", "OrderID RANGE"}, schema; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var defs []string
+	for _, d := range input.AttributeDefinitions {
+		defs = append(defs, *d.AttributeName+" "+*d.AttributeType)
+	}
+	if e, a := []string{"CustomerID S", "OrderID N", "Status S"}, defs; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 1, len(input.GlobalSecondaryIndexes); e != a {
+		t.Fatalf("expect %d indexes, got %d", e, a)
+	}
+	idx := input.GlobalSecondaryIndexes[0]
+	if e, a := "status-index", aws.StringValue(idx.IndexName); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "Status", aws.StringValue(idx.KeySchema[0].AttributeName); len(idx.KeySchema) != 1 || e != a {
+		t.Errorf("expect index key %v, got %v", e, idx.KeySchema)
+	}
+
+	if _, err := r.CreateTableInput(struct{ ID string }{}); err == nil {
+		t.Errorf("expect error for unregistered type")
+	}
+}
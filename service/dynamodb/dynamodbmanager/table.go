@@ -0,0 +1,465 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// A Table reads and writes the items of a registered Model's table as
+// values of the Model's Go struct type. Items are marshaled and the
+// Model's hooks called the same as by the Registry's helpers, and the
+// write rules of the type's fields are enforced.
+//
+//     orders, err := dynamodbmanager.NewTable(svc, registry, Order{})
+//     if err != nil {
+//         return err
+//     }
+//
+//     err = orders.Put(&Order{CustomerID: "abc", OrderID: 1})
+//
+//     var order Order
+//     found, err := orders.Get(Order{CustomerID: "abc", OrderID: 1}, &order)
+//
+// A Table is safe for concurrent use once configured.
+type Table struct {
+	// Items read by Get are read with strongly consistent reads.
+	//
+	// Defaults to false.
+	ConsistentRead bool
+
+	// The maximum number of times the unprocessed keys or items of a batch
+	// request are retried before the batch fails.
+	//
+	// Defaults to DefaultMaxBatchRetries.
+	MaxBatchRetries int
+
+	// Function used to sleep before the unprocessed keys or items of a
+	// batch request are retried.
+	//
+	// Defaults to time.Sleep.
+	SleepDelay func(time.Duration)
+
+	// Groups the keys read by BatchGet, such as by partition. The keys are
+	// requested taking a key from each group in turn, so the keys of a
+	// group are spread across requests. See PartitionKeyGrouper and
+	// HashGrouper.
+	//
+	// Defaults to nil, which requests keys in the order given.
+	BatchGrouper BatchGrouper
+
+	// Called with the latency of each BatchGetItem request of a BatchGet,
+	// including retries of unprocessed keys, for each group of the keys
+	// requested, if BatchGrouper is set.
+	//
+	// Defaults to nil.
+	BatchGroupLatency func(group string, keys int, latency time.Duration)
+
+	svc      dynamodbiface.DynamoDBAPI
+	registry *Registry
+	typ      reflect.Type
+	model    Model
+}
+
+// DefaultMaxBatchRetries is the default value of a Table's MaxBatchRetries.
+const DefaultMaxBatchRetries = 10
+
+// NewTable returns the Table for reading and writing items of the struct
+// type of v, which must be registered with the Registry.
+func NewTable(svc dynamodbiface.DynamoDBAPI, r *Registry, v interface{}) (*Table, error) {
+	t, err := structType(v)
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.Model(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{
+		MaxBatchRetries: DefaultMaxBatchRetries,
+		svc:             svc,
+		registry:        r,
+		typ:             t,
+		model:           m,
+	}, nil
+}
+
+// Model returns the Model of the Table's items.
+func (t *Table) Model() Model {
+	return t.model
+}
+
+// Put writes item to the table, replacing the item with the same primary
+// key. item should be a pointer, so the BeforeSave hook can modify it, and
+// any field with the `version` struct tag option is updated to the version
+// written.
+//
+// The version fields of item implement optimistic locking. The write fails
+// if the stored item's version is not the version of item, or if item's
+// version is zero and the item already exists. A VersionConflictError with
+// the stored item is returned when it does. If the item's fields have other
+// write rules, such as the `immutable` tag option, a ConditionFailedError
+// is returned instead, as the version may not be the condition which
+// failed.
+func (t *Table) Put(item interface{}) error {
+	if err := t.checkType(item); err != nil {
+		return err
+	}
+
+	input, err := t.registry.PutItemInput(item)
+	if err != nil {
+		return err
+	}
+	if _, err := t.svc.PutItem(input); err != nil {
+		return t.writeError(item, dynamodbattribute.WriteRules(item), err)
+	}
+
+	return updateVersions(item, input.Item)
+}
+
+// Create writes item to the table if the table has no item with the same
+// primary key, returning an AlreadyExistsError if it does. item should be
+// a pointer, the same as for Put.
+//
+// The write rules of item's fields are also enforced, the same as for Put.
+// If the table has no item with the key, but the write's condition failed,
+// the error for the write rules is returned, such as a
+// VersionConflictError if item's version is not zero.
+func (t *Table) Create(item interface{}) error {
+	if err := t.checkType(item); err != nil {
+		return err
+	}
+
+	input, err := t.registry.PutItemInput(item)
+	if err != nil {
+		return err
+	}
+
+	// The key placeholders are prefixed "c" so they cannot collide with the
+	// write rule condition's.
+	conds := []string{}
+	if input.ConditionExpression != nil {
+		conds = append(conds, "("+*input.ConditionExpression+")")
+	}
+	if input.ExpressionAttributeNames == nil {
+		input.ExpressionAttributeNames = map[string]*string{}
+	}
+	for i, name := range []string{t.model.HashKey, t.model.RangeKey} {
+		if len(name) == 0 {
+			continue
+		}
+		alias := "#c" + strconv.Itoa(i)
+		input.ExpressionAttributeNames[alias] = aws.String(name)
+		conds = append(conds, "attribute_not_exists("+alias+")")
+	}
+	input.ConditionExpression = aws.String(strings.Join(conds, " AND "))
+
+	if _, err := t.svc.PutItem(input); err != nil {
+		return t.createError(item, err)
+	}
+
+	return updateVersions(item, input.Item)
+}
+
+// Get reads the item with the same primary key as key into out, returning
+// false if the table has no item with the key. Only the key fields of key
+// need to be set. out must be a pointer to the Table's struct type.
+func (t *Table) Get(key, out interface{}) (bool, error) {
+	if err := t.checkType(key); err != nil {
+		return false, err
+	}
+	if err := t.checkType(out); err != nil {
+		return false, err
+	}
+
+	input, err := t.registry.GetItemInput(key)
+	if err != nil {
+		return false, err
+	}
+	if t.ConsistentRead {
+		input.ConsistentRead = aws.Bool(true)
+	}
+
+	resp, err := t.svc.GetItem(input)
+	if err != nil {
+		return false, err
+	}
+	if resp.Item == nil {
+		return false, nil
+	}
+
+	return true, t.registry.UnmarshalItem(resp.Item, out)
+}
+
+// Delete deletes the item with the same primary key as key. Only the key
+// fields of key need to be set. Deleting an item which does not exist is
+// not an error.
+func (t *Table) Delete(key interface{}) error {
+	if err := t.checkType(key); err != nil {
+		return err
+	}
+
+	input, err := t.registry.DeleteItemInput(key)
+	if err != nil {
+		return err
+	}
+	_, err = t.svc.DeleteItem(input)
+	return err
+}
+
+// Update writes the changes from oldItem, the item as it was read, to
+// newItem with an UpdateItem request which only writes the attributes that
+// changed. See Registry.UpdateItemInput. No request is made if the items
+// do not differ. newItem should be a pointer, and its version fields are
+// checked and updated, the same as for Put.
+func (t *Table) Update(oldItem, newItem interface{}) error {
+	_, err := t.UpdateReturning(oldItem, newItem, "", nil)
+	return err
+}
+
+// checkType returns an error if v is not a value or pointer of the Table's
+// struct type.
+func (t *Table) checkType(v interface{}) error {
+	typ, err := structType(v)
+	if err != nil {
+		return err
+	}
+	if typ != t.typ {
+		return &InvalidModelError{Type: typ, msg: "item must be of the table's type " + t.typ.String()}
+	}
+	return nil
+}
+
+// conditionalCheckFailed is the error code of a write whose
+// ConditionExpression was not satisfied.
+const conditionalCheckFailed = "ConditionalCheckFailedException"
+
+// writeError returns the error of a failed write of item, whose condition
+// enforced rules. If the condition failed, the error is a
+// VersionConflictError with the stored item if the only rules are version
+// rules, and a ConditionFailedError otherwise.
+func (t *Table) writeError(item interface{}, rules []dynamodbattribute.WriteRule, err error) error {
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != conditionalCheckFailed || len(rules) == 0 {
+		return err
+	}
+	if !onlyVersionRules(rules) {
+		return &ConditionFailedError{TableName: t.model.TableName, Err: err}
+	}
+
+	latest, lerr := t.latestItem(item)
+	if lerr != nil {
+		return err
+	}
+	return &VersionConflictError{TableName: t.model.TableName, Latest: latest, Err: err}
+}
+
+// createError returns the error of a failed Create of item. If the write's
+// condition failed, the error is an AlreadyExistsError if the only
+// condition is the key check, or the table has an item with the key.
+// Otherwise a write rule condition failed, and the error is the one
+// writeError returns.
+func (t *Table) createError(item interface{}, err error) error {
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != conditionalCheckFailed {
+		return err
+	}
+
+	rules := dynamodbattribute.WriteRules(item)
+	if len(rules) != 0 {
+		latest, lerr := t.latestItem(item)
+		if lerr != nil {
+			return err
+		}
+		if latest == nil {
+			if !onlyVersionRules(rules) {
+				return &ConditionFailedError{TableName: t.model.TableName, Err: err}
+			}
+			return &VersionConflictError{TableName: t.model.TableName, Err: err}
+		}
+	}
+	return &AlreadyExistsError{TableName: t.model.TableName, Err: err}
+}
+
+// latestItem reads the stored item with the same primary key as item with
+// a consistent read, returning nil if the table has no item with the key.
+func (t *Table) latestItem(item interface{}) (interface{}, error) {
+	input, err := t.registry.GetItemInput(item)
+	if err != nil {
+		return nil, err
+	}
+	input.ConsistentRead = aws.Bool(true)
+	resp, err := t.svc.GetItem(input)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Item == nil {
+		return nil, nil
+	}
+
+	latest := reflect.New(t.typ).Interface()
+	if err := t.registry.UnmarshalItem(resp.Item, latest); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// onlyVersionRules returns if all of the rules are version rules.
+func onlyVersionRules(rules []dynamodbattribute.WriteRule) bool {
+	for _, rule := range rules {
+		if rule.Kind != dynamodbattribute.WriteRuleVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// updateVersions sets the `version` fields of item, if it is a pointer, to
+// the values of the written item.
+func updateVersions(item interface{}, written map[string]*dynamodb.AttributeValue) error {
+	if reflect.ValueOf(item).Kind() != reflect.Ptr {
+		return nil
+	}
+
+	var names []string
+	for _, rule := range dynamodbattribute.WriteRules(item) {
+		if rule.Kind == dynamodbattribute.WriteRuleVersion {
+			names = append(names, rule.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	return dynamodbattribute.DefaultDecoder().DecodeFields(&dynamodb.AttributeValue{M: written}, item, names...)
+}
+
+// sleepBeforeRetry sleeps before the retry of a batch request's unprocessed
+// keys or items, backing off exponentially with jitter the same as
+// client.DefaultRetryer. retry is the number of retries already made.
+func (t *Table) sleepBeforeRetry(retry int) {
+	if retry > 13 {
+		retry = 13
+	}
+	delay := time.Duration((1<<uint(retry))*(rand.Intn(30)+30)) * time.Millisecond
+
+	if t.SleepDelay != nil {
+		t.SleepDelay(delay)
+	} else {
+		time.Sleep(delay)
+	}
+}
+
+// An AlreadyExistsError is an error type representing the creation of an
+// item when the table already has an item with the same primary key.
+type AlreadyExistsError struct {
+	TableName string
+
+	// The write's ConditionalCheckFailedException.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *AlreadyExistsError) Code() string {
+	return "AlreadyExistsError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *AlreadyExistsError) Message() string {
+	return "table " + e.TableName + " already has an item with the key"
+}
+
+// OrigErr returns the write's ConditionalCheckFailedException, satisfying
+// the awserr.Error interface.
+func (e *AlreadyExistsError) OrigErr() error {
+	return e.Err
+}
+
+// A VersionConflictError is an error type representing a write which
+// failed because the version of the item written is not the version of
+// the stored item, which another writer has changed.
+type VersionConflictError struct {
+	TableName string
+
+	// A pointer to the stored item, read after the write failed, or nil if
+	// the table has no item with the key.
+	Latest interface{}
+
+	// The write's ConditionalCheckFailedException.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *VersionConflictError) Code() string {
+	return "VersionConflictError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *VersionConflictError) Message() string {
+	return "version of item for table " + e.TableName + " does not match the stored item"
+}
+
+// OrigErr returns the write's ConditionalCheckFailedException, satisfying
+// the awserr.Error interface.
+func (e *VersionConflictError) OrigErr() error {
+	return e.Err
+}
+
+// A ConditionFailedError is an error type representing a write which
+// failed because a condition enforcing the write rules of the item's
+// fields, such as the `immutable` or `writeonce` tag options, was not
+// satisfied.
+type ConditionFailedError struct {
+	TableName string
+
+	// The write's ConditionalCheckFailedException.
+	Err error
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *ConditionFailedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *ConditionFailedError) Code() string {
+	return "ConditionFailedError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *ConditionFailedError) Message() string {
+	return "write rules of item for table " + e.TableName + " not satisfied by the stored item"
+}
+
+// OrigErr returns the write's ConditionalCheckFailedException, satisfying
+// the awserr.Error interface.
+func (e *ConditionFailedError) OrigErr() error {
+	return e.Err
+}
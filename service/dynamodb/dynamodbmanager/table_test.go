@@ -0,0 +1,610 @@
+package dynamodbmanager
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// mockDynamoDB stores the items of a single table in memory, keyed by the
+// wire JSON of their key attributes. Query and Scan return the items in
+// key order, in pages of pageSize items.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	keys     []string
+	items    map[string]map[string]*dynamodb.AttributeValue
+	pageSize int
+
+	// The number of keys or items of each batch request, from the end of
+	// the request, the mock leaves unprocessed.
+	unprocessed int
+
+	// The error PutItem and UpdateItem fail with, if not nil.
+	writeErr error
+
+	// The Attributes UpdateItem returns.
+	updateAttributes map[string]*dynamodb.AttributeValue
+
+	// Called with the input of each PutItem request, if not nil.
+	onPut func(*dynamodb.PutItemInput)
+
+	// Called with the input of each Query and Scan request, if not nil.
+	onQuery func(*dynamodb.QueryInput)
+	onScan  func(*dynamodb.ScanInput)
+
+	// The errors BatchWriteItem requests fail with, in the order of the
+	// requests, if not nil.
+	batchWriteErrs []error
+
+	batchGets   []*dynamodb.BatchGetItemInput
+	batchWrites []*dynamodb.BatchWriteItemInput
+	updates     []*dynamodb.UpdateItemInput
+	queries     []*dynamodb.QueryInput
+	scans       []*dynamodb.ScanInput
+}
+
+func newMockDynamoDB(keys ...string) *mockDynamoDB {
+	return &mockDynamoDB{keys: keys, items: map[string]map[string]*dynamodb.AttributeValue{}}
+}
+
+func (m *mockDynamoDB) itemKey(item map[string]*dynamodb.AttributeValue) string {
+	key := map[string]*dynamodb.AttributeValue{}
+	for _, k := range m.keys {
+		key[k] = item[k]
+	}
+	b, err := dynamodbattribute.MarshalWireJSONMap(key)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func (m *mockDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	if m.onPut != nil {
+		m.onPut(input)
+	}
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+	m.items[m.itemKey(input.Item)] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: m.items[m.itemKey(input.Key)]}, nil
+}
+
+func (m *mockDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	delete(m.items, m.itemKey(input.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	m.batchGets = append(m.batchGets, input)
+
+	out := &dynamodb.BatchGetItemOutput{
+		Responses:       map[string][]map[string]*dynamodb.AttributeValue{},
+		UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{},
+	}
+	for table, req := range input.RequestItems {
+		keys := req.Keys
+		if m.unprocessed > 0 {
+			n := len(keys) - m.unprocessed
+			if n < 0 {
+				n = 0
+			}
+			out.UnprocessedKeys[table] = &dynamodb.KeysAndAttributes{Keys: keys[n:]}
+			keys = keys[:n]
+		}
+		// Responses are returned in reverse, as DynamoDB does not return
+		// them in the order requested.
+		for i := len(keys) - 1; i >= 0; i-- {
+			if item, ok := m.items[m.itemKey(keys[i])]; ok {
+				out.Responses[table] = append(out.Responses[table], item)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *mockDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	m.batchWrites = append(m.batchWrites, input)
+	if n := len(m.batchWrites); n <= len(m.batchWriteErrs) && m.batchWriteErrs[n-1] != nil {
+		return nil, m.batchWriteErrs[n-1]
+	}
+
+	out := &dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]*dynamodb.WriteRequest{},
+	}
+	for table, reqs := range input.RequestItems {
+		if m.unprocessed > 0 {
+			n := len(reqs) - m.unprocessed
+			if n < 0 {
+				n = 0
+			}
+			out.UnprocessedItems[table] = reqs[n:]
+			reqs = reqs[:n]
+		}
+		for _, req := range reqs {
+			if req.PutRequest != nil {
+				m.items[m.itemKey(req.PutRequest.Item)] = req.PutRequest.Item
+			} else {
+				delete(m.items, m.itemKey(req.DeleteRequest.Key))
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *mockDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	m.updates = append(m.updates, input)
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+	return &dynamodb.UpdateItemOutput{Attributes: m.updateAttributes}, nil
+}
+
+// page returns the page of items after the start key, of at most limit
+// items if limit is not nil.
+func (m *mockDynamoDB) page(start map[string]*dynamodb.AttributeValue, limit *int64) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue) {
+	keys := make([]string, 0, len(m.items))
+	for k := range m.items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	i := 0
+	if start != nil {
+		i = sort.SearchStrings(keys, m.itemKey(start)) + 1
+	}
+	end := len(keys)
+	if m.pageSize > 0 && i+m.pageSize < end {
+		end = i + m.pageSize
+	}
+	if limit != nil && i+int(*limit) < end {
+		end = i + int(*limit)
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	for _, k := range keys[i:end] {
+		items = append(items, m.items[k])
+	}
+	var last map[string]*dynamodb.AttributeValue
+	if end < len(keys) {
+		last = items[len(items)-1]
+	}
+	return items, last
+}
+
+func (m *mockDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	m.queries = append(m.queries, input)
+	if m.onQuery != nil {
+		m.onQuery(input)
+	}
+	items, last := m.page(input.ExclusiveStartKey, input.Limit)
+	return &dynamodb.QueryOutput{Items: items, LastEvaluatedKey: last}, nil
+}
+
+func (m *mockDynamoDB) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	m.scans = append(m.scans, input)
+	if m.onScan != nil {
+		m.onScan(input)
+	}
+	items, last := m.page(input.ExclusiveStartKey, input.Limit)
+	return &dynamodb.ScanOutput{Items: items, LastEvaluatedKey: last}, nil
+}
+
+func (m *mockDynamoDB) QueryPages(input *dynamodb.QueryInput, fn func(*dynamodb.QueryOutput, bool) bool) error {
+	for {
+		out, _ := m.Query(input)
+		lastPage := len(out.LastEvaluatedKey) == 0
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+func (m *mockDynamoDB) ScanPages(input *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool) error {
+	for {
+		out, _ := m.Scan(input)
+		lastPage := len(out.LastEvaluatedKey) == 0
+		if !fn(out, lastPage) || lastPage {
+			return nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+type testDocument struct {
+	ID      string `dynamodbav:",hashkey"`
+	Body    string
+	Version int  `dynamodbav:",version"`
+	Loaded  bool `dynamodbav:"-"`
+}
+
+func newTestTable(t *testing.T, svc dynamodbiface.DynamoDBAPI) *Table {
+	r := NewRegistry()
+	err := r.Register(testDocument{}, Model{
+		TableName: "documents",
+		AfterLoad: func(item interface{}) error {
+			item.(*testDocument).Loaded = true
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	table, err := NewTable(svc, r, testDocument{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return table
+}
+
+func TestTablePutGetDelete(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	doc := &testDocument{ID: "a", Body: "hello"}
+	if err := table.Put(doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, doc.Version; e != a {
+		t.Errorf("expect version updated to %d, got %d", e, a)
+	}
+
+	var actual testDocument
+	found, err := table.Get(testDocument{ID: "a"}, &actual)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !found {
+		t.Fatalf("expect item found")
+	}
+	expect := testDocument{ID: "a", Body: "hello", Version: 1, Loaded: true}
+	if e, a := expect, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if err := table.Delete(testDocument{ID: "a"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	found, err = table.Get(testDocument{ID: "a"}, &actual)
+	if err != nil || found {
+		t.Errorf("expect item not found, got %v, %v", found, err)
+	}
+
+	// Items of other types are rejected.
+	if err := table.Put(&testOrder{CustomerID: "a"}); err == nil {
+		t.Errorf("expect error for item of another type")
+	}
+}
+
+func TestTableUpdate(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	oldDoc := testDocument{ID: "a", Body: "hello", Version: 1}
+	newDoc := oldDoc
+	newDoc.Body = "goodbye"
+	if err := table.Update(oldDoc, &newDoc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(svc.updates); e != a {
+		t.Fatalf("expect %d update, got %d", e, a)
+	}
+	if e, a := "SET #u0 = :u0, #u1 = :u1", aws.StringValue(svc.updates[0].UpdateExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 2, newDoc.Version; e != a {
+		t.Errorf("expect version updated to %d, got %d", e, a)
+	}
+
+	// An unchanged versioned item is not written.
+	unchanged := newDoc
+	if err := table.Update(newDoc, &unchanged); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(svc.updates); e != a {
+		t.Errorf("expect no request for unchanged item, got %d updates", a)
+	}
+	if e, a := 2, unchanged.Version; e != a {
+		t.Errorf("expect version unchanged at %d, got %d", e, a)
+	}
+	input, err := table.registry.UpdateItemInput(newDoc, &unchanged)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if input.UpdateExpression != nil || input.ConditionExpression != nil {
+		t.Errorf("expect no update or condition, got %v", input)
+	}
+}
+
+func TestTableQueryScanAll(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = 2
+	table := newTestTable(t, svc)
+
+	var expect []testDocument
+	for i := 0; i < 5; i++ {
+		doc := testDocument{ID: strconv.Itoa(i), Body: "body"}
+		if err := table.Put(&doc); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		doc.Loaded = true
+		expect = append(expect, doc)
+	}
+
+	var queried []testDocument
+	err := table.Query().KeyCondition(expression.KeyEqual("ID", "1")).All(&queried)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect, queried; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 3, len(svc.queries); e != a {
+		t.Errorf("expect %d pages, got %d", e, a)
+	}
+	if e, a := "#k0 = :k0", aws.StringValue(svc.queries[0].KeyConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var scanned []*testDocument
+	if err := table.Scan().All(&scanned); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := len(expect), len(scanned); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	for i := range expect {
+		if e, a := expect[i], *scanned[i]; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	if err := table.Query().All(&queried); err == nil {
+		t.Errorf("expect error without key condition")
+	}
+	var orders []testOrder
+	if err := table.Scan().All(&orders); err == nil {
+		t.Errorf("expect error for slice of another type")
+	}
+}
+
+func TestQueryBuilder(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = 2
+	table := newTestTable(t, svc)
+
+	for i := 0; i < 5; i++ {
+		if err := table.Put(&testDocument{ID: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	var out []testDocument
+	err := table.Query().
+		SortBetween("Body", "a", "z").
+		KeyEqual("ID", "1").
+		Index("gsi1").
+		Limit(3).
+		All(&out)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3, len(out); e != a {
+		t.Errorf("expect %d items, got %d", e, a)
+	}
+	if e, a := 2, len(svc.queries); e != a {
+		t.Fatalf("expect %d pages, got %d", e, a)
+	}
+
+	input := svc.queries[0]
+	if e, a := "#k0 = :k0 AND #k1 BETWEEN :k1 AND :k2", aws.StringValue(input.KeyConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "gsi1", aws.StringValue(input.IndexName); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := int64(1), aws.Int64Value(svc.queries[1].Limit); e != a {
+		t.Errorf("expect remaining limit %d, got %d", e, a)
+	}
+}
+
+func TestScanBuilder(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	filter := expression.Expression{
+		Expression: "#b = :b",
+		Names:      map[string]*string{"#b": aws.String("Body")},
+		Values: map[string]*dynamodb.AttributeValue{
+			":b": {S: aws.String("x")},
+		},
+	}
+	var out []testDocument
+	if err := table.Scan().Filter(filter).ConsistentRead().All(&out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	input := svc.scans[0]
+	if e, a := filter.Expression, aws.StringValue(input.FilterExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := filter.Names, input.ExpressionAttributeNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := filter.Values, input.ExpressionAttributeValues; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if !aws.BoolValue(input.ConsistentRead) {
+		t.Errorf("expect consistent read")
+	}
+
+	if err := table.Scan().Filter(expression.Expression{}).All(&out); err == nil {
+		t.Errorf("expect error for empty filter expression")
+	}
+}
+
+func TestTableVersionConflict(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	stored := &testDocument{ID: "a", Body: "theirs"}
+	for i := 0; i < 2; i++ {
+		if err := table.Put(stored); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	svc.writeErr = awserr.New("ConditionalCheckFailedException", "The conditional request failed", nil)
+	stale := testDocument{ID: "a", Body: "ours", Version: 1}
+	err := table.Put(&stale)
+	verr, ok := err.(*VersionConflictError)
+	if !ok {
+		t.Fatalf("expect VersionConflictError, got %v", err)
+	}
+	expect := &testDocument{ID: "a", Body: "theirs", Version: 2, Loaded: true}
+	if e, a := expect, verr.Latest; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := svc.writeErr, verr.OrigErr(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 1, stale.Version; e != a {
+		t.Errorf("expect version unchanged, got %d", a)
+	}
+
+	newDoc := stale
+	newDoc.Body = "changed"
+	if _, ok := table.Update(stale, &newDoc).(*VersionConflictError); !ok {
+		t.Errorf("expect VersionConflictError for update")
+	}
+
+	// The item was deleted by another writer.
+	delete(svc.items, svc.itemKey(map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("a")}}))
+	err = table.Put(&stale)
+	if verr, ok := err.(*VersionConflictError); !ok || verr.Latest != nil {
+		t.Errorf("expect VersionConflictError without latest item, got %v", err)
+	}
+
+	// Other errors are returned unchanged.
+	svc.writeErr = fmt.Errorf("network error")
+	if e, a := svc.writeErr, table.Put(&stale); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+type testAccount struct {
+	ID      string `dynamodbav:",hashkey"`
+	Owner   string `dynamodbav:",writeonce"`
+	Status  string
+	Version int `dynamodbav:",version"`
+}
+
+func TestTableConditionFailed(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	r := NewRegistry()
+	if err := r.Register(testAccount{}, Model{TableName: "accounts"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	table, err := NewTable(svc, r, testAccount{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	stored := &testAccount{ID: "a", Owner: "theirs"}
+	if err := table.Put(stored); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// The writeonce rule may be the condition which failed.
+	svc.writeErr = awserr.New("ConditionalCheckFailedException", "The conditional request failed", nil)
+	item := testAccount{ID: "a", Owner: "ours", Version: 1}
+	err = table.Put(&item)
+	cerr, ok := err.(*ConditionFailedError)
+	if !ok {
+		t.Fatalf("expect ConditionFailedError, got %v", err)
+	}
+	if e, a := svc.writeErr, cerr.OrigErr(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Updates not changing the writeonce field only check the version.
+	changed := item
+	changed.Status = "closed"
+	if _, ok := table.Update(item, &changed).(*VersionConflictError); !ok {
+		t.Errorf("expect VersionConflictError for update")
+	}
+	changed.Owner = "changed"
+	if _, ok := table.Update(item, &changed).(*ConditionFailedError); !ok {
+		t.Errorf("expect ConditionFailedError for update of writeonce field")
+	}
+}
+
+func TestTableCreate(t *testing.T) {
+	svc := newMockDynamoDB("CustomerID", "OrderID")
+	table := newTestOrderTable(t, svc)
+
+	var input *dynamodb.PutItemInput
+	svc.onPut = func(in *dynamodb.PutItemInput) { input = in }
+	if err := table.Create(&testOrder{CustomerID: "abc", OrderID: 1}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "attribute_not_exists(#c0) AND attribute_not_exists(#c1)", aws.StringValue(input.ConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectNames := map[string]*string{"#c0": aws.String("CustomerID"), "#c1": aws.String("OrderID")}
+	if e, a := expectNames, input.ExpressionAttributeNames; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Write rule conditions are kept.
+	docs := newTestTable(t, svc)
+	doc := &testDocument{ID: "a"}
+	if err := docs.Create(doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "(attribute_not_exists(#w0)) AND attribute_not_exists(#c0)", aws.StringValue(input.ConditionExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 1, doc.Version; e != a {
+		t.Errorf("expect version updated to %d, got %d", e, a)
+	}
+
+	svc.writeErr = awserr.New("ConditionalCheckFailedException", "The conditional request failed", nil)
+	err := table.Create(&testOrder{CustomerID: "abc", OrderID: 1})
+	if aerr, ok := err.(*AlreadyExistsError); !ok || aerr.OrigErr() != svc.writeErr {
+		t.Errorf("expect AlreadyExistsError, got %v", err)
+	}
+
+	// With write rule conditions, the stored item decides which failed.
+	svc = newMockDynamoDB("ID")
+	docs = newTestTable(t, svc)
+	if err := docs.Create(&testDocument{ID: "a"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	svc.writeErr = awserr.New("ConditionalCheckFailedException", "The conditional request failed", nil)
+	if err := docs.Create(&testDocument{ID: "a"}); err == nil {
+		t.Errorf("expect error")
+	} else if _, ok := err.(*AlreadyExistsError); !ok {
+		t.Errorf("expect AlreadyExistsError, got %v", err)
+	}
+	err = docs.Create(&testDocument{ID: "b", Version: 1})
+	if verr, ok := err.(*VersionConflictError); !ok || verr.Latest != nil {
+		t.Errorf("expect VersionConflictError without latest item, got %v", err)
+	}
+}
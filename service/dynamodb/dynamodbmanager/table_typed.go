@@ -0,0 +1,198 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbmanager
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// A TypedTable is a Table of items of type T, whose methods take and
+// return values of T instead of interface{} values.
+//
+//     orders, err := dynamodbmanager.NewTypedTable[Order](svc, registry)
+//     if err != nil {
+//         return err
+//     }
+//
+//     order, found, err := orders.Get(Order{CustomerID: "abc", OrderID: 1})
+type TypedTable[T any] struct {
+	table *Table
+}
+
+// NewTypedTable returns the TypedTable for items of type T, which must be
+// a struct type registered with the Registry.
+func NewTypedTable[T any](svc dynamodbiface.DynamoDBAPI, r *Registry) (*TypedTable[T], error) {
+	t, err := NewTable(svc, r, new(T))
+	if err != nil {
+		return nil, err
+	}
+	return &TypedTable[T]{table: t}, nil
+}
+
+// Table returns the untyped Table the TypedTable reads and writes with.
+func (t *TypedTable[T]) Table() *Table {
+	return t.table
+}
+
+// Put writes item to the table. See Table.Put.
+func (t *TypedTable[T]) Put(item *T) error {
+	return t.table.Put(item)
+}
+
+// Create writes item if the table has no item with the same primary key.
+// See Table.Create.
+func (t *TypedTable[T]) Create(item *T) error {
+	return t.table.Create(item)
+}
+
+// Get reads the item with the same primary key as key. See Table.Get.
+func (t *TypedTable[T]) Get(key T) (T, bool, error) {
+	var out T
+	found, err := t.table.Get(key, &out)
+	return out, found, err
+}
+
+// Delete deletes the item with the same primary key as key. See
+// Table.Delete.
+func (t *TypedTable[T]) Delete(key T) error {
+	return t.table.Delete(key)
+}
+
+// Update writes the changes from oldItem to newItem. See Table.Update.
+func (t *TypedTable[T]) Update(oldItem T, newItem *T) error {
+	return t.table.Update(oldItem, newItem)
+}
+
+// BatchGet reads the items with the same primary keys as keys, in the
+// order of keys. See Table.BatchGet.
+func (t *TypedTable[T]) BatchGet(keys []T) ([]T, error) {
+	var out []T
+	err := t.table.BatchGet(keys, &out)
+	return out, err
+}
+
+// UpdateReturning writes the changes from oldItem to newItem, and returns
+// the item attributes selected by returnValues. See Table.UpdateReturning.
+func (t *TypedTable[T]) UpdateReturning(oldItem T, newItem *T, returnValues string) (T, bool, error) {
+	var out T
+	ok, err := t.table.UpdateReturning(oldItem, newItem, returnValues, &out)
+	return out, ok, err
+}
+
+// UpdateFields sets the attributes of fields on the item with the same
+// primary key as key, and returns the item attributes selected by
+// returnValues. See Table.UpdateFields.
+func (t *TypedTable[T]) UpdateFields(key T, fields map[string]interface{}, returnValues string) (T, bool, error) {
+	var out T
+	ok, err := t.table.UpdateFields(key, fields, returnValues, &out)
+	return out, ok, err
+}
+
+// UpdateWith applies the actions of update to the item with the same
+// primary key as key, and returns the item attributes selected by
+// returnValues. See Table.UpdateWith.
+func (t *TypedTable[T]) UpdateWith(key T, update expression.Update, returnValues string) (T, bool, error) {
+	var out T
+	ok, err := t.table.UpdateWith(key, update, returnValues, &out)
+	return out, ok, err
+}
+
+// QueryAll returns every item selected by the key condition.
+func (t *TypedTable[T]) QueryAll(cond expression.KeyCondition) ([]T, error) {
+	var out []T
+	err := t.table.Query().KeyCondition(cond).All(&out)
+	return out, err
+}
+
+// ScanAll returns every item of the table.
+func (t *TypedTable[T]) ScanAll() ([]T, error) {
+	var out []T
+	err := t.table.Scan().All(&out)
+	return out, err
+}
+
+// QueryPages calls fn with each page of the items selected by the key
+// condition. Iteration stops when fn returns false. See Query.Pages.
+func (t *TypedTable[T]) QueryPages(cond expression.KeyCondition, fn func(items []T, lastPage bool) bool) error {
+	var items []T
+	return t.table.Query().KeyCondition(cond).Pages(&items, func(lastPage bool) bool {
+		page := items
+		items = nil
+		return fn(page, lastPage)
+	})
+}
+
+// ScanPages calls fn with each page of the table's items. Iteration stops
+// when fn returns false. See Scan.Pages.
+func (t *TypedTable[T]) ScanPages(fn func(items []T, lastPage bool) bool) error {
+	var items []T
+	return t.table.Scan().Pages(&items, func(lastPage bool) bool {
+		page := items
+		items = nil
+		return fn(page, lastPage)
+	})
+}
+
+// QueryIter returns a TypedIterator of the items selected by the key
+// condition. See Query.Iter.
+func (t *TypedTable[T]) QueryIter(cond expression.KeyCondition) *TypedIterator[T] {
+	return &TypedIterator[T]{it: t.table.Query().KeyCondition(cond).Iter()}
+}
+
+// ScanIter returns a TypedIterator of the table's items. See Scan.Iter.
+func (t *TypedTable[T]) ScanIter() *TypedIterator[T] {
+	return &TypedIterator[T]{it: t.table.Scan().Iter()}
+}
+
+// A TypedIterator is an Iterator of items of type T.
+//
+//     it := orders.QueryIter(expression.KeyEqual("CustomerID", "abc"))
+//     defer it.Close()
+//
+//     for it.Next() {
+//         order, err := it.Item()
+//         // ...
+//     }
+type TypedIterator[T any] struct {
+	it *Iterator
+}
+
+// Prefetch sets the number of pages the TypedIterator reads ahead. See
+// Iterator.Prefetch.
+func (it *TypedIterator[T]) Prefetch(pages int) *TypedIterator[T] {
+	it.it.Prefetch(pages)
+	return it
+}
+
+// Deadline sets an adaptive page size, so the TypedIterator's pages are
+// read before the deadline t. See Iterator.Deadline.
+func (it *TypedIterator[T]) Deadline(t time.Time) *TypedIterator[T] {
+	it.it.Deadline(t)
+	return it
+}
+
+// Next advances the TypedIterator to the next item. See Iterator.Next.
+func (it *TypedIterator[T]) Next() bool {
+	return it.it.Next()
+}
+
+// Item returns the current item. See Iterator.Item.
+func (it *TypedIterator[T]) Item() (T, error) {
+	var out T
+	err := it.it.Item(&out)
+	return out, err
+}
+
+// Err returns the error which stopped the TypedIterator, if any.
+func (it *TypedIterator[T]) Err() error {
+	return it.it.Err()
+}
+
+// Close stops the TypedIterator. See Iterator.Close.
+func (it *TypedIterator[T]) Close() {
+	it.it.Close()
+}
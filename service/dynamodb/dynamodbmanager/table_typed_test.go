@@ -0,0 +1,97 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbmanager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypedTable(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table, err := NewTypedTable[testDocument](svc, newTestTable(t, svc).registry)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	doc := testDocument{ID: "a", Body: "hello"}
+	if err := table.Put(&doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	actual, found, err := table.Get(testDocument{ID: "a"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !found {
+		t.Fatalf("expect item found")
+	}
+	expect := testDocument{ID: "a", Body: "hello", Version: 1, Loaded: true}
+	if e, a := expect, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	items, err := table.ScanAll()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []testDocument{expect}, items; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestTypedTableBatchGet(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table, err := NewTypedTable[testDocument](svc, newTestTable(t, svc).registry)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := table.Put(&testDocument{ID: id}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	items, err := table.BatchGet([]testDocument{{ID: "b"}, {ID: "c"}, {ID: "a"}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(items); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	if e, a := "b", items[0].ID; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestTypedTableIter(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	svc.pageSize = 1
+	table, err := NewTypedTable[testDocument](svc, newTestTable(t, svc).registry)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := table.Put(&testDocument{ID: id}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	it := table.ScanIter().Prefetch(1)
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		doc, err := it.Item()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"a", "b", "c"}, ids; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,203 @@
+package dynamodbmanager
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// UpdateReturning writes the changes from oldItem to newItem the same as
+// Update, and unmarshals the attributes the UpdateItem request returns, as
+// selected by returnValues, into out, a pointer to the Table's struct type.
+// returnValues is one of the dynamodb.ReturnValue constants, such as
+// dynamodb.ReturnValueAllNew for the item as updated, or
+// dynamodb.ReturnValueAllOld for the item before the update. out may be nil
+// if returnValues is empty or dynamodb.ReturnValueNone.
+//
+//     var updated Order
+//     _, err := table.UpdateReturning(order, &changed, dynamodb.ReturnValueAllNew, &updated)
+//
+// UpdateReturning returns false, and leaves out unchanged, if no attributes
+// were returned, such as if no request was made as the items do not
+// differ.
+func (t *Table) UpdateReturning(oldItem, newItem interface{}, returnValues string, out interface{}) (bool, error) {
+	if err := t.checkType(newItem); err != nil {
+		return false, err
+	}
+	if err := t.checkOut(out); err != nil {
+		return false, err
+	}
+
+	input, written, rules, err := t.registry.updateItemInput(oldItem, newItem)
+	if err != nil {
+		return false, err
+	}
+	if input.UpdateExpression == nil {
+		return false, nil
+	}
+	if len(returnValues) != 0 {
+		input.ReturnValues = aws.String(returnValues)
+	}
+
+	resp, err := t.svc.UpdateItem(input)
+	if err != nil {
+		return false, t.writeError(newItem, rules, err)
+	}
+	if err := updateVersions(newItem, written); err != nil {
+		return false, err
+	}
+
+	return t.unmarshalAttributes(resp.Attributes, out)
+}
+
+// UpdateFields sets the attributes of the fields map, keyed by attribute
+// name, on the item with the same primary key as key, and unmarshals the
+// attributes returned, as selected by returnValues, into out, the same as
+// UpdateReturning. Only the key fields of key need to be set. The item is
+// created if it does not exist.
+//
+//     var updated Order
+//     _, err := table.UpdateFields(Order{CustomerID: "abc", OrderID: 1},
+//         map[string]interface{}{"Status": "shipped"},
+//         dynamodb.ReturnValueAllNew, &updated)
+//
+// The `immutable` and `writeonce` struct tag options of key's type are
+// enforced for the fields set. Version fields are not checked or
+// incremented, and the fields must not include key attributes.
+func (t *Table) UpdateFields(key interface{}, fields map[string]interface{}, returnValues string, out interface{}) (bool, error) {
+	if err := t.checkType(key); err != nil {
+		return false, err
+	}
+	if err := t.checkOut(out); err != nil {
+		return false, err
+	}
+
+	keyAV, err := t.registry.Key(key)
+	if err != nil {
+		return false, err
+	}
+	values, err := dynamodbattribute.MarshalMap(fields)
+	if err != nil {
+		return false, err
+	}
+	if len(values) == 0 {
+		return false, &InvalidModelError{Type: t.typ, msg: "update requires at least one field"}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if _, ok := keyAV[name]; ok {
+			return false, &KeyChangedError{TableName: t.model.TableName, Attribute: name}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var update expression.Update
+	for _, name := range names {
+		update = update.Set(name, values[name])
+	}
+
+	var rules []dynamodbattribute.WriteRule
+	for _, rule := range dynamodbattribute.WriteRules(key) {
+		if _, ok := values[rule.Name]; ok && rule.Kind != dynamodbattribute.WriteRuleVersion {
+			rules = append(rules, rule)
+		}
+	}
+
+	return t.update(keyAV, update, expression.WriteCondition(values, rules), returnValues, out)
+}
+
+// UpdateWith applies the actions of update to the item with the same
+// primary key as key, and unmarshals the attributes returned, as selected
+// by returnValues, into out, the same as UpdateReturning. Only the key
+// fields of key need to be set. The item is created if it does not exist.
+//
+//     _, err := table.UpdateWith(Order{CustomerID: "abc", OrderID: 1},
+//         expression.Update{}.ListAppend("Events", []string{"shipped"}).AddToSet("Tags", []string{"priority"}),
+//         "", nil)
+//
+// The write rules of key's type are not enforced, so update must not
+// change `immutable`, `writeonce`, or `version` fields.
+func (t *Table) UpdateWith(key interface{}, update expression.Update, returnValues string, out interface{}) (bool, error) {
+	if err := t.checkType(key); err != nil {
+		return false, err
+	}
+	if err := t.checkOut(out); err != nil {
+		return false, err
+	}
+
+	keyAV, err := t.registry.Key(key)
+	if err != nil {
+		return false, err
+	}
+
+	return t.update(keyAV, update, expression.Expression{}, returnValues, out)
+}
+
+// update sends the UpdateItem request of the update and condition for the
+// item with the key, and unmarshals the attributes returned into out.
+func (t *Table) update(key map[string]*dynamodb.AttributeValue, update expression.Update, cond expression.Expression, returnValues string, out interface{}) (bool, error) {
+	expr, err := update.Build()
+	if err != nil {
+		return false, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(t.model.TableName),
+		Key:                       key,
+		UpdateExpression:          aws.String(expr.Expression),
+		ExpressionAttributeNames:  expr.Names,
+		ExpressionAttributeValues: expr.Values,
+	}
+	if len(returnValues) != 0 {
+		input.ReturnValues = aws.String(returnValues)
+	}
+	if len(cond.Expression) != 0 {
+		input.ConditionExpression = aws.String(cond.Expression)
+		for k, v := range cond.Names {
+			input.ExpressionAttributeNames[k] = v
+		}
+		if len(cond.Values) != 0 && input.ExpressionAttributeValues == nil {
+			input.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{}
+		}
+		for k, v := range cond.Values {
+			input.ExpressionAttributeValues[k] = v
+		}
+	}
+
+	resp, err := t.svc.UpdateItem(input)
+	if err != nil {
+		return false, err
+	}
+
+	return t.unmarshalAttributes(resp.Attributes, out)
+}
+
+// checkOut returns an error if out is not nil or a pointer to the Table's
+// struct type.
+func (t *Table) checkOut(out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	if err := t.checkType(out); err != nil {
+		return err
+	}
+	if reflect.ValueOf(out).Kind() != reflect.Ptr {
+		return &InvalidModelError{Type: t.typ, msg: "attributes must be read into a pointer"}
+	}
+	return nil
+}
+
+// unmarshalAttributes unmarshals the attributes returned by a write into
+// out, if there are any and out is not nil.
+func (t *Table) unmarshalAttributes(attrs map[string]*dynamodb.AttributeValue, out interface{}) (bool, error) {
+	if len(attrs) == 0 || out == nil {
+		return false, nil
+	}
+	return true, t.registry.UnmarshalItem(attrs, out)
+}
@@ -0,0 +1,174 @@
+package dynamodbmanager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+func TestTableUpdateReturning(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+	svc.updateAttributes = map[string]*dynamodb.AttributeValue{
+		"ID":      {S: aws.String("a")},
+		"Body":    {S: aws.String("goodbye")},
+		"Version": {N: aws.String("2")},
+	}
+
+	oldDoc := testDocument{ID: "a", Body: "hello", Version: 1}
+	newDoc := oldDoc
+	newDoc.Body = "goodbye"
+	var out testDocument
+	ok, err := table.UpdateReturning(oldDoc, &newDoc, dynamodb.ReturnValueAllNew, &out)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !ok {
+		t.Fatalf("expect attributes returned")
+	}
+	if e, a := dynamodb.ReturnValueAllNew, aws.StringValue(svc.updates[0].ReturnValues); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expect := testDocument{ID: "a", Body: "goodbye", Version: 2, Loaded: true}
+	if e, a := expect, out; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// No request is made for unchanged items.
+	order := testOrder{CustomerID: "abc", OrderID: 1, Status: "NEW"}
+	var outOrder testOrder
+	ok, err = newTestOrderTable(t, svc).UpdateReturning(order, &order, dynamodb.ReturnValueAllNew, &outOrder)
+	if err != nil || ok {
+		t.Errorf("expect no attributes, got %v, %v", ok, err)
+	}
+	if e, a := 1, len(svc.updates); e != a {
+		t.Errorf("expect %d update, got %d", e, a)
+	}
+
+	if _, err := table.UpdateReturning(oldDoc, &newDoc, dynamodb.ReturnValueAllNew, out); err == nil {
+		t.Errorf("expect error for non-pointer out")
+	}
+}
+
+func TestTableUpdateFields(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+	svc.updateAttributes = map[string]*dynamodb.AttributeValue{
+		"ID":   {S: aws.String("a")},
+		"Body": {S: aws.String("old")},
+	}
+
+	var out testDocument
+	ok, err := table.UpdateFields(testDocument{ID: "a"}, map[string]interface{}{
+		"Body":  "new",
+		"Count": 3,
+	}, dynamodb.ReturnValueAllOld, &out)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !ok || out.Body != "old" {
+		t.Errorf("expect old item returned, got %v, %v", ok, out)
+	}
+
+	input := svc.updates[0]
+	if e, a := "SET #u0 = :u0, #u1 = :u1", aws.StringValue(input.UpdateExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "Body", aws.StringValue(input.ExpressionAttributeNames["#u0"]); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if input.ConditionExpression != nil {
+		t.Errorf("expect no condition, got %v", *input.ConditionExpression)
+	}
+
+	_, err = table.UpdateFields(testDocument{ID: "a"}, map[string]interface{}{"ID": "b"}, "", nil)
+	if _, ok := err.(*KeyChangedError); !ok {
+		t.Errorf("expect KeyChangedError, got %v", err)
+	}
+	if _, err := table.UpdateFields(testDocument{ID: "a"}, nil, "", nil); err == nil {
+		t.Errorf("expect error for no fields")
+	}
+}
+
+func TestTableUpdateWith(t *testing.T) {
+	svc := newMockDynamoDB("ID")
+	table := newTestTable(t, svc)
+
+	update := expression.Update{}.
+		ListAppend("Events", []string{"created"}).
+		AddToSet("Tags", []string{"new"})
+	if _, err := table.UpdateWith(testDocument{ID: "a"}, update, "", nil); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	input := svc.updates[0]
+	if e, a := "SET #u0 = list_append(if_not_exists(#u0, :u1), :u0) ADD #u1 :u2", aws.StringValue(input.UpdateExpression); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expectKey := map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("a")}}
+	if e, a := expectKey, input.Key; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if input.ReturnValues != nil || input.ConditionExpression != nil {
+		t.Errorf("expect no return values or condition, got %v", input)
+	}
+
+	if _, err := table.UpdateWith(testDocument{ID: "a"}, expression.Update{}, "", nil); err == nil {
+		t.Errorf("expect error for empty update")
+	}
+}
+
+func TestTableUpdateReadFrom(t *testing.T) {
+	type testRenamed struct {
+		ID   string `dynamodbav:",hashkey"`
+		Name string `dynamodbav:"newName,readfrom=oldName"`
+	}
+
+	r := NewRegistry()
+	if err := r.Register(testRenamed{}, Model{TableName: "renamed"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	svc := newMockDynamoDB("ID")
+	table, err := NewTable(svc, r, testRenamed{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// An item written before the rename.
+	item := map[string]*dynamodb.AttributeValue{
+		"ID":      {S: aws.String("a")},
+		"oldName": {S: aws.String("old")},
+	}
+	svc.items[svc.itemKey(item)] = item
+
+	var oldItem testRenamed
+	if _, err := table.Get(testRenamed{ID: "a"}, &oldItem); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "old", oldItem.Name; e != a {
+		t.Fatalf("expect %v, got %v", e, a)
+	}
+
+	newItem := oldItem
+	newItem.Name = "new"
+	if err := table.Update(oldItem, &newItem); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Apply the SET actions of the update to the stored item.
+	input := svc.updates[0]
+	for placeholder, name := range input.ExpressionAttributeNames {
+		item[aws.StringValue(name)] = input.ExpressionAttributeValues[":"+placeholder[1:]]
+	}
+
+	var actual testRenamed
+	if _, err := table.Get(testRenamed{ID: "a"}, &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "new", actual.Name; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
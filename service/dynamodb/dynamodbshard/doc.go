@@ -0,0 +1,29 @@
+// Package dynamodbshard provides write sharding of a DynamoDB table's hot
+// partition keys, such as the date of a time series or the name of a
+// counter, which receive more writes than a single partition can take.
+//
+// Writes add a shard suffix to the partition key, spreading the items of
+// the key over a fixed number of partition key values. The suffix is
+// calculated from a value of the item, such as its ID, so the shard of an
+// item can be found again, or chosen at random for items which are only
+// read by queries of every shard.
+//
+//     events := dynamodbshard.New(svc, "events", "Day", func(s *dynamodbshard.Sharder) {
+//         s.Shards = 10
+//         s.SortKey = "Time"
+//     })
+//
+//     day, err := events.ShardKey("2016-05-01", eventID)
+//     item["Day"] = &dynamodb.AttributeValue{S: aws.String(day)}
+//     // write item with PutItem
+//
+// Queries of a key are fanned out to every shard concurrently, and the
+// items of the shards are merged in the order of the sort key. A counter
+// written to random shards is read by summing the items of every shard.
+//
+//     between := expression.SortKeyBetween("Time", start, end)
+//     items, err := events.Query("2016-05-01", dynamodbshard.QueryOptions{
+//         Sort:  &between,
+//         Limit: 100,
+//     })
+package dynamodbshard
@@ -0,0 +1,323 @@
+package dynamodbshard
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Default Sharder configuration.
+const (
+	DefaultShards    = 10
+	DefaultSeparator = "#"
+)
+
+// A Sharder adds shard suffixes to the partition keys of a table, and
+// queries the shards of a partition key. A Sharder is safe for concurrent
+// use once configured.
+type Sharder struct {
+	// The DynamoDB client the table is queried with.
+	DynamoDB dynamodbiface.DynamoDBAPI
+
+	// Name of the table.
+	TableName string
+
+	// Name of the table's string partition key attribute.
+	PartitionKey string
+
+	// Name of the table's sort key attribute, the items of the shards are
+	// merged in the order of. Empty if the table has no sort key.
+	//
+	// Defaults to empty.
+	SortKey string
+
+	// Number of shards of each partition key, at least 1. Changing the
+	// number of shards of a table with items hides the items of the shards
+	// removed from queries.
+	//
+	// Defaults to DefaultShards.
+	Shards int
+
+	// Separator between the partition key and the shard number.
+	//
+	// Defaults to DefaultSeparator.
+	Separator string
+}
+
+// New creates a new Sharder of the table's partition key attribute. Use
+// the `opts` functional options to override the default configuration.
+func New(svc dynamodbiface.DynamoDBAPI, tableName, partitionKey string, opts ...func(*Sharder)) *Sharder {
+	s := &Sharder{
+		DynamoDB:     svc,
+		TableName:    tableName,
+		PartitionKey: partitionKey,
+		Shards:       DefaultShards,
+		Separator:    DefaultSeparator,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// ShardKey returns the partition key with the suffix of the shard the
+// value is written to, calculated from a hash of the value, so the same
+// value is always written to the same shard. An InvalidShardsError is
+// returned if the Sharder has less than 1 shard.
+func (s *Sharder) ShardKey(key, value string) (string, error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(value))
+	return s.shardKey(key, int(h.Sum32()%uint32(s.Shards))), nil
+}
+
+// RandomShardKey returns the partition key with the suffix of a random
+// shard. An InvalidShardsError is returned if the Sharder has less than 1
+// shard.
+func (s *Sharder) RandomShardKey(key string) (string, error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+
+	return s.shardKey(key, rand.Intn(s.Shards)), nil
+}
+
+// ShardKeys returns the partition key with the suffix of each shard. An
+// InvalidShardsError is returned if the Sharder has less than 1 shard.
+func (s *Sharder) ShardKeys(key string) ([]string, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, s.Shards)
+	for i := range keys {
+		keys[i] = s.shardKey(key, i)
+	}
+	return keys, nil
+}
+
+func (s *Sharder) validate() error {
+	if s.Shards < 1 {
+		return &InvalidShardsError{Shards: s.Shards}
+	}
+	return nil
+}
+
+func (s *Sharder) shardKey(key string, shard int) string {
+	return key + s.Separator + strconv.Itoa(shard)
+}
+
+// QueryOptions are the options of a query of the shards of a partition
+// key.
+type QueryOptions struct {
+	// Condition of the items' sort key, or nil to read every item of the
+	// shards.
+	Sort *expression.SortCondition
+
+	// Name of the secondary index queried instead of the table. The
+	// index's partition key must be the sharded key.
+	IndexName string
+
+	// Name of the sort key attribute the items of the shards are merged
+	// in the order of, such as the sort key of the IndexName.
+	//
+	// Defaults to the Sharder's SortKey.
+	SortKey string
+
+	// Maximum number of items returned. Zero does not limit the items.
+	Limit int64
+
+	// Items are returned in descending order of the sort key.
+	Descending bool
+
+	// Shards are read with strongly consistent reads.
+	ConsistentRead bool
+}
+
+// Query queries the shards of the partition key concurrently, returning
+// the items of every shard, merged in the order of the options' or the
+// Sharder's SortKey. If a Limit is set, each shard is queried for up to
+// Limit items, and the first Limit items of the merged items are returned.
+func (s *Sharder) Query(key string, opts QueryOptions) ([]map[string]*dynamodb.AttributeValue, error) {
+	keys, err := s.ShardKeys(key)
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]map[string]*dynamodb.AttributeValue, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, shardKey := range keys {
+		wg.Add(1)
+		go func(i int, shardKey string) {
+			defer wg.Done()
+			results[i], errs[i] = s.queryShard(shardKey, opts)
+		}(i, shardKey)
+	}
+	wg.Wait()
+
+	var items []map[string]*dynamodb.AttributeValue
+	for i := range keys {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, results[i]...)
+	}
+
+	sortKey := opts.SortKey
+	if len(sortKey) == 0 {
+		sortKey = s.SortKey
+	}
+	if len(sortKey) != 0 {
+		sort.Stable(&itemsBySortKey{items: items, key: sortKey, descending: opts.Descending})
+	}
+	if opts.Limit > 0 && int64(len(items)) > opts.Limit {
+		items = items[:opts.Limit]
+	}
+	return items, nil
+}
+
+// QueryInto queries the shards of the partition key the same as Query,
+// unmarshaling the items into out, a pointer to a slice.
+func (s *Sharder) QueryInto(key string, opts QueryOptions, out interface{}) error {
+	items, err := s.Query(key, opts)
+	if err != nil {
+		return err
+	}
+	return dynamodbattribute.UnmarshalListOfMaps(items, out)
+}
+
+// queryShard returns the items of the shard, reading every page, or up to
+// the Limit items.
+func (s *Sharder) queryShard(shardKey string, opts QueryOptions) ([]map[string]*dynamodb.AttributeValue, error) {
+	cond := expression.KeyEqual(s.PartitionKey, shardKey)
+	if opts.Sort != nil {
+		cond = cond.AndSort(*opts.Sort)
+	}
+	expr, err := cond.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(s.TableName),
+		KeyConditionExpression:    aws.String(expr.Expression),
+		ExpressionAttributeNames:  expr.Names,
+		ExpressionAttributeValues: expr.Values,
+	}
+	if len(opts.IndexName) != 0 {
+		input.IndexName = aws.String(opts.IndexName)
+	}
+	if opts.Descending {
+		input.ScanIndexForward = aws.Bool(false)
+	}
+	if opts.ConsistentRead {
+		input.ConsistentRead = aws.Bool(true)
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	for {
+		if opts.Limit > 0 {
+			input.Limit = aws.Int64(opts.Limit - int64(len(items)))
+		}
+		resp, err := s.DynamoDB.Query(input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Items...)
+		if len(resp.LastEvaluatedKey) == 0 || (opts.Limit > 0 && int64(len(items)) >= opts.Limit) {
+			return items, nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// An InvalidShardsError is an error type representing a Sharder
+// configured with less than 1 shard.
+type InvalidShardsError struct {
+	Shards int
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidShardsError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidShardsError) Code() string {
+	return "InvalidShardsError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidShardsError) Message() string {
+	return "sharder must have at least 1 shard, got " + strconv.Itoa(e.Shards)
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *InvalidShardsError) OrigErr() error {
+	return nil
+}
+
+// itemsBySortKey sorts items by the value of their sort key attribute.
+// Items without the attribute, or whose values cannot be compared, are
+// sorted as equal.
+type itemsBySortKey struct {
+	items      []map[string]*dynamodb.AttributeValue
+	key        string
+	descending bool
+}
+
+func (x *itemsBySortKey) Len() int { return len(x.items) }
+
+func (x *itemsBySortKey) Swap(i, j int) { x.items[i], x.items[j] = x.items[j], x.items[i] }
+
+func (x *itemsBySortKey) Less(i, j int) bool {
+	c := compare(x.items[i][x.key], x.items[j][x.key])
+	if x.descending {
+		return c > 0
+	}
+	return c < 0
+}
+
+// compare compares the sort key values, which DynamoDB orders by the value
+// of numbers, the UTF-8 bytes of strings, and the bytes of binary values.
+func compare(a, b *dynamodb.AttributeValue) int {
+	switch {
+	case a == nil || b == nil:
+		return 0
+	case a.N != nil && b.N != nil:
+		c, err := dynamodbattribute.Number(*a.N).Compare(dynamodbattribute.Number(*b.N))
+		if err != nil {
+			return 0
+		}
+		return c
+	case a.S != nil && b.S != nil:
+		switch {
+		case *a.S < *b.S:
+			return -1
+		case *a.S > *b.S:
+			return 1
+		}
+		return 0
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B)
+	}
+	return 0
+}
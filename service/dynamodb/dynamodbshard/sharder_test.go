@@ -0,0 +1,214 @@
+package dynamodbshard
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// mockDynamoDB returns the items of each partition key value, in order of
+// the query's direction, one item a page.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu      sync.Mutex
+	items   map[string][]map[string]*dynamodb.AttributeValue
+	queries []*dynamodb.QueryInput
+}
+
+func (m *mockDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	m.mu.Lock()
+	in := *input
+	m.queries = append(m.queries, &in)
+	m.mu.Unlock()
+
+	items := m.items[*input.ExpressionAttributeValues[":k0"].S]
+	if input.ScanIndexForward != nil && !*input.ScanIndexForward {
+		reversed := make([]map[string]*dynamodb.AttributeValue, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+	i := 0
+	if input.ExclusiveStartKey != nil {
+		for i < len(items) && items[i]["T"] != input.ExclusiveStartKey["T"] {
+			i++
+		}
+		i++
+	}
+	resp := &dynamodb.QueryOutput{}
+	if i < len(items) {
+		resp.Items = items[i : i+1]
+		if i+1 < len(items) {
+			resp.LastEvaluatedKey = items[i]
+		}
+	}
+	return resp, nil
+}
+
+func testItem(t string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{"T": {N: aws.String(t)}}
+}
+
+func itemTimes(items []map[string]*dynamodb.AttributeValue) string {
+	var times []string
+	for _, item := range items {
+		times = append(times, *item["T"].N)
+	}
+	return strings.Join(times, ",")
+}
+
+func TestShardKeys(t *testing.T) {
+	s := New(nil, "events", "Day", func(s *Sharder) {
+		s.Shards = 3
+		s.Separator = "."
+	})
+
+	keys, err := s.ShardKeys("d")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"d.0", "d.1", "d.2"}, keys; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	k1, _ := s.ShardKey("d", "abc")
+	k2, _ := s.ShardKey("d", "abc")
+	if k1 != k2 {
+		t.Errorf("expect same shard for the same value, got %v and %v", k1, k2)
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		k, err := s.ShardKey("d", fmt.Sprint(i))
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		seen[k] = true
+		k, err = s.RandomShardKey("d")
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if !strings.HasPrefix(k, "d.") {
+			t.Errorf("expect shard of d, got %v", k)
+		}
+	}
+	if e, a := 3, len(seen); e != a {
+		t.Errorf("expect values spread over %d shards, got %d", e, a)
+	}
+}
+
+func TestInvalidShards(t *testing.T) {
+	s := New(&mockDynamoDB{}, "events", "Day", func(s *Sharder) {
+		s.Shards = 0
+	})
+
+	errs := make([]error, 4)
+	_, errs[0] = s.ShardKey("d", "abc")
+	_, errs[1] = s.RandomShardKey("d")
+	_, errs[2] = s.ShardKeys("d")
+	_, errs[3] = s.Query("d", QueryOptions{})
+	for i, err := range errs {
+		if _, ok := err.(*InvalidShardsError); !ok {
+			t.Errorf("%d, expect InvalidShardsError, got %v", i, err)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	svc := &mockDynamoDB{items: map[string][]map[string]*dynamodb.AttributeValue{
+		"d#0": {testItem("1"), testItem("5"), testItem("10")},
+		"d#1": {testItem("2"), testItem("3")},
+		"d#2": {testItem("4")},
+	}}
+	s := New(svc, "events", "Day", func(s *Sharder) {
+		s.Shards = 3
+		s.SortKey = "T"
+	})
+
+	sortCond := expression.SortKeyGreaterThan("T", 0)
+	items, err := s.Query("d", QueryOptions{Sort: &sortCond, ConsistentRead: true})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "1,2,3,4,5,10", itemTimes(items); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 6, len(svc.queries); e != a {
+		t.Errorf("expect %d queries, got %d", e, a)
+	}
+	for _, q := range svc.queries {
+		if e, a := "#k0 = :k0 AND #k1 > :k1", aws.StringValue(q.KeyConditionExpression); e != a {
+			t.Errorf("expect %v, got %v", e, a)
+		}
+		if !aws.BoolValue(q.ConsistentRead) {
+			t.Errorf("expect consistent read")
+		}
+	}
+
+	svc.queries = nil
+	var out []struct{ T int }
+	if err := s.QueryInto("d", QueryOptions{Limit: 2, Descending: true}, &out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "[{10} {5}]", fmt.Sprint(out); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	for _, q := range svc.queries {
+		if aws.BoolValue(q.ScanIndexForward) {
+			t.Errorf("expect descending queries")
+		}
+		if aws.Int64Value(q.Limit) > 2 {
+			t.Errorf("expect limit of at most 2, got %v", aws.Int64Value(q.Limit))
+		}
+	}
+}
+
+func TestQuerySortKey(t *testing.T) {
+	item := func(t, i string) map[string]*dynamodb.AttributeValue {
+		av := testItem(t)
+		av["I"] = &dynamodb.AttributeValue{S: aws.String(i)}
+		return av
+	}
+	svc := &mockDynamoDB{items: map[string][]map[string]*dynamodb.AttributeValue{
+		"d#0": {item("1", "c"), item("4", "a")},
+		"d#1": {item("2", "b")},
+	}}
+	s := New(svc, "events", "Day", func(s *Sharder) {
+		s.Shards = 2
+		s.SortKey = "T"
+	})
+
+	items, err := s.Query("d", QueryOptions{IndexName: "byI", SortKey: "I"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "4,2,1", itemTimes(items); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b   *dynamodb.AttributeValue
+		expect int
+	}{
+		{&dynamodb.AttributeValue{N: aws.String("9")}, &dynamodb.AttributeValue{N: aws.String("10")}, -1},
+		{&dynamodb.AttributeValue{N: aws.String("1.0")}, &dynamodb.AttributeValue{N: aws.String("1")}, 0},
+		{&dynamodb.AttributeValue{S: aws.String("b")}, &dynamodb.AttributeValue{S: aws.String("a")}, 1},
+		{&dynamodb.AttributeValue{B: []byte{1}}, &dynamodb.AttributeValue{B: []byte{1, 0}}, -1},
+		{&dynamodb.AttributeValue{S: aws.String("a")}, nil, 0},
+		{&dynamodb.AttributeValue{S: aws.String("a")}, &dynamodb.AttributeValue{N: aws.String("1")}, 0},
+	}
+	for i, c := range cases {
+		if e, a := c.expect, compare(c.a, c.b); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
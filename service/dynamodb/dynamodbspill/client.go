@@ -0,0 +1,254 @@
+package dynamodbspill
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultPointerAttribute is the default name of the attribute referencing
+// the S3 object an item's attributes were spilled to.
+const DefaultPointerAttribute = "*dynamodb-s3-spill*"
+
+// A Client spills the attributes of large items to S3, and rehydrates the
+// items read back. A Client is safe for concurrent use once configured.
+type Client struct {
+	// The S3 client the spilled attributes are written with.
+	S3 s3iface.S3API
+
+	// Bucket the spilled attributes are written to, and the prefix of the
+	// keys of the objects written.
+	Bucket    string
+	KeyPrefix string
+
+	// Size in bytes an item must exceed before its attributes are spilled.
+	//
+	// Defaults to dynamodbattribute.MaxItemSize.
+	Threshold int
+
+	// Names of the attributes which will be spilled. Attributes not in the
+	// item are ignored.
+	//
+	// Defaults to nil, spilling all attributes except KeyAttributes.
+	Attributes []string
+
+	// Names of the item's key attributes, which are never spilled. Index
+	// key attributes should also be included.
+	KeyAttributes []string
+
+	// Name of the attribute referencing the S3 object the attributes were
+	// spilled to.
+	//
+	// Defaults to DefaultPointerAttribute.
+	PointerAttribute string
+}
+
+// New creates a new Client spilling attributes to the bucket. Use the
+// `opts` functional options to override the default configuration.
+func New(client s3iface.S3API, bucket string, opts ...func(*Client)) *Client {
+	c := &Client{
+		S3:               client,
+		Bucket:           bucket,
+		Threshold:        dynamodbattribute.MaxItemSize,
+		PointerAttribute: DefaultPointerAttribute,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// MarshalMap marshals the Go value into an item with MarshalMapWithOptions,
+// and spills its attributes to S3 if it is larger than the Threshold.
+func (c *Client) MarshalMap(in interface{}, opts ...func(*dynamodbattribute.Encoder)) (map[string]*dynamodb.AttributeValue, error) {
+	item, err := dynamodbattribute.MarshalMapWithOptions(in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Offload(item)
+}
+
+// UnmarshalMap rehydrates the item's spilled attributes, and unmarshals it
+// into out with UnmarshalMapWithOptions.
+func (c *Client) UnmarshalMap(item map[string]*dynamodb.AttributeValue, out interface{}, opts ...func(*dynamodbattribute.Decoder)) error {
+	item, err := c.Rehydrate(item)
+	if err != nil {
+		return err
+	}
+
+	return dynamodbattribute.UnmarshalMapWithOptions(item, out, opts...)
+}
+
+// Offload returns the item with its attributes spilled to S3, if the item
+// is larger than the Threshold. Otherwise the item is returned unchanged.
+// The item passed in is not modified.
+//
+// A dynamodbattribute.ItemSizeError is returned if the item would still be
+// too large for DynamoDB, in which case nothing is written to S3.
+func (c *Client) Offload(item map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	if dynamodbattribute.ItemSize(item) <= c.Threshold {
+		return item, nil
+	}
+
+	out := make(map[string]*dynamodb.AttributeValue, len(item))
+	spilled := map[string]*dynamodb.AttributeValue{}
+	for name, av := range item {
+		if c.spills(name) {
+			spilled[name] = av
+		} else {
+			out[name] = av
+		}
+	}
+	if len(spilled) == 0 {
+		if err := dynamodbattribute.ValidateItemSize(item); err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+
+	name, err := newObjectName()
+	if err != nil {
+		return nil, err
+	}
+	key := c.KeyPrefix + name
+	out[c.PointerAttribute] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"Bucket": {S: aws.String(c.Bucket)},
+		"Key":    {S: aws.String(key)},
+	}}
+	if err := dynamodbattribute.ValidateItemSize(out); err != nil {
+		return nil, err
+	}
+
+	b, err := dynamodbattribute.MarshalWireJSONMap(spilled)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.S3.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(c.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(b),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Rehydrate returns the item with its spilled attributes read from S3 and
+// restored. Items without the pointer attribute are returned unchanged.
+// The item passed in is not modified.
+func (c *Client) Rehydrate(item map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	bucket, key, ok, err := c.pointer(item)
+	if !ok || err != nil {
+		return item, err
+	}
+
+	resp, err := c.S3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	spilled, err := dynamodbattribute.UnmarshalWireJSONMap(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spilled attributes s3://%s/%s, %v", bucket, key, err)
+	}
+
+	out := make(map[string]*dynamodb.AttributeValue, len(item)+len(spilled))
+	for name, av := range item {
+		if name != c.PointerAttribute {
+			out[name] = av
+		}
+	}
+	for name, av := range spilled {
+		out[name] = av
+	}
+
+	return out, nil
+}
+
+// Delete deletes the S3 object the item's attributes were spilled to, if
+// any. Use it when the item is deleted or replaced.
+func (c *Client) Delete(item map[string]*dynamodb.AttributeValue) error {
+	bucket, key, ok, err := c.pointer(item)
+	if !ok || err != nil {
+		return err
+	}
+
+	_, err = c.S3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// spills returns if the attribute is spilled to S3 when the item is too
+// large.
+func (c *Client) spills(name string) bool {
+	if name == c.PointerAttribute || containsString(c.KeyAttributes, name) {
+		return false
+	}
+	return c.Attributes == nil || containsString(c.Attributes, name)
+}
+
+// pointer returns the bucket and key of the object referenced by the
+// item's pointer attribute, and true if the item has the attribute.
+func (c *Client) pointer(item map[string]*dynamodb.AttributeValue) (bucket, key string, ok bool, err error) {
+	av, ok := item[c.PointerAttribute]
+	if !ok {
+		return "", "", false, nil
+	}
+
+	if av.M != nil {
+		bucket, key = stringValue(av.M["Bucket"]), stringValue(av.M["Key"])
+	}
+	if len(bucket) == 0 || len(key) == 0 {
+		return "", "", true, fmt.Errorf("invalid spill pointer attribute %s", c.PointerAttribute)
+	}
+
+	return bucket, key, true, nil
+}
+
+func stringValue(av *dynamodb.AttributeValue) string {
+	if av == nil {
+		return ""
+	}
+	return aws.StringValue(av.S)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// newObjectName returns a random name for a spilled attributes object.
+func newObjectName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate object name, %v", err)
+	}
+	return hex.EncodeToString(b) + ".json", nil
+}
@@ -0,0 +1,174 @@
+package dynamodbspill
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type mockS3 struct {
+	s3iface.S3API
+	objects map[string][]byte
+}
+
+func (m *mockS3) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	b, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.objects[*input.Bucket+"/"+*input.Key] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *mockS3) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	b, ok := m.objects[*input.Bucket+"/"+*input.Key]
+	if !ok {
+		return nil, awserr.New("NoSuchKey", "key not found", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func (m *mockS3) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	delete(m.objects, *input.Bucket+"/"+*input.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+type document struct {
+	ID    string
+	Title string
+	Body  string
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	m := &mockS3{objects: map[string][]byte{}}
+	c := New(m, "bucket", func(c *Client) {
+		c.KeyPrefix = "spill/"
+		c.Threshold = 100
+		c.Attributes = []string{"Body", "Missing"}
+	})
+
+	in := document{ID: "abc", Title: "title", Body: strings.Repeat("a", 200)}
+	item, err := c.MarshalMap(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if _, ok := item["Body"]; ok {
+		t.Errorf("expect Body to be spilled")
+	}
+	if e, a := "title", aws.StringValue(item["Title"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	ptr := item[DefaultPointerAttribute]
+	if ptr == nil {
+		t.Fatalf("expect pointer attribute")
+	}
+	if e, a := "bucket", aws.StringValue(ptr.M["Bucket"].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	key := aws.StringValue(ptr.M["Key"].S)
+	if !strings.HasPrefix(key, "spill/") {
+		t.Errorf("expect key prefix, got %v", key)
+	}
+	if _, ok := m.objects["bucket/"+key]; !ok {
+		t.Errorf("expect object to be written, %v", key)
+	}
+
+	var out document
+	if err := c.UnmarshalMap(item, &out); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := in, out; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if err := c.Delete(item); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 0, len(m.objects); e != a {
+		t.Errorf("expect %d objects, got %d", e, a)
+	}
+}
+
+func TestClientOffloadUnderThreshold(t *testing.T) {
+	m := &mockS3{objects: map[string][]byte{}}
+	c := New(m, "bucket")
+
+	item, err := c.MarshalMap(document{ID: "abc", Body: "small"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := item[DefaultPointerAttribute]; ok {
+		t.Errorf("expect item not to be spilled")
+	}
+	if e, a := 0, len(m.objects); e != a {
+		t.Errorf("expect %d objects, got %d", e, a)
+	}
+
+	// Items without a pointer are not read from S3.
+	out, err := c.Rehydrate(item)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := item, out; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestClientOffloadKeyAttributes(t *testing.T) {
+	m := &mockS3{objects: map[string][]byte{}}
+	c := New(m, "bucket", func(c *Client) {
+		c.Threshold = 10
+		c.KeyAttributes = []string{"ID"}
+	})
+
+	item, err := c.Offload(map[string]*dynamodb.AttributeValue{
+		"ID":    {S: aws.String("abc")},
+		"Title": {S: aws.String("title")},
+		"Body":  {S: aws.String("body")},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(item); e != a {
+		t.Errorf("expect %d attributes, got %d, %v", e, a, item)
+	}
+	if _, ok := item["ID"]; !ok {
+		t.Errorf("expect key attribute not to be spilled")
+	}
+}
+
+func TestClientOffloadTooLarge(t *testing.T) {
+	m := &mockS3{objects: map[string][]byte{}}
+	c := New(m, "bucket", func(c *Client) {
+		c.Attributes = []string{"Title"}
+	})
+
+	_, err := c.MarshalMap(document{ID: "abc", Title: "title", Body: strings.Repeat("a", dynamodbattribute.MaxItemSize)})
+	if _, ok := err.(*dynamodbattribute.ItemSizeError); !ok {
+		t.Errorf("expect *ItemSizeError, got %T, %v", err, err)
+	}
+	if e, a := 0, len(m.objects); e != a {
+		t.Errorf("expect %d objects, got %d", e, a)
+	}
+}
+
+func TestClientRehydrateInvalidPointer(t *testing.T) {
+	c := New(&mockS3{objects: map[string][]byte{}}, "bucket")
+
+	_, err := c.Rehydrate(map[string]*dynamodb.AttributeValue{
+		DefaultPointerAttribute: {S: aws.String("abc")},
+	})
+	if err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
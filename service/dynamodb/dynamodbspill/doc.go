@@ -0,0 +1,22 @@
+// Package dynamodbspill provides storing the large attributes of DynamoDB
+// items in Amazon S3, for items which would otherwise exceed DynamoDB's
+// 400KB item size limit.
+//
+// When a marshaled item is larger than the Client's Threshold, its spilled
+// attributes are written to a single S3 object and removed from the item.
+// A pointer attribute naming the object is added in their place. Items read
+// back are rehydrated by reading the object and restoring the attributes.
+//
+//     spill := dynamodbspill.New(s3.New(sess), "bucket", func(c *dynamodbspill.Client) {
+//         c.Attributes = []string{"Body", "Attachments"}
+//     })
+//
+//     item, err := spill.MarshalMap(doc)
+//     // write item with PutItem
+//
+//     // read item with GetItem
+//     err = spill.UnmarshalMap(resp.Item, &doc)
+//
+// S3 objects are not deleted when items are overwritten or deleted. Use
+// the Client's Delete method with the item being replaced to remove them.
+package dynamodbspill
@@ -0,0 +1,69 @@
+package dynamodbstore
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// A Codec encodes the values of a Store to the AttributeValues stored, and
+// decodes them back. The method signatures match those of the
+// dynamodbattribute Encoder and Decoder.
+type Codec interface {
+	Encode(in interface{}) (*dynamodb.AttributeValue, error)
+	Decode(av *dynamodb.AttributeValue, out interface{}) error
+}
+
+// An AttributeCodec is a Codec marshaling values with a dynamodbattribute
+// Encoder and Decoder, so structs are stored as map AttributeValues.
+type AttributeCodec struct {
+	// Encoder and Decoder the values are marshaled with.
+	//
+	// Default to the configuration of dynamodbattribute.Marshal and
+	// Unmarshal.
+	Encoder *dynamodbattribute.Encoder
+	Decoder *dynamodbattribute.Decoder
+}
+
+// Encode marshals the value with the AttributeCodec's Encoder.
+func (c AttributeCodec) Encode(in interface{}) (*dynamodb.AttributeValue, error) {
+	if c.Encoder == nil {
+		return dynamodbattribute.Marshal(in)
+	}
+	return c.Encoder.Encode(in)
+}
+
+// Decode unmarshals the value with the AttributeCodec's Decoder.
+func (c AttributeCodec) Decode(av *dynamodb.AttributeValue, out interface{}) error {
+	if c.Decoder == nil {
+		return dynamodbattribute.Unmarshal(av, out)
+	}
+	return c.Decoder.Decode(av, out)
+}
+
+// A JSONCodec is a Codec storing values as String AttributeValues of their
+// encoding/json encoding, for values whose types define JSON encodings.
+type JSONCodec struct{}
+
+// Encode returns the String AttributeValue of the value's JSON encoding.
+func (JSONCodec) Encode(in interface{}) (*dynamodb.AttributeValue, error) {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.AttributeValue{S: aws.String(string(b))}, nil
+}
+
+// Decode decodes the JSON encoding of the String AttributeValue into out.
+func (JSONCodec) Decode(av *dynamodb.AttributeValue, out interface{}) error {
+	if av == nil || av.S == nil {
+		return &dynamodbattribute.UnmarshalTypeError{
+			Value: "non-string attribute",
+			Type:  reflect.TypeOf(out),
+		}
+	}
+	return json.Unmarshal([]byte(*av.S), out)
+}
@@ -0,0 +1,24 @@
+// Package dynamodbstore provides a key-value store over a DynamoDB table,
+// with values that expire, for HTTP sessions, cached feature flags, and
+// other state shared between hosts.
+//
+// Each value is an item of the table, with the key in the partition key
+// attribute, the encoded value in a value attribute, and the time it
+// expires in a number attribute, in seconds since the Unix epoch. Enable
+// DynamoDB's Time to Live on the expiry attribute so expired items are
+// deleted. Items are deleted some time after they expire, so expired items
+// are also ignored when they are read.
+//
+//     sessions := dynamodbstore.New(dynamodb.New(sess), "sessions", func(s *dynamodbstore.Store) {
+//         s.TTL = 30 * time.Minute
+//     })
+//
+//     err := sessions.Put(sessionID, session)
+//
+//     var session Session
+//     found, err := sessions.Get(sessionID, &session)
+//
+// Values are marshaled with the dynamodbattribute package by default.
+// Set the Store's Codec to encode them differently, such as JSONCodec to
+// store them as JSON strings.
+package dynamodbstore
@@ -0,0 +1,162 @@
+package dynamodbstore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// Default attribute names of a Store's items.
+const (
+	DefaultKeyAttribute    = "Key"
+	DefaultValueAttribute  = "Value"
+	DefaultExpiryAttribute = "ExpiresAt"
+)
+
+// A Store reads and writes values by key in a DynamoDB table. A Store is
+// safe for concurrent use once configured.
+type Store struct {
+	// The DynamoDB client the table is read and written with.
+	DynamoDB dynamodbiface.DynamoDBAPI
+
+	// Name of the table.
+	TableName string
+
+	// Names of the table's string partition key attribute, the attribute
+	// values are stored in, and the attribute the time values expire is
+	// stored in.
+	//
+	// Default to DefaultKeyAttribute, DefaultValueAttribute, and
+	// DefaultExpiryAttribute.
+	KeyAttribute    string
+	ValueAttribute  string
+	ExpiryAttribute string
+
+	// Duration values written by Put expire after. Zero stores values
+	// which do not expire.
+	//
+	// Defaults to zero.
+	TTL time.Duration
+
+	// Codec the values are encoded and decoded with.
+	//
+	// Defaults to AttributeCodec{}.
+	Codec Codec
+
+	// Values are read with strongly consistent reads.
+	//
+	// Defaults to false.
+	ConsistentRead bool
+
+	// Function returning the current time expiry is computed from.
+	//
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// New creates a new Store of the values in the table. Use the `opts`
+// functional options to override the default configuration.
+func New(svc dynamodbiface.DynamoDBAPI, tableName string, opts ...func(*Store)) *Store {
+	s := &Store{
+		DynamoDB:        svc,
+		TableName:       tableName,
+		KeyAttribute:    DefaultKeyAttribute,
+		ValueAttribute:  DefaultValueAttribute,
+		ExpiryAttribute: DefaultExpiryAttribute,
+		Codec:           AttributeCodec{},
+		Now:             time.Now,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Get decodes the value of the key into out, returning false if the store
+// has no value for the key, or the value has expired.
+func (s *Store) Get(key string, out interface{}) (bool, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(s.TableName),
+		Key:       s.key(key),
+	}
+	if s.ConsistentRead {
+		input.ConsistentRead = aws.Bool(true)
+	}
+
+	resp, err := s.DynamoDB.GetItem(input)
+	if err != nil {
+		return false, err
+	}
+	if resp.Item == nil || s.expired(resp.Item) {
+		return false, nil
+	}
+
+	value, ok := resp.Item[s.ValueAttribute]
+	if !ok {
+		return false, nil
+	}
+	return true, s.Codec.Decode(value, out)
+}
+
+// Put writes the value of the key, replacing any previous value. The value
+// expires after the Store's TTL.
+func (s *Store) Put(key string, value interface{}) error {
+	return s.PutWithTTL(key, value, s.TTL)
+}
+
+// PutWithTTL writes the value of the key, replacing any previous value. The
+// value expires after ttl, or does not expire if ttl is zero.
+func (s *Store) PutWithTTL(key string, value interface{}, ttl time.Duration) error {
+	av, err := s.Codec.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	item := s.key(key)
+	item[s.ValueAttribute] = av
+	if ttl > 0 {
+		expires := s.Now().Add(ttl).Unix()
+		item[s.ExpiryAttribute] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(expires, 10))}
+	}
+
+	_, err = s.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(s.TableName),
+		Item:      item,
+	})
+	return err
+}
+
+// Delete deletes the value of the key. Deleting a key without a value is
+// not an error.
+func (s *Store) Delete(key string) error {
+	_, err := s.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(s.TableName),
+		Key:       s.key(key),
+	})
+	return err
+}
+
+// key returns the key of the item of the value.
+func (s *Store) key(key string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		s.KeyAttribute: {S: aws.String(key)},
+	}
+}
+
+// expired returns if the item's expiry time has passed. Items without an
+// expiry time do not expire.
+func (s *Store) expired(item map[string]*dynamodb.AttributeValue) bool {
+	av, ok := item[s.ExpiryAttribute]
+	if !ok || av.N == nil {
+		return false
+	}
+	expires, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil {
+		return false
+	}
+	return s.Now().Unix() >= expires
+}
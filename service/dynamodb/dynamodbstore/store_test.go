@@ -0,0 +1,139 @@
+package dynamodbstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// mockDynamoDB stores items in memory by their Key attribute.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	items map[string]map[string]*dynamodb.AttributeValue
+	gets  []*dynamodb.GetItemInput
+}
+
+func newMockDynamoDB() *mockDynamoDB {
+	return &mockDynamoDB{items: map[string]map[string]*dynamodb.AttributeValue{}}
+}
+
+func (m *mockDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	m.items[*input.Item[DefaultKeyAttribute].S] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	m.gets = append(m.gets, input)
+	return &dynamodb.GetItemOutput{Item: m.items[*input.Key[DefaultKeyAttribute].S]}, nil
+}
+
+func (m *mockDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	delete(m.items, *input.Key[DefaultKeyAttribute].S)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+type testSession struct {
+	UserID string
+	Roles  []string
+}
+
+func newTestStore(svc dynamodbiface.DynamoDBAPI, now *time.Time, opts ...func(*Store)) *Store {
+	return New(svc, "sessions", append([]func(*Store){func(s *Store) {
+		s.Now = func() time.Time { return *now }
+	}}, opts...)...)
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	svc := newMockDynamoDB()
+	now := time.Unix(1000, 0)
+	s := newTestStore(svc, &now, func(s *Store) { s.ConsistentRead = true })
+
+	if err := s.Put("abc", testSession{UserID: "u1", Roles: []string{"admin"}}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	item := svc.items["abc"]
+	if item[DefaultValueAttribute].M == nil {
+		t.Errorf("expect value stored as a map, got %v", item[DefaultValueAttribute])
+	}
+	if _, ok := item[DefaultExpiryAttribute]; ok {
+		t.Errorf("expect no expiry without a TTL, got %v", item[DefaultExpiryAttribute])
+	}
+
+	var out testSession
+	found, err := s.Get("abc", &out)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !found || out.UserID != "u1" || len(out.Roles) != 1 || out.Roles[0] != "admin" {
+		t.Errorf("expect session found, got %v %#v", found, out)
+	}
+	if !aws.BoolValue(svc.gets[0].ConsistentRead) {
+		t.Errorf("expect consistent read")
+	}
+
+	if err := s.Delete("abc"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if found, err := s.Get("abc", &out); err != nil || found {
+		t.Errorf("expect deleted value not found, got %v, %v", found, err)
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	svc := newMockDynamoDB()
+	now := time.Unix(1000, 0)
+	s := newTestStore(svc, &now, func(s *Store) { s.TTL = time.Minute })
+
+	if err := s.Put("abc", "v1"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "1060", aws.StringValue(svc.items["abc"][DefaultExpiryAttribute].N); e != a {
+		t.Errorf("expect expiry %v, got %v", e, a)
+	}
+	if err := s.PutWithTTL("def", "v2", time.Hour); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "4600", aws.StringValue(svc.items["def"][DefaultExpiryAttribute].N); e != a {
+		t.Errorf("expect expiry %v, got %v", e, a)
+	}
+
+	var out string
+	if found, err := s.Get("abc", &out); err != nil || !found || out != "v1" {
+		t.Errorf("expect v1 found before expiry, got %v %q, %v", found, out, err)
+	}
+
+	now = time.Unix(1060, 0)
+	if found, err := s.Get("abc", &out); err != nil || found {
+		t.Errorf("expect expired value not found, got %v, %v", found, err)
+	}
+	if found, err := s.Get("def", &out); err != nil || !found || out != "v2" {
+		t.Errorf("expect v2 found before expiry, got %v %q, %v", found, out, err)
+	}
+}
+
+func TestStoreJSONCodec(t *testing.T) {
+	svc := newMockDynamoDB()
+	now := time.Unix(1000, 0)
+	s := newTestStore(svc, &now, func(s *Store) { s.Codec = JSONCodec{} })
+
+	if err := s.Put("abc", testSession{UserID: "u1"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := `{"UserID":"u1","Roles":null}`, aws.StringValue(svc.items["abc"][DefaultValueAttribute].S); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var out testSession
+	if found, err := s.Get("abc", &out); err != nil || !found || out.UserID != "u1" {
+		t.Errorf("expect session found, got %v %#v, %v", found, out, err)
+	}
+
+	svc.items["abc"][DefaultValueAttribute] = &dynamodb.AttributeValue{N: aws.String("1")}
+	if _, err := s.Get("abc", &out); err == nil {
+		t.Errorf("expect error decoding a non-string value")
+	}
+}
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbstore
+
+import "time"
+
+// A TypedStore is a Store of values of type T, whose methods take and
+// return values of T instead of interface{} values.
+//
+//     flags := dynamodbstore.NewTyped[Flags](dynamodbstore.New(svc, "flags"))
+//     current, found, err := flags.Get("checkout")
+type TypedStore[T any] struct {
+	store *Store
+}
+
+// NewTyped returns a TypedStore of the values of type T in the Store.
+func NewTyped[T any](s *Store) *TypedStore[T] {
+	return &TypedStore[T]{store: s}
+}
+
+// Store returns the untyped Store the TypedStore reads and writes with.
+func (s *TypedStore[T]) Store() *Store {
+	return s.store
+}
+
+// Get returns the value of the key. See Store.Get.
+func (s *TypedStore[T]) Get(key string) (T, bool, error) {
+	var out T
+	found, err := s.store.Get(key, &out)
+	return out, found, err
+}
+
+// Put writes the value of the key. See Store.Put.
+func (s *TypedStore[T]) Put(key string, value T) error {
+	return s.store.Put(key, value)
+}
+
+// PutWithTTL writes the value of the key, which expires after ttl. See
+// Store.PutWithTTL.
+func (s *TypedStore[T]) PutWithTTL(key string, value T, ttl time.Duration) error {
+	return s.store.PutWithTTL(key, value, ttl)
+}
+
+// Delete deletes the value of the key. See Store.Delete.
+func (s *TypedStore[T]) Delete(key string) error {
+	return s.store.Delete(key)
+}
@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+package dynamodbstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypedStore(t *testing.T) {
+	svc := newMockDynamoDB()
+	now := time.Unix(1000, 0)
+	s := NewTyped[testSession](newTestStore(svc, &now))
+
+	if err := s.PutWithTTL("abc", testSession{UserID: "u1"}, time.Second); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	out, found, err := s.Get("abc")
+	if err != nil || !found || out.UserID != "u1" {
+		t.Errorf("expect session found, got %v %#v, %v", found, out, err)
+	}
+
+	if err := s.Delete("abc"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, found, err := s.Get("abc"); err != nil || found {
+		t.Errorf("expect deleted value not found, got %v, %v", found, err)
+	}
+}
@@ -0,0 +1,133 @@
+package dynamodbtest
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+var update = flag.Bool("dynamodbtest.update", false, "rewrite golden files with the actual values")
+
+// TestingT is the subset of testing.TB used to report assertion failures.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// AssertEqual asserts the AttributeValues are semantically equal, as
+// compared by dynamodbattribute.Equal. On mismatch the failure is reported
+// as a line diff of the formatted values. Returns if the values are equal.
+func AssertEqual(t TestingT, expect, actual *dynamodb.AttributeValue) bool {
+	if dynamodbattribute.Equal(expect, actual) {
+		return true
+	}
+
+	t.Errorf("AttributeValue mismatch (-expect +actual):\n%s",
+		Diff(dynamodbattribute.Format(expect), dynamodbattribute.Format(actual)))
+	return false
+}
+
+// AssertItemEqual asserts the items are semantically equal. See AssertEqual.
+func AssertItemEqual(t TestingT, expect, actual map[string]*dynamodb.AttributeValue) bool {
+	if dynamodbattribute.Equal(&dynamodb.AttributeValue{M: expect}, &dynamodb.AttributeValue{M: actual}) {
+		return true
+	}
+
+	t.Errorf("item mismatch (-expect +actual):\n%s",
+		Diff(dynamodbattribute.FormatMap(expect), dynamodbattribute.FormatMap(actual)))
+	return false
+}
+
+// AssertGolden asserts the formatted item matches the contents of the
+// golden file at path. When the -dynamodbtest.update flag is set the golden
+// file is written with the formatted item instead, creating its directory
+// if needed. Returns if the item matched, or the file was written.
+func AssertGolden(t TestingT, path string, item map[string]*dynamodb.AttributeValue) bool {
+	actual := dynamodbattribute.FormatMap(item) + "\n"
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("failed to create golden file directory, %v", err)
+			return false
+		}
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Errorf("failed to write golden file, %v", err)
+			return false
+		}
+		return true
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("failed to read golden file, %v, run with -dynamodbtest.update to create it", err)
+		return false
+	}
+	if expect := string(b); expect != actual {
+		t.Errorf("item does not match golden file %s (-expect +actual):\n%s", path, Diff(expect, actual))
+		return false
+	}
+
+	return true
+}
+
+// Diff returns a line diff of the strings. Lines only in expect are
+// prefixed with "-", lines only in actual with "+", and lines in both
+// with a space.
+func Diff(expect, actual string) string {
+	a := strings.Split(strings.TrimSuffix(expect, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writeLine := func(prefix, line string) {
+		buf.WriteString(prefix)
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			writeLine(" ", a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			writeLine("-", a[i])
+			i++
+		default:
+			writeLine("+", b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		writeLine("-", a[i])
+	}
+	for ; j < len(b); j++ {
+		writeLine("+", b[j])
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package dynamodbtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type recordT struct {
+	errs []string
+}
+
+func (r *recordT) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertItemEqual(t *testing.T) {
+	expect := map[string]*dynamodb.AttributeValue{
+		"ID":   {S: aws.String("abc")},
+		"Num":  {N: aws.String("1")},
+		"Tags": {SS: []*string{aws.String("a"), aws.String("b")}},
+	}
+
+	// Semantically equal items pass.
+	r := &recordT{}
+	actual := map[string]*dynamodb.AttributeValue{
+		"ID":   {S: aws.String("abc")},
+		"Num":  {N: aws.String("1.0")},
+		"Tags": {SS: []*string{aws.String("b"), aws.String("a")}},
+	}
+	if !AssertItemEqual(r, expect, actual) || len(r.errs) != 0 {
+		t.Errorf("expect equal, got %v", r.errs)
+	}
+
+	r = &recordT{}
+	actual["Num"] = &dynamodb.AttributeValue{N: aws.String("2")}
+	if AssertItemEqual(r, expect, actual) {
+		t.Fatalf("expect not equal")
+	}
+	if e, a := 1, len(r.errs); e != a {
+		t.Fatalf("expect %d errors, got %d", e, a)
+	}
+	for _, line := range []string{"-  Num: N 1\n", "+  Num: N 2\n", "   ID: S \"abc\"\n"} {
+		if !strings.Contains(r.errs[0], line) {
+			t.Errorf("expect %q in failure, got\n%s", line, r.errs[0])
+		}
+	}
+}
+
+func TestAssertEqual(t *testing.T) {
+	r := &recordT{}
+	if AssertEqual(r, &dynamodb.AttributeValue{S: aws.String("a")}, nil) {
+		t.Fatalf("expect not equal")
+	}
+	if e, a := "-S \"a\"\n+<nil>\n", r.errs[0]; !strings.HasSuffix(a, e) {
+		t.Errorf("expect suffix %q, got %q", e, a)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dynamodbtest")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "testdata", "item.golden")
+	item := map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("abc")}}
+
+	// Missing golden files fail.
+	r := &recordT{}
+	if AssertGolden(r, path, item) {
+		t.Errorf("expect missing golden file to fail")
+	}
+
+	*update = true
+	ok := AssertGolden(r, path, item)
+	*update = false
+	if !ok {
+		t.Fatalf("expect golden file written, got %v", r.errs)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "{\n  ID: S \"abc\"\n}\n", string(b); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+
+	r = &recordT{}
+	if !AssertGolden(r, path, item) {
+		t.Errorf("expect golden file match, got %v", r.errs)
+	}
+
+	item["ID"] = &dynamodb.AttributeValue{S: aws.String("xyz")}
+	if AssertGolden(r, path, item) {
+		t.Fatalf("expect golden file mismatch")
+	}
+	if e, a := "-  ID: S \"abc\"\n+  ID: S \"xyz\"\n", r.errs[0]; !strings.Contains(a, e) {
+		t.Errorf("expect %q in failure, got\n%s", e, a)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		expect, actual, diff string
+	}{
+		{"a\nb\nc", "a\nb\nc", " a\n b\n c\n"},
+		{"a\nb\nc", "a\nc", " a\n-b\n c\n"},
+		{"a\nc", "a\nb\nc\nd", " a\n+b\n c\n+d\n"},
+		{"a", "b", "-a\n+b\n"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.diff, Diff(c.expect, c.actual); e != a {
+			t.Errorf("%d, expect %q, got %q", i, e, a)
+		}
+	}
+}
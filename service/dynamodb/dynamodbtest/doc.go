@@ -0,0 +1,35 @@
+// Package dynamodbtest provides helpers for testing code which reads and
+// writes DynamoDB items.
+//
+// The assertion helpers compare AttributeValues semantically, and report
+// mismatches as a line diff of the values formatted with
+// dynamodbattribute.Format, instead of dumps of the AttributeValue structs.
+//
+//     func TestMarshalOrder(t *testing.T) {
+//         item, err := dynamodbattribute.MarshalMap(order)
+//         if err != nil {
+//             t.Fatal(err)
+//         }
+//         dynamodbtest.AssertGolden(t, "testdata/order.golden", item)
+//     }
+//
+// Golden files are rewritten with the item's formatted value when the
+// tests are run with the -dynamodbtest.update flag.
+//
+// The Local harness runs integration tests against DynamoDB Local, or any
+// other endpoint. It creates the tables of a dynamodbmanager.Registry's
+// Models, seeds them with fixture items, and deletes the tables when
+// closed. Tests using NewLocal are skipped unless the
+// DYNAMODB_LOCAL_ENDPOINT environment variable is set.
+//
+//     func TestOrders(t *testing.T) {
+//         local := dynamodbtest.NewLocal(t, registry)
+//         defer local.Close()
+//
+//         local.CreateTable(Order{})
+//         local.Seed(&Order{CustomerID: "abc", OrderID: 1})
+//
+//         orders, err := dynamodbmanager.NewTable(local.DynamoDB, registry, Order{})
+//         // ...
+//     }
+package dynamodbtest
@@ -0,0 +1,163 @@
+package dynamodbtest
+
+import (
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbmanager"
+)
+
+// EndpointEnvVar is the environment variable with the endpoint of the
+// DynamoDB Local instance integration tests are run against, such as
+// http://localhost:8000.
+const EndpointEnvVar = "DYNAMODB_LOCAL_ENDPOINT"
+
+// TB is the subset of testing.TB used by a Local harness.
+type TB interface {
+	TestingT
+	Fatalf(format string, args ...interface{})
+	Skipf(format string, args ...interface{})
+}
+
+// NewLocalClient returns a DynamoDB client of the endpoint, such as a
+// DynamoDB Local instance, with static credentials and a region, which
+// DynamoDB Local requires but does not check. The configs are merged over
+// the client's config.
+func NewLocalClient(endpoint string, cfgs ...*aws.Config) *dynamodb.DynamoDB {
+	cfg := aws.NewConfig().
+		WithEndpoint(endpoint).
+		WithRegion("us-east-1").
+		WithCredentials(credentials.NewStaticCredentials("local", "local", ""))
+
+	return dynamodb.New(session.New(cfg), cfgs...)
+}
+
+// A Local is a harness for integration tests which creates the tables of a
+// Registry's Models, seeds them with items, and deletes the tables it
+// created when closed. Failures are reported to the test with Fatalf.
+//
+// Tests sharing a DynamoDB Local instance should use Models with different
+// table names, so they can run in parallel.
+type Local struct {
+	// The DynamoDB client the tables are created and seeded with.
+	DynamoDB dynamodbiface.DynamoDBAPI
+
+	// The Registry of the Models of the tables.
+	Registry *dynamodbmanager.Registry
+
+	// If true, CreateTable deletes an existing table with the same name
+	// first, so tables left by an earlier failed run do not leak items into
+	// the test. Set by NewLocal. Otherwise creating an existing table fails
+	// the test.
+	DeleteExisting bool
+
+	t      TB
+	tables []string
+}
+
+// NewLocal returns a Local harness of the DynamoDB Local instance at the
+// endpoint in the EndpointEnvVar environment variable. The test is skipped
+// if the environment variable is not set.
+func NewLocal(t TB, r *dynamodbmanager.Registry) *Local {
+	endpoint := os.Getenv(EndpointEnvVar)
+	if len(endpoint) == 0 {
+		t.Skipf("%s not set, skipping DynamoDB Local test", EndpointEnvVar)
+	}
+
+	l := New(t, NewLocalClient(endpoint), r)
+	l.DeleteExisting = true
+	return l
+}
+
+// New returns a Local harness of the tables of the DynamoDB client svc,
+// such as a client of an endpoint other than DynamoDB Local.
+func New(t TB, svc dynamodbiface.DynamoDBAPI, r *dynamodbmanager.Registry) *Local {
+	return &Local{DynamoDB: svc, Registry: r, t: t}
+}
+
+// CreateTable creates the table of the Model registered for the struct
+// type of v, and waits until it is ACTIVE. An existing table with the same
+// name is only deleted first if DeleteExisting is set. See
+// Registry.CreateTableInput.
+func (l *Local) CreateTable(v interface{}) {
+	input, err := l.Registry.CreateTableInput(v)
+	if err != nil {
+		l.t.Fatalf("failed to build table of %T, %v", v, err)
+		return
+	}
+
+	if l.DeleteExisting {
+		if err := l.deleteTable(*input.TableName); err != nil {
+			l.t.Fatalf("failed to delete existing table %s, %v", *input.TableName, err)
+			return
+		}
+	}
+	if _, err := l.DynamoDB.CreateTable(input); err != nil {
+		l.t.Fatalf("failed to create table %s, %v", *input.TableName, err)
+		return
+	}
+	l.tables = append(l.tables, *input.TableName)
+
+	for i := 0; ; i++ {
+		resp, err := l.DynamoDB.DescribeTable(&dynamodb.DescribeTableInput{TableName: input.TableName})
+		if err != nil {
+			l.t.Fatalf("failed to describe table %s, %v", *input.TableName, err)
+			return
+		}
+		if aws.StringValue(resp.Table.TableStatus) == dynamodb.TableStatusActive {
+			return
+		}
+		if i >= 50 {
+			l.t.Fatalf("table %s not active", *input.TableName)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Seed writes the items, pointers to structs of registered types, to their
+// tables. The items' BeforeSave hooks are called, but write rules, such as
+// the `version` struct tag option, are not checked, so fixtures can be
+// written in any state.
+func (l *Local) Seed(items ...interface{}) {
+	for _, item := range items {
+		av, m, err := l.Registry.MarshalItem(item)
+		if err != nil {
+			l.t.Fatalf("failed to marshal %T, %v", item, err)
+			return
+		}
+		_, err = l.DynamoDB.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(m.TableName),
+			Item:      av,
+		})
+		if err != nil {
+			l.t.Fatalf("failed to seed table %s, %v", m.TableName, err)
+			return
+		}
+	}
+}
+
+// Close deletes the tables created by the harness.
+func (l *Local) Close() {
+	for i := len(l.tables) - 1; i >= 0; i-- {
+		if err := l.deleteTable(l.tables[i]); err != nil {
+			l.t.Errorf("failed to delete table %s, %v", l.tables[i], err)
+		}
+	}
+	l.tables = nil
+}
+
+// deleteTable deletes the table, returning nil if it does not exist.
+func (l *Local) deleteTable(name string) error {
+	_, err := l.DynamoDB.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(name)})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ResourceNotFoundException" {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package dynamodbtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbmanager"
+)
+
+type testOrder struct {
+	CustomerID string `dynamodbav:",hashkey"`
+	OrderID    int    `dynamodbav:",rangekey"`
+	Status     string
+}
+
+func newTestRegistry(t *testing.T) *dynamodbmanager.Registry {
+	r := dynamodbmanager.NewRegistry()
+	if err := r.Register(testOrder{}, dynamodbmanager.Model{TableName: "orders"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return r
+}
+
+// mockDynamoDB records the tables created and deleted, and the items put.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	tables map[string]bool
+	ops    []string
+	items  []map[string]*dynamodb.AttributeValue
+
+	// The error DeleteTable fails with, if not nil.
+	deleteErr error
+}
+
+func (m *mockDynamoDB) CreateTable(input *dynamodb.CreateTableInput) (*dynamodb.CreateTableOutput, error) {
+	if m.tables[*input.TableName] {
+		return nil, awserr.New("ResourceInUseException", "table exists", nil)
+	}
+	m.tables[*input.TableName] = true
+	m.ops = append(m.ops, "create "+*input.TableName)
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (m *mockDynamoDB) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableStatus: aws.String(dynamodb.TableStatusActive),
+	}}, nil
+}
+
+func (m *mockDynamoDB) DeleteTable(input *dynamodb.DeleteTableInput) (*dynamodb.DeleteTableOutput, error) {
+	if m.deleteErr != nil {
+		return nil, m.deleteErr
+	}
+	if !m.tables[*input.TableName] {
+		return nil, awserr.New("ResourceNotFoundException", "not found", nil)
+	}
+	delete(m.tables, *input.TableName)
+	m.ops = append(m.ops, "delete "+*input.TableName)
+	return &dynamodb.DeleteTableOutput{}, nil
+}
+
+func (m *mockDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	m.items = append(m.items, input.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// recordTB records the failures reported to it.
+type recordTB struct {
+	recordT
+	skipped bool
+}
+
+func (r *recordTB) Fatalf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordTB) Skipf(format string, args ...interface{}) {
+	r.skipped = true
+}
+
+func TestLocalCreateSeedClose(t *testing.T) {
+	svc := &mockDynamoDB{tables: map[string]bool{"orders": true}}
+	r := &recordTB{}
+	local := New(r, svc, newTestRegistry(t))
+	local.DeleteExisting = true
+
+	local.CreateTable(testOrder{})
+	local.Seed(&testOrder{CustomerID: "abc", OrderID: 1}, &testOrder{CustomerID: "abc", OrderID: 2, Status: "NEW"})
+	local.Close()
+
+	if len(r.errs) != 0 {
+		t.Fatalf("expect no failures, got %v", r.errs)
+	}
+	if e, a := fmt.Sprint([]string{"delete orders", "create orders", "delete orders"}), fmt.Sprint(svc.ops); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 2, len(svc.items); e != a {
+		t.Fatalf("expect %d items, got %d", e, a)
+	}
+	AssertItemEqual(t, map[string]*dynamodb.AttributeValue{
+		"CustomerID": {S: aws.String("abc")},
+		"OrderID":    {N: aws.String("2")},
+		"Status":     {S: aws.String("NEW")},
+	}, svc.items[1])
+}
+
+func TestLocalFailures(t *testing.T) {
+	r := &recordTB{}
+	local := New(r, &mockDynamoDB{tables: map[string]bool{}}, newTestRegistry(t))
+
+	local.CreateTable(struct{ ID string }{})
+	local.Seed(&struct{ ID string }{})
+	if e, a := 2, len(r.errs); e != a {
+		t.Errorf("expect %d failures, got %v", e, r.errs)
+	}
+}
+
+func TestLocalCreateTableExisting(t *testing.T) {
+	svc := &mockDynamoDB{tables: map[string]bool{"orders": true}}
+	r := &recordTB{}
+	local := New(r, svc, newTestRegistry(t))
+
+	// Existing tables are not deleted unless DeleteExisting is set.
+	local.CreateTable(testOrder{})
+	if e, a := 1, len(r.errs); e != a {
+		t.Errorf("expect %d failure, got %v", e, r.errs)
+	}
+	if len(svc.ops) != 0 || !svc.tables["orders"] {
+		t.Errorf("expect existing table kept, got %v", svc.ops)
+	}
+
+	// Failing to delete the existing table fails the test.
+	r.errs = nil
+	svc.deleteErr = awserr.New("ResourceInUseException", "table busy", nil)
+	local.DeleteExisting = true
+	local.CreateTable(testOrder{})
+	if e, a := 1, len(r.errs); e != a {
+		t.Errorf("expect %d failure, got %v", e, r.errs)
+	}
+	if len(svc.ops) != 0 {
+		t.Errorf("expect no table created, got %v", svc.ops)
+	}
+}
+
+func TestNewLocalSkipped(t *testing.T) {
+	if len(os.Getenv(EndpointEnvVar)) != 0 {
+		t.Skipf("%s set", EndpointEnvVar)
+	}
+
+	r := &recordTB{}
+	NewLocal(r, newTestRegistry(t))
+	if !r.skipped {
+		t.Errorf("expect test skipped without %s", EndpointEnvVar)
+	}
+}
@@ -0,0 +1,36 @@
+// Package expression provides builders for the expression strings used by
+// DynamoDB API operations, such as a Query's KeyConditionExpression. The
+// builders substitute placeholders for attribute names and values, and
+// marshal the values with the dynamodbattribute package, so callers do not
+// need to build ExpressionAttributeNames and ExpressionAttributeValues by
+// hand.
+//
+// Build a key condition for a Query:
+//
+//     cond := expression.KeyEqual("OrderID", "abc123").
+//         AndSort(expression.SortKeyBetween("Created", start, end))
+//
+//     expr, err := cond.Build()
+//     if err != nil {
+//         fmt.Println("Failed to build key condition", err)
+//         return
+//     }
+//
+//     result, err := svc.Query(&dynamodb.QueryInput{
+//         TableName:                 aws.String("exampleTable"),
+//         KeyConditionExpression:    aws.String(expr.Expression),
+//         ExpressionAttributeNames:  expr.Names,
+//         ExpressionAttributeValues: expr.Values,
+//     })
+//
+// Build an update expression for an UpdateItem request:
+//
+//     update, err := expression.Update{}.
+//         Set("Status", "shipped").
+//         ListAppend("Events", []string{"shipped"}).
+//         Build()
+//
+// When built with Go 1.18 or later the package also provides generic
+// builders, such as KeyEq and SortBetween, which take typed key references
+// so the compiler checks that key operands match the model's key types.
+package expression
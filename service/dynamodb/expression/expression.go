@@ -0,0 +1,117 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// An Expression is a built DynamoDB expression string along with the
+// attribute name and value placeholders it references. Names and Values
+// can be used directly as an operation's ExpressionAttributeNames and
+// ExpressionAttributeValues.
+type Expression struct {
+	Expression string
+	Names      map[string]*string
+	Values     map[string]*dynamodb.AttributeValue
+}
+
+// aliasList substitutes placeholders for attribute names and values while
+// an expression is being built. Each kind of expression uses its own prefix
+// so that the placeholders of expressions used in the same operation never
+// collide.
+type aliasList struct {
+	prefix string
+
+	names      map[string]*string
+	namesByKey map[string]string
+	values     map[string]*dynamodb.AttributeValue
+}
+
+func newAliasList(prefix string) *aliasList {
+	return &aliasList{
+		prefix:     prefix,
+		names:      map[string]*string{},
+		namesByKey: map[string]string{},
+		values:     map[string]*dynamodb.AttributeValue{},
+	}
+}
+
+// aliasName returns the placeholder for the attribute name, reusing the
+// existing placeholder if the name has already been aliased.
+func (a *aliasList) aliasName(name string) string {
+	if alias, ok := a.namesByKey[name]; ok {
+		return alias
+	}
+
+	alias := "#" + a.prefix + strconv.Itoa(len(a.names))
+	n := name
+	a.names[alias] = &n
+	a.namesByKey[name] = alias
+
+	return alias
+}
+
+// aliasValue marshals the value and returns its placeholder.
+func (a *aliasList) aliasValue(v interface{}) (string, error) {
+	av, err := dynamodbattribute.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return a.aliasAttributeValue(av), nil
+}
+
+// aliasAttributeValue returns a placeholder for the already marshaled
+// AttributeValue.
+func (a *aliasList) aliasAttributeValue(av *dynamodb.AttributeValue) string {
+	alias := ":" + a.prefix + strconv.Itoa(len(a.values))
+	a.values[alias] = av
+
+	return alias
+}
+
+// expression returns the Expression for the expression string and the
+// placeholders aliased.
+func (a *aliasList) expression(expr string) Expression {
+	e := Expression{Expression: expr}
+	if len(a.names) != 0 {
+		e.Names = a.names
+	}
+	if len(a.values) != 0 {
+		e.Values = a.values
+	}
+
+	return e
+}
+
+// An InvalidParameterError is an error type representing an expression
+// builder which was given invalid input.
+type InvalidParameterError struct {
+	msg string
+}
+
+// Error returns the string representation of the error.
+// satisfying the error interface
+func (e *InvalidParameterError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// Code returns the code of the error, satisfying the awserr.Error
+// interface.
+func (e *InvalidParameterError) Code() string {
+	return "InvalidParameterError"
+}
+
+// Message returns the detailed message of the error, satisfying
+// the awserr.Error interface.
+func (e *InvalidParameterError) Message() string {
+	return e.msg
+}
+
+// OrigErr always returns nil, satisfying the awserr.Error interface.
+func (e *InvalidParameterError) OrigErr() error {
+	return nil
+}
@@ -0,0 +1,183 @@
+package expression
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// A KeyCondition is a builder for a Query's KeyConditionExpression. Use
+// KeyEqual to create the condition on the partition key, and AndSort to
+// optionally add a condition on the sort key.
+type KeyCondition struct {
+	partitionKey   string
+	partitionValue interface{}
+
+	sort *SortCondition
+}
+
+// KeyEqual returns a KeyCondition which matches items whose partition key
+// attribute, name, is equal to value.
+func KeyEqual(name string, value interface{}) KeyCondition {
+	return KeyCondition{partitionKey: name, partitionValue: value}
+}
+
+// AndSort returns a copy of the KeyCondition which additionally requires
+// the items' sort key to satisfy the SortCondition.
+func (k KeyCondition) AndSort(sort SortCondition) KeyCondition {
+	k.sort = &sort
+	return k
+}
+
+// Build returns the key condition Expression. An error is returned if any
+// of the key values cannot be marshaled to a string, number, or binary
+// AttributeValue.
+func (k KeyCondition) Build() (Expression, error) {
+	aliases := newAliasList("k")
+
+	expr, err := buildKeyComparison(aliases, k.partitionKey, "=", k.partitionValue)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if k.sort != nil {
+		sortExpr, err := k.sort.build(aliases)
+		if err != nil {
+			return Expression{}, err
+		}
+		expr += " AND " + sortExpr
+	}
+
+	return aliases.expression(expr), nil
+}
+
+type sortOperator int
+
+const (
+	sortEqual sortOperator = iota
+	sortLessThan
+	sortLessThanEqual
+	sortGreaterThan
+	sortGreaterThanEqual
+	sortBetween
+	sortBeginsWith
+)
+
+var sortComparators = map[sortOperator]string{
+	sortEqual:            "=",
+	sortLessThan:         "<",
+	sortLessThanEqual:    "<=",
+	sortGreaterThan:      ">",
+	sortGreaterThanEqual: ">=",
+}
+
+// A SortCondition is a condition on the sort key of a Query's key
+// condition. Use it with KeyCondition.AndSort.
+type SortCondition struct {
+	name   string
+	op     sortOperator
+	values []interface{}
+}
+
+// SortKeyEqual returns a SortCondition matching items whose sort key
+// attribute, name, is equal to value.
+func SortKeyEqual(name string, value interface{}) SortCondition {
+	return SortCondition{name: name, op: sortEqual, values: []interface{}{value}}
+}
+
+// SortKeyLessThan returns a SortCondition matching items whose sort key
+// attribute, name, is less than value.
+func SortKeyLessThan(name string, value interface{}) SortCondition {
+	return SortCondition{name: name, op: sortLessThan, values: []interface{}{value}}
+}
+
+// SortKeyLessThanEqual returns a SortCondition matching items whose sort
+// key attribute, name, is less than or equal to value.
+func SortKeyLessThanEqual(name string, value interface{}) SortCondition {
+	return SortCondition{name: name, op: sortLessThanEqual, values: []interface{}{value}}
+}
+
+// SortKeyGreaterThan returns a SortCondition matching items whose sort key
+// attribute, name, is greater than value.
+func SortKeyGreaterThan(name string, value interface{}) SortCondition {
+	return SortCondition{name: name, op: sortGreaterThan, values: []interface{}{value}}
+}
+
+// SortKeyGreaterThanEqual returns a SortCondition matching items whose
+// sort key attribute, name, is greater than or equal to value.
+func SortKeyGreaterThanEqual(name string, value interface{}) SortCondition {
+	return SortCondition{name: name, op: sortGreaterThanEqual, values: []interface{}{value}}
+}
+
+// SortKeyBetween returns a SortCondition matching items whose sort key
+// attribute, name, is greater than or equal to lower, and less than or
+// equal to upper.
+func SortKeyBetween(name string, lower, upper interface{}) SortCondition {
+	return SortCondition{name: name, op: sortBetween, values: []interface{}{lower, upper}}
+}
+
+// SortKeyBeginsWith returns a SortCondition matching items whose string
+// sort key attribute, name, begins with prefix.
+func SortKeyBeginsWith(name string, prefix string) SortCondition {
+	return SortCondition{name: name, op: sortBeginsWith, values: []interface{}{prefix}}
+}
+
+func (s SortCondition) build(aliases *aliasList) (string, error) {
+	switch s.op {
+	case sortBetween:
+		name := aliases.aliasName(s.name)
+		lower, err := aliasKeyValue(aliases, s.name, s.values[0])
+		if err != nil {
+			return "", err
+		}
+		upper, err := aliasKeyValue(aliases, s.name, s.values[1])
+		if err != nil {
+			return "", err
+		}
+		return name + " BETWEEN " + lower + " AND " + upper, nil
+
+	case sortBeginsWith:
+		name := aliases.aliasName(s.name)
+		prefix, err := aliasKeyValue(aliases, s.name, s.values[0])
+		if err != nil {
+			return "", err
+		}
+		return "begins_with(" + name + ", " + prefix + ")", nil
+
+	default:
+		return buildKeyComparison(aliases, s.name, sortComparators[s.op], s.values[0])
+	}
+}
+
+func buildKeyComparison(aliases *aliasList, name, comparator string, value interface{}) (string, error) {
+	if len(name) == 0 {
+		return "", &InvalidParameterError{msg: "key attribute name must not be empty"}
+	}
+
+	alias := aliases.aliasName(name)
+	valueAlias, err := aliasKeyValue(aliases, name, value)
+	if err != nil {
+		return "", err
+	}
+
+	return alias + " " + comparator + " " + valueAlias, nil
+}
+
+// aliasKeyValue marshals the key value and returns its placeholder. Key
+// attributes may only be strings, numbers, or binary.
+func aliasKeyValue(aliases *aliasList, name string, value interface{}) (string, error) {
+	alias, err := aliases.aliasValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	if !isKeyAttributeValue(aliases.values[alias]) {
+		return "", &InvalidParameterError{
+			msg: "key attribute " + name + " value must be a non-empty string, number, or binary",
+		}
+	}
+
+	return alias, nil
+}
+
+func isKeyAttributeValue(av *dynamodb.AttributeValue) bool {
+	return av.S != nil || av.N != nil || av.B != nil
+}
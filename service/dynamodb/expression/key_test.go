@@ -0,0 +1,101 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestKeyConditionBuild(t *testing.T) {
+	cases := []struct {
+		cond   KeyCondition
+		expect Expression
+		err    string
+	}{
+		{
+			cond: KeyEqual("id", "abc"),
+			expect: Expression{
+				Expression: "#k0 = :k0",
+				Names:      map[string]*string{"#k0": aws.String("id")},
+				Values: map[string]*dynamodb.AttributeValue{
+					":k0": {S: aws.String("abc")},
+				},
+			},
+		},
+		{
+			cond: KeyEqual("id", "abc").AndSort(SortKeyBetween("created", 1, 5)),
+			expect: Expression{
+				Expression: "#k0 = :k0 AND #k1 BETWEEN :k1 AND :k2",
+				Names: map[string]*string{
+					"#k0": aws.String("id"),
+					"#k1": aws.String("created"),
+				},
+				Values: map[string]*dynamodb.AttributeValue{
+					":k0": {S: aws.String("abc")},
+					":k1": {N: aws.String("1")},
+					":k2": {N: aws.String("5")},
+				},
+			},
+		},
+		{
+			cond: KeyEqual("id", 12).AndSort(SortKeyBeginsWith("sk", "ORDER#")),
+			expect: Expression{
+				Expression: "#k0 = :k0 AND begins_with(#k1, :k1)",
+				Names: map[string]*string{
+					"#k0": aws.String("id"),
+					"#k1": aws.String("sk"),
+				},
+				Values: map[string]*dynamodb.AttributeValue{
+					":k0": {N: aws.String("12")},
+					":k1": {S: aws.String("ORDER#")},
+				},
+			},
+		},
+		{
+			cond: KeyEqual("id", []byte{1}).AndSort(SortKeyGreaterThanEqual("sk", 2.5)),
+			expect: Expression{
+				Expression: "#k0 = :k0 AND #k1 >= :k1",
+				Names: map[string]*string{
+					"#k0": aws.String("id"),
+					"#k1": aws.String("sk"),
+				},
+				Values: map[string]*dynamodb.AttributeValue{
+					":k0": {B: []byte{1}},
+					":k1": {N: aws.String("2.5")},
+				},
+			},
+		},
+		{
+			cond: KeyEqual("id", ""),
+			err:  "InvalidParameterError: key attribute id value must be a non-empty string, number, or binary",
+		},
+		{
+			cond: KeyEqual("id", "abc").AndSort(SortKeyLessThan("sk", []string{"a"})),
+			err:  "InvalidParameterError: key attribute sk value must be a non-empty string, number, or binary",
+		},
+		{
+			cond: KeyEqual("", "abc"),
+			err:  "InvalidParameterError: key attribute name must not be empty",
+		},
+	}
+
+	for i, c := range cases {
+		actual, err := c.cond.Build()
+		if len(c.err) != 0 {
+			if err == nil {
+				t.Errorf("case %d, expect error %q, got none", i, c.err)
+			} else if e, a := c.err, err.Error(); e != a {
+				t.Errorf("case %d, expect error %q, got %q", i, e, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d, expect no error, got %v", i, err)
+		}
+		if e, a := c.expect, actual; !reflect.DeepEqual(e, a) {
+			t.Errorf("case %d, expect %v, got %v", i, e, a)
+		}
+	}
+}
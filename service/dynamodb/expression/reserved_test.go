@@ -0,0 +1,82 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestIsReservedWord(t *testing.T) {
+	cases := map[string]bool{
+		"name":    true,
+		"Status":  true,
+		"SIZE":    true,
+		"OrderID": false,
+		"":        false,
+	}
+
+	for name, expect := range cases {
+		if e, a := expect, IsReservedWord(name); e != a {
+			t.Errorf("%s, expect %t, got %t", name, e, a)
+		}
+	}
+}
+
+func TestEscapeReservedWords(t *testing.T) {
+	type order struct {
+		ID     string
+		Name   string `dynamodbav:"name"`
+		Status string `dynamodbav:"status"`
+		Size   int    `dynamodbav:"size"`
+		Amount int
+	}
+	names := dynamodbattribute.AttributeNames(order{})
+
+	cases := []struct {
+		expr   string
+		expect Expression
+	}{
+		{
+			expr:   "ID, Amount",
+			expect: Expression{Expression: "ID, Amount"},
+		},
+		{
+			expr: "ID, name, status",
+			expect: Expression{
+				Expression: "ID, #name, #status",
+				Names: map[string]*string{
+					"#name":   aws.String("name"),
+					"#status": aws.String("status"),
+				},
+			},
+		},
+		{
+			expr: "status = :status AND size(name) > :n AND size > #size",
+			expect: Expression{
+				Expression: "#status = :status AND size(#name) > :n AND #size > #size",
+				Names: map[string]*string{
+					"#name":   aws.String("name"),
+					"#status": aws.String("status"),
+					"#size":   aws.String("size"),
+				},
+			},
+		},
+		{
+			expr: "SET status = :s, Amount = Amount + :one REMOVE Name",
+			expect: Expression{
+				Expression: "SET #status = :s, Amount = Amount + :one REMOVE Name",
+				Names: map[string]*string{
+					"#status": aws.String("status"),
+				},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, EscapeReservedWords(c.expr, names); !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+//go:build go1.18
+// +build go1.18
+
+package expression
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A PartitionKey is a typed reference to the partition key attribute of the
+// model type M, whose Go type is T. Passing a PartitionKey to KeyEq lets the
+// compiler check that the key operand's type matches the model.
+//
+// Declare key references once, typically as package variables, with
+// NewPartitionKey so a mismatch with the model is caught at initialization.
+//
+//     var OrderIDKey = expression.NewPartitionKey[Order, string]("OrderID")
+type PartitionKey[M any, T any] struct {
+	name string
+}
+
+// NewPartitionKey returns a PartitionKey for the attribute name of the model
+// type M. NewPartitionKey panics if M has no field for the attribute, or if
+// the field's type is not T.
+func NewPartitionKey[M any, T any](name string) PartitionKey[M, T] {
+	mustMatchKeyField(typeOf[M](), typeOf[T](), name)
+	return PartitionKey[M, T]{name: name}
+}
+
+// Name returns the attribute name of the key.
+func (k PartitionKey[M, T]) Name() string {
+	return k.name
+}
+
+// A SortKey is a typed reference to the sort key attribute of the model
+// type M, whose Go type is T.
+//
+//     var OrderCreatedKey = expression.NewSortKey[Order, int64]("Created")
+type SortKey[M any, T any] struct {
+	name string
+}
+
+// NewSortKey returns a SortKey for the attribute name of the model type M.
+// NewSortKey panics if M has no field for the attribute, or if the field's
+// type is not T.
+func NewSortKey[M any, T any](name string) SortKey[M, T] {
+	mustMatchKeyField(typeOf[M](), typeOf[T](), name)
+	return SortKey[M, T]{name: name}
+}
+
+// Name returns the attribute name of the key.
+func (k SortKey[M, T]) Name() string {
+	return k.name
+}
+
+// A ModelKeyCondition is a KeyCondition on the keys of the model type M.
+type ModelKeyCondition[M any] struct {
+	KeyCondition
+}
+
+// And returns a copy of the ModelKeyCondition which additionally requires
+// the items' sort key to satisfy the condition. The sort condition must be
+// for the same model type.
+func (k ModelKeyCondition[M]) And(sort ModelSortCondition[M]) ModelKeyCondition[M] {
+	return ModelKeyCondition[M]{KeyCondition: k.KeyCondition.AndSort(sort.SortCondition)}
+}
+
+// A ModelSortCondition is a SortCondition on the sort key of the model
+// type M.
+type ModelSortCondition[M any] struct {
+	SortCondition
+}
+
+// KeyEq returns a key condition matching items whose partition key is equal
+// to value.
+func KeyEq[M any, T any](key PartitionKey[M, T], value T) ModelKeyCondition[M] {
+	return ModelKeyCondition[M]{KeyCondition: KeyEqual(key.name, value)}
+}
+
+// SortEq returns a sort condition matching items whose sort key is equal to
+// value.
+func SortEq[M any, T any](key SortKey[M, T], value T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyEqual(key.name, value)}
+}
+
+// SortLt returns a sort condition matching items whose sort key is less
+// than value.
+func SortLt[M any, T any](key SortKey[M, T], value T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyLessThan(key.name, value)}
+}
+
+// SortLe returns a sort condition matching items whose sort key is less
+// than or equal to value.
+func SortLe[M any, T any](key SortKey[M, T], value T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyLessThanEqual(key.name, value)}
+}
+
+// SortGt returns a sort condition matching items whose sort key is greater
+// than value.
+func SortGt[M any, T any](key SortKey[M, T], value T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyGreaterThan(key.name, value)}
+}
+
+// SortGe returns a sort condition matching items whose sort key is greater
+// than or equal to value.
+func SortGe[M any, T any](key SortKey[M, T], value T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyGreaterThanEqual(key.name, value)}
+}
+
+// SortBetween returns a sort condition matching items whose sort key is
+// greater than or equal to lower, and less than or equal to upper.
+func SortBetween[M any, T any](key SortKey[M, T], lower, upper T) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyBetween(key.name, lower, upper)}
+}
+
+// SortBeginsWith returns a sort condition matching items whose string sort
+// key begins with prefix.
+func SortBeginsWith[M any](key SortKey[M, string], prefix string) ModelSortCondition[M] {
+	return ModelSortCondition[M]{SortCondition: SortKeyBeginsWith(key.name, prefix)}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// mustMatchKeyField panics if the struct type model does not have a field
+// for the attribute name of type keyType, or keyType cannot be used as a
+// key attribute.
+func mustMatchKeyField(model, keyType reflect.Type, name string) {
+	switch keyType.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	case reflect.Slice:
+		if keyType.Elem().Kind() != reflect.Uint8 {
+			panic(fmt.Sprintf("key %s type %s is not a string, number, or binary type", name, keyType))
+		}
+	default:
+		panic(fmt.Sprintf("key %s type %s is not a string, number, or binary type", name, keyType))
+	}
+
+	for model.Kind() == reflect.Ptr {
+		model = model.Elem()
+	}
+	if model.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("model type %s must be a struct", model))
+	}
+
+	ft, ok := attributeFieldType(model, name)
+	if !ok {
+		panic(fmt.Sprintf("model type %s has no field for key attribute %s", model, name))
+	}
+	if ft != keyType && !(ft.Kind() == reflect.Ptr && ft.Elem() == keyType) {
+		panic(fmt.Sprintf("model type %s key attribute %s is type %s, not %s", model, name, ft, keyType))
+	}
+}
+
+// attributeFieldType returns the type of the field of the struct type t
+// which is marshaled to the attribute name, following the same struct tag
+// and embedded struct rules as the dynamodbattribute package.
+func attributeFieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		tagName, ignore := fieldTagName(sf.Tag)
+		if ignore {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && len(tagName) == 0 && ft.Kind() == reflect.Struct {
+			if t, ok := attributeFieldType(ft, name); ok {
+				return t, true
+			}
+			continue
+		}
+
+		if len(tagName) == 0 {
+			tagName = sf.Name
+		}
+		if tagName == name {
+			return sf.Type, true
+		}
+	}
+
+	return nil, false
+}
+
+func fieldTagName(tag reflect.StructTag) (name string, ignore bool) {
+	tagStr := tag.Get("dynamodbav")
+	if len(tagStr) == 0 {
+		tagStr = tag.Get("json")
+	}
+
+	name = strings.Split(tagStr, ",")[0]
+	return name, name == "-"
+}
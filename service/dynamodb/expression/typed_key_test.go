@@ -0,0 +1,81 @@
+//go:build go1.18
+// +build go1.18
+
+package expression
+
+import (
+	"testing"
+)
+
+type testOrder struct {
+	Customer string `dynamodbav:"customer"`
+	Created  int64
+	testOrderDetails
+}
+
+type testOrderDetails struct {
+	Ref *string `json:"ref"`
+}
+
+var (
+	testCustomerKey = NewPartitionKey[testOrder, string]("customer")
+	testCreatedKey  = NewSortKey[testOrder, int64]("Created")
+)
+
+func TestTypedKeyCondition(t *testing.T) {
+	expr, err := KeyEq(testCustomerKey, "abc").
+		And(SortBetween(testCreatedKey, 1, 10)).Build()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "#k0 = :k0 AND #k1 BETWEEN :k1 AND :k2", expr.Expression; e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+	if e, a := "Created", *expr.Names["#k1"]; e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+	if e, a := "10", *expr.Values[":k2"].N; e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestTypedKeyEmbeddedPointerField(t *testing.T) {
+	key := NewSortKey[testOrder, string]("ref")
+	if e, a := "ref", key.Name(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestTypedKeyMismatch(t *testing.T) {
+	cases := []struct {
+		fn     func()
+		expect string
+	}{
+		{
+			fn:     func() { NewPartitionKey[testOrder, int]("customer") },
+			expect: "model type expression.testOrder key attribute customer is type string, not int",
+		},
+		{
+			fn:     func() { NewSortKey[testOrder, int64]("missing") },
+			expect: "model type expression.testOrder has no field for key attribute missing",
+		},
+		{
+			fn:     func() { NewSortKey[testOrder, bool]("Created") },
+			expect: "key Created type bool is not a string, number, or binary type",
+		},
+	}
+
+	for i, c := range cases {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Errorf("case %d, expect panic", i)
+				} else if e, a := c.expect, r; e != a {
+					t.Errorf("case %d, expect %q, got %q", i, e, a)
+				}
+			}()
+			c.fn()
+		}()
+	}
+}
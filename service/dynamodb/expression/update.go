@@ -0,0 +1,228 @@
+package expression
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// UpdateFromDiff returns the update Expression which changes an item from
+// the old to the new item the diffs were made from, such as by
+// dynamodbattribute.Diff. Added and changed attributes are SET to their new
+// value, and removed attributes are REMOVEd. Only the attributes which
+// differ are written, instead of the whole item. The returned Expression's
+// string is empty if there are no diffs.
+//
+//     diffs := dynamodbattribute.Diff(oldItem, newItem)
+//     update := expression.UpdateFromDiff(diffs)
+//     params := &dynamodb.UpdateItemInput{
+//         Key:                       key,
+//         UpdateExpression:          aws.String(update.Expression),
+//         ExpressionAttributeNames:  update.Names,
+//         ExpressionAttributeValues: update.Values,
+//     }
+//
+// The primary key attributes of an item cannot be updated, so diffs of key
+// attributes must not be included.
+func UpdateFromDiff(diffs []dynamodbattribute.AttributeDiff) Expression {
+	aliases := newAliasList("u")
+
+	var sets, removes []string
+	for _, d := range diffs {
+		names := make([]string, len(d.Path))
+		for i, name := range d.Path {
+			names[i] = aliases.aliasName(name)
+		}
+		path := strings.Join(names, ".")
+
+		if d.Kind == dynamodbattribute.DiffRemoved {
+			removes = append(removes, path)
+			continue
+		}
+		sets = append(sets, path+" = "+aliases.aliasAttributeValue(d.New))
+	}
+
+	var clauses []string
+	if len(sets) != 0 {
+		clauses = append(clauses, "SET "+strings.Join(sets, ", "))
+	}
+	if len(removes) != 0 {
+		clauses = append(clauses, "REMOVE "+strings.Join(removes, ", "))
+	}
+
+	return aliases.expression(strings.Join(clauses, " "))
+}
+
+type updateOperator int
+
+const (
+	updateSet updateOperator = iota
+	updateRemove
+	updateListAppend
+	updateListPrepend
+	updateAdd
+	updateAddToSet
+	updateDeleteFromSet
+)
+
+type updateAction struct {
+	op    updateOperator
+	name  string
+	value interface{}
+}
+
+// An Update is a builder for an UpdateItem request's UpdateExpression.
+// Each method returns a copy of the Update with the action added, and
+// operands are marshaled with dynamodbattribute when the Update is built.
+//
+//     update, err := expression.Update{}.
+//         Set("status", "shipped").
+//         ListAppend("events", []string{"shipped"}).
+//         AddToSet("tags", []string{"priority"}).
+//         Add("count", 1).
+//         Build()
+type Update struct {
+	actions []updateAction
+}
+
+func (u Update) add(op updateOperator, name string, value interface{}) Update {
+	actions := make([]updateAction, len(u.actions), len(u.actions)+1)
+	copy(actions, u.actions)
+	u.actions = append(actions, updateAction{op: op, name: name, value: value})
+	return u
+}
+
+// Set returns a copy of the Update which SETs the attribute, name, to
+// value.
+func (u Update) Set(name string, value interface{}) Update {
+	return u.add(updateSet, name, value)
+}
+
+// Remove returns a copy of the Update which REMOVEs the attribute, name.
+func (u Update) Remove(name string) Update {
+	return u.add(updateRemove, name, nil)
+}
+
+// ListAppend returns a copy of the Update which appends the elements of
+// values, which must marshal to a non-empty list, to the end of the list
+// attribute, name, with list_append. The attribute is created if it does
+// not exist.
+func (u Update) ListAppend(name string, values interface{}) Update {
+	return u.add(updateListAppend, name, values)
+}
+
+// ListPrepend returns a copy of the Update which inserts the elements of
+// values, which must marshal to a non-empty list, at the start of the list
+// attribute, name, with list_append. The attribute is created if it does
+// not exist.
+func (u Update) ListPrepend(name string, values interface{}) Update {
+	return u.add(updateListPrepend, name, values)
+}
+
+// Add returns a copy of the Update which ADDs the number value to the
+// number attribute, name. The attribute is created with the value if it
+// does not exist.
+func (u Update) Add(name string, value interface{}) Update {
+	return u.add(updateAdd, name, value)
+}
+
+// AddToSet returns a copy of the Update which ADDs the members to the set
+// attribute, name. The members are marshaled with
+// dynamodbattribute.MarshalSet, so a []string is added to a String Set and
+// a []int to a Number Set. The attribute is created if it does not exist.
+func (u Update) AddToSet(name string, members interface{}) Update {
+	return u.add(updateAddToSet, name, members)
+}
+
+// DeleteFromSet returns a copy of the Update which DELETEs the members
+// from the set attribute, name. The members are marshaled with
+// dynamodbattribute.MarshalSet.
+func (u Update) DeleteFromSet(name string, members interface{}) Update {
+	return u.add(updateDeleteFromSet, name, members)
+}
+
+// Build returns the update Expression. An error is returned if the Update
+// has no actions, or an operand cannot be marshaled to the type its action
+// requires.
+func (u Update) Build() (Expression, error) {
+	if len(u.actions) == 0 {
+		return Expression{}, &InvalidParameterError{msg: "update must have at least one action"}
+	}
+	aliases := newAliasList("u")
+
+	var sets, removes, adds, deletes []string
+	for _, a := range u.actions {
+		if len(a.name) == 0 {
+			return Expression{}, &InvalidParameterError{msg: "update attribute name must not be empty"}
+		}
+		name := aliases.aliasName(a.name)
+
+		switch a.op {
+		case updateSet:
+			value, err := aliases.aliasValue(a.value)
+			if err != nil {
+				return Expression{}, err
+			}
+			sets = append(sets, name+" = "+value)
+		case updateRemove:
+			removes = append(removes, name)
+		case updateListAppend, updateListPrepend:
+			av, err := dynamodbattribute.Marshal(a.value)
+			if err != nil {
+				return Expression{}, err
+			}
+			if av.NULL != nil || (av.L != nil && len(av.L) == 0) {
+				return Expression{}, &InvalidParameterError{msg: "list_append values of " + a.name + " must not be empty"}
+			}
+			if av.L == nil {
+				return Expression{}, &InvalidParameterError{msg: "list_append values of " + a.name + " must marshal to a list"}
+			}
+			values := aliases.aliasAttributeValue(av)
+			list := "if_not_exists(" + name + ", " +
+				aliases.aliasAttributeValue(&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}) + ")"
+			if a.op == updateListAppend {
+				sets = append(sets, name+" = list_append("+list+", "+values+")")
+			} else {
+				sets = append(sets, name+" = list_append("+values+", "+list+")")
+			}
+		case updateAdd:
+			av, err := dynamodbattribute.Marshal(a.value)
+			if err != nil {
+				return Expression{}, err
+			}
+			if av.N == nil {
+				return Expression{}, &InvalidParameterError{msg: "ADD value of " + a.name + " must marshal to a number"}
+			}
+			adds = append(adds, name+" "+aliases.aliasAttributeValue(av))
+		case updateAddToSet, updateDeleteFromSet:
+			av, err := dynamodbattribute.MarshalSet(a.value)
+			if err != nil {
+				return Expression{}, err
+			}
+			if av.NULL != nil {
+				return Expression{}, &InvalidParameterError{msg: "set members of " + a.name + " must not be empty"}
+			}
+			action := name + " " + aliases.aliasAttributeValue(av)
+			if a.op == updateAddToSet {
+				adds = append(adds, action)
+			} else {
+				deletes = append(deletes, action)
+			}
+		}
+	}
+
+	var clauses []string
+	for _, c := range []struct {
+		keyword string
+		actions []string
+	}{
+		{"SET", sets}, {"REMOVE", removes}, {"ADD", adds}, {"DELETE", deletes},
+	} {
+		if len(c.actions) != 0 {
+			clauses = append(clauses, c.keyword+" "+strings.Join(c.actions, ", "))
+		}
+	}
+
+	return aliases.expression(strings.Join(clauses, " ")), nil
+}
@@ -0,0 +1,170 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestUpdateFromDiff(t *testing.T) {
+	oldItem := map[string]*dynamodb.AttributeValue{
+		"id":     {S: aws.String("abc")},
+		"status": {S: aws.String("new")},
+		"note":   {S: aws.String("call")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Seattle")},
+			"zip":  {S: aws.String("98101")},
+		}},
+	}
+	newItem := map[string]*dynamodb.AttributeValue{
+		"id":     {S: aws.String("abc")},
+		"status": {S: aws.String("shipped")},
+		"total":  {N: aws.String("5")},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+			"zip":  {S: aws.String("98101")},
+		}},
+	}
+
+	expect := Expression{
+		Expression: "SET #u0.#u1 = :u0, #u3 = :u1, #u4 = :u2 REMOVE #u2",
+		Names: map[string]*string{
+			"#u0": aws.String("address"),
+			"#u1": aws.String("city"),
+			"#u2": aws.String("note"),
+			"#u3": aws.String("status"),
+			"#u4": aws.String("total"),
+		},
+		Values: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("Portland")},
+			":u1": {S: aws.String("shipped")},
+			":u2": {N: aws.String("5")},
+		},
+	}
+	if e, a := expect, UpdateFromDiff(dynamodbattribute.Diff(oldItem, newItem)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUpdateFromDiffRemoveOnly(t *testing.T) {
+	diffs := []dynamodbattribute.AttributeDiff{
+		{Kind: dynamodbattribute.DiffRemoved, Path: []string{"a"}, Old: &dynamodb.AttributeValue{S: aws.String("x")}},
+	}
+	expect := Expression{
+		Expression: "REMOVE #u0",
+		Names:      map[string]*string{"#u0": aws.String("a")},
+	}
+	if e, a := expect, UpdateFromDiff(diffs); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := (Expression{}), UpdateFromDiff(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUpdateBuild(t *testing.T) {
+	expr, err := Update{}.
+		Set("status", "shipped").
+		ListAppend("events", []string{"shipped"}).
+		ListPrepend("recent", []int{1}).
+		Remove("note").
+		Add("count", 2).
+		AddToSet("tags", []string{"priority"}).
+		DeleteFromSet("codes", []int{7}).
+		Build()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := Expression{
+		Expression: "SET #u0 = :u0, #u1 = list_append(if_not_exists(#u1, :u2), :u1), " +
+			"#u2 = list_append(:u3, if_not_exists(#u2, :u4)) " +
+			"REMOVE #u3 ADD #u4 :u5, #u5 :u6 DELETE #u6 :u7",
+		Names: map[string]*string{
+			"#u0": aws.String("status"),
+			"#u1": aws.String("events"),
+			"#u2": aws.String("recent"),
+			"#u3": aws.String("note"),
+			"#u4": aws.String("count"),
+			"#u5": aws.String("tags"),
+			"#u6": aws.String("codes"),
+		},
+		Values: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("shipped")},
+			":u1": {L: []*dynamodb.AttributeValue{{S: aws.String("shipped")}}},
+			":u2": {L: []*dynamodb.AttributeValue{}},
+			":u3": {L: []*dynamodb.AttributeValue{{N: aws.String("1")}}},
+			":u4": {L: []*dynamodb.AttributeValue{}},
+			":u5": {N: aws.String("2")},
+			":u6": {SS: []*string{aws.String("priority")}},
+			":u7": {NS: []*string{aws.String("7")}},
+		},
+	}
+	if e, a := expect, expr; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUpdateBuildCopies(t *testing.T) {
+	base := Update{}.Set("a", 1)
+	withB := base.Set("b", 2)
+	withC := base.Set("c", 3)
+
+	for _, c := range []struct {
+		update Update
+		expect string
+	}{
+		{withB, "SET #u0 = :u0, #u1 = :u1"},
+		{withC, "SET #u0 = :u0, #u1 = :u1"},
+		{base, "SET #u0 = :u0"},
+	} {
+		expr, err := c.update.Build()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := c.expect, expr.Expression; e != a {
+			t.Errorf("expect %v, got %v", e, a)
+		}
+	}
+	expr, _ := withC.Build()
+	if e, a := "c", *expr.Names["#u1"]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUpdateBuildErrors(t *testing.T) {
+	cases := []Update{
+		{},
+		Update{}.Set("", 1),
+		Update{}.ListAppend("events", "shipped"),
+		Update{}.Add("count", "one"),
+		Update{}.AddToSet("tags", "priority"),
+		Update{}.DeleteFromSet("tags", []string{}),
+	}
+	for i, u := range cases {
+		if _, err := u.Build(); err == nil {
+			t.Errorf("%d, expect error", i)
+		}
+	}
+}
+
+func TestUpdateBuildEmptyList(t *testing.T) {
+	cases := []Update{
+		Update{}.ListAppend("events", []string{}),
+		Update{}.ListPrepend("events", []string(nil)),
+		Update{}.ListAppend("events", []interface{}{}),
+	}
+	for i, u := range cases {
+		_, err := u.Build()
+		if err == nil {
+			t.Fatalf("%d, expect error", i)
+		}
+		if e, a := "list_append values of events must not be empty", err.(*InvalidParameterError).Message(); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package expression
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// WriteCondition returns the condition Expression which enforces the write
+// rules when item is written, e.g. by PutItem. The rules are typically those
+// returned by dynamodbattribute.WriteRules for the Go value item was
+// marshaled from. The returned Expression's string is empty if there are no
+// rules.
+//
+// A writeonce attribute is only written if it does not exist. An immutable
+// attribute is only written if it does not exist, or is equal to the value
+// in item. If an immutable attribute is not in item the write would remove
+// it, so the write must also require the attribute not exist. A version
+// attribute is only written if it is equal to the rule's expected version,
+// or does not exist if there is no expected version.
+//
+//     item, err := dynamodbattribute.MarshalMap(account)
+//     ...
+//     cond := expression.WriteCondition(item, dynamodbattribute.WriteRules(account))
+//     params := &dynamodb.PutItemInput{
+//         Item:                      item,
+//         ConditionExpression:       aws.String(cond.Expression),
+//         ExpressionAttributeNames:  cond.Names,
+//         ExpressionAttributeValues: cond.Values,
+//     }
+func WriteCondition(item map[string]*dynamodb.AttributeValue, rules []dynamodbattribute.WriteRule) Expression {
+	aliases := newAliasList("w")
+
+	conds := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		name := aliases.aliasName(rule.Name)
+		notExists := "attribute_not_exists(" + name + ")"
+
+		if rule.Kind == dynamodbattribute.WriteRuleVersion {
+			if rule.Expected == nil {
+				conds = append(conds, notExists)
+			} else {
+				conds = append(conds, name+" = "+aliases.aliasAttributeValue(rule.Expected))
+			}
+			continue
+		}
+
+		av, ok := item[rule.Name]
+		if rule.Kind == dynamodbattribute.WriteRuleWriteOnce || !ok || av == nil || av.NULL != nil {
+			conds = append(conds, notExists)
+			continue
+		}
+
+		value := aliases.aliasAttributeValue(av)
+		conds = append(conds, "("+notExists+" OR "+name+" = "+value+")")
+	}
+
+	return aliases.expression(strings.Join(conds, " AND "))
+}
@@ -0,0 +1,76 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestWriteCondition(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"id":      {S: aws.String("abc")},
+		"created": {S: aws.String("2016-01-01T00:00:00Z")},
+		"owner":   {S: aws.String("bob")},
+		"region":  {NULL: aws.Bool(true)},
+	}
+	rules := []dynamodbattribute.WriteRule{
+		{Name: "owner", Kind: dynamodbattribute.WriteRuleWriteOnce},
+		{Name: "created", Kind: dynamodbattribute.WriteRuleImmutable},
+		{Name: "region", Kind: dynamodbattribute.WriteRuleImmutable},
+		{Name: "missing", Kind: dynamodbattribute.WriteRuleImmutable},
+	}
+
+	expect := Expression{
+		Expression: "attribute_not_exists(#w0) AND (attribute_not_exists(#w1) OR #w1 = :w0) AND " +
+			"attribute_not_exists(#w2) AND attribute_not_exists(#w3)",
+		Names: map[string]*string{
+			"#w0": aws.String("owner"),
+			"#w1": aws.String("created"),
+			"#w2": aws.String("region"),
+			"#w3": aws.String("missing"),
+		},
+		Values: map[string]*dynamodb.AttributeValue{
+			":w0": {S: aws.String("2016-01-01T00:00:00Z")},
+		},
+	}
+	if e, a := expect, WriteCondition(item, rules); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestWriteConditionNoRules(t *testing.T) {
+	expect := Expression{}
+	if e, a := expect, WriteCondition(map[string]*dynamodb.AttributeValue{}, nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestWriteConditionVersion(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"version": {N: aws.String("3")},
+	}
+	rules := []dynamodbattribute.WriteRule{
+		{Name: "version", Kind: dynamodbattribute.WriteRuleVersion, Expected: &dynamodb.AttributeValue{N: aws.String("2")}},
+	}
+
+	expect := Expression{
+		Expression: "#w0 = :w0",
+		Names:      map[string]*string{"#w0": aws.String("version")},
+		Values:     map[string]*dynamodb.AttributeValue{":w0": {N: aws.String("2")}},
+	}
+	if e, a := expect, WriteCondition(item, rules); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	rules[0].Expected = nil
+	expect = Expression{
+		Expression: "attribute_not_exists(#w0)",
+		Names:      map[string]*string{"#w0": aws.String("version")},
+	}
+	if e, a := expect, WriteCondition(item, rules); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// WaiterOptions configures the table lifecycle waiters, such as
+// WaitUntilTableExistsWithOptions.
+type WaiterOptions struct {
+	// Time waited between DescribeTable attempts.
+	//
+	// Defaults to 20 seconds.
+	Delay time.Duration
+
+	// Maximum number of DescribeTable attempts before the waiter gives up,
+	// returning a ResourceNotReady error.
+	//
+	// Defaults to 25.
+	MaxAttempts int
+
+	// Closing the channel stops the waiter, which returns a RequestCanceled
+	// error.
+	//
+	// Defaults to nil, which never stops the waiter.
+	Cancel <-chan struct{}
+}
+
+// WaitUntilTableExistsWithOptions waits until the table's status is ACTIVE,
+// like WaitUntilTableExists. Use the `opts` functional options to override
+// the default WaiterOptions.
+//
+//     err := svc.WaitUntilTableExistsWithOptions(input, func(o *dynamodb.WaiterOptions) {
+//         o.Delay = 5 * time.Second
+//         o.MaxAttempts = 60
+//     })
+func (c *DynamoDB) WaitUntilTableExistsWithOptions(input *DescribeTableInput, opts ...func(*WaiterOptions)) error {
+	return c.waitForTable(input, opts, func(resp *DescribeTableOutput, err error) (bool, error) {
+		if err != nil {
+			return false, ignoreResourceNotFound(err)
+		}
+		return aws.StringValue(resp.Table.TableStatus) == TableStatusActive, nil
+	})
+}
+
+// WaitUntilTableNotExistsWithOptions waits until the table is deleted, like
+// WaitUntilTableNotExists. Use the `opts` functional options to override
+// the default WaiterOptions.
+func (c *DynamoDB) WaitUntilTableNotExistsWithOptions(input *DescribeTableInput, opts ...func(*WaiterOptions)) error {
+	return c.waitForTable(input, opts, func(resp *DescribeTableOutput, err error) (bool, error) {
+		if err != nil {
+			if isResourceNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return false, nil
+	})
+}
+
+// WaitUntilIndexActive waits until the table's status is ACTIVE, and the
+// status of its global secondary index named indexName is ACTIVE, such as
+// after the index is added with UpdateTable. Use the `opts` functional
+// options to override the default WaiterOptions.
+func (c *DynamoDB) WaitUntilIndexActive(input *DescribeTableInput, indexName string, opts ...func(*WaiterOptions)) error {
+	return c.waitForTable(input, opts, func(resp *DescribeTableOutput, err error) (bool, error) {
+		if err != nil {
+			return false, ignoreResourceNotFound(err)
+		}
+		if aws.StringValue(resp.Table.TableStatus) != TableStatusActive {
+			return false, nil
+		}
+		for _, index := range resp.Table.GlobalSecondaryIndexes {
+			if aws.StringValue(index.IndexName) == indexName {
+				return aws.StringValue(index.IndexStatus) == IndexStatusActive, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// waitForTable describes the table until done returns true or an error,
+// waiting between attempts.
+func (c *DynamoDB) waitForTable(input *DescribeTableInput, opts []func(*WaiterOptions), done func(*DescribeTableOutput, error) (bool, error)) error {
+	o := WaiterOptions{
+		Delay:       20 * time.Second,
+		MaxAttempts: 25,
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+
+	for i := 0; i < o.MaxAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-o.Cancel:
+				return awserr.New("RequestCanceled", "waiter canceled", nil)
+			case <-time.After(o.Delay):
+			}
+		}
+
+		req, resp := c.DescribeTableRequest(input)
+		req.Handlers.Build.PushBack(request.MakeAddToUserAgentFreeFormHandler("Waiter"))
+		ok, err := done(resp, req.Send())
+		if err != nil || ok {
+			return err
+		}
+	}
+
+	return awserr.New("ResourceNotReady",
+		fmt.Sprintf("exceeded %d wait attempts", o.MaxAttempts), nil)
+}
+
+func isResourceNotFound(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == "ResourceNotFoundException"
+}
+
+func ignoreResourceNotFound(err error) error {
+	if isResourceNotFound(err) {
+		return nil
+	}
+	return err
+}
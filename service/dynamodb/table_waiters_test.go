@@ -0,0 +1,113 @@
+package dynamodb_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const notFoundBody = `{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"not found"}`
+
+// mockDescribeTable returns a client responding to each DescribeTable
+// request with the next of the bodies, the last repeated. Bodies which
+// are errors are sent with status 400.
+func mockDescribeTable(bodies ...string) (*dynamodb.DynamoDB, *int) {
+	svc := dynamodb.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	attempts := 0
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		body := bodies[len(bodies)-1]
+		if attempts < len(bodies) {
+			body = bodies[attempts]
+		}
+		attempts++
+		status := 200
+		if body == notFoundBody {
+			status = 400
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			Header:     http.Header{},
+		}
+	})
+	return svc, &attempts
+}
+
+func fastWaiter(o *dynamodb.WaiterOptions) {
+	o.Delay = time.Millisecond
+}
+
+func TestWaitUntilTableExistsWithOptions(t *testing.T) {
+	svc, attempts := mockDescribeTable(notFoundBody,
+		`{"Table":{"TableStatus":"CREATING"}}`,
+		`{"Table":{"TableStatus":"ACTIVE"}}`)
+
+	err := svc.WaitUntilTableExistsWithOptions(&dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}, fastWaiter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *attempts)
+}
+
+func TestWaitUntilTableExistsMaxAttempts(t *testing.T) {
+	svc, attempts := mockDescribeTable(`{"Table":{"TableStatus":"CREATING"}}`)
+
+	err := svc.WaitUntilTableExistsWithOptions(&dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}, fastWaiter, func(o *dynamodb.WaiterOptions) {
+		o.MaxAttempts = 4
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "ResourceNotReady", err.(awserr.Error).Code())
+	assert.Equal(t, 4, *attempts)
+}
+
+func TestWaitUntilTableExistsCancel(t *testing.T) {
+	svc, attempts := mockDescribeTable(`{"Table":{"TableStatus":"CREATING"}}`)
+	cancel := make(chan struct{})
+	close(cancel)
+
+	err := svc.WaitUntilTableExistsWithOptions(&dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}, func(o *dynamodb.WaiterOptions) {
+		o.Delay = time.Hour
+		o.Cancel = cancel
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "RequestCanceled", err.(awserr.Error).Code())
+	assert.Equal(t, 1, *attempts)
+}
+
+func TestWaitUntilTableNotExistsWithOptions(t *testing.T) {
+	svc, attempts := mockDescribeTable(`{"Table":{"TableStatus":"DELETING"}}`, notFoundBody)
+
+	err := svc.WaitUntilTableNotExistsWithOptions(&dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}, fastWaiter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *attempts)
+}
+
+func TestWaitUntilIndexActive(t *testing.T) {
+	svc, attempts := mockDescribeTable(
+		`{"Table":{"TableStatus":"UPDATING","GlobalSecondaryIndexes":[{"IndexName":"byDate","IndexStatus":"CREATING"}]}}`,
+		`{"Table":{"TableStatus":"ACTIVE","GlobalSecondaryIndexes":[{"IndexName":"byDate","IndexStatus":"CREATING"},{"IndexName":"byUser","IndexStatus":"ACTIVE"}]}}`,
+		`{"Table":{"TableStatus":"ACTIVE","GlobalSecondaryIndexes":[{"IndexName":"byDate","IndexStatus":"ACTIVE"}]}}`)
+
+	err := svc.WaitUntilIndexActive(&dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}, "byDate", fastWaiter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *attempts)
+}
@@ -0,0 +1,197 @@
+package dynamodb
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Error codes of the errors a ThrottleRetryer backs off from with their own
+// delays.
+const (
+	throttledErrCode = "ProvisionedThroughputExceededException"
+	conflictErrCode  = "TransactionConflictException"
+)
+
+// A ThrottleRetryer is a request.Retryer for DynamoDB clients which backs
+// off from throttled requests, and from transaction conflicts, with their
+// own delays, and can limit the client's retries with a TokenBucket. Set it
+// as the Retryer of the config of a client, such as a client used for a
+// single table, to replace the client's default retryer:
+//
+//     r := dynamodb.NewThrottleRetryer(func(r *dynamodb.ThrottleRetryer) {
+//         r.Tokens = dynamodb.NewTokenBucket(100, 10)
+//     })
+//     svc := dynamodb.New(sess, request.WithRetryer(aws.NewConfig(), r))
+//
+// Errors are retried when the DynamoDB client's default retryer retries
+// them, and transaction conflicts are also retried. A ThrottleRetryer is
+// safe for concurrent use once configured.
+type ThrottleRetryer struct {
+	// Maximum number of retries of a request.
+	//
+	// Defaults to 10.
+	NumMaxRetries int
+
+	// Delay before the first retry of a request which failed with an
+	// error other than throttling or a transaction conflict, doubled for
+	// each retry after.
+	//
+	// Defaults to 50 milliseconds, as the DynamoDB client's default
+	// retryer.
+	BaseDelay time.Duration
+
+	// Delay before the first retry of a throttled request, doubled for
+	// each retry after. Retries are delayed between half and all of the
+	// delay, so throttled clients spread out their retries.
+	//
+	// Defaults to 100 milliseconds.
+	ThrottleDelay time.Duration
+
+	// Delay before the first retry of a request which failed with a
+	// transaction conflict, doubled for each retry after, and jittered
+	// like ThrottleDelay.
+	//
+	// Defaults to 20 milliseconds.
+	ConflictDelay time.Duration
+
+	// Maximum delay before a retry.
+	//
+	// Defaults to 20 seconds.
+	MaxDelay time.Duration
+
+	// Token bucket each retry takes a token from. Requests are not retried
+	// once the bucket is empty, so a throttled client backs off instead of
+	// adding to the load on the table.
+	//
+	// Defaults to nil, which does not limit retries.
+	Tokens *TokenBucket
+}
+
+// NewThrottleRetryer creates a new ThrottleRetryer. Use the `opts`
+// functional options to override the default configuration.
+func NewThrottleRetryer(opts ...func(*ThrottleRetryer)) *ThrottleRetryer {
+	r := &ThrottleRetryer{
+		NumMaxRetries: 10,
+		BaseDelay:     50 * time.Millisecond,
+		ThrottleDelay: 100 * time.Millisecond,
+		ConflictDelay: 20 * time.Millisecond,
+		MaxDelay:      20 * time.Second,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// MaxRetries returns the maximum number of retries of a request,
+// satisfying the request.Retryer interface.
+func (r *ThrottleRetryer) MaxRetries() int {
+	return r.NumMaxRetries
+}
+
+// RetryRules returns the delay before the request is retried, satisfying
+// the request.Retryer interface.
+func (r *ThrottleRetryer) RetryRules(req *request.Request) time.Duration {
+	switch errCode(req.Error) {
+	case throttledErrCode:
+		return jitter(r.backoff(r.ThrottleDelay, req.RetryCount))
+	case conflictErrCode:
+		return jitter(r.backoff(r.ConflictDelay, req.RetryCount))
+	default:
+		return r.backoff(r.BaseDelay, req.RetryCount)
+	}
+}
+
+// ShouldRetry returns if the request should be retried, satisfying the
+// request.Retryer interface. Requests are not retried if the Tokens bucket
+// is empty, and no token is taken once the request has no retries left.
+func (r *ThrottleRetryer) ShouldRetry(req *request.Request) bool {
+	if req.RetryCount >= r.NumMaxRetries {
+		return false
+	}
+
+	retry := errCode(req.Error) == conflictErrCode || req.IsErrorRetryable() ||
+		(req.HTTPResponse != nil && req.HTTPResponse.StatusCode >= 500)
+	if !retry {
+		return false
+	}
+	return r.Tokens == nil || r.Tokens.Take()
+}
+
+// backoff returns the base delay doubled for each retry, up to MaxDelay.
+func (r *ThrottleRetryer) backoff(base time.Duration, retryCount int) time.Duration {
+	// Stop doubling before the delay could overflow.
+	if retryCount > 30 {
+		retryCount = 30
+	}
+	delay := base * time.Duration(1<<uint(retryCount))
+	if delay > r.MaxDelay || delay <= 0 {
+		delay = r.MaxDelay
+	}
+	return delay
+}
+
+// jitter returns a random delay between half and all of delay.
+func jitter(delay time.Duration) time.Duration {
+	if delay < 2 {
+		return delay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// errCode returns the code of the awserr.Error, or an empty string.
+func errCode(err error) string {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return ""
+}
+
+// A TokenBucket is a client-side token bucket limiting the rate of retries
+// of a ThrottleRetryer. The bucket starts full, and is refilled at a
+// constant rate. A TokenBucket is safe for concurrent use, and can be
+// shared by the retryers of several clients.
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates a new full TokenBucket holding up to capacity
+// tokens, refilled with refillPerSecond tokens a second.
+func NewTokenBucket(capacity int, refillPerSecond float64) *TokenBucket {
+	return &TokenBucket{
+		capacity: float64(capacity),
+		rate:     refillPerSecond,
+		tokens:   float64(capacity),
+		last:     time.Now(),
+	}
+}
+
+// Take takes a token from the bucket, returning false if the bucket is
+// empty.
+func (b *TokenBucket) Take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,96 @@
+package dynamodb_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func failedRequest(code string, status, retryCount int) *request.Request {
+	return &request.Request{
+		Error:        awserr.New(code, "failed", nil),
+		HTTPResponse: &http.Response{StatusCode: status},
+		RetryCount:   retryCount,
+	}
+}
+
+func TestThrottleRetryerConfigured(t *testing.T) {
+	r := dynamodb.NewThrottleRetryer(func(r *dynamodb.ThrottleRetryer) {
+		r.NumMaxRetries = 3
+	})
+	d := dynamodb.New(unit.Session, request.WithRetryer(aws.NewConfig(), r))
+	assert.Equal(t, 3, d.MaxRetries())
+	assert.Equal(t, r, d.Retryer)
+}
+
+func TestThrottleRetryerOptIn(t *testing.T) {
+	r := client.DefaultRetryer{NumMaxRetries: 3}
+	d := dynamodb.New(unit.Session, request.WithRetryer(aws.NewConfig(), r))
+	assert.Equal(t, 10, d.MaxRetries())
+	assert.NotEqual(t, r, d.Retryer)
+}
+
+func TestThrottleRetryerShouldRetry(t *testing.T) {
+	r := dynamodb.NewThrottleRetryer()
+
+	assert.True(t, r.ShouldRetry(failedRequest("ProvisionedThroughputExceededException", 400, 0)))
+	assert.True(t, r.ShouldRetry(failedRequest("TransactionConflictException", 400, 0)))
+	assert.True(t, r.ShouldRetry(failedRequest("InternalServerError", 500, 0)))
+	assert.False(t, r.ShouldRetry(failedRequest("ValidationException", 400, 0)))
+	assert.False(t, r.ShouldRetry(failedRequest("ConditionalCheckFailedException", 400, 0)))
+}
+
+func TestThrottleRetryerRetryRules(t *testing.T) {
+	r := dynamodb.NewThrottleRetryer(func(r *dynamodb.ThrottleRetryer) {
+		r.MaxDelay = time.Second
+	})
+
+	assert.Equal(t, 200*time.Millisecond, r.RetryRules(failedRequest("InternalServerError", 500, 2)))
+	assert.Equal(t, time.Second, r.RetryRules(failedRequest("InternalServerError", 500, 40)))
+
+	for i := 0; i < 20; i++ {
+		delay := r.RetryRules(failedRequest("ProvisionedThroughputExceededException", 400, 2))
+		assert.True(t, delay >= 200*time.Millisecond && delay <= 400*time.Millisecond, "throttle delay %v", delay)
+
+		delay = r.RetryRules(failedRequest("TransactionConflictException", 400, 2))
+		assert.True(t, delay >= 40*time.Millisecond && delay <= 80*time.Millisecond, "conflict delay %v", delay)
+	}
+}
+
+func TestThrottleRetryerTokens(t *testing.T) {
+	r := dynamodb.NewThrottleRetryer(func(r *dynamodb.ThrottleRetryer) {
+		r.Tokens = dynamodb.NewTokenBucket(2, 0)
+	})
+
+	assert.True(t, r.ShouldRetry(failedRequest("ProvisionedThroughputExceededException", 400, 0)))
+	assert.False(t, r.ShouldRetry(failedRequest("ValidationException", 400, 0)))
+	assert.True(t, r.ShouldRetry(failedRequest("ProvisionedThroughputExceededException", 400, 1)))
+	assert.False(t, r.ShouldRetry(failedRequest("ProvisionedThroughputExceededException", 400, 2)))
+}
+
+func TestThrottleRetryerNoRetriesLeft(t *testing.T) {
+	tokens := dynamodb.NewTokenBucket(1, 0)
+	r := dynamodb.NewThrottleRetryer(func(r *dynamodb.ThrottleRetryer) {
+		r.NumMaxRetries = 2
+		r.Tokens = tokens
+	})
+
+	assert.False(t, r.ShouldRetry(failedRequest("ProvisionedThroughputExceededException", 400, 2)))
+	assert.True(t, tokens.Take(), "expect token not taken for final attempt")
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	b := dynamodb.NewTokenBucket(1, 1000)
+	assert.True(t, b.Take())
+	time.Sleep(5 * time.Millisecond)
+	assert.True(t, b.Take())
+}